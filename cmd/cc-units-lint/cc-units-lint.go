@@ -0,0 +1,249 @@
+// cc-units-lint scans a tree of collector, router and metric store configuration files
+// for invalid, ambiguous and inconsistent unit strings
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+	"gopkg.in/yaml.v3"
+)
+
+// Exit codes
+const (
+	exitOK     = 0 // No issues found
+	exitIssues = 1 // Issues found
+	exitError  = 2 // Usage error or unreadable files
+)
+
+// unitField is a unit string found in a configuration file
+type unitField struct {
+	File   string // Path of the configuration file
+	Path   string // Location in the file like 'unit_rules.mem_bw.target_unit'
+	Key    string // Name of the field like 'unit' or 'target_unit'
+	Metric string // Metric the unit belongs to (optional)
+	Value  string
+}
+
+// finding is an issue reported for a unit field
+type finding struct {
+	Field   unitField
+	Kind    string
+	Message string
+}
+
+// isUnitKey checks whether a field contains a unit string
+func isUnitKey(key string) bool {
+	return key == "unit" || strings.HasSuffix(key, "_unit")
+}
+
+// isPlaceholder checks for wildcards and placeholders like '*' or '<copy>' used in router configurations
+func isPlaceholder(value string) bool {
+	return strings.ContainsAny(value, "*<>")
+}
+
+// metricName returns the metric of an object out of its 'name' or 'metric' field
+func metricName(obj map[string]interface{}, parentKey string) string {
+	for _, k := range []string{"name", "metric"} {
+		if s, ok := obj[k].(string); ok && len(s) > 0 {
+			return s
+		}
+	}
+	return parentKey
+}
+
+// extract walks a decoded configuration and collects all unit fields
+func extract(file, path, parentKey string, node interface{}, fields []unitField) []unitField {
+	join := func(key string) string {
+		if len(path) == 0 {
+			return key
+		}
+		return path + "." + key
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		metric := metricName(n, parentKey)
+		// Tag rules like {"key": "unit", "value": "MB/s"}. They apply to the metrics matching
+		// a condition, so they are not checked for consistency.
+		if k, ok := n["key"].(string); ok && k == "unit" {
+			if v, ok := n["value"].(string); ok && !isPlaceholder(v) {
+				fields = append(fields, unitField{File: file, Path: join("value"), Key: "unit", Value: v})
+			}
+		}
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := n[k]
+			if isUnitKey(k) {
+				switch u := v.(type) {
+				case string:
+					if !isPlaceholder(u) {
+						fields = append(fields, unitField{File: file, Path: join(k), Key: k, Metric: metric, Value: u})
+					}
+					continue
+				case map[string]interface{}:
+					// Unit notation of the job archive like {"base": "B/s", "prefix": "G"}
+					if base, ok := u["base"].(string); ok {
+						prefix, _ := u["prefix"].(string)
+						if len(base) > 0 {
+							fields = append(fields, unitField{File: file, Path: join(k), Key: k, Metric: metric, Value: prefix + base})
+						}
+						continue
+					}
+				}
+			}
+			fields = extract(file, join(k), k, v, fields)
+		}
+	case []interface{}:
+		for i, v := range n {
+			fields = extract(file, fmt.Sprintf("%s[%d]", path, i), parentKey, v, fields)
+		}
+	}
+	return fields
+}
+
+// readFile decodes a JSON or YAML configuration file and extracts the unit fields
+func readFile(path string) ([]unitField, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var content interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &content)
+	default:
+		err = json.Unmarshal(data, &content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode '%s': %v", path, err)
+	}
+	return extract(path, "", "", content, make([]unitField, 0)), nil
+}
+
+// lint checks all unit fields and the consistency of the units of a metric across files
+func lint(fields []unitField, strict bool) []finding {
+	findings := make([]finding, 0)
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		values = append(values, f.Value)
+	}
+	for _, issue := range units.Lint(values) {
+		if issue.Kind == units.IssueNonCanonical && !strict {
+			continue
+		}
+		msg := issue.Message
+		if len(issue.Suggestion) > 0 {
+			msg = fmt.Sprintf("%s (use '%s')", msg, issue.Suggestion)
+		}
+		findings = append(findings, finding{Field: fields[issue.Index], Kind: issue.Kind.String(), Message: msg})
+	}
+
+	// The same field of a metric should have the same unit everywhere
+	seen := make(map[string]unitField)
+	for _, f := range fields {
+		if len(f.Metric) == 0 {
+			continue
+		}
+		u := units.NewUnit(f.Value)
+		if !u.Valid() {
+			continue
+		}
+		id := f.Metric + "\x00" + f.Key
+		first, ok := seen[id]
+		if !ok {
+			seen[id] = f
+			continue
+		}
+		if !units.NewUnit(first.Value).Equal(u) {
+			findings = append(findings, finding{
+				Field:   f,
+				Kind:    "inconsistent",
+				Message: fmt.Sprintf("%s of metric '%s' is '%s' but '%s' in %s:%s", f.Key, f.Metric, f.Value, first.Value, first.File, first.Path),
+			})
+		}
+	}
+	return findings
+}
+
+// run lints the files and directories given in args (without the program name) and returns
+// the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("cc-units-lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	strict := flags.Bool("strict", false, "Report non-canonical unit spellings as issues")
+	quiet := flags.Bool("quiet", false, "Only set the exit code")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: cc-units-lint [options] <dir|file>...\n\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return exitOK
+	} else if err != nil {
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	exitCode := exitOK
+	fields := make([]unitField, 0)
+	for _, root := range flags.Args() {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".json", ".yaml", ".yml":
+			default:
+				return nil
+			}
+			f, err := readFile(path)
+			if err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				exitCode = exitError
+				return nil
+			}
+			fields = append(fields, f...)
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			exitCode = exitError
+		}
+	}
+
+	findings := lint(fields, *strict)
+	if !*quiet {
+		for _, f := range findings {
+			fmt.Fprintf(stdout, "%s:%s: %s: %s\n", f.Field.File, f.Field.Path, f.Kind, f.Message)
+		}
+		fmt.Fprintf(stdout, "%d unit fields checked, %d issues found\n", len(fields), len(findings))
+	}
+	if len(findings) > 0 && exitCode == exitOK {
+		exitCode = exitIssues
+	}
+	return exitCode
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates the files below a temporary directory and returns the directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write '%s': %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	valid := writeFiles(t, map[string]string{
+		"router.json":        `{"unit_rules": {"mem_bw": {"target_unit": "GB/s"}}}`,
+		"store.yaml":         "metrics:\n  - name: mem_bw\n    unit: GByte/s\n",
+		"notes.txt":          `{"unit": "xyz"}`,
+		"vendor/lib.json":    `{"unit": "xyz"}`,
+		".git/config.json":   `{"unit": "xyz"}`,
+		"tags/add_tags.json": `{"add_tags": [{"key": "unit", "value": "<copy>"}]}`,
+	})
+	inconsistent := writeFiles(t, map[string]string{
+		"router.json": `{"unit_rules": {"mem_bw": {"target_unit": "MB/s"}}}`,
+		"store.json":  `{"unit_rules": {"mem_bw": {"target_unit": "GB/s"}}}`,
+	})
+	broken := writeFiles(t, map[string]string{
+		"router.json": `{"unit_rules": {"mem_bw": {"target_unit": "xyz"}}}`,
+		"store.json":  `{"unit_rules": `,
+	})
+
+	for _, c := range []struct {
+		args   []string
+		code   int
+		stdout string // Expected substring of the output
+		stderr string // Expected substring of the error output
+	}{
+		{[]string{valid}, exitOK, "2 unit fields checked, 0 issues found", ""},
+		{[]string{filepath.Join(valid, "router.json")}, exitOK, "1 unit fields checked, 0 issues found", ""},
+		{[]string{"-strict", valid}, exitIssues, "non-canonical", ""},
+		{[]string{"-quiet", "-strict", valid}, exitIssues, "", ""},
+		{[]string{inconsistent}, exitIssues, "inconsistent: target_unit of metric 'mem_bw' is 'GB/s' but 'MB/s'", ""},
+		{[]string{broken}, exitError, "1 unit fields checked, 1 issues found", "failed to decode"},
+		{[]string{filepath.Join(valid, "missing")}, exitError, "0 unit fields checked", "no such file or directory"},
+		{[]string{"-h"}, exitOK, "", "Usage: cc-units-lint"},
+		{[]string{"-unknown", valid}, exitError, "", "flag provided but not defined: -unknown"},
+		{nil, exitError, "", "Usage: cc-units-lint"},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, &stdout, &stderr)
+		if code != c.code {
+			t.Errorf("Expected exit code %d for %v but got %d: %s%s", c.code, c.args, code, stdout.String(), stderr.String())
+		}
+		if !strings.Contains(stdout.String(), c.stdout) {
+			t.Errorf("Expected output '%s' for %v but got '%s'", c.stdout, c.args, stdout.String())
+		}
+		if !strings.Contains(stderr.String(), c.stderr) {
+			t.Errorf("Expected error output '%s' for %v but got '%s'", c.stderr, c.args, stderr.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-quiet", inconsistent}, &stdout, &stderr); code != exitIssues || stdout.Len() > 0 {
+		t.Errorf("Expected exit code %d without output but got %d: %s", exitIssues, code, stdout.String())
+	}
+}
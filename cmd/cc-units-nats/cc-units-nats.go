@@ -0,0 +1,44 @@
+// cc-units-nats subscribes to a NATS subject with metric messages, converts the values to
+// the configured canonical units and republishes them, so downstream sinks never see mixed units
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ClusterCockpit/cc-metric-collector/internal/natsNormalizer"
+	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
+)
+
+func main() {
+	cfg := flag.String("config", "./units-nats.json", "Path to configuration file")
+	debug := flag.Bool("debug", false, "Activate debug output")
+	flag.Parse()
+	if *debug {
+		cclog.SetDebug()
+	}
+
+	config, err := os.ReadFile(*cfg)
+	if err != nil {
+		cclog.Error("Error reading configuration file ", *cfg, ": ", err.Error())
+		os.Exit(1)
+	}
+	n, err := natsNormalizer.NewNatsNormalizer("units", config)
+	if err != nil {
+		cclog.Error(err.Error())
+		os.Exit(1)
+	}
+	if err := n.Start(); err != nil {
+		cclog.Error(err.Error())
+		n.Close()
+		os.Exit(1)
+	}
+
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	<-shutdown
+	cclog.Info("Shutdown...")
+	n.Close()
+}
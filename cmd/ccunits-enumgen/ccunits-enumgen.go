@@ -0,0 +1,402 @@
+// ccunits-enumgen generates the Measure and Prefix constants, MeasuresMap, PrefixDataMap,
+// the built-in aliases, the metadata and dimensions of the measures and the lookup functions of the built-in measures and prefixes out
+// of the declarative definitions in pkg/ccUnits/ccUnitBuiltin.yaml. It does not import the
+// ccUnits package, so it works even if the generated file is broken.
+// It is called by 'go generate' in pkg/ccUnits.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const header = "Code generated by ccunits-enumgen from ccUnitBuiltin.yaml. DO NOT EDIT."
+
+// measureDefinition is the definition of a built-in measure
+type measureDefinition struct {
+	Name         string             `yaml:"name"` // Name of the Go constant
+	ID           int                `yaml:"id"`   // Stable ID of the measure
+	Long         string             `yaml:"long"`
+	Short        string             `yaml:"short"`
+	Regex        string             `yaml:"regex"`
+	NonDividable bool               `yaml:"non_dividable"`
+	Aliases      []string           `yaml:"aliases"`
+	Dimension    map[string]int     `yaml:"dimension"`   // Exponents of the base dimensions
+	Scale        float64            `yaml:"scale"`       // Value in the unit of the dimension, 1 if unset
+	Logarithmic  bool               `yaml:"logarithmic"` // Measure has no dimension
+	Metadata     metadataDefinition `yaml:"metadata"`
+}
+
+// metadataDefinition is the metadata of a built-in measure for UIs
+type metadataDefinition struct {
+	Description string   `yaml:"description"`
+	Singular    string   `yaml:"singular"`
+	Plural      string   `yaml:"plural"`
+	Category    string   `yaml:"category"`
+	Color       string   `yaml:"color"`
+	TypicalMin  float64  `yaml:"typical_min"`
+	TypicalMax  float64  `yaml:"typical_max"`
+	ValidMin    *float64 `yaml:"valid_min"`
+	ValidMax    *float64 `yaml:"valid_max"`
+	NonNegative bool     `yaml:"non_negative"`
+	Counter     bool     `yaml:"counter"`
+}
+
+// categories are the values of the MeasureCategory constants in pkg/ccUnits
+var categories = map[string]bool{
+	"data": true, "compute": true, "ratio": true, "temperature": true, "frequency": true, "time": true,
+	"power": true, "energy": true, "network": true, "electrical": true, "count": true,
+}
+
+// baseDimensions maps the base dimensions to the index constants in pkg/ccUnits
+var baseDimensions = map[string]string{
+	"data": "dimData", "flop": "dimFlop", "time": "dimTime", "temperature": "dimTemperature",
+	"cycle": "dimCycle", "request": "dimRequest", "packet": "dimPacket", "event": "dimEvent",
+	"item": "dimItem", "energy": "dimEnergy", "current": "dimCurrent", "revolution": "dimRevolution",
+}
+
+// colorRegex matches color hints like '#1f77b4'
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// prefixDefinition is the definition of a built-in prefix with the value base^exponent
+type prefixDefinition struct {
+	Name     string   `yaml:"name"` // Name of the Go constant
+	Base     int      `yaml:"base"`
+	Exponent int      `yaml:"exponent"`
+	Long     string   `yaml:"long"`
+	Short    string   `yaml:"short"`
+	Regex    string   `yaml:"regex"`
+	Aliases  []string `yaml:"aliases"`
+}
+
+// definitions is the content of ccUnitBuiltin.yaml
+type definitions struct {
+	Measures []measureDefinition `yaml:"measures"`
+	Prefixes []prefixDefinition  `yaml:"prefixes"`
+}
+
+// readDefinitions reads and checks the definitions file
+func readDefinitions(path string) (definitions, error) {
+	var defs definitions
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return defs, err
+	}
+	if err := yaml.Unmarshal(data, &defs); err != nil {
+		return defs, fmt.Errorf("failed to decode '%s': %v", path, err)
+	}
+	return defs, defs.check()
+}
+
+// check validates the definitions. Names, IDs, short names and aliases have to be unique.
+func (defs definitions) check() error {
+	names := map[string]bool{"InvalidMeasure": true, "InvalidPrefix": true}
+	checkName := func(name string) error {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("invalid name '%s'", name)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate name '%s'", name)
+		}
+		names[name] = true
+		return nil
+	}
+	aliases := make(map[string]string)
+	checkAliases := func(name string, list []string) error {
+		for _, a := range list {
+			if other, ok := aliases[a]; ok || len(a) == 0 {
+				return fmt.Errorf("invalid alias '%s' of '%s' (used by '%s')", a, name, other)
+			}
+			aliases[a] = name
+		}
+		return nil
+	}
+
+	ids := make(map[int]string)
+	shorts := make(map[string]string)
+	for _, m := range defs.Measures {
+		if err := checkName(m.Name); err != nil {
+			return err
+		}
+		if other, ok := ids[m.ID]; ok || m.ID <= 0 {
+			return fmt.Errorf("invalid ID %d of measure '%s' (used by '%s')", m.ID, m.Name, other)
+		}
+		ids[m.ID] = m.Name
+		if other, ok := shorts[m.Short]; ok || len(m.Short) == 0 || len(m.Long) == 0 {
+			return fmt.Errorf("invalid names of measure '%s' (short name used by '%s')", m.Name, other)
+		}
+		shorts[m.Short] = m.Name
+		if err := checkAliases(m.Name, m.Aliases); err != nil {
+			return err
+		}
+		if err := m.checkDimension(); err != nil {
+			return fmt.Errorf("invalid dimension of measure '%s': %v", m.Name, err)
+		}
+		if err := m.Metadata.check(); err != nil {
+			return fmt.Errorf("invalid metadata of measure '%s': %v", m.Name, err)
+		}
+	}
+
+	values := make(map[string]string)
+	for _, p := range defs.Prefixes {
+		if err := checkName(p.Name); err != nil {
+			return err
+		}
+		if p.Base != 2 && p.Base != 10 {
+			return fmt.Errorf("invalid base %d of prefix '%s'", p.Base, p.Name)
+		}
+		v := p.value()
+		if other, ok := values[v]; ok {
+			return fmt.Errorf("prefix '%s' has the same value as '%s'", p.Name, other)
+		}
+		values[v] = p.Name
+		if err := checkAliases(p.Name, p.Aliases); err != nil {
+			return err
+		}
+	}
+	shorts = make(map[string]string)
+	for _, p := range defs.Prefixes {
+		if other, ok := shorts[p.Short]; ok {
+			return fmt.Errorf("prefix '%s' has the same short name as '%s'", p.Name, other)
+		}
+		shorts[p.Short] = p.Name
+	}
+	return nil
+}
+
+// checkDimension validates the dimension of a measure. All measures except the logarithmic
+// ones need a dimension of known base dimensions.
+func (m measureDefinition) checkDimension() error {
+	switch {
+	case m.Logarithmic && (m.Dimension != nil || m.Scale != 0):
+		return fmt.Errorf("logarithmic measure with dimension")
+	case !m.Logarithmic && m.Dimension == nil:
+		return fmt.Errorf("missing dimension")
+	case m.Scale < 0:
+		return fmt.Errorf("invalid scale %v", m.Scale)
+	}
+	for d := range m.Dimension {
+		if _, ok := baseDimensions[d]; !ok {
+			return fmt.Errorf("unknown base dimension '%s'", d)
+		}
+	}
+	return nil
+}
+
+// check validates the metadata of a measure. Description, names and category are required.
+func (md metadataDefinition) check() error {
+	switch {
+	case len(md.Description) == 0:
+		return fmt.Errorf("missing description")
+	case len(md.Singular) == 0 || len(md.Plural) == 0:
+		return fmt.Errorf("missing singular or plural name")
+	case !categories[md.Category]:
+		return fmt.Errorf("unknown category '%s'", md.Category)
+	case len(md.Color) > 0 && !colorRegex.MatchString(md.Color):
+		return fmt.Errorf("invalid color '%s'", md.Color)
+	case md.TypicalMin > md.TypicalMax:
+		return fmt.Errorf("invalid typical range [%v, %v]", md.TypicalMin, md.TypicalMax)
+	case md.ValidMin != nil && md.ValidMax != nil && *md.ValidMin > *md.ValidMax:
+		return fmt.Errorf("invalid valid range [%v, %v]", *md.ValidMin, *md.ValidMax)
+	}
+	return nil
+}
+
+// value returns the constant expression of a prefix like '1e3' or '1 << 10'
+func (p prefixDefinition) value() string {
+	switch {
+	case p.Exponent == 0:
+		return "1"
+	case p.Base == 2:
+		return fmt.Sprintf("1 << %d", p.Exponent)
+	}
+	return fmt.Sprintf("1e%d", p.Exponent)
+}
+
+// generate returns the Go source of the built-in measures and prefixes
+func generate(defs definitions) ([]byte, error) {
+	measures := append([]measureDefinition(nil), defs.Measures...)
+	sort.Slice(measures, func(i, j int) bool { return measures[i].ID < measures[j].ID })
+	q := strconv.Quote
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\npackage ccunits\n\n", header)
+
+	b.WriteString("// Built-in measures. The IDs are stable and must not be changed or reused.\nconst (\n\tInvalidMeasure Measure = 0\n")
+	for _, m := range measures {
+		fmt.Fprintf(&b, "\t%s Measure = %d\n", m.Name, m.ID)
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// Built-in prefixes\nconst (\n\tInvalidPrefix Prefix = 0\n")
+	for _, p := range defs.Prefixes {
+		fmt.Fprintf(&b, "\t%s = %s\n", p.Name, p.value())
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// MeasuresMap contains the names and regular expressions of the measures\n")
+	b.WriteString("var MeasuresMap map[Measure]MeasureData = map[Measure]MeasureData{\n")
+	for _, m := range measures {
+		fmt.Fprintf(&b, "\t%s: %s,\n", m.Name, measureData(m))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// PrefixDataMap contains the names and regular expressions of the prefixes\n")
+	b.WriteString("var PrefixDataMap map[Prefix]PrefixData = map[Prefix]PrefixData{\n")
+	for _, p := range defs.Prefixes {
+		fmt.Fprintf(&b, "\t%s: %s,\n", p.Name, prefixData(p))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Built-in prefixes with their exact value as base and exponent\n")
+	b.WriteString("var prefixTableEntries = []struct {\n\tprefix   Prefix\n\tbase     int64\n\texponent int\n}{\n")
+	for _, p := range defs.Prefixes {
+		fmt.Fprintf(&b, "\t{%s, %d, %d},\n", p.Name, p.Base, p.Exponent)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Built-in aliases of the measures and prefixes\n")
+	b.WriteString("var builtinMeasureAliases = map[string]Measure{\n")
+	for _, m := range measures {
+		for _, a := range m.Aliases {
+			fmt.Fprintf(&b, "\t%s: %s,\n", q(a), m.Name)
+		}
+	}
+	b.WriteString("}\n\nvar builtinPrefixAliases = map[string]Prefix{\n")
+	for _, p := range defs.Prefixes {
+		for _, a := range p.Aliases {
+			fmt.Fprintf(&b, "\t%s: %s,\n", q(a), p.Name)
+		}
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// builtinMeasure returns the data of a built-in measure without locking the registry\n")
+	b.WriteString("func builtinMeasure(m Measure) (MeasureData, bool) {\n\tswitch m {\n")
+	for _, m := range measures {
+		fmt.Fprintf(&b, "\tcase %s:\n\t\treturn MeasureData%s, true\n", m.Name, measureData(m))
+	}
+	b.WriteString("\t}\n\treturn MeasureData{}, false\n}\n\n")
+
+	b.WriteString("// builtinPrefix returns the data of a built-in prefix without locking the registry\n")
+	b.WriteString("func builtinPrefix(p Prefix) (PrefixData, bool) {\n\tswitch p {\n")
+	for _, p := range defs.Prefixes {
+		fmt.Fprintf(&b, "\tcase %s:\n\t\treturn PrefixData%s, true\n", p.Name, prefixData(p))
+	}
+	b.WriteString("\t}\n\treturn PrefixData{}, false\n}\n\n")
+
+	b.WriteString("// builtinMetadata returns the metadata of a built-in measure without locking the registry\n")
+	b.WriteString("func builtinMetadata(m Measure) (MeasureMetadata, bool) {\n\tswitch m {\n")
+	for _, m := range measures {
+		fmt.Fprintf(&b, "\tcase %s:\n\t\treturn MeasureMetadata%s, true\n", m.Name, metadata(m.Metadata))
+	}
+	b.WriteString("\t}\n\treturn MeasureMetadata{}, false\n}\n\n")
+
+	b.WriteString("// builtinDimension returns the dimension and scale of a built-in measure. Logarithmic\n")
+	b.WriteString("// measures have no dimension.\n")
+	b.WriteString("func builtinDimension(m Measure) (dimension, float64, bool) {\n\tswitch m {\n")
+	for _, m := range measures {
+		if m.Logarithmic {
+			continue
+		}
+		fmt.Fprintf(&b, "\tcase %s:\n\t\treturn %s, true\n", m.Name, dimensionLiteral(m))
+	}
+	b.WriteString("\t}\n\treturn dimension{}, 0, false\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// measureData returns the composite literal of the data of a measure
+func measureData(m measureDefinition) string {
+	fields := []string{"Long: " + strconv.Quote(m.Long), "Short: " + strconv.Quote(m.Short), "Regex: " + strconv.Quote(m.Regex)}
+	if m.NonDividable {
+		fields = append(fields, "NonDividable: true")
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// metadata returns the composite literal of the metadata of a measure
+func metadata(md metadataDefinition) string {
+	fields := []string{
+		"Description: " + strconv.Quote(md.Description),
+		"Singular: " + strconv.Quote(md.Singular),
+		"Plural: " + strconv.Quote(md.Plural),
+		"Category: " + strconv.Quote(md.Category),
+		"Color: " + strconv.Quote(md.Color),
+		"TypicalMin: " + formatFloat(md.TypicalMin),
+		"TypicalMax: " + formatFloat(md.TypicalMax),
+	}
+	if md.ValidMin != nil {
+		fields = append(fields, "ValidMin: validBound("+formatFloat(*md.ValidMin)+")")
+	}
+	if md.ValidMax != nil {
+		fields = append(fields, "ValidMax: validBound("+formatFloat(*md.ValidMax)+")")
+	}
+	if md.NonNegative {
+		fields = append(fields, "NonNegative: true")
+	}
+	if md.Counter {
+		fields = append(fields, "Counter: true")
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// dimensionLiteral returns the dimension literal and scale of a measure like
+// 'dimension{dimEnergy: 1, dimTime: -1}, 1'
+func dimensionLiteral(m measureDefinition) string {
+	names := make([]string, 0, len(m.Dimension))
+	for d, e := range m.Dimension {
+		if e != 0 {
+			names = append(names, d)
+		}
+	}
+	sort.Strings(names)
+	fields := make([]string, 0, len(names))
+	for _, d := range names {
+		fields = append(fields, fmt.Sprintf("%s: %d", baseDimensions[d], m.Dimension[d]))
+	}
+	scale := m.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	return "dimension{" + strings.Join(fields, ", ") + "}, " + formatFloat(scale)
+}
+
+// formatFloat returns the literal of a float value like '0' or '1e+12'
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// prefixData returns the composite literal of the data of a prefix
+func prefixData(p prefixDefinition) string {
+	return fmt.Sprintf("{Long: %s, Short: %s, Regex: %s}", strconv.Quote(p.Long), strconv.Quote(p.Short), strconv.Quote(p.Regex))
+}
+
+func main() {
+	in := flag.String("in", "ccUnitBuiltin.yaml", "Path of the definitions file")
+	out := flag.String("out", "ccUnitBuiltin.go", "Path of the Go output file")
+	flag.Parse()
+
+	defs, err := readDefinitions(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read definitions: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := generate(defs)
+	if err == nil {
+		err = os.WriteFile(*out, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write '%s': %v\n", *out, err)
+		os.Exit(1)
+	}
+}
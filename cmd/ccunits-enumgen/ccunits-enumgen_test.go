@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestGeneratedUpToDate fails if ccUnitBuiltin.yaml changed without running 'go generate' in pkg/ccUnits
+func TestGeneratedUpToDate(t *testing.T) {
+	defs, err := readDefinitions("../../pkg/ccUnits/ccUnitBuiltin.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read definitions: %v", err)
+	}
+	expected, err := generate(defs)
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	current, err := os.ReadFile("../../pkg/ccUnits/ccUnitBuiltin.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !bytes.Equal(current, expected) {
+		t.Errorf("'ccUnitBuiltin.go' is outdated, run 'go generate' in pkg/ccUnits")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	md := metadataDefinition{Description: "Amount of data", Singular: "Byte", Plural: "Bytes", Category: "data"}
+	dim := map[string]int{"data": 1}
+	valid := measureDefinition{Name: "Bytes", ID: 1, Long: "byte", Short: "B", Dimension: dim, Metadata: md}
+	for name, defs := range map[string]definitions{
+		"duplicate ID":    {Measures: []measureDefinition{valid, {Name: "Bits", ID: 1, Long: "bit", Short: "bit", Metadata: md}}},
+		"reserved ID":     {Measures: []measureDefinition{{Name: "Bits", ID: 0, Long: "bit", Short: "bit"}}},
+		"duplicate short": {Measures: []measureDefinition{valid, {Name: "Bits", ID: 2, Long: "bit", Short: "B", Metadata: md}}},
+		"invalid name":    {Measures: []measureDefinition{{Name: "bits", ID: 2, Long: "bit", Short: "bit"}}},
+		"duplicate value": {Prefixes: []prefixDefinition{{Name: "Kilo", Base: 10, Exponent: 3}, {Name: "Thousand", Base: 10, Exponent: 3, Short: "k"}}},
+		"invalid base":    {Prefixes: []prefixDefinition{{Name: "Dozen", Base: 12, Exponent: 1}}},
+		"no metadata":     {Measures: []measureDefinition{{Name: "Bits", ID: 2, Long: "bit", Short: "bit", Dimension: dim}}},
+		"invalid color":   {Measures: []measureDefinition{{Name: "Bits", ID: 2, Long: "bit", Short: "bit", Dimension: dim, Metadata: metadataDefinition{Description: "Bits", Singular: "Bit", Plural: "Bits", Category: "data", Color: "blue"}}}},
+		"no dimension":    {Measures: []measureDefinition{{Name: "Bits", ID: 2, Long: "bit", Short: "bit", Metadata: md}}},
+		"unknown base":    {Measures: []measureDefinition{{Name: "Bits", ID: 2, Long: "bit", Short: "bit", Dimension: map[string]int{"bit": 1}, Metadata: md}}},
+	} {
+		if err := defs.check(); err == nil {
+			t.Errorf("Expected error for %s", name)
+		}
+	}
+	if err := (definitions{Measures: []measureDefinition{valid}}).check(); err != nil {
+		t.Errorf("Unexpected error for valid definitions: %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+// ccunits-gen writes the prefix and measure tables of the ccUnits package as TypeScript
+// and JSON definitions, so consumers in other languages stay in sync with the Go source.
+// It also generates the switch-based lookup of the known measure and prefix spellings used
+// by the parser. It is called by 'go generate' in pkg/ccUnits.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+)
+
+const header = "Code generated by ccunits-gen. DO NOT EDIT."
+
+// prefixDefinition is the exported definition of a prefix
+type prefixDefinition struct {
+	Long   string  `json:"long"`
+	Short  string  `json:"short"`
+	Factor float64 `json:"factor"`
+	Regex  string  `json:"regex,omitempty"`
+}
+
+// measureDefinition is the exported definition of a measure
+type measureDefinition struct {
+	ID           int      `json:"id"`
+	Long         string   `json:"long"`
+	Short        string   `json:"short"`
+	Regex        string   `json:"regex,omitempty"`
+	NonDividable bool     `json:"nonDividable"`
+	Description  string   `json:"description,omitempty"`
+	Singular     string   `json:"singular,omitempty"`
+	Plural       string   `json:"plural,omitempty"`
+	Category     string   `json:"category,omitempty"`
+	Color        string   `json:"color,omitempty"`
+	TypicalMin   float64  `json:"typicalMin"`
+	TypicalMax   float64  `json:"typicalMax"`
+	ValidMin     *float64 `json:"validMin,omitempty"`
+	ValidMax     *float64 `json:"validMax,omitempty"`
+	NonNegative  bool     `json:"nonNegative"`
+	Counter      bool     `json:"counter"`
+}
+
+// tables contains all exported definitions
+type tables struct {
+	Prefixes []prefixDefinition  `json:"prefixes"`
+	Measures []measureDefinition `json:"measures"`
+}
+
+// collect reads the tables of the ccUnits package sorted by factor and measure ID
+func collect() tables {
+	t := tables{
+		Prefixes: make([]prefixDefinition, 0, len(units.PrefixDataMap)),
+		Measures: make([]measureDefinition, 0, len(units.MeasuresMap)),
+	}
+	for p, data := range units.PrefixDataMap {
+		t.Prefixes = append(t.Prefixes, prefixDefinition{
+			Long:   data.Long,
+			Short:  data.Short,
+			Factor: float64(p),
+			Regex:  data.Regex,
+		})
+	}
+	sort.Slice(t.Prefixes, func(i, j int) bool { return t.Prefixes[i].Factor < t.Prefixes[j].Factor })
+	for m, data := range units.MeasuresMap {
+		md, _ := units.Metadata(m)
+		t.Measures = append(t.Measures, measureDefinition{
+			ID:           int(m),
+			Long:         data.Long,
+			Short:        data.Short,
+			Regex:        data.Regex,
+			NonDividable: data.NonDividable,
+			Description:  md.Description,
+			Singular:     md.Singular,
+			Plural:       md.Plural,
+			Category:     string(md.Category),
+			Color:        md.Color,
+			TypicalMin:   md.TypicalMin,
+			TypicalMax:   md.TypicalMax,
+			ValidMin:     md.ValidMin,
+			ValidMax:     md.ValidMax,
+			NonNegative:  md.NonNegative,
+			Counter:      md.Counter,
+		})
+	}
+	sort.Slice(t.Measures, func(i, j int) bool { return t.Measures[i].ID < t.Measures[j].ID })
+	return t
+}
+
+// generateJSON returns the tables as JSON document
+func generateJSON(t tables) ([]byte, error) {
+	out, err := json.MarshalIndent(struct {
+		Comment string `json:"_comment"`
+		tables
+	}{header, t}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// generateTypeScript returns the tables as TypeScript module
+func generateTypeScript(t tables) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\n", header)
+	b.WriteString("export interface PrefixDefinition {\n  long: string;\n  short: string;\n  factor: number;\n  regex?: string;\n}\n\n")
+	b.WriteString("export interface MeasureDefinition {\n  id: number;\n  long: string;\n  short: string;\n  regex?: string;\n  nonDividable: boolean;\n  description?: string;\n  singular?: string;\n  plural?: string;\n  category?: string;\n  color?: string;\n  typicalMin: number;\n  typicalMax: number;\n  validMin?: number;\n  validMax?: number;\n  nonNegative: boolean;\n  counter: boolean;\n}\n\n")
+	for _, list := range []struct {
+		name  string
+		typ   string
+		value interface{}
+	}{
+		{"prefixes", "PrefixDefinition", t.Prefixes},
+		{"measures", "MeasureDefinition", t.Measures},
+	} {
+		data, err := json.MarshalIndent(list.value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "export const %s: %s[] = %s;\n\n", list.name, list.typ, data)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// isWord checks whether a name is a word like 'Seconds' or 'DecibelMilliwatt' and no symbol
+// like '%', 'degC', 'RPM' or 'cyc' whose case and plural forms are no spellings of the unit
+func isWord(name string) bool {
+	if len(name) <= 3 {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return unicode.IsLower(rune(name[len(name)-1]))
+}
+
+// spellings returns the candidate spellings of a name. For words, these are the singular in
+// the original, lower, upper and title case, each with and without plural 's' (except for
+// words like 'Hertz' without plural 's'). Symbols are only spelled as they are.
+func spellings(name string) []string {
+	if !isWord(name) {
+		return []string{name}
+	}
+	singular := strings.TrimSuffix(strings.TrimSuffix(name, "s"), "S")
+	plural := !strings.ContainsAny(singular[len(singular)-1:], "sSxXzZ")
+	out := make([]string, 0)
+	lower := strings.ToLower(singular)
+	for _, s := range []string{singular, lower, strings.ToUpper(lower[:1]) + lower[1:]} {
+		out = append(out, s)
+		if plural {
+			out = append(out, s+"s")
+		}
+	}
+	upper := strings.ToUpper(singular)
+	if plural {
+		return append(out, upper, upper+"S")
+	}
+	return append(out, upper)
+}
+
+// prefixCandidates returns the prefix strings produced by splitting a unit string with
+// PrefixUnitSplitRegexStr
+func prefixCandidates() []string {
+	out := []string{""}
+	for _, c := range "kKmMgGtTpPeEzZyYunµμ" {
+		out = append(out, string(c), string(c)+"i")
+	}
+	return append(out, "i")
+}
+
+// matchFirst returns the index of the first regular expression matching a string or -1
+func matchFirst(regexes []*regexp.Regexp, s string) int {
+	for i, r := range regexes {
+		if r != nil && r.MatchString(s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookupCase is a case of the generated switch statements
+type lookupCase struct {
+	comment string
+	value   string
+	keys    []string
+}
+
+// writeSwitch writes a function with a switch statement over the candidate spellings
+func writeSwitch(b *bytes.Buffer, doc string, signature string, invalid string, cases []lookupCase) {
+	fmt.Fprintf(b, "%s\nfunc %s {\n\tswitch s {\n", doc, signature)
+	for _, c := range cases {
+		sort.Strings(c.keys)
+		quoted := make([]string, 0, len(c.keys))
+		for _, k := range c.keys {
+			quoted = append(quoted, strconv.Quote(k))
+		}
+		fmt.Fprintf(b, "\tcase %s:\n\t\treturn %s, true // %s\n", strings.Join(quoted, ", "), c.value, c.comment)
+	}
+	fmt.Fprintf(b, "\t}\n\treturn %s, false\n}\n", invalid)
+}
+
+// generateLookup returns the Go source of the lookup functions. The candidate spellings are
+// matched with the regular expressions in the same order as the parser (first match by
+// measure ID or prefix factor), so the lookup returns the same results as the regular
+// expressions for the built-in measures and prefixes.
+func generateLookup(t tables) ([]byte, error) {
+	measureRegexes := make([]*regexp.Regexp, len(t.Measures))
+	measureNames := make([]string, 0)
+	for i, m := range t.Measures {
+		if len(m.Regex) > 0 {
+			measureRegexes[i] = regexp.MustCompile(m.Regex)
+		}
+		measureNames = append(measureNames, m.Short, m.Long)
+	}
+	measureCases := make([]lookupCase, len(t.Measures))
+	seen := make(map[string]bool)
+	for _, name := range measureNames {
+		for _, s := range spellings(name) {
+			if seen[s] || len(s) == 0 {
+				continue
+			}
+			seen[s] = true
+			if i := matchFirst(measureRegexes, s); i >= 0 {
+				measureCases[i].keys = append(measureCases[i].keys, s)
+			}
+		}
+	}
+
+	prefixRegexes := make([]*regexp.Regexp, len(t.Prefixes))
+	for i, p := range t.Prefixes {
+		if len(p.Regex) > 0 {
+			prefixRegexes[i] = regexp.MustCompile(p.Regex)
+		}
+	}
+	prefixCases := make([]lookupCase, len(t.Prefixes))
+	for _, s := range prefixCandidates() {
+		if i := matchFirst(prefixRegexes, s); i >= 0 {
+			prefixCases[i].keys = append(prefixCases[i].keys, s)
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\npackage ccunits\n\n", header)
+	cases := make([]lookupCase, 0)
+	for i, m := range t.Measures {
+		if len(measureCases[i].keys) > 0 {
+			cases = append(cases, lookupCase{m.Long, fmt.Sprintf("Measure(%d)", m.ID), measureCases[i].keys})
+		}
+	}
+	writeSwitch(&b, "// lookupMeasure returns the built-in measure of a known spelling", "lookupMeasure(s string) (Measure, bool)", "InvalidMeasure", cases)
+	b.WriteString("\n")
+	cases = make([]lookupCase, 0)
+	for i, p := range t.Prefixes {
+		if len(prefixCases[i].keys) > 0 {
+			name := p.Long
+			if len(name) == 0 {
+				name = "Base"
+			}
+			cases = append(cases, lookupCase{name, fmt.Sprintf("Prefix(%s)", strconv.FormatFloat(p.Factor, 'g', -1, 64)), prefixCases[i].keys})
+		}
+	}
+	writeSwitch(&b, "// lookupPrefix returns the built-in prefix of a known spelling", "lookupPrefix(s string) (Prefix, bool)", "InvalidPrefix", cases)
+	return format.Source(b.Bytes())
+}
+
+func main() {
+	tsFile := flag.String("ts", "", "Path of the TypeScript output file")
+	jsonFile := flag.String("json", "", "Path of the JSON output file")
+	goFile := flag.String("go", "", "Path of the Go output file with the lookup functions")
+	flag.Parse()
+	if len(*tsFile) == 0 && len(*jsonFile) == 0 && len(*goFile) == 0 {
+		fmt.Fprintln(os.Stderr, "At least one of -ts, -json and -go is required")
+		os.Exit(2)
+	}
+
+	t := collect()
+	for _, out := range []struct {
+		path     string
+		generate func(tables) ([]byte, error)
+	}{
+		{*tsFile, generateTypeScript},
+		{*jsonFile, generateJSON},
+		{*goFile, generateLookup},
+	} {
+		if len(out.path) == 0 {
+			continue
+		}
+		data, err := out.generate(t)
+		if err == nil {
+			err = os.WriteFile(out.path, data, 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write '%s': %v\n", out.path, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedUpToDate fails if the tables changed without running 'go generate' in pkg/ccUnits
+func TestGeneratedUpToDate(t *testing.T) {
+	defs := collect()
+	for _, f := range []struct {
+		path     string
+		generate func(tables) ([]byte, error)
+	}{
+		{"../../pkg/ccUnits/generated/ccUnits.ts", generateTypeScript},
+		{"../../pkg/ccUnits/generated/ccUnits.json", generateJSON},
+		{"../../pkg/ccUnits/ccUnitLookup.go", generateLookup},
+	} {
+		expected, err := f.generate(defs)
+		if err != nil {
+			t.Fatalf("Failed to generate '%s': %v", f.path, err)
+		}
+		current, err := os.ReadFile(f.path)
+		if err != nil {
+			t.Fatalf("Failed to read '%s': %v", f.path, err)
+		}
+		if !bytes.Equal(current, expected) {
+			t.Errorf("'%s' is outdated, run 'go generate' in pkg/ccUnits", f.path)
+		}
+	}
+}
+
+func TestSpellings(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Seconds":          "Second Seconds second seconds Second Seconds SECOND SECONDS",
+		"Hertz":            "Hertz hertz Hertz HERTZ",
+		"DecibelMilliwatt": "DecibelMilliwatt DecibelMilliwatts decibelmilliwatt decibelmilliwatts Decibelmilliwatt Decibelmilliwatts DECIBELMILLIWATT DECIBELMILLIWATTS",
+		"%":                "%",
+		"degC":             "degC",
+		"DegreeC":          "DegreeC",
+		"RPM":              "RPM",
+		"cyc":              "cyc",
+		"A":                "A",
+	} {
+		if s := strings.Join(spellings(name), " "); s != expected {
+			t.Errorf("Expected spellings '%s' for '%s' but got '%s'", expected, name, s)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build js && wasm
+
+// ccunits-wasm exposes the ccUnits package to JavaScript as global object 'ccUnits'
+package main
+
+import (
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+)
+
+func main() {
+	units.RegisterJavaScript("ccUnits")
+	// Keep the module running, so the functions stay callable
+	select {}
+}
@@ -0,0 +1,201 @@
+// ccunits converts values between units and explores the units supported by the ccUnits package
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+)
+
+// Exit codes
+const (
+	exitOK    = 0 // Command succeeded
+	exitError = 1 // Command failed like for invalid values or units
+	exitUsage = 2 // Unknown command, missing or invalid arguments
+)
+
+const usage = `Usage: ccunits <command> [arguments]
+
+Commands:
+  convert <value> <from> <to>   Convert a value from one unit to another (e.g. convert 1234 MiB/s GB/s)
+  humanize [-binary] <value> <unit>
+                                Print a value with the prefix that fits best (e.g. humanize 123456789 B)
+  explain <unit>                Show how a unit string is parsed
+  list                          List all supported prefixes and measures
+`
+
+// usageError is returned for missing, surplus or unknown arguments of a command
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// parseUnit parses a unit string and fails for invalid units
+func parseUnit(unitStr string) (units.Unit, error) {
+	u := units.NewUnit(unitStr)
+	if !u.Valid() {
+		return nil, fmt.Errorf("invalid unit '%s'", unitStr)
+	}
+	return u, nil
+}
+
+// parseValue parses the value argument
+func parseValue(valueStr string) (float64, error) {
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", valueStr)
+	}
+	return value, nil
+}
+
+func convert(args []string, w io.Writer) error {
+	if len(args) != 3 {
+		return usageError("convert requires <value> <from> <to>")
+	}
+	value, err := parseValue(args[0])
+	if err != nil {
+		return err
+	}
+	in, err := parseUnit(args[1])
+	if err != nil {
+		return err
+	}
+	out, err := parseUnit(args[2])
+	if err != nil {
+		return err
+	}
+	q, err := units.Quantity{Value: value, Unit: in}.ConvertTo(out)
+	if err != nil {
+		return fmt.Errorf("cannot convert '%s' to '%s': %v", in.Short(), out.Short(), err)
+	}
+	fmt.Fprintln(w, q.String())
+	return nil
+}
+
+func humanize(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("humanize", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	binary := fs.Bool("binary", false, "Use binary prefixes (Ki, Mi, ...)")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if fs.NArg() != 2 {
+		return usageError("humanize requires <value> <unit>")
+	}
+	value, err := parseValue(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	in, err := parseUnit(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	q := units.Quantity{Value: value, Unit: in}
+	fmt.Fprintln(w, q.Humanize(*binary).String())
+	return nil
+}
+
+func explain(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return usageError("explain requires <unit>")
+	}
+	u := units.NewUnit(args[0])
+	fmt.Fprintf(w, "Input:       %s\n", args[0])
+	fmt.Fprintf(w, "Valid:       %v\n", u.Valid())
+	if u.Valid() {
+		p := u.GetPrefix()
+		m := u.GetMeasure()
+		fmt.Fprintf(w, "Short:       %s\n", u.Short())
+		fmt.Fprintf(w, "Long:        %s\n", u.String())
+		fmt.Fprintf(w, "Prefix:      %s (%s, factor %g)\n", p.Prefix(), p.String(), float64(p))
+		fmt.Fprintf(w, "Measure:     %s (%s)\n", m.Short(), m.String())
+		if d := u.GetUnitDenominator(); d != units.InvalidMeasure {
+			fmt.Fprintf(w, "Denominator: %s (%s)\n", d.Short(), d.String())
+		}
+	}
+	for _, issue := range units.Lint(args) {
+		fmt.Fprintf(w, "Issue:       %s: %s\n", issue.Kind.String(), issue.Message)
+		if len(issue.Suggestion) > 0 {
+			fmt.Fprintf(w, "Suggestion:  %s\n", issue.Suggestion)
+		}
+	}
+	return nil
+}
+
+func list(args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return usageError("list takes no arguments")
+	}
+	prefixes := make([]units.Prefix, 0, len(units.PrefixDataMap))
+	for p := range units.PrefixDataMap {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+	fmt.Fprintln(w, "Prefixes:")
+	for _, p := range prefixes {
+		data := units.PrefixDataMap[p]
+		short := data.Short
+		if len(short) == 0 {
+			short = "-"
+		}
+		fmt.Fprintf(w, "  %-3s %-6s %g\n", short, data.Long, float64(p))
+	}
+
+	measures := make([]units.Measure, 0, len(units.MeasuresMap))
+	for m := range units.MeasuresMap {
+		measures = append(measures, m)
+	}
+	sort.Slice(measures, func(i, j int) bool { return measures[i] < measures[j] })
+	fmt.Fprintln(w, "Measures:")
+	for _, m := range measures {
+		data := units.MeasuresMap[m]
+		fmt.Fprintf(w, "  %-9s %s\n", data.Short, data.Long)
+	}
+	return nil
+}
+
+// run executes the command in args (without the program name) and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	var err error
+	switch args[0] {
+	case "convert":
+		err = convert(args[1:], stdout)
+	case "humanize":
+		err = humanize(args[1:], stdout)
+	case "explain":
+		err = explain(args[1:], stdout)
+	case "list":
+		err = list(args[1:], stdout)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "Unknown command '%s'\n\n%s", args[0], usage)
+		return exitUsage
+	}
+	var ue usageError
+	if errors.As(err, &ue) {
+		fmt.Fprintf(stderr, "Error: %v\n\n%s", err, usage)
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, c := range []struct {
+		args   []string
+		code   int
+		stdout string // Expected substring of the output
+		stderr string // Expected substring of the error output
+	}{
+		{[]string{"convert", "1500", "MB/s", "GB/s"}, exitOK, "1.5 GB/s", ""},
+		{[]string{"convert", "45", "degC", "degF"}, exitOK, "113 degF", ""},
+		{[]string{"humanize", "123456789", "B"}, exitOK, "123.456789 MB", ""},
+		{[]string{"humanize", "-binary", "1048576", "B"}, exitOK, "1 MiB", ""},
+		{[]string{"explain", "kByte/s"}, exitOK, "Short:       KB/s", ""},
+		{[]string{"explain", "xyz"}, exitOK, "Valid:       false", ""},
+		{[]string{"list"}, exitOK, "Measures:", ""},
+		{[]string{"help"}, exitOK, "Usage: ccunits", ""},
+		{[]string{"--help"}, exitOK, "Usage: ccunits", ""},
+		// Invalid values and units
+		{[]string{"convert", "abc", "MB", "GB"}, exitError, "", "invalid value 'abc'"},
+		{[]string{"convert", "1", "xyz", "GB"}, exitError, "", "invalid unit 'xyz'"},
+		{[]string{"convert", "1", "MB", "W"}, exitError, "", "cannot convert 'MB' to 'W'"},
+		{[]string{"humanize", "1", "xyz"}, exitError, "", "invalid unit 'xyz'"},
+		// Usage errors
+		{nil, exitUsage, "", "Usage: ccunits"},
+		{[]string{"unknown"}, exitUsage, "", "Unknown command 'unknown'"},
+		{[]string{"convert", "1", "MB"}, exitUsage, "", "convert requires"},
+		{[]string{"humanize", "-x", "1", "B"}, exitUsage, "", "flag provided but not defined: -x"},
+		{[]string{"humanize", "1"}, exitUsage, "", "humanize requires"},
+		{[]string{"explain"}, exitUsage, "", "explain requires"},
+		{[]string{"list", "all"}, exitUsage, "", "list takes no arguments"},
+	} {
+		var stdout, stderr bytes.Buffer
+		code := run(c.args, &stdout, &stderr)
+		if code != c.code {
+			t.Errorf("Expected exit code %d for %v but got %d: %s", c.code, c.args, code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), c.stdout) {
+			t.Errorf("Expected output '%s' for %v but got '%s'", c.stdout, c.args, stdout.String())
+		}
+		if !strings.Contains(stderr.String(), c.stderr) {
+			t.Errorf("Expected error output '%s' for %v but got '%s'", c.stderr, c.args, stderr.String())
+		}
+		if c.code == exitOK && stderr.Len() > 0 {
+			t.Errorf("Unexpected error output for %v: %s", c.args, stderr.String())
+		}
+	}
+}
@@ -34,7 +34,7 @@ func (m *CpustatCollector) Init(config json.RawMessage) error {
 	m.name = "CpustatCollector"
 	m.setup()
 	m.parallel = true
-	m.meta = map[string]string{"source": m.name, "group": "CPU", "unit": "Percent"}
+	m.meta = map[string]string{"source": m.name, "group": "CPU", "unit": "%"}
 	m.nodetags = map[string]string{"type": "node"}
 	if len(config) > 0 {
 		err := json.Unmarshal(config, &m.config)
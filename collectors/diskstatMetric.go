@@ -94,13 +94,13 @@ func (m *DiskstatCollector) Read(interval time.Duration, output chan lp.CCMetric
 		total := (stat.Blocks * uint64(stat.Bsize)) / uint64(1000000000)
 		y, err := lp.New("disk_total", tags, m.meta, map[string]interface{}{"value": total}, time.Now())
 		if err == nil {
-			y.AddMeta("unit", "GBytes")
+			y.AddMeta("unit", "GB")
 			output <- y
 		}
 		free := (stat.Bfree * uint64(stat.Bsize)) / uint64(1000000000)
 		y, err = lp.New("disk_free", tags, m.meta, map[string]interface{}{"value": free}, time.Now())
 		if err == nil {
-			y.AddMeta("unit", "GBytes")
+			y.AddMeta("unit", "GB")
 			output <- y
 		}
 		if total > 0 {
@@ -112,7 +112,7 @@ func (m *DiskstatCollector) Read(interval time.Duration, output chan lp.CCMetric
 	}
 	y, err := lp.New("part_max_used", map[string]string{"type": "node"}, m.meta, map[string]interface{}{"value": int(part_max_used)}, time.Now())
 	if err == nil {
-		y.AddMeta("unit", "percent")
+		y.AddMeta("unit", "%")
 		output <- y
 	}
 }
@@ -227,7 +227,7 @@ func (m *GpfsCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 				},
 				timestamp,
 			); err == nil {
-			y.AddMeta("unit", "bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		if m.config.SendBandwidths {
@@ -243,7 +243,7 @@ func (m *GpfsCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 						},
 						timestamp,
 					); err == nil {
-					y.AddMeta("unit", "bytes/sec")
+					y.AddMeta("unit", "B/s")
 					output <- y
 				}
 			}
@@ -267,7 +267,7 @@ func (m *GpfsCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 				},
 				timestamp,
 			); err == nil {
-			y.AddMeta("unit", "bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		if m.config.SendBandwidths {
@@ -283,7 +283,7 @@ func (m *GpfsCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 						},
 						timestamp,
 					); err == nil {
-					y.AddMeta("unit", "bytes/sec")
+					y.AddMeta("unit", "B/s")
 					output <- y
 				}
 			}
@@ -380,7 +380,7 @@ func (m *GpfsCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 					},
 					timestamp,
 				); err == nil {
-				y.AddMeta("unit", "bytes")
+				y.AddMeta("unit", "B")
 				output <- y
 			}
 			iops := numReads + numWrites
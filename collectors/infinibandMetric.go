@@ -6,6 +6,7 @@ import (
 
 	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
 	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
 	"golang.org/x/sys/unix"
 
 	"encoding/json"
@@ -21,7 +22,6 @@ type InfinibandCollectorMetric struct {
 	name             string
 	path             string
 	unit             string
-	scale            int64
 	addToIBTotal     bool
 	addToIBTotalPkgs bool
 	currentState     int64
@@ -121,16 +121,14 @@ func (m *InfinibandCollector) Init(config json.RawMessage) error {
 			{
 				name:         "ib_recv",
 				path:         filepath.Join(countersDir, "port_rcv_data"),
-				unit:         "bytes",
-				scale:        4,
+				unit:         "B",
 				addToIBTotal: true,
 				lastState:    -1,
 			},
 			{
 				name:         "ib_xmit",
 				path:         filepath.Join(countersDir, "port_xmit_data"),
-				unit:         "bytes",
-				scale:        4,
+				unit:         "B",
 				addToIBTotal: true,
 				lastState:    -1,
 			},
@@ -138,7 +136,6 @@ func (m *InfinibandCollector) Init(config json.RawMessage) error {
 				name:             "ib_recv_pkts",
 				path:             filepath.Join(countersDir, "port_rcv_packets"),
 				unit:             "packets",
-				scale:            1,
 				addToIBTotalPkgs: true,
 				lastState:        -1,
 			},
@@ -146,7 +143,6 @@ func (m *InfinibandCollector) Init(config json.RawMessage) error {
 				name:             "ib_xmit_pkts",
 				path:             filepath.Join(countersDir, "port_xmit_packets"),
 				unit:             "packets",
-				scale:            1,
 				addToIBTotalPkgs: true,
 				lastState:        -1,
 			},
@@ -221,8 +217,13 @@ func (m *InfinibandCollector) Read(interval time.Duration, output chan lp.CCMetr
 					fmt.Sprintf("Read(): Failed to convert Infininiband metrice %s='%s' to int64: %v", counterDef.name, data, err))
 				continue
 			}
-			// Scale raw value
-			v *= counterDef.scale
+			// Convert raw value (data counters count 4-byte words)
+			c, _, err := units.ConvertInfinibandCounter(filepath.Base(counterDef.path), uint64(v))
+			if err != nil {
+				cclog.ComponentError(m.name, fmt.Sprintf("Read(): %v", err))
+				continue
+			}
+			v = int64(c)
 
 			// Save current state
 			counterDef.currentState = v
@@ -286,7 +287,7 @@ func (m *InfinibandCollector) Read(interval time.Duration, output chan lp.CCMetr
 						"value": ib_total,
 					},
 					now); err == nil {
-				y.AddMeta("unit", "bytes")
+				y.AddMeta("unit", "B")
 				output <- y
 			}
 
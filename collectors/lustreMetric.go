@@ -127,14 +127,14 @@ var LustreAbsMetrics = []LustreMetricDefinition{
 		name:       "lustre_read_bytes",
 		lineprefix: "read_bytes",
 		lineoffset: 6,
-		unit:       "bytes",
+		unit:       "B",
 		calc:       "none",
 	},
 	{
 		name:       "lustre_write_bytes",
 		lineprefix: "write_bytes",
 		lineoffset: 6,
-		unit:       "bytes",
+		unit:       "B",
 		calc:       "none",
 	},
 	{
@@ -200,14 +200,14 @@ var LustreDiffMetrics = []LustreMetricDefinition{
 		name:       "lustre_read_bytes_diff",
 		lineprefix: "read_bytes",
 		lineoffset: 6,
-		unit:       "bytes",
+		unit:       "B",
 		calc:       "difference",
 	},
 	{
 		name:       "lustre_write_bytes_diff",
 		lineprefix: "write_bytes",
 		lineoffset: 6,
-		unit:       "bytes",
+		unit:       "B",
 		calc:       "difference",
 	},
 	{
@@ -273,14 +273,14 @@ var LustreDeriveMetrics = []LustreMetricDefinition{
 		name:       "lustre_read_bw",
 		lineprefix: "read_bytes",
 		lineoffset: 6,
-		unit:       "bytes/sec",
+		unit:       "B/s",
 		calc:       "derivative",
 	},
 	{
 		name:       "lustre_write_bw",
 		lineprefix: "write_bytes",
 		lineoffset: 6,
-		unit:       "bytes/sec",
+		unit:       "B/s",
 		calc:       "derivative",
 	},
 }
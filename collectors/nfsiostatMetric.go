@@ -95,7 +95,7 @@ func (m *NfsIOStatCollector) Init(config json.RawMessage) error {
 	m.name = "NfsIOStatCollector"
 	m.setup()
 	m.parallel = true
-	m.meta = map[string]string{"source": m.name, "group": "NFS", "unit": "bytes"}
+	m.meta = map[string]string{"source": m.name, "group": "NFS", "unit": "B"}
 	m.tags = map[string]string{"type": "node"}
 	m.config.UseServerAddressAsSType = false
 	if len(config) > 0 {
@@ -10,6 +10,7 @@ import (
 
 	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
 	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 )
 
@@ -206,6 +207,21 @@ func (m *NvidiaCollector) Init(config json.RawMessage) error {
 	return nil
 }
 
+// newNvmlMetric creates a metric out of a value returned by the NVML query function and
+// converts it to the unit reported by the collector (see units.NvmlFieldUnits)
+func newNvmlMetric(name string, device NvidiaCollectorDevice, function string, value float64) (lp.CCMetric, error) {
+	v, u, err := units.NormalizeGpuValue(units.NVML, function, value)
+	if err != nil {
+		return nil, err
+	}
+	y, err := lp.New(name, device.tags, device.meta, map[string]interface{}{"value": v}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	y.AddMeta("unit", u.Short())
+	return y, nil
+}
+
 func readMemoryInfo(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 	if !device.excludeMetrics["nv_fb_mem_total"] || !device.excludeMetrics["nv_fb_mem_used"] || !device.excludeMetrics["nv_fb_mem_reserved"] {
 		var total uint64
@@ -221,28 +237,22 @@ func readMemoryInfo(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 		used = meminfo.Used
 
 		if !device.excludeMetrics["nv_fb_mem_total"] {
-			t := float64(total) / (1024 * 1024)
-			y, err := lp.New("nv_fb_mem_total", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
+			y, err := newNvmlMetric("nv_fb_mem_total", device, "nvmlDeviceGetMemoryInfo", float64(total))
 			if err == nil {
-				y.AddMeta("unit", "MByte")
 				output <- y
 			}
 		}
 
 		if !device.excludeMetrics["nv_fb_mem_used"] {
-			f := float64(used) / (1024 * 1024)
-			y, err := lp.New("nv_fb_mem_used", device.tags, device.meta, map[string]interface{}{"value": f}, time.Now())
+			y, err := newNvmlMetric("nv_fb_mem_used", device, "nvmlDeviceGetMemoryInfo", float64(used))
 			if err == nil {
-				y.AddMeta("unit", "MByte")
 				output <- y
 			}
 		}
 
 		if v2 && !device.excludeMetrics["nv_fb_mem_reserved"] {
-			r := float64(reserved) / (1024 * 1024)
-			y, err := lp.New("nv_fb_mem_reserved", device.tags, device.meta, map[string]interface{}{"value": r}, time.Now())
+			y, err := newNvmlMetric("nv_fb_mem_reserved", device, "nvmlDeviceGetMemoryInfo", float64(reserved))
 			if err == nil {
-				y.AddMeta("unit", "MByte")
 				output <- y
 			}
 		}
@@ -258,18 +268,14 @@ func readBarMemoryInfo(device NvidiaCollectorDevice, output chan lp.CCMetric) er
 			return err
 		}
 		if !device.excludeMetrics["nv_bar1_mem_total"] {
-			t := float64(meminfo.Bar1Total) / (1024 * 1024)
-			y, err := lp.New("nv_bar1_mem_total", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
+			y, err := newNvmlMetric("nv_bar1_mem_total", device, "nvmlDeviceGetBAR1MemoryInfo", float64(meminfo.Bar1Total))
 			if err == nil {
-				y.AddMeta("unit", "MByte")
 				output <- y
 			}
 		}
 		if !device.excludeMetrics["nv_bar1_mem_used"] {
-			t := float64(meminfo.Bar1Used) / (1024 * 1024)
-			y, err := lp.New("nv_bar1_mem_used", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
+			y, err := newNvmlMetric("nv_bar1_mem_used", device, "nvmlDeviceGetBAR1MemoryInfo", float64(meminfo.Bar1Used))
 			if err == nil {
-				y.AddMeta("unit", "MByte")
 				output <- y
 			}
 		}
@@ -301,16 +307,14 @@ func readUtilization(device NvidiaCollectorDevice, output chan lp.CCMetric) erro
 		util, ret := nvml.DeviceGetUtilizationRates(device.device)
 		if ret == nvml.SUCCESS {
 			if !device.excludeMetrics["nv_util"] {
-				y, err := lp.New("nv_util", device.tags, device.meta, map[string]interface{}{"value": float64(util.Gpu)}, time.Now())
+				y, err := newNvmlMetric("nv_util", device, "nvmlDeviceGetUtilizationRates", float64(util.Gpu))
 				if err == nil {
-					y.AddMeta("unit", "%")
 					output <- y
 				}
 			}
 			if !device.excludeMetrics["nv_mem_util"] {
-				y, err := lp.New("nv_mem_util", device.tags, device.meta, map[string]interface{}{"value": float64(util.Memory)}, time.Now())
+				y, err := newNvmlMetric("nv_mem_util", device, "nvmlDeviceGetUtilizationRates", float64(util.Memory))
 				if err == nil {
-					y.AddMeta("unit", "%")
 					output <- y
 				}
 			}
@@ -328,9 +332,8 @@ func readTemp(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 		// * NVML_TEMPERATURE_COUNT
 		temp, ret := nvml.DeviceGetTemperature(device.device, nvml.TEMPERATURE_GPU)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_temp", device.tags, device.meta, map[string]interface{}{"value": float64(temp)}, time.Now())
+			y, err := newNvmlMetric("nv_temp", device, "nvmlDeviceGetTemperature", float64(temp))
 			if err == nil {
-				y.AddMeta("unit", "degC")
 				output <- y
 			}
 		}
@@ -351,9 +354,8 @@ func readFan(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 		// This value may exceed 100% in certain cases.
 		fan, ret := nvml.DeviceGetFanSpeed(device.device)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_fan", device.tags, device.meta, map[string]interface{}{"value": float64(fan)}, time.Now())
+			y, err := newNvmlMetric("nv_fan", device, "nvmlDeviceGetFanSpeed", float64(fan))
 			if err == nil {
-				y.AddMeta("unit", "%")
 				output <- y
 			}
 		}
@@ -450,9 +452,8 @@ func readPowerUsage(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 		if mode == nvml.FEATURE_ENABLED {
 			power, ret := nvml.DeviceGetPowerUsage(device.device)
 			if ret == nvml.SUCCESS {
-				y, err := lp.New("nv_power_usage", device.tags, device.meta, map[string]interface{}{"value": float64(power) / 1000}, time.Now())
+				y, err := newNvmlMetric("nv_power_usage", device, "nvmlDeviceGetPowerUsage", float64(power))
 				if err == nil {
-					y.AddMeta("unit", "watts")
 					output <- y
 				}
 			}
@@ -471,9 +472,8 @@ func readClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 	if !device.excludeMetrics["nv_graphics_clock"] {
 		graphicsClock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_GRAPHICS)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_graphics_clock", device.tags, device.meta, map[string]interface{}{"value": float64(graphicsClock)}, time.Now())
+			y, err := newNvmlMetric("nv_graphics_clock", device, "nvmlDeviceGetClockInfo", float64(graphicsClock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -482,9 +482,8 @@ func readClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 	if !device.excludeMetrics["nv_sm_clock"] {
 		smCock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_SM)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_sm_clock", device.tags, device.meta, map[string]interface{}{"value": float64(smCock)}, time.Now())
+			y, err := newNvmlMetric("nv_sm_clock", device, "nvmlDeviceGetClockInfo", float64(smCock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -493,9 +492,8 @@ func readClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 	if !device.excludeMetrics["nv_mem_clock"] {
 		memClock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_MEM)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_mem_clock", device.tags, device.meta, map[string]interface{}{"value": float64(memClock)}, time.Now())
+			y, err := newNvmlMetric("nv_mem_clock", device, "nvmlDeviceGetClockInfo", float64(memClock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -503,9 +501,8 @@ func readClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error {
 	if !device.excludeMetrics["nv_video_clock"] {
 		memClock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_VIDEO)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_video_clock", device.tags, device.meta, map[string]interface{}{"value": float64(memClock)}, time.Now())
+			y, err := newNvmlMetric("nv_video_clock", device, "nvmlDeviceGetClockInfo", float64(memClock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -528,9 +525,8 @@ func readMaxClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 	if !device.excludeMetrics["nv_max_graphics_clock"] {
 		max_gclk, ret := nvml.DeviceGetMaxClockInfo(device.device, nvml.CLOCK_GRAPHICS)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_max_graphics_clock", device.tags, device.meta, map[string]interface{}{"value": float64(max_gclk)}, time.Now())
+			y, err := newNvmlMetric("nv_max_graphics_clock", device, "nvmlDeviceGetMaxClockInfo", float64(max_gclk))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -539,9 +535,8 @@ func readMaxClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 	if !device.excludeMetrics["nv_max_sm_clock"] {
 		maxSmClock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_SM)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_max_sm_clock", device.tags, device.meta, map[string]interface{}{"value": float64(maxSmClock)}, time.Now())
+			y, err := newNvmlMetric("nv_max_sm_clock", device, "nvmlDeviceGetClockInfo", float64(maxSmClock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -550,9 +545,8 @@ func readMaxClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 	if !device.excludeMetrics["nv_max_mem_clock"] {
 		maxMemClock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_MEM)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_max_mem_clock", device.tags, device.meta, map[string]interface{}{"value": float64(maxMemClock)}, time.Now())
+			y, err := newNvmlMetric("nv_max_mem_clock", device, "nvmlDeviceGetClockInfo", float64(maxMemClock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -561,9 +555,8 @@ func readMaxClocks(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 	if !device.excludeMetrics["nv_max_video_clock"] {
 		maxMemClock, ret := nvml.DeviceGetClockInfo(device.device, nvml.CLOCK_VIDEO)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_max_video_clock", device.tags, device.meta, map[string]interface{}{"value": float64(maxMemClock)}, time.Now())
+			y, err := newNvmlMetric("nv_max_video_clock", device, "nvmlDeviceGetClockInfo", float64(maxMemClock))
 			if err == nil {
-				y.AddMeta("unit", "MHz")
 				output <- y
 			}
 		}
@@ -612,9 +605,8 @@ func readPowerLimit(device NvidiaCollectorDevice, output chan lp.CCMetric) error
 		// If the card's total power draw reaches this limit the power management algorithm kicks in.
 		pwr_limit, ret := nvml.DeviceGetPowerManagementLimit(device.device)
 		if ret == nvml.SUCCESS {
-			y, err := lp.New("nv_power_max_limit", device.tags, device.meta, map[string]interface{}{"value": float64(pwr_limit) / 1000}, time.Now())
+			y, err := newNvmlMetric("nv_power_max_limit", device, "nvmlDeviceGetPowerManagementLimit", float64(pwr_limit))
 			if err == nil {
-				y.AddMeta("unit", "watts")
 				output <- y
 			}
 		}
@@ -842,7 +834,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_power", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -854,7 +846,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_thermal", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -866,7 +858,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_sync_boost", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -878,7 +870,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_board_limit", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -890,7 +882,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_low_util", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -902,7 +894,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_reliability", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -914,7 +906,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_below_app_clock", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -926,7 +918,7 @@ func readViolationStats(device NvidiaCollectorDevice, output chan lp.CCMetric) e
 			t := float64(violTime.ViolationTime) * 1e-9
 			y, err := lp.New("nv_violation_below_base_clock", device.tags, device.meta, map[string]interface{}{"value": t}, time.Now())
 			if err == nil {
-				y.AddMeta("unit", "sec")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 		}
@@ -11,6 +11,7 @@ import (
 
 	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
 	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
 )
 
 // running average power limit (RAPL) monitoring attributes for a zone
@@ -54,7 +55,7 @@ func (m *RAPLCollector) Init(config json.RawMessage) error {
 	m.meta = map[string]string{
 		"source": m.name,
 		"group":  "energy",
-		"unit":   "Watt",
+		"unit":   "W",
 	}
 
 	// Read in the JSON configuration
@@ -225,26 +226,19 @@ func (m *RAPLCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 			if i, err := strconv.ParseInt(strings.TrimSpace(string(v)), 10, 64); err == nil {
 				energy := i
 
-				// Compute average power (Δ energy / Δ time)
-				energyDiff := energy - p.energy
-				if energyDiff < 0 {
-					// Handle overflow:
-					// ( p.maxEnergyRange - p.energy ) + energy
-					// = p.maxEnergyRange + ( energy - p.energy )
-					// = p.maxEnergyRange + diffEnergy
-					energyDiff += p.maxEnergyRange
-				}
-				timeDiff := energyTimestamp.Sub(p.energyTimestamp)
-				averagePower := float64(energyDiff) / float64(timeDiff.Microseconds())
-
-				y, err := lp.New(
-					"rapl_average_power",
-					p.tags,
-					m.meta,
-					map[string]interface{}{"value": averagePower},
-					energyTimestamp)
+				// Compute average power (Δ energy / Δ time), handles an overflow of the counter
+				counter := units.NewPowercapCounter(uint64(p.maxEnergyRange))
+				averagePower, err := counter.Power(uint64(p.energy), uint64(energy), energyTimestamp.Sub(p.energyTimestamp))
 				if err == nil {
-					output <- y
+					y, err := lp.New(
+						"rapl_average_power",
+						p.tags,
+						m.meta,
+						map[string]interface{}{"value": averagePower.Value},
+						energyTimestamp)
+					if err == nil {
+						output <- y
+					}
 				}
 
 				// Save current energy counter state
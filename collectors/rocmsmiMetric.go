@@ -8,6 +8,7 @@ import (
 
 	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
 	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
 	"github.com/ClusterCockpit/go-rocm-smi/pkg/rocm_smi"
 )
 
@@ -160,6 +161,21 @@ func (m *RocmSmiCollector) Init(config json.RawMessage) error {
 	return err
 }
 
+// newRocmSmiMetric creates a metric out of a member of the GPU metrics table and converts it
+// to the unit reported by the collector (see units.RocmSmiFieldUnits)
+func newRocmSmiMetric(name string, dev RocmSmiCollectorDevice, field string, value interface{}, timestamp time.Time) (lp.CCMetric, error) {
+	v, u, err := units.NormalizeGpuValue(units.ROCmSMI, field, value)
+	if err != nil {
+		return nil, err
+	}
+	y, err := lp.New(name, dev.tags, dev.meta, map[string]interface{}{"value": v}, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	y.AddMeta("unit", u.Short())
+	return y, nil
+}
+
 // Read collects all metrics belonging to the sample collector
 // and sends them through the output channel to the collector manager
 func (m *RocmSmiCollector) Read(interval time.Duration, output chan lp.CCMetric) {
@@ -175,119 +191,119 @@ func (m *RocmSmiCollector) Read(interval time.Duration, output chan lp.CCMetric)
 
 		if !dev.excludeMetrics["rocm_gfx_util"] {
 			value := metrics.Average_gfx_activity
-			y, err := lp.New("rocm_gfx_util", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_gfx_util", dev, "average_gfx_activity", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_umc_util"] {
 			value := metrics.Average_umc_activity
-			y, err := lp.New("rocm_umc_util", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_umc_util", dev, "average_umc_activity", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_mm_util"] {
 			value := metrics.Average_mm_activity
-			y, err := lp.New("rocm_mm_util", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_mm_util", dev, "average_mm_activity", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_avg_power"] {
 			value := metrics.Average_socket_power
-			y, err := lp.New("rocm_avg_power", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_avg_power", dev, "average_socket_power", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_temp_mem"] {
 			value := metrics.Temperature_mem
-			y, err := lp.New("rocm_temp_mem", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_temp_mem", dev, "temperature_mem", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_temp_hotspot"] {
 			value := metrics.Temperature_hotspot
-			y, err := lp.New("rocm_temp_hotspot", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_temp_hotspot", dev, "temperature_hotspot", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_temp_edge"] {
 			value := metrics.Temperature_edge
-			y, err := lp.New("rocm_temp_edge", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_temp_edge", dev, "temperature_edge", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_temp_vrgfx"] {
 			value := metrics.Temperature_vrgfx
-			y, err := lp.New("rocm_temp_vrgfx", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_temp_vrgfx", dev, "temperature_vrgfx", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_temp_vrsoc"] {
 			value := metrics.Temperature_vrsoc
-			y, err := lp.New("rocm_temp_vrsoc", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_temp_vrsoc", dev, "temperature_vrsoc", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_temp_vrmem"] {
 			value := metrics.Temperature_vrmem
-			y, err := lp.New("rocm_temp_vrmem", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_temp_vrmem", dev, "temperature_vrmem", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_gfx_clock"] {
 			value := metrics.Average_gfxclk_frequency
-			y, err := lp.New("rocm_gfx_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_gfx_clock", dev, "average_gfxclk_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_soc_clock"] {
 			value := metrics.Average_socclk_frequency
-			y, err := lp.New("rocm_soc_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_soc_clock", dev, "average_socclk_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_u_clock"] {
 			value := metrics.Average_uclk_frequency
-			y, err := lp.New("rocm_u_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_u_clock", dev, "average_uclk_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_v0_clock"] {
 			value := metrics.Average_vclk0_frequency
-			y, err := lp.New("rocm_v0_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_v0_clock", dev, "average_vclk0_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_v1_clock"] {
 			value := metrics.Average_vclk1_frequency
-			y, err := lp.New("rocm_v1_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_v1_clock", dev, "average_vclk1_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_d0_clock"] {
 			value := metrics.Average_dclk0_frequency
-			y, err := lp.New("rocm_d0_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_d0_clock", dev, "average_dclk0_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
 		}
 		if !dev.excludeMetrics["rocm_d1_clock"] {
 			value := metrics.Average_dclk1_frequency
-			y, err := lp.New("rocm_d1_clock", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+			y, err := newRocmSmiMetric("rocm_d1_clock", dev, "average_dclk1_frequency", value, timestamp)
 			if err == nil {
 				output <- y
 			}
@@ -295,7 +311,7 @@ func (m *RocmSmiCollector) Read(interval time.Duration, output chan lp.CCMetric)
 		if !dev.excludeMetrics["rocm_temp_hbm"] {
 			for i := 0; i < rocm_smi.NUM_HBM_INSTANCES; i++ {
 				value := metrics.Temperature_hbm[i]
-				y, err := lp.New("rocm_temp_hbm", dev.tags, dev.meta, map[string]interface{}{"value": value}, timestamp)
+				y, err := newRocmSmiMetric("rocm_temp_hbm", dev, "temperature_hbm", value, timestamp)
 				if err == nil {
 					y.AddTag("stype", "device")
 					y.AddTag("stype-id", fmt.Sprintf("%d", i))
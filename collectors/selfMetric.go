@@ -51,32 +51,32 @@ func (m *SelfCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 
 		y, err := lp.New("total_alloc", m.tags, m.meta, map[string]interface{}{"value": memstats.TotalAlloc}, timestamp)
 		if err == nil {
-			y.AddMeta("unit", "Bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		y, err = lp.New("heap_alloc", m.tags, m.meta, map[string]interface{}{"value": memstats.HeapAlloc}, timestamp)
 		if err == nil {
-			y.AddMeta("unit", "Bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		y, err = lp.New("heap_sys", m.tags, m.meta, map[string]interface{}{"value": memstats.HeapSys}, timestamp)
 		if err == nil {
-			y.AddMeta("unit", "Bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		y, err = lp.New("heap_idle", m.tags, m.meta, map[string]interface{}{"value": memstats.HeapIdle}, timestamp)
 		if err == nil {
-			y.AddMeta("unit", "Bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		y, err = lp.New("heap_inuse", m.tags, m.meta, map[string]interface{}{"value": memstats.HeapInuse}, timestamp)
 		if err == nil {
-			y.AddMeta("unit", "Bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		y, err = lp.New("heap_released", m.tags, m.meta, map[string]interface{}{"value": memstats.HeapReleased}, timestamp)
 		if err == nil {
-			y.AddMeta("unit", "Bytes")
+			y.AddMeta("unit", "B")
 			output <- y
 		}
 		y, err = lp.New("heap_objects", m.tags, m.meta, map[string]interface{}{"value": memstats.HeapObjects}, timestamp)
@@ -104,14 +104,14 @@ func (m *SelfCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 			t := float64(sec) + (float64(nsec) * 1e-9)
 			y, err := lp.New("rusage_user_time", m.tags, m.meta, map[string]interface{}{"value": t}, timestamp)
 			if err == nil {
-				y.AddMeta("unit", "seconds")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 			sec, nsec = rusage.Stime.Unix()
 			t = float64(sec) + (float64(nsec) * 1e-9)
 			y, err = lp.New("rusage_system_time", m.tags, m.meta, map[string]interface{}{"value": t}, timestamp)
 			if err == nil {
-				y.AddMeta("unit", "seconds")
+				y.AddMeta("unit", "s")
 				output <- y
 			}
 			y, err = lp.New("rusage_vol_ctx_switch", m.tags, m.meta, map[string]interface{}{"value": rusage.Nvcsw}, timestamp)
@@ -11,6 +11,7 @@ import (
 
 	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
 	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
 )
 
 // See: https://www.kernel.org/doc/html/latest/hwmon/sysfs-interface.html
@@ -43,6 +44,20 @@ type TempCollector struct {
 	sensors []*TempCollectorSensor
 }
 
+// hwmonTemperature converts the raw value of a hwmon temperature attribute like 'temp1_input'
+// from its unit in sysfs (millidegree Celsius) to degree Celsius
+func hwmonTemperature(file string, value int64) (int64, error) {
+	u, ok := units.GetSysfsFileUnit(file)
+	if !ok {
+		return value, fmt.Errorf("unknown unit of file '%s'", file)
+	}
+	conv, err := units.NewConverter(u, units.NewUnit("degC"))
+	if err != nil {
+		return value, err
+	}
+	return conv.ApplyInt64(value), nil
+}
+
 func (m *TempCollector) Init(config json.RawMessage) error {
 	// Check if already initialized
 	if m.init {
@@ -141,8 +156,10 @@ func (m *TempCollector) Init(config json.RawMessage) error {
 			maxTempFile := strings.TrimSuffix(file, "_input") + "_max"
 			if buffer, err := os.ReadFile(maxTempFile); err == nil {
 				if x, err := strconv.ParseInt(strings.TrimSpace(string(buffer)), 10, 64); err == nil {
-					sensor.maxTempName = strings.Replace(sensor.metricName, "temp", "max_temp", 1)
-					sensor.maxTemp = x / 1000
+					if t, err := hwmonTemperature(maxTempFile, x); err == nil {
+						sensor.maxTempName = strings.Replace(sensor.metricName, "temp", "max_temp", 1)
+						sensor.maxTemp = t
+					}
 				}
 			}
 		}
@@ -152,8 +169,10 @@ func (m *TempCollector) Init(config json.RawMessage) error {
 			criticalTempFile := strings.TrimSuffix(file, "_input") + "_crit"
 			if buffer, err := os.ReadFile(criticalTempFile); err == nil {
 				if x, err := strconv.ParseInt(strings.TrimSpace(string(buffer)), 10, 64); err == nil {
-					sensor.critTempName = strings.Replace(sensor.metricName, "temp", "crit_temp", 1)
-					sensor.critTemp = x / 1000
+					if t, err := hwmonTemperature(criticalTempFile, x); err == nil {
+						sensor.critTempName = strings.Replace(sensor.metricName, "temp", "crit_temp", 1)
+						sensor.critTemp = t
+					}
 				}
 			}
 		}
@@ -189,7 +208,13 @@ func (m *TempCollector) Read(interval time.Duration, output chan lp.CCMetric) {
 				fmt.Sprintf("Read(): Failed to convert temperature '%s' to int64: %v", buffer, err))
 			continue
 		}
-		x /= 1000
+		x, err = hwmonTemperature(sensor.file, x)
+		if err != nil {
+			cclog.ComponentError(
+				m.name,
+				fmt.Sprintf("Read(): Failed to convert temperature of file '%s': %v", sensor.file, err))
+			continue
+		}
 		y, err := lp.New(
 			sensor.metricName,
 			sensor.tags,
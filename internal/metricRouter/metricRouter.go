@@ -11,8 +11,8 @@ import (
 
 	agg "github.com/ClusterCockpit/cc-metric-collector/internal/metricAggregator"
 	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
 	mct "github.com/ClusterCockpit/cc-metric-collector/pkg/multiChanTicker"
-	units "github.com/ClusterCockpit/cc-units"
 )
 
 const ROUTER_MAX_FORWARD = 50
@@ -26,36 +26,38 @@ type metricRouterTagConfig struct {
 
 // Metric router configuration
 type metricRouterConfig struct {
-	HostnameTagName   string                               `json:"hostname_tag"`        // Key name used when adding the hostname to a metric (default 'hostname')
-	AddTags           []metricRouterTagConfig              `json:"add_tags"`            // List of tags that are added when the condition is met
-	DelTags           []metricRouterTagConfig              `json:"delete_tags"`         // List of tags that are removed when the condition is met
-	IntervalAgg       []agg.MetricAggregatorIntervalConfig `json:"interval_aggregates"` // List of aggregation function processed at the end of an interval
-	DropMetrics       []string                             `json:"drop_metrics"`        // List of metric names to drop. For fine-grained dropping use drop_metrics_if
-	DropMetricsIf     []string                             `json:"drop_metrics_if"`     // List of evaluatable terms to drop metrics
-	RenameMetrics     map[string]string                    `json:"rename_metrics"`      // Map to rename metric name from key to value
-	IntervalStamp     bool                                 `json:"interval_timestamp"`  // Update timestamp periodically by ticker each interval?
-	NumCacheIntervals int                                  `json:"num_cache_intervals"` // Number of intervals of cached metrics for evaluation
-	MaxForward        int                                  `json:"max_forward"`         // Number of maximal forwarded metrics at one select
-	NormalizeUnits    bool                                 `json:"normalize_units"`     // Check unit meta flag and normalize it using cc-units
-	ChangeUnitPrefix  map[string]string                    `json:"change_unit_prefix"`  // Add prefix that should be applied to the metrics
-	dropMetrics       map[string]bool                      // Internal map for O(1) lookup
+	HostnameTagName   string                                `json:"hostname_tag"`        // Key name used when adding the hostname to a metric (default 'hostname')
+	AddTags           []metricRouterTagConfig               `json:"add_tags"`            // List of tags that are added when the condition is met
+	DelTags           []metricRouterTagConfig               `json:"delete_tags"`         // List of tags that are removed when the condition is met
+	IntervalAgg       []agg.MetricAggregatorIntervalConfig  `json:"interval_aggregates"` // List of aggregation function processed at the end of an interval
+	DropMetrics       []string                              `json:"drop_metrics"`        // List of metric names to drop. For fine-grained dropping use drop_metrics_if
+	DropMetricsIf     []string                              `json:"drop_metrics_if"`     // List of evaluatable terms to drop metrics
+	RenameMetrics     map[string]string                     `json:"rename_metrics"`      // Map to rename metric name from key to value
+	IntervalStamp     bool                                  `json:"interval_timestamp"`  // Update timestamp periodically by ticker each interval?
+	NumCacheIntervals int                                   `json:"num_cache_intervals"` // Number of intervals of cached metrics for evaluation
+	MaxForward        int                                   `json:"max_forward"`         // Number of maximal forwarded metrics at one select
+	NormalizeUnits    bool                                  `json:"normalize_units"`     // Check unit meta flag and normalize it using cc-units
+	ChangeUnitPrefix  map[string]string                     `json:"change_unit_prefix"`  // Add prefix that should be applied to the metrics
+	UnitRules         map[string]units.MetricNormalizerRule `json:"unit_rules"`          // Expected and target units for metrics
+	dropMetrics       map[string]bool                       // Internal map for O(1) lookup
 }
 
 // Metric router data structure
 type metricRouter struct {
-	hostname    string              // Hostname used in tags
-	coll_input  chan lp.CCMetric    // Input channel from CollectorManager
-	recv_input  chan lp.CCMetric    // Input channel from ReceiveManager
-	cache_input chan lp.CCMetric    // Input channel from MetricCache
-	outputs     []chan lp.CCMetric  // List of all output channels
-	done        chan bool           // channel to finish / stop metric router
-	wg          *sync.WaitGroup     // wait group for all goroutines in cc-metric-collector
-	timestamp   time.Time           // timestamp periodically updated by ticker each interval
-	ticker      mct.MultiChanTicker // periodically ticking once each interval
-	config      metricRouterConfig  // json encoded config for metric router
-	cache       MetricCache         // pointer to MetricCache
-	cachewg     sync.WaitGroup      // wait group for MetricCache
-	maxForward  int                 // number of metrics to forward maximally in one iteration
+	hostname    string                  // Hostname used in tags
+	coll_input  chan lp.CCMetric        // Input channel from CollectorManager
+	recv_input  chan lp.CCMetric        // Input channel from ReceiveManager
+	cache_input chan lp.CCMetric        // Input channel from MetricCache
+	outputs     []chan lp.CCMetric      // List of all output channels
+	done        chan bool               // channel to finish / stop metric router
+	wg          *sync.WaitGroup         // wait group for all goroutines in cc-metric-collector
+	timestamp   time.Time               // timestamp periodically updated by ticker each interval
+	ticker      mct.MultiChanTicker     // periodically ticking once each interval
+	config      metricRouterConfig      // json encoded config for metric router
+	cache       MetricCache             // pointer to MetricCache
+	cachewg     sync.WaitGroup          // wait group for MetricCache
+	maxForward  int                     // number of metrics to forward maximally in one iteration
+	normalizer  *units.MetricNormalizer // applies the unit rules
 }
 
 // MetricRouter access functions
@@ -119,6 +121,13 @@ func (r *metricRouter) Init(ticker mct.MultiChanTicker, wg *sync.WaitGroup, rout
 			r.cache.AddAggregation(agg.Name, agg.Function, agg.Condition, agg.Tags, agg.Meta)
 		}
 	}
+	if len(r.config.UnitRules) > 0 {
+		r.normalizer, err = units.NewMetricNormalizer(r.config.UnitRules)
+		if err != nil {
+			cclog.ComponentError("MetricRouter", "Unit rules initialization failed:", err.Error())
+			return err
+		}
+	}
 	r.config.dropMetrics = make(map[string]bool)
 	for _, mname := range r.config.DropMetrics {
 		r.config.dropMetrics[mname] = true
@@ -210,6 +219,9 @@ func (r *metricRouter) dropMetric(point lp.CCMetric) bool {
 	return false
 }
 
+// prepareUnit normalizes the unit of a metric and converts its value according to the unit
+// rules and the unit prefix changes. It returns false if the metric has to be dropped because
+// the value cannot be converted by its rule (incompatible unit or rejected negative value).
 func (r *metricRouter) prepareUnit(point lp.CCMetric) bool {
 	if r.config.NormalizeUnits {
 		if in_unit, ok := point.GetMeta("unit"); ok {
@@ -219,6 +231,18 @@ func (r *metricRouter) prepareUnit(point lp.CCMetric) bool {
 			}
 		}
 	}
+	if r.normalizer != nil && r.normalizer.HasRule(point.Name()) {
+		if val, ok := point.GetField("value"); ok {
+			in_unit, _ := point.GetMeta("unit")
+			out_val, out_unit, err := r.normalizer.NormalizeValue(point.Name(), val, in_unit)
+			if err != nil {
+				cclog.ComponentError("MetricRouter", "Dropping metric", point.Name()+":", err.Error())
+				return false
+			}
+			point.AddField("value", out_val)
+			point.AddMeta("unit", out_unit.Short())
+		}
+	}
 	if newP, ok := r.config.ChangeUnitPrefix[point.Name()]; ok {
 
 		newPrefix := units.NewPrefix(newP)
@@ -271,7 +295,9 @@ func (r *metricRouter) Start() {
 			r.DoDelTags(point)
 		}
 
-		r.prepareUnit(point)
+		if !r.prepareUnit(point) {
+			return
+		}
 
 		for _, o := range r.outputs {
 			o <- point
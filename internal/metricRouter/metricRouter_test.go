@@ -0,0 +1,100 @@
+package metricRouter
+
+import (
+	"testing"
+	"time"
+
+	lp "github.com/ClusterCockpit/cc-metric-collector/pkg/ccMetric"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+)
+
+func TestPrepareUnit(t *testing.T) {
+	normalizer, err := units.NewMetricNormalizer(map[string]units.MetricNormalizerRule{
+		"mem_bw":    {TargetUnit: "GB/s"},
+		"mem_used":  {TargetUnit: "B"},
+		"cpu_power": {ExpectedUnit: "mW", TargetUnit: "W"},
+		"cpu_load":  {TargetUnit: "%"},
+		"net_bw":    {TargetUnit: "B/s", Negative: units.NegativeReject},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create normalizer: %v", err)
+	}
+	r := &metricRouter{
+		config:     metricRouterConfig{NormalizeUnits: true},
+		normalizer: normalizer,
+	}
+	for _, name := range []string{"mem_bw", "mem_used", "cpu_power", "cpu_load"} {
+		if !normalizer.HasRule(name) {
+			t.Errorf("Expected rule for metric '%s'", name)
+		}
+	}
+	if normalizer.HasRule("cpu_freq") {
+		t.Errorf("Unexpected rule for metric 'cpu_freq'")
+	}
+
+	for _, c := range []struct {
+		name     string
+		unit     string // Empty for metrics without unit
+		value    interface{}
+		expected interface{}
+		outUnit  string
+	}{
+		{"mem_bw", "MB/s", 2500.0, 2.5, "GB/s"},
+		{"mem_bw", "MB/s", float32(500), 0.5, "GB/s"},
+		{"mem_bw", "MB/s", int64(2500), 2.5, "GB/s"},
+		{"mem_used", "kB", int64(3), int64(3000), "B"},
+		{"mem_used", "kB", int(3), int64(3000), "B"},
+		{"mem_used", "KiB", uint64(2), uint64(2048), "B"},
+		{"mem_used", "kB", int32(-3), int64(-3000), "B"},
+		{"mem_used", "B", int64(7), int64(7), "B"},
+		{"mem_used", "GB", uint32(5), uint64(5000000000), "B"},
+		{"mem_used", "EB", int64(10), 1e19, "B"},
+		{"cpu_power", "", int64(1500), 1.5, "W"},
+		{"cpu_power", "", 250.0, 0.25, "W"},
+		{"cpu_freq", "MHz", int64(2400), int64(2400), "MHz"},
+		{"cpu_freq", "", 2400.0, 2400.0, ""},
+	} {
+		meta := map[string]string{}
+		if len(c.unit) > 0 {
+			meta["unit"] = c.unit
+		}
+		point, err := lp.New(c.name, map[string]string{"type": "node"}, meta, map[string]interface{}{"value": c.value}, time.Now())
+		if err != nil {
+			t.Fatalf("Failed to create metric: %v", err)
+		}
+		if !r.prepareUnit(point) {
+			t.Errorf("Unexpected drop of metric '%s'", c.name)
+		}
+		if v, _ := point.GetField("value"); v != c.expected {
+			t.Errorf("Expected %v (%T) for %v %s of metric '%s' but got %v (%T)", c.expected, c.expected, c.value, c.unit, c.name, v, v)
+		}
+		if u, _ := point.GetMeta("unit"); u != c.outUnit {
+			t.Errorf("Expected unit '%s' for metric '%s' but got '%s'", c.outUnit, c.name, u)
+		}
+	}
+
+	// Metrics whose value cannot be converted by their rule are dropped
+	for _, c := range []struct {
+		name  string
+		unit  string
+		value interface{}
+	}{
+		{"cpu_load", "", 0.5},        // No unit and no expected unit
+		{"cpu_load", "MB", int64(5)}, // Not convertible
+		{"mem_bw", "GHz", 2.5},
+		{"net_bw", "kB/s", int64(-3)}, // Negative value rejected
+		{"net_bw", "MB/s", -1.5},
+	} {
+		meta := map[string]string{}
+		if len(c.unit) > 0 {
+			meta["unit"] = c.unit
+		}
+		point, err := lp.New(c.name, map[string]string{"type": "node"}, meta, map[string]interface{}{"value": c.value}, time.Now())
+		if err != nil {
+			t.Fatalf("Failed to create metric: %v", err)
+		}
+		if r.prepareUnit(point) {
+			t.Errorf("Expected drop of metric '%s' with %v %s", c.name, c.value, c.unit)
+		}
+	}
+}
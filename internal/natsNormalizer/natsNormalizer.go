@@ -0,0 +1,299 @@
+package natsNormalizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cclog "github.com/ClusterCockpit/cc-metric-collector/pkg/ccLogger"
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+	influx "github.com/influxdata/line-protocol/v2/lineprotocol"
+	nats "github.com/nats-io/nats.go"
+)
+
+// NatsNormalizerConfig is the configuration of the normalization middleware
+type NatsNormalizerConfig struct {
+	Addr          string                                `json:"address"`
+	Port          string                                `json:"port"`
+	InputSubject  string                                `json:"input_subject"`           // Subject to receive metrics from
+	OutputSubject string                                `json:"output_subject"`          // Subject to publish normalized metrics to
+	UnitTag       string                                `json:"unit_tag,omitempty"`      // Tag containing the unit (default: unit)
+	UnitRules     map[string]units.MetricNormalizerRule `json:"unit_rules"`              // Unit policies of the metrics
+	DropInvalid   bool                                  `json:"drop_invalid,omitempty"`  // Drop metrics with rule that cannot be normalized instead of forwarding them unchanged
+	DefaultUnits  map[string]string                     `json:"default_units,omitempty"` // Expected units by metric name or glob pattern, fills missing unit tags
+}
+
+// NatsNormalizer receives metric messages in InfluxDB line protocol, converts the values
+// of the metrics with unit rule to their target unit and republishes them
+type NatsNormalizer struct {
+	name       string
+	config     NatsNormalizerConfig
+	normalizer *units.MetricNormalizer
+	units      *units.MetricUnits
+	nc         *nats.Conn
+	sub        *nats.Subscription
+}
+
+// processState is the scratch space for decoding the metrics of a message. It is pooled,
+// so high message rates do not allocate new maps and slices for every metric.
+type processState struct {
+	tags      map[string]string
+	tagKeys   []string
+	fieldKeys []string
+	fields    map[string]influx.Value
+}
+
+var processStatePool = sync.Pool{
+	New: func() interface{} {
+		return &processState{
+			tags:   make(map[string]string),
+			fields: make(map[string]influx.Value),
+		}
+	},
+}
+
+// reset clears the scratch space for the next metric
+func (s *processState) reset() {
+	for k := range s.tags {
+		delete(s.tags, k)
+	}
+	for k := range s.fields {
+		delete(s.fields, k)
+	}
+	s.tagKeys = s.tagKeys[:0]
+	s.fieldKeys = s.fieldKeys[:0]
+}
+
+// Process normalizes all metrics of a message in line protocol and returns the message to
+// republish. The field 'value' of metrics with unit rule is converted and the unit tag is
+// rewritten. Metrics without rule are forwarded unchanged.
+func (n *NatsNormalizer) Process(data []byte) ([]byte, error) {
+	var enc influx.Encoder
+	enc.SetPrecision(influx.Nanosecond)
+	state := processStatePool.Get().(*processState)
+	defer processStatePool.Put(state)
+	d := influx.NewDecoderWithBytes(data)
+	for d.Next() {
+		state.reset()
+		tags := state.tags
+		fields := state.fields
+		measurement, err := d.Measurement()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode measurement: %v", err)
+		}
+		name := string(measurement)
+
+		for {
+			key, value, err := d.NextTag()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode tag: %v", err)
+			}
+			if key == nil {
+				break
+			}
+			tags[string(key)] = string(value)
+		}
+
+		for {
+			key, value, err := d.NextField()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode field: %v", err)
+			}
+			if key == nil {
+				break
+			}
+			state.fieldKeys = append(state.fieldKeys, string(key))
+			// Copy the value because it refers to the buffer of the decoder
+			v, ok := influx.NewValue(value.Interface())
+			if !ok {
+				return nil, fmt.Errorf("invalid value of field '%s'", string(key))
+			}
+			fields[string(key)] = v
+		}
+
+		t, err := d.Time(influx.Nanosecond, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode time: %v", err)
+		}
+
+		if _, ok := n.lookupDefaultUnit(name); ok {
+			u, err := n.units.Fill(name, tags[n.config.UnitTag])
+			if err != nil {
+				cclog.ComponentError(n.name, err.Error())
+				if n.config.DropInvalid {
+					continue
+				}
+			} else {
+				tags[n.config.UnitTag] = u.Short()
+			}
+		}
+
+		if value, ok := fields["value"]; ok && n.normalizer.HasRule(name) {
+			if err := n.normalize(name, tags, fields, value); err != nil {
+				cclog.ComponentError(n.name, err.Error())
+				if n.config.DropInvalid {
+					continue
+				}
+			}
+		}
+
+		enc.StartLine(name)
+		for k := range tags {
+			state.tagKeys = append(state.tagKeys, k)
+		}
+		sort.Strings(state.tagKeys)
+		for _, k := range state.tagKeys {
+			enc.AddTag(k, tags[k])
+		}
+		for _, k := range state.fieldKeys {
+			enc.AddField(k, fields[k])
+		}
+		enc.EndLine(t)
+		if err := enc.Err(); err != nil {
+			return nil, fmt.Errorf("failed to encode metric '%s': %v", name, err)
+		}
+	}
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %v", err)
+	}
+	return enc.Bytes(), nil
+}
+
+// lookupDefaultUnit returns the expected unit of a metric from the default units
+func (n *NatsNormalizer) lookupDefaultUnit(name string) (units.Unit, bool) {
+	if n.units == nil {
+		return units.UnitValue{}.Unit(), false
+	}
+	return n.units.Lookup(name)
+}
+
+// normalize converts the value field of a metric and rewrites the unit tag. Integer values
+// stay integers if the conversion has an exact integer factor like from KiB to B.
+func (n *NatsNormalizer) normalize(name string, tags map[string]string, fields map[string]influx.Value, value influx.Value) error {
+	var in interface{}
+	switch value.Kind() {
+	case influx.Float:
+		in = value.FloatV()
+	case influx.Int:
+		in = value.IntV()
+	case influx.Uint:
+		in = value.UintV()
+	default:
+		return fmt.Errorf("value of metric '%s' is not numeric", name)
+	}
+	out, unit, err := n.normalizer.NormalizeValue(name, in, tags[n.config.UnitTag])
+	if err != nil {
+		return err
+	}
+	var v influx.Value
+	switch x := out.(type) {
+	case int64:
+		v = influx.IntValue(x)
+	case uint64:
+		v = influx.UintValue(x)
+	case float64:
+		var ok bool
+		if v, ok = influx.FloatValue(x); !ok {
+			return fmt.Errorf("invalid value %v of metric '%s' after normalization", x, name)
+		}
+	default:
+		return fmt.Errorf("invalid value %v of metric '%s' after normalization", out, name)
+	}
+	fields["value"] = v
+	tags[n.config.UnitTag] = unit.Short()
+	return nil
+}
+
+// receive handles the messages of the input subject
+func (n *NatsNormalizer) receive(m *nats.Msg) {
+	out, err := n.Process(m.Data)
+	if err != nil {
+		cclog.ComponentError(n.name, err.Error())
+		return
+	}
+	if len(out) == 0 {
+		return
+	}
+	if err := n.nc.Publish(n.config.OutputSubject, out); err != nil {
+		cclog.ComponentError(n.name, "Publish:", err.Error())
+	}
+}
+
+// Start subscribes to the input subject
+func (n *NatsNormalizer) Start() error {
+	cclog.ComponentDebug(n.name, "START")
+	sub, err := n.nc.Subscribe(n.config.InputSubject, n.receive)
+	if err != nil {
+		return err
+	}
+	n.sub = sub
+	return nil
+}
+
+// Close unsubscribes and closes the connection to the NATS server
+func (n *NatsNormalizer) Close() {
+	cclog.ComponentDebug(n.name, "CLOSE")
+	if n.sub != nil {
+		n.sub.Drain()
+	}
+	if n.nc != nil {
+		n.nc.Flush()
+		n.nc.Close()
+	}
+}
+
+// newNatsNormalizer creates the normalizer without connecting to the NATS server
+func newNatsNormalizer(name string, config json.RawMessage) (*NatsNormalizer, error) {
+	n := new(NatsNormalizer)
+	n.name = fmt.Sprintf("NatsNormalizer(%s)", name)
+	n.config.Addr = "localhost"
+	n.config.Port = "4222"
+	n.config.UnitTag = "unit"
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &n.config); err != nil {
+			cclog.ComponentError(n.name, "Error reading config:", err.Error())
+			return nil, err
+		}
+	}
+	if len(n.config.Addr) == 0 ||
+		len(n.config.Port) == 0 ||
+		len(n.config.InputSubject) == 0 ||
+		len(n.config.OutputSubject) == 0 {
+		return nil, errors.New("not all configuration variables set required by NatsNormalizer")
+	}
+	if n.config.InputSubject == n.config.OutputSubject {
+		return nil, errors.New("input and output subject of NatsNormalizer must differ")
+	}
+	normalizer, err := units.NewMetricNormalizer(n.config.UnitRules)
+	if err != nil {
+		return nil, err
+	}
+	n.normalizer = normalizer
+	if len(n.config.DefaultUnits) > 0 {
+		defaults, err := units.NewMetricUnits(n.config.DefaultUnits)
+		if err != nil {
+			return nil, err
+		}
+		n.units = defaults
+	}
+	return n, nil
+}
+
+// NewNatsNormalizer creates a new normalization middleware and connects to the NATS server
+func NewNatsNormalizer(name string, config json.RawMessage) (*NatsNormalizer, error) {
+	n, err := newNatsNormalizer(name, config)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("nats://%s:%s", n.config.Addr, n.config.Port)
+	cclog.ComponentDebug(n.name, "NewNatsNormalizer", url, "Subjects", n.config.InputSubject, "->", n.config.OutputSubject)
+	nc, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	n.nc = nc
+	return n, nil
+}
@@ -0,0 +1,75 @@
+package natsNormalizer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcess(t *testing.T) {
+	n, err := newNatsNormalizer("test", []byte(`{
+		"input_subject": "raw",
+		"output_subject": "normalized",
+		"unit_rules": {
+			"mem_bw": {"target_unit": "GB/s"},
+			"cpu_power": {"expected_unit": "mW", "target_unit": "W"},
+			"mem_used": {"target_unit": "B"},
+			"net_bytes_in": {"target_unit": "B"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to create normalizer: %v", err)
+	}
+	input := "mem_bw,hostname=n1,type=node,unit=MB/s value=2500 1700000000000000000\n" +
+		"cpu_power,hostname=n1,type=socket value=1500i 1700000000000000000\n" +
+		"mem_used,hostname=n1,type=node,unit=KiB value=4i 1700000000000000000\n" +
+		"net_bytes_in,hostname=n1,type=node,unit=kB value=3u 1700000000000000000\n" +
+		"cpu_load,hostname=n1,type=node value=1.5,comment=\"x y\" 1700000000000000000\n"
+	out, err := n.Process([]byte(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	expected := []string{
+		"mem_bw,hostname=n1,type=node,unit=GB/s value=2.5 1700000000000000000",
+		"cpu_power,hostname=n1,type=socket,unit=W value=1.5 1700000000000000000",
+		"mem_used,hostname=n1,type=node,unit=B value=4096i 1700000000000000000",
+		"net_bytes_in,hostname=n1,type=node,unit=B value=3000u 1700000000000000000",
+		"cpu_load,hostname=n1,type=node value=1.5,comment=\"x y\" 1700000000000000000",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines but got %d: %s", len(expected), len(lines), out)
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Errorf("Expected '%s' but got '%s'", e, lines[i])
+		}
+	}
+}
+
+func TestProcessDefaultUnits(t *testing.T) {
+	n, err := newNatsNormalizer("test", []byte(`{
+		"input_subject": "raw",
+		"output_subject": "normalized",
+		"drop_invalid": true,
+		"default_units": {"mem_*": "MB", "nv_*_power": "W"},
+		"unit_rules": {"mem_used": {"target_unit": "GB"}}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to create normalizer: %v", err)
+	}
+	input := "mem_used,hostname=n1 value=2000 1700000000000000000\n" +
+		"nv_gpu_power,hostname=n1,unit=s value=1 1700000000000000000\n" +
+		"cpu_load,hostname=n1 value=1.5 1700000000000000000\n"
+	out, err := n.Process([]byte(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "mem_used,hostname=n1,unit=GB value=2 1700000000000000000\n" +
+		"cpu_load,hostname=n1 value=1.5 1700000000000000000\n"
+	if string(out) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, out)
+	}
+	if _, err := newNatsNormalizer("test", []byte(`{"input_subject": "a", "output_subject": "b", "default_units": {"x": "xyz"}}`)); err == nil {
+		t.Errorf("Expected error for invalid default unit")
+	}
+}
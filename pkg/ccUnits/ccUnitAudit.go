@@ -0,0 +1,114 @@
+package ccunits
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AuditEntry is an applied or planned conversion of a metric during a batch normalization
+// like Normalize() of a job archive or a migration of a metric store
+type AuditEntry struct {
+	Metric  string  `json:"metric"`
+	Scope   string  `json:"scope,omitempty"` // Scope of job metrics like 'node' or 'socket'
+	Unit    string  `json:"unit"`            // Unit before the conversion
+	NewUnit string  `json:"new_unit"`        // Unit after the conversion
+	Factor  float64 `json:"factor"`
+	Offset  float64 `json:"offset,omitempty"`
+	Points  int     `json:"points"` // Number of converted values (NaN values are not counted)
+}
+
+// String returns the entry like 'mem_bw (node): MB/s -> GB/s factor 0.001, 120 points'
+func (e AuditEntry) String() string {
+	name := e.Metric
+	if len(e.Scope) > 0 {
+		name += " (" + e.Scope + ")"
+	}
+	s := fmt.Sprintf("%s: %s -> %s factor %g", name, e.Unit, e.NewUnit, e.Factor)
+	if e.Offset != 0 {
+		s += fmt.Sprintf(" offset %g", e.Offset)
+	}
+	return s + fmt.Sprintf(", %d points", e.Points)
+}
+
+// auditKey identifies the entries of the audit log
+type auditKey struct {
+	metric, scope, unit, newUnit string
+}
+
+// AuditLog records the conversions of a batch normalization, so large migrations can be
+// reviewed afterwards. Conversions of the same metric, scope and units are combined into a
+// single entry by adding their points. It is safe for concurrent use.
+type AuditLog struct {
+	lock    sync.Mutex
+	entries map[auditKey]*AuditEntry
+}
+
+// NewAuditLog creates an empty audit log
+func NewAuditLog() *AuditLog {
+	return &AuditLog{
+		entries: make(map[auditKey]*AuditEntry),
+	}
+}
+
+// Record adds a conversion of a number of values to the audit log
+func (l *AuditLog) Record(metric string, scope string, in Unit, out Unit, conv Converter, points int) {
+	k := auditKey{metric: metric, scope: scope, unit: in.Short(), newUnit: out.Short()}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if e, ok := l.entries[k]; ok {
+		e.Points += points
+		return
+	}
+	l.entries[k] = &AuditEntry{
+		Metric:  metric,
+		Scope:   scope,
+		Unit:    k.unit,
+		NewUnit: k.newUnit,
+		Factor:  conv.Factor(),
+		Offset:  conv.Offset(),
+		Points:  points,
+	}
+}
+
+// Entries returns the recorded conversions sorted by metric, scope and unit
+func (l *AuditLog) Entries() []AuditEntry {
+	l.lock.Lock()
+	entries := make([]AuditEntry, 0, len(l.entries))
+	for _, e := range l.entries {
+		entries = append(entries, *e)
+	}
+	l.lock.Unlock()
+	sortAuditEntries(entries)
+	return entries
+}
+
+// String returns the recorded conversions with one line per entry
+func (l *AuditLog) String() string {
+	return formatAuditEntries(l.Entries())
+}
+
+// sortAuditEntries sorts entries by metric, scope and unit
+func sortAuditEntries(entries []AuditEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		return a.Unit < b.Unit
+	})
+}
+
+// formatAuditEntries writes entries with one line per entry
+func formatAuditEntries(entries []AuditEntry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(e.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
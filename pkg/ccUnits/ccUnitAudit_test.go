@@ -0,0 +1,96 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeAudited(t *testing.T) {
+	input := `{
+		"mem_bw": {
+			"node": {
+				"unit": "MB/s",
+				"timestep": 60,
+				"series": [{"hostname": "n1", "statistics": {"min": 1000, "avg": 1500, "max": 2000}, "data": [1000, null, 2000]}]
+			}
+		},
+		"cpu_load": {
+			"node": {
+				"unit": {"base": ""},
+				"timestep": 60,
+				"series": [{"hostname": "n1", "data": [1, 2]}]
+			}
+		}
+	}`
+	var jobData JobData
+	if err := json.Unmarshal([]byte(input), &jobData); err != nil {
+		t.Fatalf("Failed to decode job data: %v", err)
+	}
+	targets := map[string]Unit{"mem_bw": NewUnit("GB/s")}
+	expected := []AuditEntry{{Metric: "mem_bw", Scope: "node", Unit: "MB/s", NewUnit: "GB/s", Factor: 0.001, Points: 5}}
+
+	// The dry-run does not change the job data
+	plan, err := PlanNormalize(jobData, targets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Errorf("Expected plan %v but got %v", expected, plan)
+	}
+	if jobData["mem_bw"]["node"].Series[0].Data[0] != 1000 {
+		t.Errorf("Job data changed by dry-run")
+	}
+
+	log := NewAuditLog()
+	if err := NormalizeAudited(jobData, targets, log); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if entries := log.Entries(); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("Expected audit log %v but got %v", expected, entries)
+	}
+	if s := log.String(); s != "mem_bw (node): MB/s -> GB/s factor 0.001, 5 points\n" {
+		t.Errorf("Unexpected audit log '%s'", s)
+	}
+	if jobData["mem_bw"]["node"].Series[0].Data[0] != 1 {
+		t.Errorf("Job data not converted")
+	}
+
+	if _, err := PlanNormalize(jobData, map[string]Unit{"mem_bw": NewUnit("W")}); err == nil {
+		t.Errorf("Expected error for incompatible target unit")
+	}
+}
+
+func TestMigrationAudit(t *testing.T) {
+	e, err := NewMigrationEngine([]MigrationRule{
+		{Metric: "mem_bw", From: "MB/s", To: "GB/s"},
+		{Metric: "temp", From: "degF", To: "degC"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	log := NewAuditLog()
+	e.SetAuditLog(log)
+	for _, v := range []float64{1000, 2000, 3000} {
+		if _, _, err := e.Migrate("mem_bw", "MB/s", v); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	e.Migrate("mem_bw", "GB/s", 1.0) // Already migrated
+	e.Migrate("temp", "degF", 212.0)
+	e.Migrate("cpu_load", "", 1.0) // No rule
+
+	entries := log.Entries()
+	if len(entries) != 2 || entries[0].Metric != "mem_bw" || entries[0].Points != 3 || entries[0].Factor != 0.001 {
+		t.Fatalf("Unexpected audit log %v", entries)
+	}
+	if entries[1].Metric != "temp" || entries[1].Offset == 0 || !strings.Contains(entries[1].String(), "offset") {
+		t.Errorf("Expected offset of temperature conversion in %v", entries[1])
+	}
+
+	report := e.DryRun(map[string]string{"mem_bw": "MB/s"})
+	if len(report) != 1 || report[0].Factor != 0.001 {
+		t.Errorf("Expected factor in dry-run report %v", report)
+	}
+}
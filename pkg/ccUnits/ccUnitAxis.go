@@ -0,0 +1,115 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// Axis is a plot axis for a value range: "nice" bounds and tick steps in a prefix that fits
+// the range, like 0 to 1.2 GB/s with ticks every 0.2 GB/s
+type Axis struct {
+	Min    float64   `json:"min"`  // Lower bound in Unit
+	Max    float64   `json:"max"`  // Upper bound in Unit
+	Step   float64   `json:"step"` // Distance between the ticks in Unit
+	Unit   Unit      `json:"unit"` // Unit with the selected prefix
+	Ticks  []float64 `json:"ticks"`
+	Labels []string  `json:"labels"` // Formatted ticks like '0.2 GB/s'
+}
+
+// Default number of ticks of an axis
+const defaultAxisTicks = 6
+
+// niceAxisSteps are the mantissas of tick steps
+var niceAxisSteps = []float64{1, 2, 2.5, 5, 10}
+
+// niceAxisStep returns the smallest step of 1, 2, 2.5 or 5 times a power of ten which is
+// larger or equal to the rough step
+func niceAxisStep(rough float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(rough)))
+	for _, s := range niceAxisSteps {
+		if step := s * magnitude; step >= rough*(1-1e-9) {
+			return step
+		}
+	}
+	return 10 * magnitude
+}
+
+// roundAxisValue removes floating point noise like 0.6000000000000001 from a tick
+func roundAxisValue(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// NewAxis creates an axis for values from min to max in a unit, e.g. for the plotting layer
+// of the performance UI. The prefix is selected like Humanize() for the larger absolute
+// bound, so 0 to 1.2e9 B/s gets the bounds 0 and 1.2 GB/s. The bounds are extended to
+// multiples of the step, a step of 1, 2, 2.5 or 5 times a power of ten which results in at
+// most maxTicks ticks (default 6 for maxTicks < 2). The labels are formatted with the global
+// formatter configuration and the options (see DefaultFormatter()); WithBinaryPrefixes()
+// selects binary prefixes. It returns an error for invalid units and non-finite values.
+func NewAxis(min, max float64, u Unit, maxTicks int, options ...FormatOption) (Axis, error) {
+	if u == nil || !u.Valid() {
+		return Axis{}, fmt.Errorf("invalid unit for axis: %w", ErrInvalidMeasure)
+	}
+	if math.IsNaN(min) || math.IsNaN(max) || math.IsInf(min, 0) || math.IsInf(max, 0) {
+		return Axis{}, fmt.Errorf("invalid axis range %g to %g: %w", min, max, ErrInvalidValue)
+	}
+	if maxTicks < 2 {
+		maxTicks = defaultAxisTicks
+	}
+	if min > max {
+		min, max = max, min
+	}
+	formatter := DefaultFormatter(options...)
+
+	// Select the prefix for the larger absolute bound
+	largest := math.Max(math.Abs(min), math.Abs(max))
+	out := Quantity{Value: largest, Unit: u}.Humanize(formatter.binary).Unit
+	conv, err := NewConverter(u, out)
+	if err != nil {
+		return Axis{}, err
+	}
+	min, max = conv.ApplyFloat64(min), conv.ApplyFloat64(max)
+	if min == max {
+		// Empty range around the value
+		if min == 0 {
+			max = 1
+		} else {
+			min, max = min-math.Abs(min)/2, max+math.Abs(max)/2
+		}
+	}
+
+	step := niceAxisStep((max - min) / float64(maxTicks-1))
+	lo := math.Floor(min/step+1e-9) * step
+	hi := math.Ceil(max/step-1e-9) * step
+	for (hi-lo)/step > float64(maxTicks-1)+1e-9 {
+		step = niceAxisStep(step * (1 + 1e-6))
+		lo = math.Floor(min/step+1e-9) * step
+		hi = math.Ceil(max/step-1e-9) * step
+	}
+
+	// Decimals needed to write the step, like 1 for 0.2 and 2 for 0.25
+	decimals := 0
+	for decimals < 15 && math.Abs(roundAxisValue(step, decimals)-step) > step*1e-9 {
+		decimals++
+	}
+	labels := DefaultFormatter(append(options, WithPrecision(decimals))...)
+	a := Axis{
+		Min:  roundAxisValue(lo, decimals),
+		Max:  roundAxisValue(hi, decimals),
+		Step: roundAxisValue(step, decimals),
+		Unit: out,
+	}
+	n := int(math.Round((hi-lo)/step)) + 1
+	a.Ticks = make([]float64, n)
+	a.Labels = make([]string, n)
+	for i := range a.Ticks {
+		v := roundAxisValue(lo+float64(i)*step, decimals)
+		if v == 0 {
+			v = 0 // No '-0'
+		}
+		a.Ticks[i] = v
+		a.Labels[i] = labels.Format(Quantity{Value: v, Unit: out})
+	}
+	return a, nil
+}
@@ -0,0 +1,45 @@
+package ccunits
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewAxis(t *testing.T) {
+	a, err := NewAxis(0, 1.15e9, NewUnit("B/s"), 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a.Unit.Short() != "GB/s" || a.Min != 0 || a.Max != 1.2 || a.Step != 0.2 {
+		t.Errorf("Unexpected axis %g to %g step %g in '%s'", a.Min, a.Max, a.Step, a.Unit.Short())
+	}
+	labels := []string{"0.0 GB/s", "0.2 GB/s", "0.4 GB/s", "0.6 GB/s", "0.8 GB/s", "1.0 GB/s", "1.2 GB/s"}
+	if !reflect.DeepEqual(a.Labels, labels) {
+		t.Errorf("Expected labels %v but got %v", labels, a.Labels)
+	}
+
+	a, err = NewAxis(-3, 17, NewUnit("degC"), 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a.Min != -5 || a.Max != 20 || a.Step != 5 || len(a.Ticks) != 6 {
+		t.Errorf("Unexpected axis %g to %g step %g with %d ticks", a.Min, a.Max, a.Step, len(a.Ticks))
+	}
+
+	a, err = NewAxis(0, 3*(1<<30), NewUnit("B"), 4, WithBinaryPrefixes())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a.Unit.Short() != "GiB" || a.Max != 3 || a.Labels[1] != "1 GiB" {
+		t.Errorf("Unexpected axis to %g in '%s' with labels %v", a.Max, a.Unit.Short(), a.Labels)
+	}
+
+	a, err = NewAxis(5, 5, NewUnit("W"), 5)
+	if err != nil || a.Min > 5 || a.Max < 5 || len(a.Ticks) < 2 {
+		t.Errorf("Unexpected axis %g to %g for empty range (%v)", a.Min, a.Max, err)
+	}
+
+	if _, err := NewAxis(0, 1, NewUnit("xyz"), 5); err == nil {
+		t.Errorf("Expected error for invalid unit")
+	}
+}
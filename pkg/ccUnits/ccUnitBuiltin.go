@@ -0,0 +1,351 @@
+// Code generated by ccunits-enumgen from ccUnitBuiltin.yaml. DO NOT EDIT.
+
+package ccunits
+
+// Built-in measures. The IDs are stable and must not be changed or reused.
+const (
+	InvalidMeasure   Measure = 0
+	Bytes            Measure = 1
+	Flops            Measure = 2
+	Percentage       Measure = 3
+	TemperatureC     Measure = 4
+	TemperatureF     Measure = 5
+	Rotation         Measure = 6
+	Frequency        Measure = 7
+	Time             Measure = 8
+	Watt             Measure = 9
+	Joule            Measure = 10
+	Cycles           Measure = 11
+	Requests         Measure = 12
+	Packets          Measure = 13
+	Events           Measure = 14
+	Volt             Measure = 15
+	Ampere           Measure = 16
+	Count            Measure = 17
+	Ratio            Measure = 18
+	Decibel          Measure = 19
+	DecibelMilliwatt Measure = 20
+	Minutes          Measure = 21
+	Hours            Measure = 22
+	Days             Measure = 23
+	InfinibandWords  Measure = 24
+)
+
+// Built-in prefixes
+const (
+	InvalidPrefix Prefix = 0
+	Base                 = 1
+	Yotta                = 1e24
+	Zetta                = 1e21
+	Exa                  = 1e18
+	Peta                 = 1e15
+	Tera                 = 1e12
+	Giga                 = 1e9
+	Mega                 = 1e6
+	Kilo                 = 1e3
+	Milli                = 1e-3
+	Micro                = 1e-6
+	Nano                 = 1e-9
+	Kibi                 = 1 << 10
+	Mebi                 = 1 << 20
+	Gibi                 = 1 << 30
+	Tebi                 = 1 << 40
+	Pebi                 = 1 << 50
+	Exbi                 = 1 << 60
+	Zebi                 = 1 << 70
+	Yobi                 = 1 << 80
+)
+
+// MeasuresMap contains the names and regular expressions of the measures
+var MeasuresMap map[Measure]MeasureData = map[Measure]MeasureData{
+	Bytes:            {Long: "byte", Short: "B", Regex: "^([bB][yY]?[tT]?[eE]?[sS]?)", NonDividable: true},
+	Flops:            {Long: "Flops", Short: "Flops", Regex: "^([fF][lL]?[oO]?[pP]?[sS]?)", NonDividable: true},
+	Percentage:       {Long: "Percent", Short: "%", Regex: "^(%|[pP]ercent)"},
+	TemperatureC:     {Long: "DegreeC", Short: "degC", Regex: "^(deg[Cc]|°[cC])"},
+	TemperatureF:     {Long: "DegreeF", Short: "degF", Regex: "^(deg[fF]|°[fF])"},
+	Rotation:         {Long: "RPM", Short: "RPM", Regex: "^([rR][pP][mM])"},
+	Frequency:        {Long: "Hertz", Short: "Hz", Regex: "^([hH][eE]?[rR]?[tT]?[zZ])"},
+	Time:             {Long: "Seconds", Short: "s", Regex: "^([sS][eE]?[cC]?[oO]?[nN]?[dD]?[sS]?)"},
+	Watt:             {Long: "Watts", Short: "W", Regex: "^([wW][aA]?[tT]?[tT]?[sS]?)"},
+	Joule:            {Long: "Joules", Short: "J", Regex: "^([jJ][oO]?[uU]?[lL]?[eE]?[sS]?)"},
+	Cycles:           {Long: "Cycles", Short: "cyc", Regex: "^([cC][yY][cC]?[lL]?[eE]?[sS]?)", NonDividable: true},
+	Requests:         {Long: "Requests", Short: "requests", Regex: "^([rR][eE][qQ][uU]?[eE]?[sS]?[tT]?[sS]?)", NonDividable: true},
+	Packets:          {Long: "Packets", Short: "packets", Regex: "^([pP][aA]?[cC]?[kK][eE]?[tT][sS]?)", NonDividable: true},
+	Events:           {Long: "Events", Short: "events", Regex: "^([eE][vV]?[eE]?[nN][tT][sS]?)", NonDividable: true},
+	Volt:             {Long: "Volts", Short: "V", Regex: "^([vV][oO]?[lL]?[tT]?[sS]?)$"},
+	Ampere:           {Long: "Ampere", Short: "A", Regex: "^([aA][mM]?[pP]?[eE]?[rR]?[eE]?[sS]?)$"},
+	Count:            {Long: "Count", Short: "count", Regex: "^([cC][oO][uU][nN][tT][sS]?)", NonDividable: true},
+	Ratio:            {Long: "Ratio", Short: "ratio", Regex: "^([rR][aA][tT][iI][oO])"},
+	Decibel:          {Long: "Decibel", Short: "dB", Regex: "^(d[bB]$|[dD]ecibels?$)"},
+	DecibelMilliwatt: {Long: "DecibelMilliwatt", Short: "dBm", Regex: "^(d[bB][mM]|[dD]ecibel-?[mM]illiwatts?)"},
+	Minutes:          {Long: "Minutes", Short: "min", Regex: "^(min|mins|[mM]inutes?)$"},
+	Hours:            {Long: "Hours", Short: "h", Regex: "^(h|hrs?|[hH]ours?)$"},
+	Days:             {Long: "Days", Short: "d", Regex: "^(d|[dD]ays?)$"},
+	InfinibandWords:  {Long: "InfinibandWords", Short: "IBW", Regex: "^(IBW|IB[wW]ords?|[iI]nfini[bB]and-?[wW]ords?)$", NonDividable: true},
+}
+
+// PrefixDataMap contains the names and regular expressions of the prefixes
+var PrefixDataMap map[Prefix]PrefixData = map[Prefix]PrefixData{
+	Base:  {Long: "", Short: "", Regex: "^$"},
+	Yotta: {Long: "Yotta", Short: "Y", Regex: "^[yY]$"},
+	Zetta: {Long: "Zetta", Short: "Z", Regex: "^[zZ]$"},
+	Exa:   {Long: "Exa", Short: "E", Regex: "^[eE]$"},
+	Peta:  {Long: "Peta", Short: "P", Regex: "^[pP]$"},
+	Tera:  {Long: "Tera", Short: "T", Regex: "^[tT]$"},
+	Giga:  {Long: "Giga", Short: "G", Regex: "^[gG]$"},
+	Mega:  {Long: "Mega", Short: "M", Regex: "^[M]$"},
+	Kilo:  {Long: "Kilo", Short: "K", Regex: "^[kK]$"},
+	Milli: {Long: "Milli", Short: "m", Regex: "^[m]$"},
+	Micro: {Long: "Micro", Short: "u", Regex: "^[uµμ]$"},
+	Nano:  {Long: "Nano", Short: "n", Regex: "^[n]$"},
+	Kibi:  {Long: "Kibi", Short: "Ki", Regex: "^[kK][i]$"},
+	Mebi:  {Long: "Mebi", Short: "Mi", Regex: "^[M][i]$"},
+	Gibi:  {Long: "Gibi", Short: "Gi", Regex: "^[gG][i]$"},
+	Tebi:  {Long: "Tebi", Short: "Ti", Regex: "^[tT][i]$"},
+	Pebi:  {Long: "Pebi", Short: "Pi", Regex: "^[pP][i]$"},
+	Exbi:  {Long: "Exbi", Short: "Ei", Regex: "^[eE][i]$"},
+	Zebi:  {Long: "Zebi", Short: "Zi", Regex: "^[zZ][i]$"},
+	Yobi:  {Long: "Yobi", Short: "Yi", Regex: "^[yY][i]$"},
+}
+
+// Built-in prefixes with their exact value as base and exponent
+var prefixTableEntries = []struct {
+	prefix   Prefix
+	base     int64
+	exponent int
+}{
+	{Base, 10, 0},
+	{Yotta, 10, 24},
+	{Zetta, 10, 21},
+	{Exa, 10, 18},
+	{Peta, 10, 15},
+	{Tera, 10, 12},
+	{Giga, 10, 9},
+	{Mega, 10, 6},
+	{Kilo, 10, 3},
+	{Milli, 10, -3},
+	{Micro, 10, -6},
+	{Nano, 10, -9},
+	{Kibi, 2, 10},
+	{Mebi, 2, 20},
+	{Gibi, 2, 30},
+	{Tebi, 2, 40},
+	{Pebi, 2, 50},
+	{Exbi, 2, 60},
+	{Zebi, 2, 70},
+	{Yobi, 2, 80},
+}
+
+// Built-in aliases of the measures and prefixes
+var builtinMeasureAliases = map[string]Measure{}
+
+var builtinPrefixAliases = map[string]Prefix{}
+
+// builtinMeasure returns the data of a built-in measure without locking the registry
+func builtinMeasure(m Measure) (MeasureData, bool) {
+	switch m {
+	case Bytes:
+		return MeasureData{Long: "byte", Short: "B", Regex: "^([bB][yY]?[tT]?[eE]?[sS]?)", NonDividable: true}, true
+	case Flops:
+		return MeasureData{Long: "Flops", Short: "Flops", Regex: "^([fF][lL]?[oO]?[pP]?[sS]?)", NonDividable: true}, true
+	case Percentage:
+		return MeasureData{Long: "Percent", Short: "%", Regex: "^(%|[pP]ercent)"}, true
+	case TemperatureC:
+		return MeasureData{Long: "DegreeC", Short: "degC", Regex: "^(deg[Cc]|°[cC])"}, true
+	case TemperatureF:
+		return MeasureData{Long: "DegreeF", Short: "degF", Regex: "^(deg[fF]|°[fF])"}, true
+	case Rotation:
+		return MeasureData{Long: "RPM", Short: "RPM", Regex: "^([rR][pP][mM])"}, true
+	case Frequency:
+		return MeasureData{Long: "Hertz", Short: "Hz", Regex: "^([hH][eE]?[rR]?[tT]?[zZ])"}, true
+	case Time:
+		return MeasureData{Long: "Seconds", Short: "s", Regex: "^([sS][eE]?[cC]?[oO]?[nN]?[dD]?[sS]?)"}, true
+	case Watt:
+		return MeasureData{Long: "Watts", Short: "W", Regex: "^([wW][aA]?[tT]?[tT]?[sS]?)"}, true
+	case Joule:
+		return MeasureData{Long: "Joules", Short: "J", Regex: "^([jJ][oO]?[uU]?[lL]?[eE]?[sS]?)"}, true
+	case Cycles:
+		return MeasureData{Long: "Cycles", Short: "cyc", Regex: "^([cC][yY][cC]?[lL]?[eE]?[sS]?)", NonDividable: true}, true
+	case Requests:
+		return MeasureData{Long: "Requests", Short: "requests", Regex: "^([rR][eE][qQ][uU]?[eE]?[sS]?[tT]?[sS]?)", NonDividable: true}, true
+	case Packets:
+		return MeasureData{Long: "Packets", Short: "packets", Regex: "^([pP][aA]?[cC]?[kK][eE]?[tT][sS]?)", NonDividable: true}, true
+	case Events:
+		return MeasureData{Long: "Events", Short: "events", Regex: "^([eE][vV]?[eE]?[nN][tT][sS]?)", NonDividable: true}, true
+	case Volt:
+		return MeasureData{Long: "Volts", Short: "V", Regex: "^([vV][oO]?[lL]?[tT]?[sS]?)$"}, true
+	case Ampere:
+		return MeasureData{Long: "Ampere", Short: "A", Regex: "^([aA][mM]?[pP]?[eE]?[rR]?[eE]?[sS]?)$"}, true
+	case Count:
+		return MeasureData{Long: "Count", Short: "count", Regex: "^([cC][oO][uU][nN][tT][sS]?)", NonDividable: true}, true
+	case Ratio:
+		return MeasureData{Long: "Ratio", Short: "ratio", Regex: "^([rR][aA][tT][iI][oO])"}, true
+	case Decibel:
+		return MeasureData{Long: "Decibel", Short: "dB", Regex: "^(d[bB]$|[dD]ecibels?$)"}, true
+	case DecibelMilliwatt:
+		return MeasureData{Long: "DecibelMilliwatt", Short: "dBm", Regex: "^(d[bB][mM]|[dD]ecibel-?[mM]illiwatts?)"}, true
+	case Minutes:
+		return MeasureData{Long: "Minutes", Short: "min", Regex: "^(min|mins|[mM]inutes?)$"}, true
+	case Hours:
+		return MeasureData{Long: "Hours", Short: "h", Regex: "^(h|hrs?|[hH]ours?)$"}, true
+	case Days:
+		return MeasureData{Long: "Days", Short: "d", Regex: "^(d|[dD]ays?)$"}, true
+	case InfinibandWords:
+		return MeasureData{Long: "InfinibandWords", Short: "IBW", Regex: "^(IBW|IB[wW]ords?|[iI]nfini[bB]and-?[wW]ords?)$", NonDividable: true}, true
+	}
+	return MeasureData{}, false
+}
+
+// builtinPrefix returns the data of a built-in prefix without locking the registry
+func builtinPrefix(p Prefix) (PrefixData, bool) {
+	switch p {
+	case Base:
+		return PrefixData{Long: "", Short: "", Regex: "^$"}, true
+	case Yotta:
+		return PrefixData{Long: "Yotta", Short: "Y", Regex: "^[yY]$"}, true
+	case Zetta:
+		return PrefixData{Long: "Zetta", Short: "Z", Regex: "^[zZ]$"}, true
+	case Exa:
+		return PrefixData{Long: "Exa", Short: "E", Regex: "^[eE]$"}, true
+	case Peta:
+		return PrefixData{Long: "Peta", Short: "P", Regex: "^[pP]$"}, true
+	case Tera:
+		return PrefixData{Long: "Tera", Short: "T", Regex: "^[tT]$"}, true
+	case Giga:
+		return PrefixData{Long: "Giga", Short: "G", Regex: "^[gG]$"}, true
+	case Mega:
+		return PrefixData{Long: "Mega", Short: "M", Regex: "^[M]$"}, true
+	case Kilo:
+		return PrefixData{Long: "Kilo", Short: "K", Regex: "^[kK]$"}, true
+	case Milli:
+		return PrefixData{Long: "Milli", Short: "m", Regex: "^[m]$"}, true
+	case Micro:
+		return PrefixData{Long: "Micro", Short: "u", Regex: "^[uµμ]$"}, true
+	case Nano:
+		return PrefixData{Long: "Nano", Short: "n", Regex: "^[n]$"}, true
+	case Kibi:
+		return PrefixData{Long: "Kibi", Short: "Ki", Regex: "^[kK][i]$"}, true
+	case Mebi:
+		return PrefixData{Long: "Mebi", Short: "Mi", Regex: "^[M][i]$"}, true
+	case Gibi:
+		return PrefixData{Long: "Gibi", Short: "Gi", Regex: "^[gG][i]$"}, true
+	case Tebi:
+		return PrefixData{Long: "Tebi", Short: "Ti", Regex: "^[tT][i]$"}, true
+	case Pebi:
+		return PrefixData{Long: "Pebi", Short: "Pi", Regex: "^[pP][i]$"}, true
+	case Exbi:
+		return PrefixData{Long: "Exbi", Short: "Ei", Regex: "^[eE][i]$"}, true
+	case Zebi:
+		return PrefixData{Long: "Zebi", Short: "Zi", Regex: "^[zZ][i]$"}, true
+	case Yobi:
+		return PrefixData{Long: "Yobi", Short: "Yi", Regex: "^[yY][i]$"}, true
+	}
+	return PrefixData{}, false
+}
+
+// builtinMetadata returns the metadata of a built-in measure without locking the registry
+func builtinMetadata(m Measure) (MeasureMetadata, bool) {
+	switch m {
+	case Bytes:
+		return MeasureMetadata{Description: "Amount of data like memory usage or transferred data", Singular: "Byte", Plural: "Bytes", Category: "data", Color: "#1f77b4", TypicalMin: 0, TypicalMax: 1e+12, NonNegative: true}, true
+	case Flops:
+		return MeasureMetadata{Description: "Floating point operations", Singular: "Flop", Plural: "Flops", Category: "compute", Color: "#ff7f0e", TypicalMin: 0, TypicalMax: 1e+12, NonNegative: true, Counter: true}, true
+	case Percentage:
+		return MeasureMetadata{Description: "Share of a total like the CPU utilization", Singular: "Percent", Plural: "Percent", Category: "ratio", Color: "#2ca02c", TypicalMin: 0, TypicalMax: 100, ValidMin: validBound(0), ValidMax: validBound(100)}, true
+	case TemperatureC:
+		return MeasureMetadata{Description: "Temperature in degree Celsius", Singular: "Degree Celsius", Plural: "Degrees Celsius", Category: "temperature", Color: "#d62728", TypicalMin: 20, TypicalMax: 100, ValidMin: validBound(-273.15)}, true
+	case TemperatureF:
+		return MeasureMetadata{Description: "Temperature in degree Fahrenheit", Singular: "Degree Fahrenheit", Plural: "Degrees Fahrenheit", Category: "temperature", Color: "#d62728", TypicalMin: 68, TypicalMax: 212, ValidMin: validBound(-459.67)}, true
+	case Rotation:
+		return MeasureMetadata{Description: "Rotational speed of fans in revolutions per minute", Singular: "Revolution per Minute", Plural: "Revolutions per Minute", Category: "frequency", Color: "#9467bd", TypicalMin: 0, TypicalMax: 20000, ValidMin: validBound(0)}, true
+	case Frequency:
+		return MeasureMetadata{Description: "Clock frequency of processors and memory", Singular: "Hertz", Plural: "Hertz", Category: "frequency", Color: "#8c564b", TypicalMin: 0, TypicalMax: 5e+09, ValidMin: validBound(0)}, true
+	case Time:
+		return MeasureMetadata{Description: "Duration like runtimes and latencies", Singular: "Second", Plural: "Seconds", Category: "time", Color: "#e377c2", TypicalMin: 0, TypicalMax: 86400}, true
+	case Watt:
+		return MeasureMetadata{Description: "Power consumption", Singular: "Watt", Plural: "Watts", Category: "power", Color: "#bcbd22", TypicalMin: 0, TypicalMax: 1000}, true
+	case Joule:
+		return MeasureMetadata{Description: "Energy consumption", Singular: "Joule", Plural: "Joules", Category: "energy", Color: "#17becf", TypicalMin: 0, TypicalMax: 1e+09, Counter: true}, true
+	case Cycles:
+		return MeasureMetadata{Description: "Clock cycles of processors", Singular: "Cycle", Plural: "Cycles", Category: "compute", Color: "#ff9896", TypicalMin: 0, TypicalMax: 1e+12, NonNegative: true, Counter: true}, true
+	case Requests:
+		return MeasureMetadata{Description: "Requests to services like file systems", Singular: "Request", Plural: "Requests", Category: "count", Color: "#aec7e8", TypicalMin: 0, TypicalMax: 1e+06, NonNegative: true, Counter: true}, true
+	case Packets:
+		return MeasureMetadata{Description: "Network packets", Singular: "Packet", Plural: "Packets", Category: "network", Color: "#98df8a", TypicalMin: 0, TypicalMax: 1e+09, NonNegative: true, Counter: true}, true
+	case Events:
+		return MeasureMetadata{Description: "Occurrences of hardware or software events", Singular: "Event", Plural: "Events", Category: "count", Color: "#c5b0d5", TypicalMin: 0, TypicalMax: 1e+09, NonNegative: true, Counter: true}, true
+	case Volt:
+		return MeasureMetadata{Description: "Electrical voltage", Singular: "Volt", Plural: "Volts", Category: "electrical", Color: "#c49c94", TypicalMin: 0, TypicalMax: 250}, true
+	case Ampere:
+		return MeasureMetadata{Description: "Electrical current", Singular: "Ampere", Plural: "Amperes", Category: "electrical", Color: "#f7b6d2", TypicalMin: 0, TypicalMax: 100}, true
+	case Count:
+		return MeasureMetadata{Description: "Number of items like processes or threads", Singular: "Count", Plural: "Counts", Category: "count", Color: "#7f7f7f", TypicalMin: 0, TypicalMax: 10000, NonNegative: true}, true
+	case Ratio:
+		return MeasureMetadata{Description: "Share of a total as fraction between 0 and 1 like a cache hit ratio", Singular: "Ratio", Plural: "Ratio", Category: "ratio", Color: "#98df8a", TypicalMin: 0, TypicalMax: 1, ValidMin: validBound(0), ValidMax: validBound(1)}, true
+	case Decibel:
+		return MeasureMetadata{Description: "Logarithmic power ratio like a signal-to-noise ratio", Singular: "Decibel", Plural: "Decibels", Category: "ratio", Color: "#ff9896", TypicalMin: -30, TypicalMax: 30}, true
+	case DecibelMilliwatt:
+		return MeasureMetadata{Description: "Logarithmic power level relative to 1 mW like the optical power of transceivers", Singular: "Decibel-Milliwatt", Plural: "Decibel-Milliwatts", Category: "power", Color: "#dbdb8d", TypicalMin: -40, TypicalMax: 10}, true
+	case Minutes:
+		return MeasureMetadata{Description: "Duration in minutes like queue wait times", Singular: "Minute", Plural: "Minutes", Category: "time", Color: "#e377c2", TypicalMin: 0, TypicalMax: 1440}, true
+	case Hours:
+		return MeasureMetadata{Description: "Duration in hours like wallclock limits of jobs", Singular: "Hour", Plural: "Hours", Category: "time", Color: "#e377c2", TypicalMin: 0, TypicalMax: 168}, true
+	case Days:
+		return MeasureMetadata{Description: "Duration in days like the runtime of long jobs", Singular: "Day", Plural: "Days", Category: "time", Color: "#e377c2", TypicalMin: 0, TypicalMax: 30}, true
+	case InfinibandWords:
+		return MeasureMetadata{Description: "Data counted by InfiniBand port counters (PortXmitData, PortRcvData) in 4-byte words", Singular: "InfiniBand word", Plural: "InfiniBand words", Category: "network", Color: "#1f77b4", TypicalMin: 0, TypicalMax: 2.5e+11, NonNegative: true, Counter: true}, true
+	}
+	return MeasureMetadata{}, false
+}
+
+// builtinDimension returns the dimension and scale of a built-in measure. Logarithmic
+// measures have no dimension.
+func builtinDimension(m Measure) (dimension, float64, bool) {
+	switch m {
+	case Bytes:
+		return dimension{dimData: 1}, 1, true
+	case Flops:
+		return dimension{dimFlop: 1}, 1, true
+	case Percentage:
+		return dimension{}, 0.01, true
+	case TemperatureC:
+		return dimension{dimTemperature: 1}, 1.8, true
+	case TemperatureF:
+		return dimension{dimTemperature: 1}, 1, true
+	case Rotation:
+		return dimension{dimRevolution: 1, dimTime: -1}, 0.016666666666666666, true
+	case Frequency:
+		return dimension{dimTime: -1}, 1, true
+	case Time:
+		return dimension{dimTime: 1}, 1, true
+	case Watt:
+		return dimension{dimEnergy: 1, dimTime: -1}, 1, true
+	case Joule:
+		return dimension{dimEnergy: 1}, 1, true
+	case Cycles:
+		return dimension{dimCycle: 1}, 1, true
+	case Requests:
+		return dimension{dimRequest: 1}, 1, true
+	case Packets:
+		return dimension{dimPacket: 1}, 1, true
+	case Events:
+		return dimension{dimEvent: 1}, 1, true
+	case Volt:
+		return dimension{dimCurrent: -1, dimEnergy: 1, dimTime: -1}, 1, true
+	case Ampere:
+		return dimension{dimCurrent: 1}, 1, true
+	case Count:
+		return dimension{dimItem: 1}, 1, true
+	case Ratio:
+		return dimension{}, 1, true
+	case Minutes:
+		return dimension{dimTime: 1}, 60, true
+	case Hours:
+		return dimension{dimTime: 1}, 3600, true
+	case Days:
+		return dimension{dimTime: 1}, 86400, true
+	case InfinibandWords:
+		return dimension{dimData: 1}, 4, true
+	}
+	return dimension{}, 0, false
+}
@@ -0,0 +1,98 @@
+package ccunits
+
+import (
+	"hash/maphash"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Number of shards of the caches. Stores copy only the map of a single shard, so they stay
+// cheap while the cache fills up.
+const cacheShards = 32
+
+// cacheShard is a part of a sharded cache. The map is never modified after it is published,
+// readers load it without locking and writers replace it under the lock.
+type cacheShard[K comparable, V any] struct {
+	sync.Mutex
+	entries atomic.Pointer[map[K]V]
+	_       [48]byte // Padding to keep the shards in separate cache lines
+}
+
+// shardedCache is a concurrent map for the hot paths of parsing and conversion. Lookups are
+// lock-free, so many goroutines reading the same few dozen keys do not contend. It is tuned
+// for keys which are stored once and read often and is bounded in size.
+type shardedCache[K comparable, V any] struct {
+	shards     [cacheShards]cacheShard[K, V]
+	hash       func(K) uint64
+	maxEntries int // Maximal number of entries per shard
+}
+
+// newShardedCache creates a cache with at most maxEntries entries
+func newShardedCache[K comparable, V any](maxEntries int, hash func(K) uint64) *shardedCache[K, V] {
+	c := &shardedCache[K, V]{
+		hash:       hash,
+		maxEntries: (maxEntries + cacheShards - 1) / cacheShards,
+	}
+	for i := range c.shards {
+		c.shards[i].entries.Store(&map[K]V{})
+	}
+	return c
+}
+
+// shard returns the shard of a key
+func (c *shardedCache[K, V]) shard(key K) *cacheShard[K, V] {
+	return &c.shards[c.hash(key)%cacheShards]
+}
+
+// Load returns the cached value of a key
+func (c *shardedCache[K, V]) Load(key K) (V, bool) {
+	v, ok := (*c.shard(key).entries.Load())[key]
+	return v, ok
+}
+
+// Store caches a value if the shard is not full. The check function is called with the
+// lock held and can prevent storing, e.g. if the value is outdated.
+func (c *shardedCache[K, V]) Store(key K, value V, check func() bool) {
+	s := c.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	old := *s.entries.Load()
+	if _, ok := old[key]; ok || len(old) >= c.maxEntries || (check != nil && !check()) {
+		return
+	}
+	entries := make(map[K]V, len(old)+1)
+	for k, v := range old {
+		entries[k] = v
+	}
+	entries[key] = value
+	s.entries.Store(&entries)
+}
+
+// Reset drops all cached values
+func (c *shardedCache[K, V]) Reset() {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.Lock()
+		s.entries.Store(&map[K]V{})
+		s.Unlock()
+	}
+}
+
+var cacheSeed = maphash.MakeSeed()
+
+// hashString is the hash function for caches with string keys
+func hashString(s string) uint64 {
+	return maphash.String(cacheSeed, s)
+}
+
+// hashUnitValue is the hash function for caches with unit value keys
+func hashUnitValue(u UnitValue) uint64 {
+	return u.Hash()
+}
+
+// hashPrefixPair is the hash function for caches with a pair of prefixes as key
+func hashPrefixPair(p [2]Prefix) uint64 {
+	h := math.Float64bits(float64(p[0]))*0x9e3779b97f4a7c15 ^ math.Float64bits(float64(p[1]))
+	return h ^ (h >> 29)
+}
@@ -0,0 +1,111 @@
+package ccunits
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestShardedCache(t *testing.T) {
+	c := newShardedCache[string, int](cacheShards, hashString)
+	for i := 0; i < 4*cacheShards; i++ {
+		c.Store(fmt.Sprint(i), i, nil)
+	}
+	stored := 0
+	for i := 0; i < 4*cacheShards; i++ {
+		if v, ok := c.Load(fmt.Sprint(i)); ok {
+			if v != i {
+				t.Errorf("Load(%d) = %d", i, v)
+			}
+			stored++
+		}
+	}
+	if stored == 0 || stored > cacheShards {
+		t.Errorf("cache holds %d entries, limit is %d", stored, cacheShards)
+	}
+	c.Store("rejected", 1, func() bool { return false })
+	if _, ok := c.Load("rejected"); ok {
+		t.Errorf("value stored although check failed")
+	}
+	c.Reset()
+	for i := 0; i < 4*cacheShards; i++ {
+		if _, ok := c.Load(fmt.Sprint(i)); ok {
+			t.Errorf("Load(%d) after Reset() succeeded", i)
+		}
+	}
+
+	conv := GetPrefixPrefixFactor(Kilo, Mega)
+	if v := conv(2000.0); v != 2.0 {
+		t.Errorf("cached conversion returns %v instead of 2", v)
+	}
+	if v := GetPrefixPrefixFactor(Kilo, Mega)(int64(3000)); v != int64(3) {
+		t.Errorf("cached conversion returns %v instead of 3", v)
+	}
+}
+
+// Unit strings of the ingest path used by the cache benchmarks
+var benchmarkUnitStrings = []string{
+	"MB/s", "GHz", "W", "J", "degC", "%", "B", "kB", "GB", "FLOP/s", "MFLOP/s", "s", "ms", "Hz", "packets/s", "requests",
+}
+
+func BenchmarkInternUnitParallel(b *testing.B) {
+	for _, s := range benchmarkUnitStrings {
+		InternUnit(s)
+	}
+	b.Run("sharded", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				InternUnit(benchmarkUnitStrings[i%len(benchmarkUnitStrings)])
+				i++
+			}
+		})
+	})
+	b.Run("sync.Map", func(b *testing.B) {
+		var m sync.Map
+		for _, s := range benchmarkUnitStrings {
+			m.Store(s, parseUnitValue(s))
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				s := benchmarkUnitStrings[i%len(benchmarkUnitStrings)]
+				if _, ok := m.Load(s); !ok {
+					m.Store(s, parseUnitValue(s))
+				}
+				i++
+			}
+		})
+	})
+}
+
+func BenchmarkPrefixFactorParallel(b *testing.B) {
+	pairs := make([][2]Unit, 0)
+	for _, p := range [][2]string{{"kB", "MB"}, {"MHz", "GHz"}, {"ms", "s"}, {"W", "mW"}, {"GB/s", "MB/s"}} {
+		pairs = append(pairs, [2]Unit{NewUnit(p[0]), NewUnit(p[1])})
+	}
+	b.Run("sharded", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				p := pairs[i%len(pairs)]
+				GetPrefixPrefixFactor(p[0].GetPrefix(), p[1].GetPrefix())
+				i++
+			}
+		})
+	})
+	b.Run("sync.Map", func(b *testing.B) {
+		var m sync.Map
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				p := pairs[i%len(pairs)]
+				key := [2]Prefix{p[0].GetPrefix(), p[1].GetPrefix()}
+				if _, ok := m.Load(key); !ok {
+					m.Store(key, NewPrefixConverter(key[0], key[1]).Apply)
+				}
+				i++
+			}
+		})
+	})
+}
@@ -0,0 +1,30 @@
+package ccunits
+
+// Predicates for the semantic category of a unit. Display and aggregation layers can use
+// them instead of maintaining their own lists of measures, e.g. rates are averaged when
+// downsampling while data volumes are summed up. Invalid units are in no category.
+
+// IsRate checks whether the unit is a rate per time like 'MB/s', 'Flops/s' or 'GB/h'
+func (u UnitValue) IsRate() bool {
+	return u.Valid() && isTimeMeasure(u.divMeasure)
+}
+
+// IsDataVolume checks whether the unit is an amount of data like 'GB' (but not 'GB/s')
+func (u UnitValue) IsDataVolume() bool {
+	return u.Valid() && u.measure == Bytes && u.divMeasure == InvalidMeasure
+}
+
+// IsTemperature checks whether the unit is a temperature in Celsius or Fahrenheit
+func (u UnitValue) IsTemperature() bool {
+	return u.Valid() && (u.measure == TemperatureC || u.measure == TemperatureF) && u.divMeasure == InvalidMeasure
+}
+
+// IsPercentage checks whether the unit is a percentage
+func (u UnitValue) IsPercentage() bool {
+	return u.Valid() && u.measure == Percentage && u.divMeasure == InvalidMeasure
+}
+
+// IsEnergy checks whether the unit is an amount of energy like 'kJ' (but not the power 'W')
+func (u UnitValue) IsEnergy() bool {
+	return u.Valid() && u.measure == Joule && u.divMeasure == InvalidMeasure
+}
@@ -0,0 +1,157 @@
+package ccunits
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Units of the cgroup v2 interface files
+// See https://www.kernel.org/doc/Documentation/admin-guide/cgroup-v2.rst
+
+// CgroupFileUnits maps the single value cgroup v2 interface files to the unit of their value
+var CgroupFileUnits map[string]UnitValue = map[string]UnitValue{
+	"memory.current":       {Base, Bytes, InvalidMeasure},
+	"memory.peak":          {Base, Bytes, InvalidMeasure},
+	"memory.min":           {Base, Bytes, InvalidMeasure},
+	"memory.low":           {Base, Bytes, InvalidMeasure},
+	"memory.high":          {Base, Bytes, InvalidMeasure},
+	"memory.max":           {Base, Bytes, InvalidMeasure},
+	"memory.swap.current":  {Base, Bytes, InvalidMeasure},
+	"memory.swap.peak":     {Base, Bytes, InvalidMeasure},
+	"memory.swap.high":     {Base, Bytes, InvalidMeasure},
+	"memory.swap.max":      {Base, Bytes, InvalidMeasure},
+	"memory.zswap.current": {Base, Bytes, InvalidMeasure},
+	"memory.zswap.max":     {Base, Bytes, InvalidMeasure},
+	"hugetlb.*.current":    {Base, Bytes, InvalidMeasure},
+	"hugetlb.*.max":        {Base, Bytes, InvalidMeasure},
+}
+
+// CgroupKeyUnits maps the keyed cgroup v2 interface files (like cpu.stat or io.stat) to the
+// units of their keys
+var CgroupKeyUnits map[string]map[string]UnitValue = map[string]map[string]UnitValue{
+	"cpu.stat": {
+		"usage_usec":                 {Micro, Time, InvalidMeasure},
+		"user_usec":                  {Micro, Time, InvalidMeasure},
+		"system_usec":                {Micro, Time, InvalidMeasure},
+		"throttled_usec":             {Micro, Time, InvalidMeasure},
+		"nr_bursts":                  {Base, Events, InvalidMeasure},
+		"burst_usec":                 {Micro, Time, InvalidMeasure},
+		"nr_periods":                 {Base, Events, InvalidMeasure},
+		"nr_throttled":               {Base, Events, InvalidMeasure},
+		"core_sched.force_idle_usec": {Micro, Time, InvalidMeasure},
+	},
+	"memory.stat": {
+		"anon":               {Base, Bytes, InvalidMeasure},
+		"file":               {Base, Bytes, InvalidMeasure},
+		"kernel":             {Base, Bytes, InvalidMeasure},
+		"kernel_stack":       {Base, Bytes, InvalidMeasure},
+		"pagetables":         {Base, Bytes, InvalidMeasure},
+		"sec_pagetables":     {Base, Bytes, InvalidMeasure},
+		"percpu":             {Base, Bytes, InvalidMeasure},
+		"sock":               {Base, Bytes, InvalidMeasure},
+		"vmalloc":            {Base, Bytes, InvalidMeasure},
+		"shmem":              {Base, Bytes, InvalidMeasure},
+		"zswap":              {Base, Bytes, InvalidMeasure},
+		"zswapped":           {Base, Bytes, InvalidMeasure},
+		"file_mapped":        {Base, Bytes, InvalidMeasure},
+		"file_dirty":         {Base, Bytes, InvalidMeasure},
+		"file_writeback":     {Base, Bytes, InvalidMeasure},
+		"swapcached":         {Base, Bytes, InvalidMeasure},
+		"anon_thp":           {Base, Bytes, InvalidMeasure},
+		"file_thp":           {Base, Bytes, InvalidMeasure},
+		"shmem_thp":          {Base, Bytes, InvalidMeasure},
+		"inactive_anon":      {Base, Bytes, InvalidMeasure},
+		"active_anon":        {Base, Bytes, InvalidMeasure},
+		"inactive_file":      {Base, Bytes, InvalidMeasure},
+		"active_file":        {Base, Bytes, InvalidMeasure},
+		"unevictable":        {Base, Bytes, InvalidMeasure},
+		"slab_reclaimable":   {Base, Bytes, InvalidMeasure},
+		"slab_unreclaimable": {Base, Bytes, InvalidMeasure},
+		"slab":               {Base, Bytes, InvalidMeasure},
+		"pgfault":            {Base, Events, InvalidMeasure},
+		"pgmajfault":         {Base, Events, InvalidMeasure},
+	},
+	"memory.swap.events": {
+		"high": {Base, Events, InvalidMeasure},
+		"max":  {Base, Events, InvalidMeasure},
+		"fail": {Base, Events, InvalidMeasure},
+	},
+	"memory.events": {
+		"low":            {Base, Events, InvalidMeasure},
+		"high":           {Base, Events, InvalidMeasure},
+		"max":            {Base, Events, InvalidMeasure},
+		"oom":            {Base, Events, InvalidMeasure},
+		"oom_kill":       {Base, Events, InvalidMeasure},
+		"oom_group_kill": {Base, Events, InvalidMeasure},
+	},
+	"io.stat": {
+		"rbytes": {Base, Bytes, InvalidMeasure},
+		"wbytes": {Base, Bytes, InvalidMeasure},
+		"dbytes": {Base, Bytes, InvalidMeasure},
+		"rios":   {Base, Requests, InvalidMeasure},
+		"wios":   {Base, Requests, InvalidMeasure},
+		"dios":   {Base, Requests, InvalidMeasure},
+	},
+	"io.max": {
+		"rbps":  {Base, Bytes, Time},
+		"wbps":  {Base, Bytes, Time},
+		"riops": {Base, Requests, Time},
+		"wiops": {Base, Requests, Time},
+	},
+	"cpu.pressure": {
+		"avg10":  {Base, Percentage, InvalidMeasure},
+		"avg60":  {Base, Percentage, InvalidMeasure},
+		"avg300": {Base, Percentage, InvalidMeasure},
+		"total":  {Micro, Time, InvalidMeasure},
+	},
+	"memory.pressure": {
+		"avg10":  {Base, Percentage, InvalidMeasure},
+		"avg60":  {Base, Percentage, InvalidMeasure},
+		"avg300": {Base, Percentage, InvalidMeasure},
+		"total":  {Micro, Time, InvalidMeasure},
+	},
+	"io.pressure": {
+		"avg10":  {Base, Percentage, InvalidMeasure},
+		"avg60":  {Base, Percentage, InvalidMeasure},
+		"avg300": {Base, Percentage, InvalidMeasure},
+		"total":  {Micro, Time, InvalidMeasure},
+	},
+}
+
+// GetCgroupUnit returns the unit of a cgroup v2 interface file. For keyed files like cpu.stat,
+// the key selects the entry, for single value files like memory.current, key should be empty.
+// The file is the base name of the interface file, so the path to the cgroup can be included.
+// Each call returns a new unit, so callers can change it.
+func GetCgroupUnit(file, key string) (Unit, bool) {
+	name := filepath.Base(file)
+	if len(key) > 0 {
+		if keys, ok := CgroupKeyUnits[name]; ok {
+			if u, ok := keys[key]; ok {
+				return u.Unit(), true
+			}
+		}
+		return invalidUnitValue.Unit(), false
+	}
+	if u, ok := CgroupFileUnits[name]; ok {
+		return u.Unit(), true
+	}
+	for pattern, u := range CgroupFileUnits {
+		if match, err := filepath.Match(pattern, name); err == nil && match {
+			return u.Unit(), true
+		}
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// NewCgroupQuantity creates a quantity for a value read from a cgroup v2 interface file.
+// It returns an error if the unit of the file or key is unknown.
+func NewCgroupQuantity(file, key string, value float64) (Quantity, error) {
+	u, ok := GetCgroupUnit(file, key)
+	if !ok {
+		if len(key) > 0 {
+			return Quantity{Value: value, Unit: u}, fmt.Errorf("unknown unit for key '%s' in cgroup file '%s': %w", key, file, ErrInvalidMeasure)
+		}
+		return Quantity{Value: value, Unit: u}, fmt.Errorf("unknown unit for cgroup file '%s': %w", file, ErrInvalidMeasure)
+	}
+	return Quantity{Value: value, Unit: u}, nil
+}
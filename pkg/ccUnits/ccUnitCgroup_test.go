@@ -0,0 +1,106 @@
+package ccunits
+
+import "testing"
+
+func TestGetCgroupUnit(t *testing.T) {
+	for _, c := range []struct {
+		file     string
+		key      string
+		expected string // Empty for unknown files and keys
+	}{
+		{"memory.current", "", "B"},
+		{"/sys/fs/cgroup/slurm/job_42/memory.max", "", "B"},
+		{"memory.swap.peak", "", "B"},
+		{"hugetlb.2MB.current", "", "B"},
+		{"/sys/fs/cgroup/user.slice/hugetlb.1GB.max", "", "B"},
+		{"cpu.stat", "usage_usec", "us"},
+		{"/sys/fs/cgroup/cpu.stat", "nr_throttled", "events"},
+		{"memory.stat", "anon", "B"},
+		{"memory.stat", "pgmajfault", "events"},
+		{"memory.events", "oom_kill", "events"},
+		{"io.stat", "rbytes", "B"},
+		{"io.stat", "wios", "requests"},
+		{"io.max", "rbps", "B/s"},
+		{"io.max", "wiops", "requests/s"},
+		{"memory.pressure", "avg10", "%"},
+		{"io.pressure", "total", "us"},
+		{"memory.current", "anon", ""},
+		{"cpu.stat", "", ""},
+		{"cpu.stat", "unknown_usec", ""},
+		{"cpu.max", "", ""},
+		{"hugetlb.2MB.events", "", ""},
+		{"", "", ""},
+	} {
+		u, ok := GetCgroupUnit(c.file, c.key)
+		if ok != (len(c.expected) > 0) || ok && u.Short() != c.expected {
+			t.Errorf("Expected unit '%s' for key '%s' in cgroup file '%s' but got '%s' (%v)", c.expected, c.key, c.file, u.Short(), ok)
+		}
+		if !ok && u.Valid() {
+			t.Errorf("Expected invalid unit for key '%s' in cgroup file '%s'", c.key, c.file)
+		}
+	}
+}
+
+func TestNewCgroupQuantity(t *testing.T) {
+	for _, c := range []struct {
+		file          string
+		key           string
+		value         float64
+		out           string
+		expected      float64
+		errorExpected bool
+	}{
+		{"memory.current", "", 2147483648, "GiB", 2, false},
+		{"cpu.stat", "usage_usec", 1500000, "s", 1.5, false},
+		{"io.stat", "rbytes", 5e6, "MB", 5, false},
+		{"memory.pressure", "avg60", 12.5, "%", 12.5, false},
+		{"cpu.stat", "unknown", 1, "s", 0, true},
+		{"cgroup.procs", "", 1, "s", 0, true},
+	} {
+		q, err := NewCgroupQuantity(c.file, c.key, c.value)
+		if c.errorExpected {
+			if err == nil || q.Unit.Valid() {
+				t.Errorf("Expected error for key '%s' in cgroup file '%s' but got '%s'", c.key, c.file, q.String())
+			}
+			if q.Value != c.value {
+				t.Errorf("Expected unchanged value %v but got %v", c.value, q.Value)
+			}
+			continue
+		}
+		if err == nil {
+			q, err = q.ConvertTo(NewUnit(c.out))
+		}
+		if err != nil || q.Value != c.expected {
+			t.Errorf("Expected '%v %s' for key '%s' in cgroup file '%s' but got '%s': %v", c.expected, c.out, c.key, c.file, q.String(), err)
+		}
+	}
+}
+
+func TestCgroupUnitCopies(t *testing.T) {
+	// Changing a returned unit must not change the tables
+	for _, c := range [][2]string{{"memory.current", ""}, {"hugetlb.2MB.max", ""}, {"cpu.stat", "usage_usec"}} {
+		u, _ := GetCgroupUnit(c[0], c[1])
+		expected := u.Short()
+		u.SetPrefix(Giga)
+		if v, _ := GetCgroupUnit(c[0], c[1]); v.Short() != expected {
+			t.Errorf("Expected '%s' for key '%s' in cgroup file '%s' but got '%s'", expected, c[1], c[0], v.Short())
+		}
+	}
+}
+
+func TestCgroupUnitsParse(t *testing.T) {
+	// The short names of all units like 'us' of cpu.stat parse back to the same unit
+	check := func(name string, u UnitValue) {
+		if v := NewUnitValue(u.Short()); !v.Equal(u) {
+			t.Errorf("Expected '%s' of '%s' to parse to itself but got '%s'", u.Short(), name, v.Short())
+		}
+	}
+	for file, u := range CgroupFileUnits {
+		check(file, u)
+	}
+	for file, keys := range CgroupKeyUnits {
+		for key, u := range keys {
+			check(file+" "+key, u)
+		}
+	}
+}
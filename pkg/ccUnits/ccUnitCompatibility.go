@@ -0,0 +1,130 @@
+package ccunits
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CompatibilityMatrix contains which units can be converted into each other, so external
+// tools and UIs know the legal conversions without asking the backend. Convertibility does
+// not depend on the prefixes, so the matrix contains units without prefix.
+type CompatibilityMatrix struct {
+	Units []string   `json:"units"` // Units without prefix like 'B', 'B/s' or 'W'
+	Kinds [][]string `json:"kinds"` // Kind of the conversion from Units[i] to Units[j] ('factor', 'temperature' or 'non-linear'), empty if not convertible
+	index map[UnitValue]int
+}
+
+// defaultMatrixUnits returns the units without prefix of all measures and the rates per
+// second of data and counter measures like 'B/s' or 'Flops/s'
+func defaultMatrixUnits() []UnitValue {
+	registryLock.RLock()
+	measures := make([]Measure, 0, len(MeasuresMap))
+	for m := range MeasuresMap {
+		measures = append(measures, m)
+	}
+	registryLock.RUnlock()
+	sort.Slice(measures, func(i, j int) bool { return measures[i] < measures[j] })
+
+	units := make([]UnitValue, 0, 2*len(measures))
+	for _, m := range measures {
+		units = append(units, UnitValue{prefix: Base, measure: m, divMeasure: InvalidMeasure})
+	}
+	for _, m := range measures {
+		if md, ok := Metadata(m); ok && (md.Counter || md.Category == CategoryData) && m != Time {
+			units = append(units, UnitValue{prefix: Base, measure: m, divMeasure: Time})
+		}
+	}
+	return units
+}
+
+// NewCompatibilityMatrix creates the compatibility matrix between the units. Without units,
+// the matrix contains the units without prefix of all measures and the rates per second of
+// data and counter measures like 'B/s'. Prefixes of the given units are removed. It returns
+// an error for invalid units.
+func NewCompatibilityMatrix(units ...string) (CompatibilityMatrix, error) {
+	var values []UnitValue
+	if len(units) == 0 {
+		values = defaultMatrixUnits()
+	} else {
+		values = make([]UnitValue, 0, len(units))
+		seen := make(map[UnitValue]bool, len(units))
+		for _, s := range units {
+			v := InternUnit(s)
+			if !v.Valid() {
+				return CompatibilityMatrix{}, invalidUnitError(s)
+			}
+			if v = v.Base(); !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	m := CompatibilityMatrix{
+		Units: make([]string, len(values)),
+		Kinds: make([][]string, len(values)),
+		index: make(map[UnitValue]int, len(values)),
+	}
+	for i, in := range values {
+		m.Units[i] = in.Short()
+		m.index[in] = i
+		m.Kinds[i] = make([]string, len(values))
+		for j, out := range values {
+			if kind, err := converterKindOf(in, out); err == nil {
+				m.Kinds[i][j] = kind.String()
+			}
+		}
+	}
+	return m, nil
+}
+
+// lookup returns the index of a unit in the matrix
+func (m CompatibilityMatrix) lookup(unitStr string) (int, error) {
+	v := InternUnit(unitStr)
+	if !v.Valid() {
+		return -1, invalidUnitError(unitStr)
+	}
+	if m.index != nil {
+		if i, ok := m.index[v.Base()]; ok {
+			return i, nil
+		}
+	} else {
+		// Matrix decoded from JSON
+		for i, s := range m.Units {
+			if InternUnit(s) == v.Base() {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("unit '%s' is not contained in the compatibility matrix: %w", unitStr, ErrInvalidMeasure)
+}
+
+// Convertible checks whether values can be converted from one unit to the other. Both units
+// are given with any prefix like 'MB/s' and 'GiB/s'. It returns an error for invalid units
+// and units without prefix that are not contained in the matrix.
+func (m CompatibilityMatrix) Convertible(in string, out string) (bool, error) {
+	i, err := m.lookup(in)
+	if err != nil {
+		return false, err
+	}
+	j, err := m.lookup(out)
+	if err != nil {
+		return false, err
+	}
+	return len(m.Kinds[i][j]) > 0, nil
+}
+
+// ConvertibleTo returns the units of the matrix into which values of the unit can be
+// converted, including the unit without prefix itself
+func (m CompatibilityMatrix) ConvertibleTo(unitStr string) ([]string, error) {
+	i, err := m.lookup(unitStr)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0)
+	for j, kind := range m.Kinds[i] {
+		if len(kind) > 0 {
+			out = append(out, m.Units[j])
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package ccunits
+
+import "testing"
+
+func TestCompatibilityMatrix(t *testing.T) {
+	m, err := NewCompatibilityMatrix()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, c := range []struct {
+		in       string
+		out      string
+		expected bool
+	}{
+		{"MB/s", "GiB/s", true},
+		{"kJ/s", "mW", true},
+		{"degC", "degF", true},
+		{"dBm", "W", true},
+		{"%", "ratio", true},
+		{"B", "B/s", false},
+		{"W", "J", false},
+	} {
+		ok, err := m.Convertible(c.in, c.out)
+		if err != nil || ok != c.expected {
+			t.Errorf("Expected convertible %v for '%s' to '%s' but got %v: %v", c.expected, c.in, c.out, ok, err)
+		}
+	}
+	if _, err := m.Convertible("xyz", "B"); err == nil {
+		t.Errorf("Expected error for invalid unit")
+	}
+	if _, err := m.Convertible("V/A", "B"); err == nil {
+		t.Errorf("Expected error for unit not contained in the matrix")
+	}
+	if to, err := m.ConvertibleTo("%"); err != nil || len(to) != 2 {
+		t.Errorf("Expected '%%' and 'ratio' but got %v: %v", to, err)
+	}
+	if _, err := NewCompatibilityMatrix("MB", "xyz"); err == nil {
+		t.Errorf("Expected error for invalid unit")
+	}
+}
@@ -0,0 +1,87 @@
+package ccunits
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// compositeTimeUnits contains the short time units of durations like '1h 30m' which the
+// unit parser reads differently ('m' is no unit on its own)
+var compositeTimeUnits = map[string]UnitValue{
+	"m": {Base, Minutes, InvalidMeasure},
+}
+
+// compositeUnit returns a new unit of a part of a composite quantity
+func compositeUnit(unitStr string) Unit {
+	if u, ok := compositeTimeUnits[unitStr]; ok {
+		return u.Unit()
+	}
+	return NewUnit(unitStr)
+}
+
+// compositePart splits the next part like '30m' or '512 MiB' off a composite quantity
+func compositePart(s string) (value string, unitStr string, rest string) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	n := numberPrefixLen(s)
+	value, s = s[:n], strings.TrimLeftFunc(s[n:], unicode.IsSpace)
+	end := strings.IndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsDigit(r) })
+	if end < 0 {
+		end = len(s)
+	}
+	return value, s[:end], s[end:]
+}
+
+// ParseCompositeQuantity parses human inputs with several parts like '1h 30m', '2d 4h' or
+// '1 GiB 512 MiB' into a single quantity, as wallclock limits and memory requests are often
+// written in job scripts and configurations. Every part needs a value and a unit, the
+// whitespace between the parts is optional ('1h30m'). Durations accept 'd', 'h', 'm' (minutes),
+// 's', 'ms', 'us' and 'ns'. The result is the sum of the parts in the smallest unit of the
+// parts, like '90 min' for '1h 30m' or '1536 MiB' for '1 GiB 512 MiB'. It returns an error
+// for missing values or units and parts which cannot be converted into each other.
+func ParseCompositeQuantity(s string) (Quantity, error) {
+	type part struct {
+		value float64
+		unit  Unit
+	}
+	var parts []part
+	for rest := strings.TrimSpace(s); len(rest) > 0; {
+		var valueStr, unitStr string
+		valueStr, unitStr, rest = compositePart(rest)
+		rest = strings.TrimSpace(rest)
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid value in quantity '%s': %w", s, ErrInvalidValue)
+		}
+		u := compositeUnit(unitStr)
+		if !u.Valid() {
+			return Quantity{Value: 0, Unit: u}, fmt.Errorf("invalid unit '%s' in quantity '%s': %w", unitStr, s, ErrInvalidMeasure)
+		}
+		parts = append(parts, part{value: value, unit: u})
+	}
+	if len(parts) == 0 {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("empty quantity: %w", ErrInvalidMeasure)
+	}
+
+	// The smallest unit has the largest factor from the unit of the first part
+	out, outFactor := parts[0].unit, 1.0
+	for _, p := range parts[1:] {
+		conv, err := NewConverter(parts[0].unit, p.unit)
+		if err != nil {
+			return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("parts '%s' and '%s' of quantity '%s' cannot be combined: %w", parts[0].unit.Short(), p.unit.Short(), s, ErrIncompatibleMeasure)
+		}
+		if f := conv.Factor(); f > outFactor {
+			out, outFactor = p.unit, f
+		}
+	}
+	sum := Quantity{Value: 0, Unit: out}
+	for _, p := range parts {
+		q, err := Quantity{Value: p.value, Unit: p.unit}.ConvertTo(out)
+		if err != nil {
+			return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, err
+		}
+		sum.Value += q.Value
+	}
+	return sum, nil
+}
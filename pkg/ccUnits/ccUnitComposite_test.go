@@ -0,0 +1,37 @@
+package ccunits
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCompositeQuantity(t *testing.T) {
+	for s, e := range map[string]string{
+		"1h 30m":        "90 min",
+		"2d 4h":         "52 h",
+		"1h30m15s":      "5415 s",
+		"1 GiB 512 MiB": "1536 MiB",
+		"300 ms":        "300 ms",
+		"1m 500ms":      "60500 ms",
+		"1ms 500us":     "1500 us",
+		"2µs 30ns":      "2030 ns",
+		"1s 1μs":        "1.000001e+06 us",
+	} {
+		q, err := ParseCompositeQuantity(s)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", s, err)
+			continue
+		}
+		if q.String() != e {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", e, s, q.String())
+		}
+	}
+	for _, s := range []string{"", "1h 30", "h 30m", "1h xyz"} {
+		if q, err := ParseCompositeQuantity(s); err == nil || q.Unit.Valid() {
+			t.Errorf("Expected error and invalid unit for '%s' but got '%s'", s, q.String())
+		}
+	}
+	if _, err := ParseCompositeQuantity("1 GiB 30m"); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package ccunits
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Decoding of configuration values with unit like max_bandwidth: "25 GB/s" or
+// MEMORY_LIMIT=64GiB into quantities, so invalid values are rejected at startup instead of
+// being interpreted in a wrong unit later. JSON and YAML decode them directly with
+// Quantity.UnmarshalText(), mapstructure (and viper) with QuantityDecodeHook().
+
+// numberPrefixLen returns the length of the longest prefix of a quantity string which is a
+// number like '64' of '64GiB' or '1.5e3' of '1.5e3W'
+func numberPrefixLen(s string) int {
+	end := 0
+	for end < len(s) && strings.IndexByte("0123456789+-.eE", s[end]) >= 0 {
+		end++
+	}
+	for ; end > 0; end-- {
+		if _, err := strconv.ParseFloat(s[:end], 64); err == nil {
+			return end
+		}
+	}
+	return 0
+}
+
+// ParseQuantity parses a value with unit like '25 GB/s', '64GiB' or '1.5e3 W'. Value and
+// unit may be separated by whitespace. It returns an error for missing values and invalid
+// units.
+func ParseQuantity(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	valueStr, unitStr, hasSpace := strings.Cut(s, " ")
+	if !hasSpace {
+		n := numberPrefixLen(s)
+		valueStr, unitStr = s[:n], s[n:]
+	}
+	unitStr = strings.TrimSpace(unitStr)
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid value in quantity '%s': %w", s, ErrInvalidValue)
+	}
+	u := NewUnit(unitStr)
+	if !u.Valid() {
+		return Quantity{Value: value, Unit: u}, fmt.Errorf("invalid unit '%s' in quantity '%s': %w", unitStr, s, ErrInvalidMeasure)
+	}
+	return Quantity{Value: value, Unit: u}, nil
+}
+
+// ParseQuantityAs parses a quantity like ParseQuantity() and checks that it can be converted
+// to the expected unit, like a bandwidth limit to 'B/s'. The quantity is returned in its
+// parsed unit.
+func ParseQuantityAs(s string, expected string) (Quantity, error) {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		return q, err
+	}
+	out := NewUnit(expected)
+	if !out.Valid() {
+		return q, invalidUnitError(expected)
+	}
+	if !q.Unit.Compatible(out) {
+		return q, fmt.Errorf("quantity '%s' cannot be converted to '%s': %w", s, out.Short(), ErrIncompatibleMeasure)
+	}
+	return q, nil
+}
+
+// MarshalText writes the quantity like String(), e.g. '12.5 GB/s'
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalText parses a quantity with ParseQuantity(). It returns an error for missing
+// values and invalid units.
+func (q *Quantity) UnmarshalText(text []byte) error {
+	v, err := ParseQuantity(string(text))
+	if err != nil {
+		return err
+	}
+	*q = v
+	return nil
+}
+
+var (
+	quantityType  = reflect.TypeOf(Quantity{})
+	unitType      = reflect.TypeOf((*Unit)(nil)).Elem()
+	unitValueType = reflect.TypeOf(UnitValue{})
+)
+
+// QuantityDecodeHook converts strings to quantities ('25 GB/s'), units ('GB/s') and unit
+// values while decoding configurations with mapstructure. It has the signature of
+// mapstructure.DecodeHookFuncType, so no dependency on mapstructure is needed:
+//
+//	viper.Unmarshal(&config, viper.DecodeHook(ccunits.QuantityDecodeHook))
+//
+// Numbers decoded into quantities have no unit and are rejected. Other values are returned
+// unchanged.
+func QuantityDecodeHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != quantityType && to != unitType && to != unitValueType {
+		return data, nil
+	}
+	if from.Kind() != reflect.String {
+		if to == quantityType && from.Kind() != reflect.Map && from.Kind() != reflect.Struct {
+			return data, fmt.Errorf("quantity %v without unit: %w", data, ErrInvalidMeasure)
+		}
+		return data, nil
+	}
+	s := reflect.ValueOf(data).String()
+	switch to {
+	case quantityType:
+		return ParseQuantity(s)
+	case unitType:
+		u := NewUnit(s)
+		if !u.Valid() {
+			return data, invalidUnitError(s)
+		}
+		return u, nil
+	default:
+		v := InternUnit(s)
+		if !v.Valid() {
+			return data, invalidUnitError(s)
+		}
+		return v, nil
+	}
+}
+
+// LookupQuantityEnv parses the environment variable with a quantity like MEMORY_LIMIT=64GiB.
+// If the expected unit is not empty, the quantity must be convertible to it (see
+// ParseQuantityAs()). It returns false if the variable is not set.
+func LookupQuantityEnv(name string, expected string) (Quantity, bool, error) {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, false, nil
+	}
+	var q Quantity
+	var err error
+	if len(expected) > 0 {
+		q, err = ParseQuantityAs(s, expected)
+	} else {
+		q, err = ParseQuantity(s)
+	}
+	if err != nil {
+		return q, true, fmt.Errorf("%s: %w", name, err)
+	}
+	return q, true, nil
+}
+
+// QuantityFromEnv returns the quantity of an environment variable like LookupQuantityEnv()
+// or the default quantity if the variable is not set
+func QuantityFromEnv(name string, expected string, def Quantity) (Quantity, error) {
+	q, ok, err := LookupQuantityEnv(name, expected)
+	if !ok {
+		return def, nil
+	}
+	return q, err
+}
@@ -0,0 +1,98 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseQuantity(t *testing.T) {
+	for s, e := range map[string]string{
+		"25 GB/s":  "25 GB/s",
+		"64GiB":    "64 GiB",
+		"1.5e3W":   "1500 W",
+		" 300 ms ": "300 ms",
+		"2EB":      "2 EB",
+	} {
+		q, err := ParseQuantity(s)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", s, err)
+			continue
+		}
+		if q.String() != e {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", e, s, q.String())
+		}
+	}
+	for _, s := range []string{"", "GB", "25 xyz", "abc 3"} {
+		if q, err := ParseQuantity(s); err == nil || q.Unit.Valid() {
+			t.Errorf("Expected error and invalid unit for '%s' but got '%s'", s, q.String())
+		}
+	}
+	if _, err := ParseQuantityAs("25 GB/s", "B/s"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := ParseQuantityAs("25 GB", "B/s"); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+}
+
+func TestQuantityConfig(t *testing.T) {
+	var config struct {
+		MaxBandwidth Quantity `json:"max_bandwidth" yaml:"max_bandwidth"`
+	}
+	if err := json.Unmarshal([]byte(`{"max_bandwidth": "25 GB/s"}`), &config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.MaxBandwidth.String() != "25 GB/s" {
+		t.Errorf("Expected '25 GB/s' but got '%s'", config.MaxBandwidth.String())
+	}
+	if err := yaml.Unmarshal([]byte("max_bandwidth: 64GiB\n"), &config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.MaxBandwidth.String() != "64 GiB" {
+		t.Errorf("Expected '64 GiB' but got '%s'", config.MaxBandwidth.String())
+	}
+	if err := json.Unmarshal([]byte(`{"max_bandwidth": "25 xyz"}`), &config); !errors.Is(err, ErrInvalidMeasure) {
+		t.Errorf("Expected ErrInvalidMeasure but got %v", err)
+	}
+	data, err := json.Marshal(config)
+	if err != nil || string(data) != `{"max_bandwidth":"64 GiB"}` {
+		t.Errorf("Unexpected encoding '%s' (%v)", data, err)
+	}
+
+	out, err := QuantityDecodeHook(reflect.TypeOf(""), quantityType, "1.5 kW")
+	if q, ok := out.(Quantity); err != nil || !ok || q.String() != "1.5 KW" {
+		t.Errorf("Unexpected result %v (%v)", out, err)
+	}
+	out, err = QuantityDecodeHook(reflect.TypeOf(""), unitType, "MB/s")
+	if u, ok := out.(Unit); err != nil || !ok || u.Short() != "MB/s" {
+		t.Errorf("Unexpected result %v (%v)", out, err)
+	}
+	if _, err := QuantityDecodeHook(reflect.TypeOf(0), quantityType, 5); err == nil {
+		t.Errorf("Expected error for quantity without unit")
+	}
+	if out, err := QuantityDecodeHook(reflect.TypeOf(""), reflect.TypeOf(""), "x"); err != nil || out != "x" {
+		t.Errorf("Expected unchanged value but got %v (%v)", out, err)
+	}
+}
+
+func TestQuantityFromEnv(t *testing.T) {
+	t.Setenv("CCUNITS_TEST_LIMIT", "64GiB")
+	q, err := QuantityFromEnv("CCUNITS_TEST_LIMIT", "B", NewQuantity(1, "GB"))
+	if err != nil || q.String() != "64 GiB" {
+		t.Errorf("Unexpected result %s (%v)", q.String(), err)
+	}
+	if _, err := QuantityFromEnv("CCUNITS_TEST_LIMIT", "W", NewQuantity(1, "W")); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+	if q, ok, err := LookupQuantityEnv("CCUNITS_TEST_UNSET", ""); ok || err != nil || q.Unit.Valid() {
+		t.Errorf("Expected unset variable with invalid unit but got %s (%v, %v)", q.String(), ok, err)
+	}
+	q, err = QuantityFromEnv("CCUNITS_TEST_UNSET", "", NewQuantity(1, "GB"))
+	if err != nil || q.String() != "1 GB" {
+		t.Errorf("Expected default but got %s (%v)", q.String(), err)
+	}
+}
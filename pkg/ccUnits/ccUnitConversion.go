@@ -0,0 +1,63 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// ConversionFunc converts a value between two measures without prefix with a formula which
+// is not a multiplication, like the logarithmic dBm to W
+type ConversionFunc func(value float64) float64
+
+// conversions are the non-linear conversions between measures. They start with the built-in
+// conversions of the logarithmic measures.
+var conversions = map[[2]Measure]ConversionFunc{
+	{Decibel, Ratio}:         func(v float64) float64 { return math.Pow(10, v/10) },
+	{Ratio, Decibel}:         func(v float64) float64 { return 10 * math.Log10(v) },
+	{DecibelMilliwatt, Watt}: func(v float64) float64 { return math.Pow(10, v/10) / 1000 },
+	{Watt, DecibelMilliwatt}: func(v float64) float64 { return 10 * math.Log10(v*1000) },
+}
+
+// lookupConversion returns the non-linear conversion between two measures
+func lookupConversion(in Measure, out Measure) (ConversionFunc, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	fn, ok := conversions[[2]Measure{in, out}]
+	return fn, ok
+}
+
+// RegisterConversion adds a non-linear conversion between two measures with the formula
+// for each direction. The formulas work on values without prefix; prefixes of the units are
+// applied before and after, so a conversion from dBm to W also converts dBm to mW.
+func RegisterConversion(in Measure, out Measure, forward ConversionFunc, backward ConversionFunc) error {
+	if forward == nil || backward == nil {
+		return fmt.Errorf("conversion between '%s' and '%s' requires both formulas: %w", in.String(), out.String(), ErrIncompatibleMeasure)
+	}
+	if in == out {
+		return fmt.Errorf("invalid conversion of '%s' to itself: %w", in.String(), ErrIncompatibleMeasure)
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	for _, m := range []Measure{in, out} {
+		if _, ok := MeasuresMap[m]; !ok {
+			return fmt.Errorf("invalid measure %d for conversion: %w", int(m), ErrInvalidMeasure)
+		}
+	}
+	conversions[[2]Measure{in, out}] = forward
+	conversions[[2]Measure{out, in}] = backward
+	registryChanged()
+	return nil
+}
+
+// nonLinear is a non-linear conversion with the prefix factors to and from the values
+// without prefix
+type nonLinear struct {
+	in  prefixFactor
+	fn  ConversionFunc
+	out prefixFactor
+}
+
+// apply converts a value
+func (n *nonLinear) apply(v float64) float64 {
+	return n.fn(v*n.in.factor) * n.out.factor
+}
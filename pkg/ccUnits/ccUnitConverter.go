@@ -0,0 +1,344 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// converterKind selects the formula of a Converter
+type converterKind int
+
+const (
+	converterFactor    converterKind = iota // Multiplication with the prefix and scale factor
+	converterTempC2F                        // Celsius to Fahrenheit
+	converterTempF2C                        // Fahrenheit to Celsius
+	converterNonLinear                      // Registered formula like dBm to W
+)
+
+// String returns the name of the formula like 'factor' or 'non-linear'
+func (k converterKind) String() string {
+	switch k {
+	case converterFactor:
+		return "factor"
+	case converterTempC2F, converterTempF2C:
+		return "temperature"
+	case converterNonLinear:
+		return "non-linear"
+	}
+	return "unknown"
+}
+
+// Converter converts values from one unit to another. In contrast to the conversion
+// functions returned by GetUnitUnitFactor() it works on typed values without boxing them
+// in interface{}, so ApplyFloat64() and ApplyInt64() can be inlined in tight loops.
+// The zero value converts nothing (factor 0), use NewConverter() or NewPrefixConverter().
+type Converter struct {
+	kind converterKind
+	pf   prefixFactor // Prefix factor, for temperatures from the input prefix to Base
+	post prefixFactor // Temperatures: prefix factor from Base to the output prefix
+	nl   *nonLinear   // Formula of non-linear conversions
+}
+
+// converterKindOf checks whether two units are convertible and returns the formula. Units
+// with the same dimension like 'J/s' and 'W', ratios and percentages or temperature rates
+// like 'degC/s' and 'degF/s' are convertible by a factor, temperatures without denominator
+// by the Celsius/Fahrenheit formula and units of registered measures only if they have the
+// same measures.
+func converterKindOf(in UnitValue, out UnitValue) (converterKind, error) {
+	plain := in.GetUnitDenominator() == InvalidMeasure && out.GetUnitDenominator() == InvalidMeasure
+	switch {
+	case plain && in.GetMeasure() == TemperatureC && out.GetMeasure() == TemperatureF:
+		return converterTempC2F, nil
+	case plain && in.GetMeasure() == TemperatureF && out.GetMeasure() == TemperatureC:
+		return converterTempF2C, nil
+	case plain && in.GetMeasure() != out.GetMeasure():
+		if _, ok := lookupConversion(in.GetMeasure(), out.GetMeasure()); ok {
+			return converterNonLinear, nil
+		}
+	}
+	inDim, _, inOk := unitDimension(in)
+	outDim, _, outOk := unitDimension(out)
+	switch {
+	case inOk && outOk && inDim == outDim:
+		return converterFactor, nil
+	case inOk && outOk:
+	case in.GetMeasure() == out.GetMeasure() && in.GetUnitDenominator() == out.GetUnitDenominator():
+		return converterFactor, nil
+	}
+	return converterFactor, fmt.Errorf("invalid measures in in and out Unit: %w", ErrIncompatibleMeasure)
+}
+
+// scaleRatio returns the factor between the scales of two convertible units like 0.01 from
+// percentages to ratios
+func scaleRatio(in UnitValue, out UnitValue) float64 {
+	_, inScale, inOk := unitDimension(in)
+	_, outScale, outOk := unitDimension(out)
+	if !inOk || !outOk {
+		return 1
+	}
+	return inScale / outScale
+}
+
+// NewPrefixConverter creates the converter between two prefixes
+func NewPrefixConverter(in Prefix, out Prefix) Converter {
+	return Converter{
+		kind: converterFactor,
+		pf:   getPrefixFactor(in, out),
+	}
+}
+
+// NewConverter creates the converter for unit to unit conversion. It returns an error if
+// the units are not convertible. Temperatures with prefixes like millidegree Celsius are
+// converted to the unit without prefix before the Celsius/Fahrenheit formula is applied.
+// Units with the same dimension are converted with the prefix factor times the ratio of
+// their scales like 100 from ratios (0-1) to percentages (0-100) and measures with a
+// registered conversion like dBm and W with its formula.
+func NewConverter(in Unit, out Unit) (Converter, error) {
+	kind, err := converterKindOf(ValueOf(in), ValueOf(out))
+	if err != nil {
+		return Converter{}, err
+	}
+	if kind == converterNonLinear {
+		fn, ok := lookupConversion(in.GetMeasure(), out.GetMeasure())
+		if !ok {
+			return Converter{}, fmt.Errorf("no conversion from '%s' to '%s': %w", in.Short(), out.Short(), ErrIncompatibleMeasure)
+		}
+		return Converter{kind: kind, nl: &nonLinear{
+			in:  getPrefixFactor(in.GetPrefix(), Base),
+			fn:  fn,
+			out: getPrefixFactor(Base, out.GetPrefix()),
+		}}, nil
+	}
+	if kind != converterFactor {
+		return Converter{
+			kind: kind,
+			pf:   getPrefixFactor(in.GetPrefix(), Base),
+			post: getPrefixFactor(Base, out.GetPrefix()),
+		}, nil
+	}
+	return Converter{
+		kind: converterFactor,
+		pf:   scaledFactor(getPrefixFactor(in.GetPrefix(), out.GetPrefix()), scaleRatio(ValueOf(in), ValueOf(out))),
+	}, nil
+}
+
+// Factor returns the raw factor of the conversion. Converted values are value * Factor() + Offset().
+// Non-linear conversions have no factor (NaN).
+func (c Converter) Factor() float64 {
+	switch c.kind {
+	case converterNonLinear:
+		return math.NaN()
+	case converterTempC2F:
+		return c.pf.factor * 1.8 * c.post.factor
+	case converterTempF2C:
+		return c.pf.factor / 1.8 * c.post.factor
+	}
+	return c.pf.factor
+}
+
+// Offset returns the offset of the conversion which is only set for temperatures. Non-linear
+// conversions have no offset (NaN).
+func (c Converter) Offset() float64 {
+	switch c.kind {
+	case converterNonLinear:
+		return math.NaN()
+	case converterTempC2F:
+		return 32 * c.post.factor
+	case converterTempF2C:
+		return -32 / 1.8 * c.post.factor
+	}
+	return 0
+}
+
+// applyFormula converts a value with the formula of temperatures or non-linear conversions
+func (c Converter) applyFormula(v float64) float64 {
+	switch c.kind {
+	case converterTempC2F:
+		return ((v*c.pf.factor)*1.8 + 32) * c.post.factor
+	case converterNonLinear:
+		return c.nl.apply(v)
+	}
+	return ((v*c.pf.factor - 32) / 1.8) * c.post.factor
+}
+
+// ApplyFloat64 converts a floating point value
+func (c Converter) ApplyFloat64(v float64) float64 {
+	if c.kind == converterFactor {
+		return v * c.pf.factor
+	}
+	return c.applyFormula(v)
+}
+
+// ApplyInt64 converts an integer value. Integer factors between prefixes like Kilo and
+// Mega are applied without the detour via float64.
+func (c Converter) ApplyInt64(v int64) int64 {
+	if c.kind == converterFactor {
+		return c.pf.applyInt64(v)
+	}
+	return int64(c.applyFormula(float64(v)))
+}
+
+// ApplyUint64 converts an unsigned integer value
+func (c Converter) ApplyUint64(v uint64) uint64 {
+	if c.kind == converterFactor {
+		return c.pf.applyUint64(v)
+	}
+	return uint64(c.applyFormula(float64(v)))
+}
+
+// applyExactInteger converts an integer value of any type if the conversion has an exact
+// integer factor like from KByte to Byte and returns it with the same type. It returns false
+// for other values, other conversions and results which do not fit into the type.
+func (c Converter) applyExactInteger(value interface{}) (interface{}, bool) {
+	if c.kind != converterFactor || c.pf.mul <= 0 {
+		return value, false
+	}
+	switch v := value.(type) {
+	case int:
+		r, ok := c.exactInt64(int64(v), math.MinInt, math.MaxInt)
+		return int(r), ok
+	case int32:
+		r, ok := c.exactInt64(int64(v), math.MinInt32, math.MaxInt32)
+		return int32(r), ok
+	case int64:
+		r, ok := c.exactInt64(v, math.MinInt64, math.MaxInt64)
+		return r, ok
+	case uint:
+		r, ok := c.exactUint64(uint64(v), math.MaxUint)
+		return uint(r), ok
+	case uint32:
+		r, ok := c.exactUint64(uint64(v), math.MaxUint32)
+		return uint32(r), ok
+	case uint64:
+		r, ok := c.exactUint64(v, math.MaxUint64)
+		return r, ok
+	}
+	return value, false
+}
+
+// exactInt64 converts an integer value with the integer factor if the result lies between
+// min and max
+func (c Converter) exactInt64(v int64, min int64, max int64) (int64, bool) {
+	if v > max/c.pf.mul || v < min/c.pf.mul {
+		return v, false
+	}
+	return c.ApplyInt64(v), true
+}
+
+// exactUint64 converts an unsigned integer value with the integer factor if the result is
+// not larger than max
+func (c Converter) exactUint64(v uint64, max uint64) (uint64, bool) {
+	if v > max/uint64(c.pf.mul) {
+		return v, false
+	}
+	return c.ApplyUint64(v), true
+}
+
+// CheckedInt64 converts an integer value like ApplyInt64() but returns an error wrapping
+// ErrOverflow instead of a wrapped around value if the result does not fit into int64
+func (c Converter) CheckedInt64(v int64) (int64, error) {
+	if c.kind == converterFactor && c.pf.mul > 0 {
+		if v > math.MaxInt64/c.pf.mul || v < math.MinInt64/c.pf.mul {
+			return 0, fmt.Errorf("cannot convert %d with factor %d: %w", v, c.pf.mul, ErrOverflow)
+		}
+		return v * c.pf.mul, nil
+	}
+	if c.kind == converterFactor && c.pf.div > 0 {
+		return v / c.pf.div, nil
+	}
+	f := math.Round(c.ApplyFloat64(float64(v)))
+	if math.IsNaN(f) || f >= math.Ldexp(1, 63) || f < -math.Ldexp(1, 63) {
+		return 0, fmt.Errorf("cannot convert %d: %w", v, ErrOverflow)
+	}
+	return int64(f), nil
+}
+
+// Apply converts a value of any numeric type and returns it with the same type. Values of
+// other types are returned unchanged. It behaves like the functions returned by
+// GetUnitUnitFactor().
+func (c Converter) Apply(value interface{}) interface{} {
+	unprefixed := c.pf.factor == 1 && c.post.factor == 1
+	switch {
+	case c.kind == converterTempC2F && unprefixed:
+		return convertTempC2TempF(value)
+	case c.kind == converterTempF2C && unprefixed:
+		return convertTempF2TempC(value)
+	case c.kind != converterFactor:
+		return c.applyFormulaValue(value)
+	}
+	switch v := value.(type) {
+	case float64:
+		return v * c.pf.factor
+	case float32:
+		return float32(float64(v) * c.pf.factor)
+	case int:
+		return int(c.pf.applyInt64(int64(v)))
+	case int32:
+		return int32(c.pf.applyInt64(int64(v)))
+	case int64:
+		return c.pf.applyInt64(v)
+	case uint:
+		return uint(c.pf.applyUint64(uint64(v)))
+	case uint32:
+		return uint32(c.pf.applyUint64(uint64(v)))
+	case uint64:
+		return c.pf.applyUint64(v)
+	}
+	return value
+}
+
+// applyFormulaValue converts a value of any numeric type with the formula and returns it
+// with the same type
+func (c Converter) applyFormulaValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return c.applyFormula(v)
+	case float32:
+		return float32(c.applyFormula(float64(v)))
+	case int:
+		return int(c.applyFormula(float64(v)))
+	case int32:
+		return int32(c.applyFormula(float64(v)))
+	case int64:
+		return int64(c.applyFormula(float64(v)))
+	case uint:
+		return uint(c.applyFormula(float64(v)))
+	case uint32:
+		return uint32(c.applyFormula(float64(v)))
+	case uint64:
+		return uint64(c.applyFormula(float64(v)))
+	}
+	return value
+}
+
+// RoundTripResult is the result of CheckRoundTrip()
+type RoundTripResult struct {
+	MaxRelativeError float64 // Largest relative error of all samples (absolute error for samples of 0)
+	WorstSample      float64 // Sample with the largest error
+}
+
+// CheckRoundTrip converts the samples from the in unit to the out unit and back and reports
+// the maximum relative error, so pipelines can verify that chosen target units do not
+// introduce unacceptable loss. It returns an error if the units are not convertible.
+func CheckRoundTrip(in Unit, out Unit, samples []float64) (RoundTripResult, error) {
+	var res RoundTripResult
+	forward, err := NewConverter(in, out)
+	if err != nil {
+		return res, err
+	}
+	backward, err := NewConverter(out, in)
+	if err != nil {
+		return res, err
+	}
+	for _, v := range samples {
+		e := math.Abs(backward.ApplyFloat64(forward.ApplyFloat64(v)) - v)
+		if v != 0 {
+			e /= math.Abs(v)
+		}
+		if e > res.MaxRelativeError || math.IsNaN(e) {
+			res.MaxRelativeError = e
+			res.WorstSample = v
+		}
+	}
+	return res, nil
+}
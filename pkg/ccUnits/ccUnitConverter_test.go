@@ -0,0 +1,246 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConverter(t *testing.T) {
+	conv, err := NewConverter(NewUnit("kB"), NewUnit("MB"))
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	if v := conv.ApplyFloat64(2500); v != 2.5 {
+		t.Errorf("ApplyFloat64(2500) = %v instead of 2.5", v)
+	}
+	if v := conv.ApplyInt64(3000); v != 3 {
+		t.Errorf("ApplyInt64(3000) = %v instead of 3", v)
+	}
+	if v := conv.Apply(uint32(4000)); v != uint32(4) {
+		t.Errorf("Apply(uint32(4000)) = %v instead of 4", v)
+	}
+	if conv.Factor() != 0.001 || conv.Offset() != 0 {
+		t.Errorf("Unexpected factor %v and offset %v", conv.Factor(), conv.Offset())
+	}
+
+	conv, err = NewConverter(NewUnit("degC"), NewUnit("degF"))
+	if err != nil {
+		t.Fatalf("Failed to create converter: %v", err)
+	}
+	if v := conv.ApplyFloat64(100); v != 212 {
+		t.Errorf("ApplyFloat64(100) = %v instead of 212", v)
+	}
+	if v := conv.ApplyFloat64(0); v != conv.Factor()*0+conv.Offset() {
+		t.Errorf("ApplyFloat64(0) = %v does not match the offset %v", v, conv.Offset())
+	}
+
+	if _, err := NewConverter(NewUnit("MB"), NewUnit("GHz")); err == nil {
+		t.Errorf("Expected error for converting 'MB' to 'GHz'")
+	}
+
+	// Converters and conversion functions return the same values
+	in, out := NewUnit("KiB/s"), NewUnit("MB/s")
+	conv, _ = NewConverter(in, out)
+	f, _ := GetUnitUnitFactor(in, out)
+	for _, v := range []interface{}{float64(1234), int64(1 << 20), uint(1 << 30)} {
+		if a, b := conv.Apply(v), f(v); a != b {
+			t.Errorf("Converter returns %v and conversion function %v for %v", a, b, v)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { conv.ApplyFloat64(1234) }); allocs != 0 {
+		t.Errorf("Expected no allocations for ApplyFloat64() but got %v", allocs)
+	}
+}
+
+func TestConverterSlices(t *testing.T) {
+	conv := NewPrefixConverter(Kilo, Mega)
+	floats := make([]float64, 19)
+	ints := make([]int64, 19)
+	for i := range floats {
+		floats[i] = float64(i * 1000)
+		ints[i] = int64(i * 1000)
+	}
+	conv.ApplyFloat64s(floats)
+	conv.ApplyInt64s(ints)
+	for i := range floats {
+		if floats[i] != float64(i) || ints[i] != int64(i) {
+			t.Errorf("Value %d converted to %v and %v", i, floats[i], ints[i])
+		}
+	}
+	uints := []uint64{1, 2, 3}
+	NewPrefixConverter(Mebi, Kibi).ApplyUint64s(uints)
+	if uints[0] != 1024 || uints[2] != 3072 {
+		t.Errorf("Unexpected values %v", uints)
+	}
+	temps := []float64{0, 100}
+	if conv, err := NewConverter(NewUnit("degC"), NewUnit("degF")); err == nil {
+		conv.ApplyFloat64s(temps)
+	}
+	if temps[0] != 32 || temps[1] != 212 {
+		t.Errorf("Unexpected temperatures %v", temps)
+	}
+}
+
+func BenchmarkConverterSlice(b *testing.B) {
+	values := make([]float64, 1<<16)
+	conv := NewPrefixConverter(Kibi, Kilo)
+	b.SetBytes(int64(8 * len(values)))
+	b.Run("kernel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			conv.ApplyFloat64s(values)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, v := range values {
+				values[j] = conv.ApplyFloat64(v)
+			}
+		}
+	})
+}
+
+func TestNonLinearConversions(t *testing.T) {
+	restoreRegistry(t)
+	for _, c := range []struct {
+		in, out       string
+		value, result float64
+	}{
+		{"dBm", "mW", 0, 1},
+		{"dBm", "W", 30, 1},
+		{"mW", "dBm", 100, 20},
+		{"dB", "ratio", 20, 100},
+		{"ratio", "dB", 0.5, -3.0103},
+	} {
+		conv, err := NewConverter(NewUnit(c.in), NewUnit(c.out))
+		if err != nil {
+			t.Fatalf("Failed to create converter from '%s' to '%s': %v", c.in, c.out, err)
+		}
+		if v := conv.ApplyFloat64(c.value); math.Abs(v-c.result) > 1e-4 {
+			t.Errorf("Expected %v %s = %v %s but got %v", c.value, c.in, c.result, c.out, v)
+		}
+		f, _ := GetUnitUnitFactor(NewUnit(c.in), NewUnit(c.out))
+		if v := f(c.value); v != conv.ApplyFloat64(c.value) {
+			t.Errorf("Conversion function returns %v instead of %v", v, conv.ApplyFloat64(c.value))
+		}
+	}
+	if !NewUnit("dBm").Compatible(NewUnit("kW")) || NewUnit("dB").Compatible(NewUnit("W")) {
+		t.Errorf("Unexpected compatibility of logarithmic units")
+	}
+
+	// Registered conversions use the prefixes of the units
+	m, err := RegisterMeasure(MeasureDefinition{Long: "Neper", Short: "Np"})
+	if err != nil {
+		t.Fatalf("Failed to register measure: %v", err)
+	}
+	err = RegisterConversion(m, Ratio,
+		func(v float64) float64 { return math.Exp(2 * v) },
+		func(v float64) float64 { return math.Log(v) / 2 })
+	if err != nil {
+		t.Fatalf("Failed to register conversion: %v", err)
+	}
+	res, err := CheckRoundTrip(NewUnit("ratio"), NewUnit("Np"), []float64{0.5, 1, 2})
+	if err != nil || res.MaxRelativeError > 1e-12 {
+		t.Errorf("Unexpected round trip error %v: %v", res.MaxRelativeError, err)
+	}
+	if err := RegisterConversion(m, Ratio, nil, nil); err == nil {
+		t.Errorf("Expected error for conversion without formulas")
+	}
+}
+
+func TestTemperaturePrefixes(t *testing.T) {
+	mdegC := NewUnitFromParts(Milli, TemperatureC, InvalidMeasure)
+	degF := NewUnit("degF")
+	conv, err := NewConverter(mdegC, degF)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := conv.ApplyFloat64(45000); math.Abs(v-113) > 1e-9 {
+		t.Errorf("Expected 45000 mdegC to be 113 degF but got %v", v)
+	}
+	if v := 45000*conv.Factor() + conv.Offset(); math.Abs(v-113) > 1e-9 {
+		t.Errorf("Expected factor and offset to result in 113 degF but got %v", v)
+	}
+	f, err := GetUnitUnitFactor(mdegC, degF)
+	if v, ok := f(45000.0).(float64); err != nil || !ok || math.Abs(v-113) > 1e-9 {
+		t.Errorf("Expected conversion function to return 113 degF but got %v (%v)", f(45000.0), err)
+	}
+	back, err := NewConverter(degF, mdegC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := back.ApplyFloat64(113); math.Abs(v-45000) > 1e-6 {
+		t.Errorf("Expected 113 degF to be 45000 mdegC but got %v", v)
+	}
+	if v := 113*back.Factor() + back.Offset(); math.Abs(v-45000) > 1e-6 {
+		t.Errorf("Expected factor and offset to result in 45000 mdegC but got %v", v)
+	}
+	q, err := NewHwmonQuantity("temp1_input", 45000)
+	if err == nil {
+		q, err = q.ConvertTo(degF)
+	}
+	if err != nil || math.Abs(q.Value-113) > 1e-9 {
+		t.Errorf("Expected hwmon temperature of 113 degF but got %s (%v)", q.String(), err)
+	}
+}
+
+func TestTemperatureRates(t *testing.T) {
+	for _, c := range []struct {
+		q        Quantity
+		out      string
+		expected string
+	}{
+		{NewQuantity(1, "degC/s"), "degF/s", "1.8 degF/s"},
+		{NewQuantity(9, "degF/s"), "degC/s", "5 degC/s"},
+	} {
+		q, err := c.q.ConvertTo(NewUnit(c.out))
+		if err != nil || q.String() != c.expected {
+			t.Errorf("Expected %s for %s but got %s: %v", c.expected, c.q.String(), q.String(), err)
+		}
+	}
+	if q, err := NewQuantity(1.8, "degF/s").ConvertTo(NewUnit("degC/min")); err != nil || math.Abs(q.Value-60) > 1e-9 {
+		t.Errorf("Expected 60 degC/min but got %s: %v", q.String(), err)
+	}
+}
+
+func TestTimeScales(t *testing.T) {
+	for _, c := range []struct {
+		in       Quantity
+		out      string
+		expected float64
+	}{
+		{NewQuantity(2, "h"), "s", 7200},
+		{NewQuantity(90, "minutes"), "h", 1.5},
+		{NewQuantity(1.5, "days"), "hours", 36},
+		{NewQuantity(3600, "ms"), "min", 0.06},
+		{NewQuantity(3.6, "GB/h"), "MB/s", 1},
+	} {
+		q, err := c.in.ConvertTo(NewUnit(c.out))
+		if err != nil || math.Abs(q.Value-c.expected) > 1e-12 {
+			t.Errorf("Expected %v %s for %s but got %s: %v", c.expected, c.out, c.in.String(), q.String(), err)
+		}
+	}
+	if conv, err := NewConverter(NewUnit("h"), NewUnit("s")); err != nil || conv.ApplyInt64(3) != 10800 {
+		t.Errorf("Expected 10800 s for 3 h: %v", err)
+	}
+	if !NewUnitValue("GB/h").IsRate() || NewUnitValue("kh").Valid() {
+		t.Errorf("Unexpected rate category or prefix of hours")
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	samples := []float64{0, 1, 1234.5678, 1e15, -3}
+	res, err := CheckRoundTrip(NewUnit("KiB/s"), NewUnit("GB/s"), samples)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.MaxRelativeError > 1e-15 {
+		t.Errorf("Unexpected relative error %v for sample %v", res.MaxRelativeError, res.WorstSample)
+	}
+	res, err = CheckRoundTrip(NewUnit("degC"), NewUnit("degF"), samples)
+	if err != nil || res.MaxRelativeError > 1e-12 {
+		t.Errorf("Unexpected relative error %v for sample %v: %v", res.MaxRelativeError, res.WorstSample, err)
+	}
+	if _, err := CheckRoundTrip(NewUnit("MB"), NewUnit("W"), samples); err == nil {
+		t.Errorf("Expected error for converting 'MB' to 'W'")
+	}
+}
@@ -0,0 +1,91 @@
+package ccunits
+
+import "fmt"
+
+// CPUTimeUnit is the unit of CPU time in job efficiency reports. CPU seconds as reported by
+// cgroups or Slurm are core-seconds: one core busy for one second.
+type CPUTimeUnit int
+
+const (
+	CoreSeconds CPUTimeUnit = iota // One core busy for one second (CPU seconds)
+	CoreHours                      // One core busy for one hour
+	NodeSeconds                    // All cores of a node busy for one second
+	NodeHours                      // All cores of a node busy for one hour
+)
+
+// String returns the short name of the CPU time unit
+func (u CPUTimeUnit) String() string {
+	switch u {
+	case CoreSeconds:
+		return "core-s"
+	case CoreHours:
+		return "core-h"
+	case NodeSeconds:
+		return "node-s"
+	case NodeHours:
+		return "node-h"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the short name of the CPU time unit, so it is readable in configurations
+func (u CPUTimeUnit) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText reads the short name of the CPU time unit
+func (u *CPUTimeUnit) UnmarshalText(text []byte) error {
+	for _, unit := range []CPUTimeUnit{CoreSeconds, CoreHours, NodeSeconds, NodeHours} {
+		if unit.String() == string(text) {
+			*u = unit
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid CPU time unit '%s': %w", string(text), ErrInvalidMeasure)
+}
+
+// coreSeconds returns the number of core-seconds of one unit
+func (u CPUTimeUnit) coreSeconds(coresPerNode int) (float64, error) {
+	if u < CoreSeconds || u > NodeHours {
+		return 0, fmt.Errorf("invalid CPU time unit %d: %w", int(u), ErrInvalidMeasure)
+	}
+	seconds := 1.0
+	if u == CoreHours || u == NodeHours {
+		seconds = 3600
+	}
+	if u == NodeSeconds || u == NodeHours {
+		if coresPerNode <= 0 {
+			return 0, fmt.Errorf("invalid number of cores per node %d for '%s': %w", coresPerNode, u.String(), ErrInvalidValue)
+		}
+		seconds *= float64(coresPerNode)
+	}
+	return seconds, nil
+}
+
+// ConvertCPUTime converts CPU time between core-seconds, core-hours, node-seconds and
+// node-hours. The number of cores per node is only used for the node units.
+func ConvertCPUTime(value float64, in CPUTimeUnit, out CPUTimeUnit, coresPerNode int) (float64, error) {
+	inSeconds, err := in.coreSeconds(coresPerNode)
+	if err != nil {
+		return value, err
+	}
+	outSeconds, err := out.coreSeconds(coresPerNode)
+	if err != nil {
+		return value, err
+	}
+	return value * inSeconds / outSeconds, nil
+}
+
+// CPUTime converts a quantity of CPU time like '5400000 ms' from cgroup or Slurm accounting to
+// the CPU time unit out, e.g. to core-hours for job efficiency reports. The quantity must be a
+// time without unit denominator, also in the time scales like '1.5 h' or '90 min'.
+func CPUTime(q Quantity, out CPUTimeUnit, coresPerNode int) (float64, error) {
+	if !q.Valid() || !isTimeMeasure(q.Unit.GetMeasure()) || q.Unit.GetUnitDenominator() != InvalidMeasure {
+		return q.Value, fmt.Errorf("invalid unit for CPU time: %w", ErrIncompatibleMeasure)
+	}
+	conv, err := NewConverter(q.Unit, Time.BaseUnit())
+	if err != nil {
+		return q.Value, err
+	}
+	return ConvertCPUTime(conv.ApplyFloat64(q.Value), CoreSeconds, out, coresPerNode)
+}
@@ -0,0 +1,40 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCPUTime(t *testing.T) {
+	if v, err := CPUTime(NewQuantity(5400000, "ms"), CoreHours, 0); err != nil || v != 1.5 {
+		t.Errorf("Expected 1.5 core-h but got %v: %v", v, err)
+	}
+	if v, err := CPUTime(NewQuantity(1.5, "h"), CoreHours, 0); err != nil || v != 1.5 {
+		t.Errorf("Expected 1.5 core-h for 1.5 h but got %v: %v", v, err)
+	}
+	if v, err := CPUTime(NewQuantity(90, "min"), CoreSeconds, 0); err != nil || v != 5400 {
+		t.Errorf("Expected 5400 core-s for 90 min but got %v: %v", v, err)
+	}
+	if v, err := CPUTime(NewQuantity(2, "d"), NodeHours, 48); err != nil || v != 1 {
+		t.Errorf("Expected 1 node-h for 2 d on 48 cores but got %v: %v", v, err)
+	}
+	if _, err := CPUTime(NewQuantity(1, "h/s"), CoreHours, 0); err == nil {
+		t.Errorf("Expected error for CPU time in 'h/s'")
+	}
+	if v, err := ConvertCPUTime(96, CoreHours, NodeHours, 48); err != nil || v != 2 {
+		t.Errorf("Expected 2 node-h but got %v: %v", v, err)
+	}
+	if v, err := ConvertCPUTime(1, NodeSeconds, CoreSeconds, 64); err != nil || v != 64 {
+		t.Errorf("Expected 64 core-s but got %v: %v", v, err)
+	}
+	if _, err := ConvertCPUTime(1, CoreHours, NodeHours, 0); err == nil {
+		t.Errorf("Expected error for node-h without cores per node")
+	}
+	if _, err := CPUTime(NewQuantity(1, "GB"), CoreHours, 0); err == nil {
+		t.Errorf("Expected error for CPU time in 'GB'")
+	}
+	var u CPUTimeUnit
+	if err := json.Unmarshal([]byte(`"node-h"`), &u); err != nil || u != NodeHours {
+		t.Errorf("Expected NodeHours but got %s: %v", u.String(), err)
+	}
+}
@@ -0,0 +1,124 @@
+package ccunits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Units of the counters in Darshan I/O logs like 'POSIX_BYTES_READ' or 'MPIIO_F_WRITE_TIME'.
+// See https://www.mcs.anl.gov/research/projects/darshan/docs/darshan-util.html
+
+// darshanCounterRule maps the counter names (without module) matching the regular expression
+// to a unit. Rules without valid unit mark counters which are no quantities like ranks,
+// modes and variances.
+type darshanCounterRule struct {
+	regex *regexp.Regexp
+	unit  UnitValue
+}
+
+// darshanCounterRules are checked in order, the first matching rule decides
+var darshanCounterRules = []darshanCounterRule{
+	{regexp.MustCompile(`VARIANCE|_RANK$|^MODE$|^STRIPE_OFFSET$|^RENAMED_FROM$`), invalidUnitValue},
+	{regexp.MustCompile(`^F_.*TIME(STAMP)?$`), UnitValue{Base, Time, InvalidMeasure}},
+	{regexp.MustCompile(`BYTES|BYTE_|_SIZE$|ALIGNMENT$|_STRIDE$|_ACCESS$`), UnitValue{Base, Bytes, InvalidMeasure}},
+	{regexp.MustCompile(`^STRIPE_WIDTH$|^OSTS$|^MDTS$`), UnitValue{Base, Count, InvalidMeasure}},
+	{regexp.MustCompile(`^SIZE_|_COUNT$|_NOT_ALIGNED$|S$`), UnitValue{Base, Requests, InvalidMeasure}},
+}
+
+// DarshanCounterUnit returns the unit of a Darshan counter like bytes for
+// 'POSIX_BYTES_READ', seconds for 'POSIX_F_READ_TIME' and requests (I/O operations) for
+// 'POSIX_READS' or the access size histogram 'POSIX_SIZE_READ_0_100'. Counters which are no
+// quantities like 'POSIX_MODE', 'POSIX_FASTEST_RANK' or variances and unknown counters have
+// no unit (false).
+func DarshanCounterUnit(counter string) (Unit, bool) {
+	module, name, ok := strings.Cut(counter, "_")
+	if !ok || len(module) == 0 {
+		return invalidUnitValue.Unit(), false
+	}
+	for _, r := range darshanCounterRules {
+		if r.regex.MatchString(name) {
+			return r.unit.Unit(), r.unit.Valid()
+		}
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// DarshanRecord contains the counters of a file record of a module in a Darshan log
+type DarshanRecord struct {
+	Module   string             // Module like 'POSIX', 'MPI-IO' or 'STDIO'
+	Rank     int                // MPI rank or -1 for records shared by all ranks
+	RecordID string             // Record ID (hash of the file name)
+	File     string             // File name (optional)
+	Counters map[string]float64 // Raw counter values by name
+}
+
+// Quantities converts the counters of the record into quantities with their units.
+// Counters without unit like ranks and modes are omitted.
+func (r DarshanRecord) Quantities() map[string]Quantity {
+	out := make(map[string]Quantity, len(r.Counters))
+	for name, value := range r.Counters {
+		if u, ok := DarshanCounterUnit(name); ok {
+			out[name] = Quantity{Value: value, Unit: u}
+		}
+	}
+	return out
+}
+
+// ParseDarshanRecords reads the text output of 'darshan-parser' with the tab-separated
+// columns module, rank, record id, counter, value, file name, mount point and file system
+// and groups the counters into records. Comments and empty lines are skipped. It returns an
+// error for malformed lines.
+func ParseDarshanRecords(r io.Reader) ([]DarshanRecord, error) {
+	type recordKey struct {
+		module   string
+		rank     int
+		recordID string
+	}
+	var records []DarshanRecord
+	index := make(map[recordKey]int)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid Darshan record in line %d: '%s': %w", line, text, ErrInvalidValue)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rank '%s' in line %d: %w", fields[1], line, ErrInvalidValue)
+		}
+		value, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value '%s' for counter '%s' in line %d: %w", fields[4], fields[3], line, ErrInvalidValue)
+		}
+		key := recordKey{fields[0], rank, fields[2]}
+		i, ok := index[key]
+		if !ok {
+			i = len(records)
+			index[key] = i
+			records = append(records, DarshanRecord{
+				Module:   fields[0],
+				Rank:     rank,
+				RecordID: fields[2],
+				Counters: make(map[string]float64),
+			})
+			if len(fields) > 5 {
+				records[i].File = fields[5]
+			}
+		}
+		records[i].Counters[fields[3]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
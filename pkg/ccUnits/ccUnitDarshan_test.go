@@ -0,0 +1,58 @@
+package ccunits
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDarshanCounterUnit(t *testing.T) {
+	for counter, expected := range map[string]string{
+		"POSIX_BYTES_READ":             "B",
+		"POSIX_MAX_BYTE_WRITTEN":       "B",
+		"POSIX_MAX_READ_TIME_SIZE":     "B",
+		"POSIX_ACCESS1_ACCESS":         "B",
+		"POSIX_ACCESS1_COUNT":          "requests",
+		"POSIX_READS":                  "requests",
+		"POSIX_SIZE_READ_0_100":        "requests",
+		"MPIIO_COLL_WRITES":            "requests",
+		"POSIX_F_READ_TIME":            "s",
+		"POSIX_F_OPEN_START_TIMESTAMP": "s",
+		"POSIX_F_FASTEST_RANK_TIME":    "s",
+		"LUSTRE_STRIPE_WIDTH":          "count",
+		"POSIX_MODE":                   "",
+		"POSIX_FASTEST_RANK":           "",
+		"POSIX_F_VARIANCE_RANK_BYTES":  "",
+		"UNKNOWN":                      "",
+	} {
+		u, ok := DarshanCounterUnit(counter)
+		if ok != (len(expected) > 0) || ok && u.Short() != expected || !ok && u.Valid() {
+			t.Errorf("Expected unit '%s' for Darshan counter '%s' but got '%s' (%v)", expected, counter, u.Short(), ok)
+		}
+	}
+}
+
+func TestParseDarshanRecords(t *testing.T) {
+	log := `# darshan log version: 3.41
+#<module>	<rank>	<record id>	<counter>	<value>	<file name>	<mount pt>	<fs type>
+POSIX	-1	6301063301082038805	POSIX_READS	4	/scratch/out.dat	/scratch	lustre
+POSIX	-1	6301063301082038805	POSIX_BYTES_READ	4194304	/scratch/out.dat	/scratch	lustre
+POSIX	-1	6301063301082038805	POSIX_MODE	436	/scratch/out.dat	/scratch	lustre
+POSIX	-1	6301063301082038805	POSIX_F_READ_TIME	0.125000	/scratch/out.dat	/scratch	lustre
+STDIO	0	9457796068806373448	STDIO_WRITES	2	<STDOUT>	UNKNOWN	UNKNOWN
+`
+	records, err := ParseDarshanRecords(strings.NewReader(log))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("Expected 2 records but got %d: %v", len(records), err)
+	}
+	r := records[0]
+	if r.Module != "POSIX" || r.Rank != -1 || r.File != "/scratch/out.dat" || len(r.Counters) != 4 {
+		t.Errorf("Unexpected record %+v", r)
+	}
+	q := r.Quantities()
+	if len(q) != 3 || q["POSIX_BYTES_READ"].String() != "4.194304e+06 B" || q["POSIX_F_READ_TIME"].Unit.Short() != "s" {
+		t.Errorf("Unexpected quantities %v", q)
+	}
+	if _, err := ParseDarshanRecords(strings.NewReader("POSIX\t-1\t1\tPOSIX_READS\tfour\n")); err == nil {
+		t.Errorf("Expected error for invalid value")
+	}
+}
@@ -0,0 +1,475 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Registered aliases for measures and prefixes. They are checked before the regular
+// expressions by NewMeasure and NewPrefix. They start with the built-in aliases.
+var measureAliases map[string]Measure = copyAliases(builtinMeasureAliases)
+var prefixAliases map[string]Prefix = copyAliases(builtinPrefixAliases)
+
+// copyAliases returns a copy of an alias table
+func copyAliases[T Measure | Prefix](aliases map[string]T) map[string]T {
+	out := make(map[string]T, len(aliases))
+	for a, v := range aliases {
+		out[a] = v
+	}
+	return out
+}
+
+// registryLock guards the measures, prefixes and aliases (MeasuresMap, PrefixDataMap,
+// measureAliases, prefixAliases, prefixSymbols, measureMetadata and conversions). Parsing and
+// formatting take the read lock, registrations the write lock.
+var registryLock sync.RWMutex
+
+// registryGeneration is incremented by every registration. Caches built out of the
+// registry compare it to detect outdated entries.
+var registryGeneration atomic.Uint64
+
+// registryChanged invalidates all caches after a registration. It has to be called with
+// the write lock held.
+func registryChanged() {
+	registryGeneration.Add(1)
+	resetInternedUnits()
+}
+
+// MeasureDefinition describes a custom measure
+type MeasureDefinition struct {
+	Long         string           `json:"long" yaml:"long"`                                       // Long name like 'Bits'
+	Short        string           `json:"short" yaml:"short"`                                     // Short name like 'bit'
+	Regex        string           `json:"regex,omitempty" yaml:"regex,omitempty"`                 // Regular expression matching the measure (optional)
+	Aliases      []string         `json:"aliases,omitempty" yaml:"aliases,omitempty"`             // Additional names of the measure
+	NonDividable bool             `json:"non_dividable,omitempty" yaml:"non_dividable,omitempty"` // Measure cannot be divided into fractions
+	Metadata     *MeasureMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`           // Description for UIs (optional)
+}
+
+// PrefixDefinition describes a custom prefix
+type PrefixDefinition struct {
+	Long    string   `json:"long" yaml:"long"`                           // Long name like 'Hecto'
+	Short   string   `json:"short" yaml:"short"`                         // Short name like 'h'
+	Factor  float64  `json:"factor" yaml:"factor"`                       // Factor of the prefix like 100
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"` // Additional names of the prefix
+}
+
+// UnitDefinitions is the content of a unit definition file. Aliases map a new name to
+// an existing measure or prefix like 'octets' -> 'B'.
+type UnitDefinitions struct {
+	Measures       []MeasureDefinition `json:"measures,omitempty" yaml:"measures,omitempty"`
+	Prefixes       []PrefixDefinition  `json:"prefixes,omitempty" yaml:"prefixes,omitempty"`
+	MeasureAliases map[string]string   `json:"measure_aliases,omitempty" yaml:"measure_aliases,omitempty"`
+	PrefixAliases  map[string]string   `json:"prefix_aliases,omitempty" yaml:"prefix_aliases,omitempty"`
+}
+
+// updatePrefixSymbols collects the short names and aliases of registered prefixes which
+// are checked first when splitting the prefix from the measure. It has to be called with
+// the write lock held.
+func updatePrefixSymbols() {
+	prefixSymbols = sortedPrefixSymbols()
+}
+
+// sortedPrefixSymbols returns the symbols of the prefix aliases, longest first
+func sortedPrefixSymbols() []string {
+	symbols := make([]string, 0)
+	for s := range prefixAliases {
+		if len(s) > 0 {
+			symbols = append(symbols, s)
+		}
+	}
+	// Longest symbols first because the first match is used
+	sort.Slice(symbols, func(i, j int) bool {
+		if len(symbols[i]) != len(symbols[j]) {
+			return len(symbols[i]) > len(symbols[j])
+		}
+		return symbols[i] < symbols[j]
+	})
+	return symbols
+}
+
+// measureSpelling returns the measure of a registered alias or a known spelling of a
+// built-in measure. It has to be called with the lock held.
+func measureSpelling(s string) (Measure, bool) {
+	if m, ok := measureAliases[s]; ok {
+		return m, true
+	}
+	return lookupMeasure(s)
+}
+
+// prefixSpelling returns the prefix of a registered alias or a known spelling of a built-in
+// prefix. It has to be called with the lock held.
+func prefixSpelling(s string) (Prefix, bool) {
+	if p, ok := prefixAliases[s]; ok {
+		return p, true
+	}
+	return lookupPrefix(s)
+}
+
+// registeredMeasureName returns the long name of a measure. It has to be called with the lock held.
+func registeredMeasureName(m Measure) string {
+	if data, ok := builtinMeasure(m); ok {
+		return data.Long
+	}
+	return MeasuresMap[m].Long
+}
+
+// registeredPrefixName returns the long name of a prefix. It has to be called with the lock held.
+func registeredPrefixName(p Prefix) string {
+	if data, ok := builtinPrefix(p); ok {
+		return data.Long
+	}
+	return PrefixDataMap[p].Long
+}
+
+// hasName checks whether a name is contained in the names
+func hasName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueNames returns the names without duplicates in their order
+func uniqueNames(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if !hasName(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// checkMeasure validates a measure definition. Its names must not be spellings of existing
+// measures or of other definitions of the same registration (taken). It has to be called
+// with the lock held.
+func checkMeasure(def MeasureDefinition, taken map[string]bool) error {
+	if len(def.Long) == 0 || len(def.Short) == 0 {
+		return fmt.Errorf("measure requires long and short name: %w", ErrInvalidMeasure)
+	}
+	if len(def.Regex) > 0 {
+		if _, err := regexp.Compile(def.Regex); err != nil {
+			return fmt.Errorf("invalid regex for measure '%s': %v: %w", def.Long, err, ErrInvalidMeasure)
+		}
+	}
+	for _, data := range MeasuresMap {
+		if data.Short == def.Short {
+			return fmt.Errorf("measure with short name '%s' already exists: %w", def.Short, ErrInvalidMeasure)
+		}
+	}
+	for _, a := range uniqueNames(append([]string{def.Short, def.Long}, def.Aliases...)) {
+		if len(a) == 0 {
+			return fmt.Errorf("empty alias for measure '%s': %w", def.Long, ErrInvalidMeasure)
+		}
+		if m, ok := measureSpelling(a); ok {
+			return fmt.Errorf("name '%s' of measure '%s' is already used by measure '%s': %w", a, def.Long, registeredMeasureName(m), ErrInvalidMeasure)
+		}
+		if taken[a] {
+			return fmt.Errorf("name '%s' of measure '%s' is defined twice: %w", a, def.Long, ErrInvalidMeasure)
+		}
+		taken[a] = true
+	}
+	return nil
+}
+
+// addMeasure adds a checked measure definition and returns the new measure. It has to be
+// called with the lock held.
+func addMeasure(def MeasureDefinition) Measure {
+	next := InvalidMeasure
+	for m := range MeasuresMap {
+		if m > next {
+			next = m
+		}
+	}
+	next++
+	MeasuresMap[next] = MeasureData{
+		Long:         def.Long,
+		Short:        def.Short,
+		Regex:        def.Regex,
+		NonDividable: def.NonDividable,
+	}
+	for _, a := range append([]string{def.Short, def.Long}, def.Aliases...) {
+		measureAliases[a] = next
+	}
+	if def.Metadata != nil {
+		measureMetadata[next] = *def.Metadata
+	}
+	return next
+}
+
+// checkMeasureAlias validates an alias for a measure. The alias must not be a spelling of
+// another measure, so built-in spellings like 'B' cannot be redirected. It has to be
+// called with the lock held.
+func checkMeasureAlias(alias string, m Measure) error {
+	if len(alias) == 0 {
+		return fmt.Errorf("empty alias for measure '%s': %w", registeredMeasureName(m), ErrInvalidMeasure)
+	}
+	if _, ok := MeasuresMap[m]; !ok {
+		return fmt.Errorf("invalid measure for alias '%s': %w", alias, ErrInvalidMeasure)
+	}
+	if other, ok := measureSpelling(alias); ok && other != m {
+		return fmt.Errorf("alias '%s' for measure '%s' is already used by measure '%s': %w", alias, registeredMeasureName(m), registeredMeasureName(other), ErrInvalidMeasure)
+	}
+	return nil
+}
+
+// checkPrefix validates a prefix definition. Its factor must be new and its names must not
+// be spellings of existing prefixes or of other definitions of the same registration
+// (taken). It has to be called with the lock held.
+func checkPrefix(def PrefixDefinition, taken map[string]bool) error {
+	if len(def.Long) == 0 || len(def.Short) == 0 {
+		return fmt.Errorf("prefix requires long and short name: %w", ErrInvalidPrefix)
+	}
+	if def.Factor <= 0 {
+		return fmt.Errorf("invalid factor %v for prefix '%s': %w", def.Factor, def.Long, ErrInvalidPrefix)
+	}
+	if data, ok := PrefixDataMap[Prefix(def.Factor)]; ok {
+		return fmt.Errorf("prefix '%s' has the same factor as prefix '%s': %w", def.Long, data.Long, ErrInvalidPrefix)
+	}
+	for _, a := range uniqueNames(append([]string{def.Short}, def.Aliases...)) {
+		if len(a) == 0 {
+			return fmt.Errorf("empty alias for prefix '%s': %w", def.Long, ErrInvalidPrefix)
+		}
+		if p, ok := prefixSpelling(a); ok {
+			return fmt.Errorf("name '%s' of prefix '%s' is already used by prefix '%s': %w", a, def.Long, registeredPrefixName(p), ErrInvalidPrefix)
+		}
+		if taken[a] {
+			return fmt.Errorf("name '%s' of prefix '%s' is defined twice: %w", a, def.Long, ErrInvalidPrefix)
+		}
+		taken[a] = true
+	}
+	return nil
+}
+
+// addPrefix adds a checked prefix definition and returns the new prefix. It has to be
+// called with the lock held.
+func addPrefix(def PrefixDefinition) Prefix {
+	p := Prefix(def.Factor)
+	PrefixDataMap[p] = PrefixData{
+		Long:  def.Long,
+		Short: def.Short,
+	}
+	for _, a := range append([]string{def.Short}, def.Aliases...) {
+		prefixAliases[a] = p
+	}
+	return p
+}
+
+// checkPrefixAlias validates an alias for a prefix. The alias must not be a spelling of
+// another prefix. It has to be called with the lock held.
+func checkPrefixAlias(alias string, p Prefix) error {
+	if len(alias) == 0 {
+		return fmt.Errorf("empty alias for prefix '%s': %w", registeredPrefixName(p), ErrInvalidPrefix)
+	}
+	if _, ok := PrefixDataMap[p]; !ok {
+		return fmt.Errorf("invalid prefix for alias '%s': %w", alias, ErrInvalidPrefix)
+	}
+	if other, ok := prefixSpelling(alias); ok && other != p {
+		return fmt.Errorf("alias '%s' for prefix '%s' is already used by prefix '%s': %w", alias, registeredPrefixName(p), registeredPrefixName(other), ErrInvalidPrefix)
+	}
+	return nil
+}
+
+// RegisterMeasure adds a custom measure. It returns the new measure or an error if the
+// definition is invalid or one of its names is already used by another measure.
+func RegisterMeasure(def MeasureDefinition) (Measure, error) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if err := checkMeasure(def, make(map[string]bool)); err != nil {
+		return InvalidMeasure, err
+	}
+	m := addMeasure(def)
+	registryChanged()
+	return m, nil
+}
+
+// RegisterMeasureAlias adds an additional name for a measure. Names of other measures like
+// 'B' cannot be used as alias.
+func RegisterMeasureAlias(alias string, m Measure) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if err := checkMeasureAlias(alias, m); err != nil {
+		return err
+	}
+	measureAliases[alias] = m
+	registryChanged()
+	return nil
+}
+
+// RegisterPrefix adds a custom prefix. It returns the new prefix or an error if the
+// definition is invalid, a prefix with the same factor exists or one of its names is
+// already used by another prefix.
+func RegisterPrefix(def PrefixDefinition) (Prefix, error) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if err := checkPrefix(def, make(map[string]bool)); err != nil {
+		return InvalidPrefix, err
+	}
+	p := addPrefix(def)
+	updatePrefixSymbols()
+	registryChanged()
+	return p, nil
+}
+
+// RegisterPrefixAlias adds an additional name for a prefix. Names of other prefixes like 'M'
+// cannot be used as alias.
+func RegisterPrefixAlias(alias string, p Prefix) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if err := checkPrefixAlias(alias, p); err != nil {
+		return err
+	}
+	prefixAliases[alias] = p
+	updatePrefixSymbols()
+	registryChanged()
+	return nil
+}
+
+// RegisterDefinitions adds all measures, prefixes and aliases of the unit definitions.
+// The prefixes and measures are registered before the aliases, so aliases can refer to them.
+// The definitions are registered atomically: all of them are validated before the first one
+// is added, so an invalid definition leaves the registry unchanged.
+func RegisterDefinitions(defs UnitDefinitions) error {
+	// The regular expressions of the measures and prefixes cannot be compiled while the
+	// write lock is held, so the targets of the aliases are matched with the current tables
+	tables := getParserTables()
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	takenPrefixes := make(map[string]bool)
+	factors := make(map[Prefix]string)
+	for _, def := range defs.Prefixes {
+		if err := checkPrefix(def, takenPrefixes); err != nil {
+			return err
+		}
+		if other, ok := factors[Prefix(def.Factor)]; ok {
+			return fmt.Errorf("prefix '%s' has the same factor as prefix '%s': %w", def.Long, other, ErrInvalidPrefix)
+		}
+		factors[Prefix(def.Factor)] = def.Long
+	}
+	takenMeasures := make(map[string]bool)
+	for _, def := range defs.Measures {
+		if err := checkMeasure(def, takenMeasures); err != nil {
+			return err
+		}
+	}
+	// The aliases are checked against the new prefixes and measures, which get their values
+	// when they are added
+	for alias, target := range defs.PrefixAliases {
+		p := definedPrefix(target, defs.Prefixes, tables)
+		if p == InvalidPrefix {
+			return fmt.Errorf("unknown prefix '%s' for alias '%s': %w", target, alias, ErrInvalidPrefix)
+		}
+		if len(alias) == 0 || takenPrefixes[alias] {
+			return fmt.Errorf("invalid alias '%s' for prefix '%s': %w", alias, target, ErrInvalidPrefix)
+		}
+		if other, ok := prefixSpelling(alias); ok && other != p {
+			return fmt.Errorf("alias '%s' for prefix '%s' is already used by prefix '%s': %w", alias, target, registeredPrefixName(other), ErrInvalidPrefix)
+		}
+	}
+	measureIDs := make(map[string]Measure)
+	for alias, target := range defs.MeasureAliases {
+		m, pending := definedMeasure(target, defs.Measures, tables)
+		if m == InvalidMeasure && pending < 0 {
+			return fmt.Errorf("unknown measure '%s' for alias '%s': %w", target, alias, ErrInvalidMeasure)
+		}
+		if len(alias) == 0 || takenMeasures[alias] {
+			return fmt.Errorf("invalid alias '%s' for measure '%s': %w", alias, target, ErrInvalidMeasure)
+		}
+		if other, ok := measureSpelling(alias); ok && (pending >= 0 || other != m) {
+			return fmt.Errorf("alias '%s' for measure '%s' is already used by measure '%s': %w", alias, target, registeredMeasureName(other), ErrInvalidMeasure)
+		}
+	}
+
+	for _, def := range defs.Prefixes {
+		addPrefix(def)
+	}
+	for _, def := range defs.Measures {
+		m := addMeasure(def)
+		measureIDs[def.Short] = m
+	}
+	for alias, target := range defs.PrefixAliases {
+		prefixAliases[alias] = definedPrefix(target, defs.Prefixes, tables)
+	}
+	for alias, target := range defs.MeasureAliases {
+		m, pending := definedMeasure(target, defs.Measures, tables)
+		if pending >= 0 {
+			m = measureIDs[defs.Measures[pending].Short]
+		}
+		measureAliases[alias] = m
+	}
+	updatePrefixSymbols()
+	registryChanged()
+	return nil
+}
+
+// definedPrefix returns the prefix of a name which is either a name of one of the prefix
+// definitions or of an existing prefix. It has to be called with the lock held.
+func definedPrefix(name string, defs []PrefixDefinition, tables *parserTables) Prefix {
+	for _, def := range defs {
+		if name == def.Short || hasName(def.Aliases, name) {
+			return Prefix(def.Factor)
+		}
+	}
+	if p, ok := prefixSpelling(name); ok {
+		return p
+	}
+	for _, matcher := range tables.prefixes {
+		if matcher.regex.MatchString(name) {
+			return matcher.prefix
+		}
+	}
+	return InvalidPrefix
+}
+
+// definedMeasure returns the measure of a name which is an existing measure or the index
+// of the measure definition with this name (-1 if none). The new measures get their value
+// when they are added. It has to be called with the lock held.
+func definedMeasure(name string, defs []MeasureDefinition, tables *parserTables) (Measure, int) {
+	for i, def := range defs {
+		if name == def.Short || name == def.Long || hasName(def.Aliases, name) {
+			return InvalidMeasure, i
+		}
+	}
+	if m, ok := measureSpelling(name); ok {
+		return m, -1
+	}
+	for _, matcher := range tables.measures {
+		if matcher.regex.MatchString(name) {
+			return matcher.measure, -1
+		}
+	}
+	return InvalidMeasure, -1
+}
+
+// LoadDefinitions reads custom measures, prefixes and aliases from a JSON or YAML file
+// (selected by the file extension '.yaml' or '.yml') and registers them. It should be
+// called at startup before any units are parsed.
+func LoadDefinitions(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read unit definitions: %w", err)
+	}
+	var defs UnitDefinitions
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &defs)
+	default:
+		err = json.Unmarshal(data, &defs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode unit definitions in '%s': %w", path, err)
+	}
+	return RegisterDefinitions(defs)
+}
@@ -0,0 +1,194 @@
+package ccunits
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	restoreRegistry(t)
+	if md, ok := Metadata(Watt); !ok || md.Category != CategoryPower || len(md.Description) == 0 {
+		t.Errorf("Unexpected metadata %v of Watt", md)
+	}
+	md := MeasureMetadata{Description: "Amount of light", Category: CategoryCount}
+	m, err := RegisterMeasure(MeasureDefinition{Long: "Lumen", Short: "lm", Metadata: &md})
+	if err != nil {
+		t.Fatalf("Failed to register measure: %v", err)
+	}
+	if got, ok := Metadata(m); !ok || got != md {
+		t.Errorf("Unexpected metadata %v of registered measure", got)
+	}
+	if _, ok := Metadata(InvalidMeasure); ok {
+		t.Errorf("Expected no metadata for InvalidMeasure")
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	restoreRegistry(t)
+	defs := `
+prefixes:
+  - long: Hecto
+    short: h
+    factor: 100
+measures:
+  - long: Bits
+    short: bit
+    aliases: [bits, Bit]
+    non_dividable: true
+measure_aliases:
+  octets: B
+prefix_aliases:
+  kilo: K
+`
+	path := filepath.Join(t.TempDir(), "units.yaml")
+	if err := os.WriteFile(path, []byte(defs), 0644); err != nil {
+		t.Fatalf("Failed to write unit definitions: %v", err)
+	}
+	if err := LoadDefinitions(path); err != nil {
+		t.Fatalf("Failed to load unit definitions: %v", err)
+	}
+
+	definitionTests := map[string]string{
+		"octets":  "B",
+		"Moctets": "MB",
+		"kbits":   "Kbit",
+		"Gbit/s":  "Gbit/s",
+		"hW":      "hW",
+		"hertz":   "Hz",
+		"kiloW":   "KW",
+	}
+	for input, expected := range definitionTests {
+		u := NewUnit(input)
+		if !u.Valid() {
+			t.Errorf("Unit '%s' should be valid", input)
+			continue
+		}
+		if u.Short() != expected {
+			t.Errorf("Unit '%s': expected '%s' but got '%s'", input, expected, u.Short())
+		}
+	}
+
+	conv, err := GetUnitUnitFactor(NewUnit("hW"), NewUnit("kW"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := conv(float64(10)); v != float64(1) {
+		t.Errorf("Expected 10 hW = 1 kW but got %v", v)
+	}
+
+	if _, err := RegisterMeasure(MeasureDefinition{Long: "Bits", Short: "bit"}); err == nil {
+		t.Errorf("Expected error for duplicate measure")
+	}
+}
+
+func TestRegistrationCollisions(t *testing.T) {
+	restoreRegistry(t)
+	for _, err := range []error{
+		RegisterMeasureAlias("B", Watt),
+		RegisterMeasureAlias("Hz", Bytes),
+		errOf(RegisterMeasure(MeasureDefinition{Long: "Blocks", Short: "blk", Aliases: []string{"W"}})),
+		errOf(RegisterMeasure(MeasureDefinition{Long: "Seconds", Short: "sek"})),
+	} {
+		if !errors.Is(err, ErrInvalidMeasure) {
+			t.Errorf("Expected error for measure name collision but got %v", err)
+		}
+	}
+	for _, err := range []error{
+		RegisterPrefixAlias("M", Kilo),
+		errOf(RegisterPrefix(PrefixDefinition{Long: "Myria", Short: "G", Factor: 1e4})),
+	} {
+		if !errors.Is(err, ErrInvalidPrefix) {
+			t.Errorf("Expected error for prefix name collision but got %v", err)
+		}
+	}
+	if u := NewUnit("MB"); u.Short() != "MB" {
+		t.Errorf("Expected 'MB' after rejected registrations but got '%s'", u.Short())
+	}
+	// Aliases of the same measure or prefix are accepted
+	if err := RegisterMeasureAlias("bytes", Bytes); err != nil {
+		t.Errorf("Unexpected error for alias of the same measure: %v", err)
+	}
+	if err := RegisterPrefixAlias("k", Kilo); err != nil {
+		t.Errorf("Unexpected error for alias of the same prefix: %v", err)
+	}
+}
+
+func TestRegisterDefinitionsAtomic(t *testing.T) {
+	restoreRegistry(t)
+	generation := registryGeneration.Load()
+	for _, defs := range []UnitDefinitions{
+		{
+			Prefixes:       []PrefixDefinition{{Long: "Hecto", Short: "h", Factor: 100}},
+			Measures:       []MeasureDefinition{{Long: "Bits", Short: "bit"}},
+			MeasureAliases: map[string]string{"xyz": "unknown"},
+		},
+		{
+			Measures: []MeasureDefinition{{Long: "Bits", Short: "bit"}, {Long: "Bauds", Short: "bit"}},
+		},
+		{
+			Prefixes: []PrefixDefinition{{Long: "Hecto", Short: "h", Factor: 100}, {Long: "Hundred", Short: "hu", Factor: 100}},
+		},
+		{
+			Measures:      []MeasureDefinition{{Long: "Bits", Short: "bit"}},
+			PrefixAliases: map[string]string{"mega": "K", "M": "K"},
+		},
+	} {
+		if err := RegisterDefinitions(defs); err == nil {
+			t.Errorf("Expected error for definitions %+v", defs)
+		}
+		if u := NewUnit("bit"); u.Short() == "bit" {
+			t.Errorf("Unexpected measure 'bit' after failed registration")
+		}
+		if p := NewPrefix("h"); p != InvalidPrefix {
+			t.Errorf("Unexpected prefix 'h' after failed registration")
+		}
+	}
+	if registryGeneration.Load() != generation {
+		t.Errorf("Registry changed by failed registrations")
+	}
+
+	// Aliases can refer to the new measures and prefixes
+	err := RegisterDefinitions(UnitDefinitions{
+		Prefixes:       []PrefixDefinition{{Long: "Hecto", Short: "h", Factor: 100}},
+		Measures:       []MeasureDefinition{{Long: "Bits", Short: "bit"}},
+		MeasureAliases: map[string]string{"bitz": "bit"},
+		PrefixAliases:  map[string]string{"hecto": "h"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u := NewUnit("hectobitz"); u.Short() != "hbit" {
+		t.Errorf("Expected 'hbit' for 'hectobitz' but got '%s'", u.Short())
+	}
+}
+
+func TestConcurrentRegistration(t *testing.T) {
+	restoreRegistry(t)
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			RegisterMeasureAlias(fmt.Sprintf("concurrent%d", i), Watt)
+		}
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			if u := NewUnit("concurrent99"); u.Short() != "W" {
+				t.Errorf("Expected 'W' for alias 'concurrent99' but got '%s'", u.Short())
+			}
+			return
+		default:
+			NewUnit("MB/s").Short()
+			NewUnit("concurrent1")
+		}
+	}
+}
+
+// errOf returns the error of a function with two results
+func errOf[T any](_ T, err error) error {
+	return err
+}
@@ -0,0 +1,304 @@
+package ccunits
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DerivedTerm is a factor of a derived unit. It is either a unit like 'J' or 'GB' or a
+// counted entity like 'socket' or 'core' which is no unit.
+type DerivedTerm struct {
+	Unit     UnitValue // Unit of the term, the invalid unit value for entities
+	Entity   string    // Name of the counted entity if the term is no unit
+	Exponent int       // Exponent of the term, negative for terms in the denominator
+}
+
+// String returns the unit or entity of the term without exponent
+func (t DerivedTerm) String() string {
+	if len(t.Entity) > 0 {
+		return t.Entity
+	}
+	return t.Unit.Short()
+}
+
+// DerivedUnit is a unit expression like 'bytes/(socket*second)' or 'J/(core*s)' as product
+// of units and entities with exponents. It keeps the information of complex normalized
+// metrics which cannot be represented by a Unit with a single unit denominator.
+type DerivedUnit struct {
+	Terms []DerivedTerm // Terms in the order of their first appearance, no term has exponent 0
+}
+
+// String returns the expression in short notation like 'B/(socket*s)'
+func (d DerivedUnit) String() string {
+	num := make([]string, 0, len(d.Terms))
+	den := make([]string, 0, len(d.Terms))
+	for _, t := range d.Terms {
+		e := t.Exponent
+		if e < 0 {
+			e = -e
+		}
+		s := t.String()
+		if e != 1 {
+			s += "^" + strconv.Itoa(e)
+		}
+		if t.Exponent > 0 {
+			num = append(num, s)
+		} else {
+			den = append(den, s)
+		}
+	}
+	out := strings.Join(num, "*")
+	if len(num) == 0 {
+		out = "1"
+	}
+	switch {
+	case len(den) == 1:
+		out += "/" + den[0]
+	case len(den) > 1:
+		out += "/(" + strings.Join(den, "*") + ")"
+	}
+	return out
+}
+
+// Unit returns the derived unit as Unit if it can be represented by one, like 'GB/s' with a
+// single unit in the numerator and at most one unit without prefix in the denominator.
+func (d DerivedUnit) Unit() (Unit, bool) {
+	var num, den *DerivedTerm
+	for i := range d.Terms {
+		t := &d.Terms[i]
+		switch {
+		case len(t.Entity) > 0:
+			return invalidUnitValue.Unit(), false
+		case t.Exponent == 1 && num == nil:
+			num = t
+		case t.Exponent == -1 && den == nil && t.Unit.prefix == Base && t.Unit.divMeasure == InvalidMeasure:
+			den = t
+		default:
+			return invalidUnitValue.Unit(), false
+		}
+	}
+	if num == nil {
+		return invalidUnitValue.Unit(), false
+	}
+	if den == nil {
+		return num.Unit.Unit(), true
+	}
+	if num.Unit.divMeasure != InvalidMeasure {
+		return invalidUnitValue.Unit(), false
+	}
+	return num.Unit.WithDenominator(den.Unit.measure).Unit(), true
+}
+
+// NewDerivedUnit returns the derived unit of a unit like the terms 'MB' and 's^-1' for 'MB/s'.
+// Invalid units result in the derived unit without terms.
+func NewDerivedUnit(u Unit) DerivedUnit {
+	v := ValueOf(u)
+	if !v.Valid() {
+		return DerivedUnit{}
+	}
+	d := DerivedUnit{Terms: []DerivedTerm{{Unit: v.WithDenominator(InvalidMeasure), Exponent: 1}}}
+	if v.divMeasure != InvalidMeasure {
+		d.multiply(DerivedUnit{Terms: []DerivedTerm{{Unit: NewUnitValueFromParts(Base, v.divMeasure, InvalidMeasure), Exponent: 1}}}, -1)
+	}
+	return d
+}
+
+// PerEntity returns the derived unit divided by a counted entity like 'GB/(socket*s)' for
+// 'GB/s' per 'socket'. The entity is placed in front of the other terms of the denominator.
+func (d DerivedUnit) PerEntity(entity string) DerivedUnit {
+	terms := make([]DerivedTerm, 0, len(d.Terms)+1)
+	found := false
+	for _, t := range d.Terms {
+		if t.Entity == entity {
+			t.Exponent--
+			found = true
+			if t.Exponent == 0 {
+				continue
+			}
+		}
+		terms = append(terms, t)
+	}
+	if !found {
+		i := 0
+		for i < len(terms) && terms[i].Exponent > 0 {
+			i++
+		}
+		terms = append(terms[:i], append([]DerivedTerm{{Unit: invalidUnitValue, Entity: entity, Exponent: -1}}, terms[i:]...)...)
+	}
+	return DerivedUnit{Terms: terms}
+}
+
+// DerivedQuantity is a value together with a derived unit like 12.5 GB/(socket*s)
+type DerivedQuantity struct {
+	Value float64
+	Unit  DerivedUnit
+}
+
+// String returns the value and the derived unit like '12.5 GB/(socket*s)'
+func (q DerivedQuantity) String() string {
+	return fmt.Sprintf("%g %s", q.Value, q.Unit.String())
+}
+
+// multiply adds the terms of another derived unit with their exponents multiplied by a factor
+func (d *DerivedUnit) multiply(other DerivedUnit, factor int) {
+	for _, o := range other.Terms {
+		found := false
+		for i := range d.Terms {
+			if d.Terms[i].Unit == o.Unit && d.Terms[i].Entity == o.Entity {
+				d.Terms[i].Exponent += o.Exponent * factor
+				found = true
+				break
+			}
+		}
+		if !found {
+			o.Exponent *= factor
+			d.Terms = append(d.Terms, o)
+		}
+	}
+	terms := d.Terms[:0]
+	for _, t := range d.Terms {
+		if t.Exponent != 0 {
+			terms = append(terms, t)
+		}
+	}
+	d.Terms = terms
+}
+
+// wholeMeasure returns the measure if the whole string is a name of it like 'bytes' or 'sec'
+func wholeMeasure(s string) Measure {
+	matches, full := matchMeasures(s)
+	if len(matches) != 1 || !full {
+		return InvalidMeasure
+	}
+	return matches[0]
+}
+
+// derivedTerm returns the term for a name in a unit expression. Names which are a measure
+// (maybe with prefix) as a whole are units, all other names are entities.
+func derivedTerm(name string) DerivedTerm {
+	if m := wholeMeasure(name); m != InvalidMeasure {
+		return DerivedTerm{Unit: NewUnitValueFromParts(Base, m, InvalidMeasure), Exponent: 1}
+	}
+	if pre, rest := splitPrefix(name); len(pre) > 0 {
+		p, m := NewPrefix(pre), wholeMeasure(rest)
+		if p != InvalidPrefix && m != InvalidMeasure && !(isNonDividable(m) && p < Base) && !isPrefixless(m) {
+			return DerivedTerm{Unit: NewUnitValueFromParts(p, m, InvalidMeasure), Exponent: 1}
+		}
+	}
+	return DerivedTerm{Unit: invalidUnitValue, Entity: name, Exponent: 1}
+}
+
+// derivedParser is a recursive descent parser for unit expressions
+type derivedParser struct {
+	input string
+	pos   int
+}
+
+// peek returns the next character after skipping spaces or 0 at the end
+func (p *derivedParser) peek() byte {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// product parses terms combined with '*' and '/'
+func (p *derivedParser) product() (DerivedUnit, error) {
+	d, err := p.power()
+	if err != nil {
+		return d, err
+	}
+	for {
+		factor := 1
+		switch p.peek() {
+		case '*':
+		case '/':
+			factor = -1
+		default:
+			return d, nil
+		}
+		p.pos++
+		o, err := p.power()
+		if err != nil {
+			return d, err
+		}
+		d.multiply(o, factor)
+	}
+}
+
+// power parses a term with optional integer exponent like 's^2'
+func (p *derivedParser) power() (DerivedUnit, error) {
+	d, err := p.primary()
+	if err != nil || p.peek() != '^' {
+		return d, err
+	}
+	p.pos++
+	start := p.pos
+	if p.pos < len(p.input) && p.input[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	e, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return d, fmt.Errorf("invalid exponent at position %d", start)
+	}
+	var out DerivedUnit
+	out.multiply(d, e)
+	return out, nil
+}
+
+// primary parses a name, '1' or an expression in parentheses
+func (p *derivedParser) primary() (DerivedUnit, error) {
+	switch c := p.peek(); {
+	case c == 0:
+		return DerivedUnit{}, fmt.Errorf("unexpected end")
+	case c == '(':
+		p.pos++
+		d, err := p.product()
+		if err != nil {
+			return d, err
+		}
+		if p.peek() != ')' {
+			return d, fmt.Errorf("missing ')' at position %d", p.pos)
+		}
+		p.pos++
+		return d, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(" */()^", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	switch {
+	case len(name) == 0:
+		return DerivedUnit{}, fmt.Errorf("unexpected '%c' at position %d", p.input[start], start)
+	case name == "1":
+		return DerivedUnit{}, nil
+	case unicode.IsDigit(rune(name[0])):
+		return DerivedUnit{}, fmt.Errorf("invalid name '%s'", name)
+	}
+	return DerivedUnit{Terms: []DerivedTerm{derivedTerm(name)}}, nil
+}
+
+// ParseDerivedUnit parses a unit expression like 'bytes/(socket*second)', 'J/(core*s)' or
+// 'Flops/s^2' into a derived unit. Names which are a measure as a whole, maybe with prefix,
+// are units, all other names are counted entities like 'socket' or 'core'. Terms are combined
+// with '*' and '/', grouped with parentheses and raised to integer powers with '^'.
+func ParseDerivedUnit(expr string) (DerivedUnit, error) {
+	p := derivedParser{input: strings.TrimSpace(expr)}
+	d, err := p.product()
+	if err == nil && p.peek() != 0 {
+		err = fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos)
+	}
+	if err != nil {
+		return DerivedUnit{}, fmt.Errorf("invalid unit expression '%s': %v: %w", expr, err, ErrInvalidMeasure)
+	}
+	return d, nil
+}
@@ -0,0 +1,67 @@
+package ccunits
+
+import "testing"
+
+func TestParseDerivedUnit(t *testing.T) {
+	for input, expected := range map[string]string{
+		"bytes/(socket*second)": "B/(socket*s)",
+		"J/(core*s)":            "J/(core*s)",
+		"GB/s":                  "GB/s",
+		"Flops/s^2":             "Flops/s^2",
+		"1/s":                   "1/s",
+		"s*W/s":                 "W",
+		"packets/node/sec":      "packets/(node*s)",
+		"(kJ*core^-1)/ms":       "KJ/(core*ms)",
+	} {
+		d, err := ParseDerivedUnit(input)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", input, err)
+			continue
+		}
+		if d.String() != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input, d.String())
+		}
+	}
+	for _, input := range []string{"", "B/(s", "B/s)", "B^x", "2/s", "B**s"} {
+		if _, err := ParseDerivedUnit(input); err == nil {
+			t.Errorf("Expected error for '%s'", input)
+		}
+	}
+
+	d, _ := ParseDerivedUnit("bytes/(socket*second)")
+	if len(d.Terms) != 3 || d.Terms[1].Entity != "socket" || d.Terms[2].Unit != NewUnitValue("s") || d.Terms[2].Exponent != -1 {
+		t.Errorf("Unexpected terms %v", d.Terms)
+	}
+	if u, ok := d.Unit(); ok || u.Valid() {
+		t.Errorf("Expected no unit for '%s' but got '%s'", d.String(), u.Short())
+	}
+	d, _ = ParseDerivedUnit("Mbytes/second")
+	if u, ok := d.Unit(); !ok || u.Short() != "MB/s" {
+		t.Errorf("Expected unit 'MB/s' for '%s'", d.String())
+	}
+}
+
+func TestPerResource(t *testing.T) {
+	topo := Topology{Sockets: 2, NUMADomains: 8, Cores: 128}
+	for _, c := range []struct {
+		q        Quantity
+		r        Resource
+		expected string
+	}{
+		{NewQuantity(400, "GB/s"), ResourceSocket, "200 GB/(socket*s)"},
+		{NewQuantity(6.4, "TFlops/s"), ResourceCore, "0.05 TFlops/(core*s)"},
+		{NewQuantity(400, "W"), ResourceNUMADomain, "50 W/numa"},
+	} {
+		q, err := PerResource(c.q, topo, c.r)
+		if err != nil || q.String() != c.expected {
+			t.Errorf("Expected '%s' but got '%s': %v", c.expected, q.String(), err)
+		}
+	}
+	if _, err := PerResource(NewQuantity(1, "W"), Topology{}, ResourceSocket); err == nil {
+		t.Errorf("Expected error for topology without sockets")
+	}
+	d, _ := ParseDerivedUnit("J/(core*s)")
+	if s := d.PerEntity("core").String(); s != "J/(core^2*s)" {
+		t.Errorf("Expected 'J/(core^2*s)' but got '%s'", s)
+	}
+}
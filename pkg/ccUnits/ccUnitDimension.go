@@ -0,0 +1,198 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Indices of the base dimensions in a dimension vector
+const (
+	dimData = iota
+	dimFlop
+	dimTime
+	dimTemperature
+	dimCycle
+	dimRequest
+	dimPacket
+	dimEvent
+	dimItem
+	dimEnergy
+	dimCurrent
+	dimRevolution
+	numDimensions
+)
+
+// dimension is the product of the base dimensions with the exponents as vector like
+// energy^1 * time^-1 for power. The built-in dimensions of the measures are generated
+// out of ccUnitBuiltin.yaml.
+type dimension [numDimensions]int8
+
+// add returns the dimension of the product of two dimensions
+func (d dimension) add(o dimension, sign int8) dimension {
+	for i := range d {
+		d[i] += sign * o[i]
+	}
+	return d
+}
+
+// dimensionNames are the names of the base dimensions in a dimension vector
+var dimensionNames = [numDimensions]string{"data", "flop", "time", "temperature", "cycle", "request", "packet", "event", "item", "energy", "current", "revolution"}
+
+// String returns the dimension like 'energy/time' or '1' for dimensionless
+func (d dimension) String() string {
+	var num, den []string
+	for i, e := range d {
+		name := dimensionNames[i]
+		if e > 1 || e < -1 {
+			name = fmt.Sprintf("%s^%d", name, abs8(e))
+		}
+		switch {
+		case e > 0:
+			num = append(num, name)
+		case e < 0:
+			den = append(den, name)
+		}
+	}
+	s := "1"
+	if len(num) > 0 {
+		s = strings.Join(num, "*")
+	}
+	if len(den) > 0 {
+		s += "/" + strings.Join(den, "/")
+	}
+	return s
+}
+
+// abs8 returns the absolute value of an exponent
+func abs8(e int8) int8 {
+	if e < 0 {
+		return -e
+	}
+	return e
+}
+
+// unitDimension returns the dimension and scale of a unit like energy/time for 'J/s'.
+// The scale is the value of the unit without prefix in the unit of the dimension like 0.01
+// for percentages. Units with logarithmic or registered measures have no dimension.
+func unitDimension(v UnitValue) (dimension, float64, bool) {
+	d, scale, ok := builtinDimension(v.measure)
+	if !ok || v.divMeasure == InvalidMeasure {
+		return d, scale, ok
+	}
+	div, divScale, ok := builtinDimension(v.divMeasure)
+	if !ok {
+		return dimension{}, 0, false
+	}
+	return d.add(div, -1), scale / divScale, true
+}
+
+// scaledFactor returns the prefix factor multiplied with the ratio of the scales of two
+// units. The integer factors are kept if the result is still exact.
+func scaledFactor(pf prefixFactor, ratio float64) prefixFactor {
+	if ratio == 1 {
+		return pf
+	}
+	f := pf.factor * ratio
+	out := prefixFactor{factor: f}
+	if r := math.Round(f); f >= 1 && f < math.MaxInt64 && math.Abs(f-r) <= 1e-12*r {
+		out.factor, out.mul = r, int64(r)
+	}
+	if r := math.Round(1 / f); f < 1 && f > 0 && math.Abs(1/f-r) <= 1e-12*r {
+		out.factor, out.div = 1/r, int64(r)
+	}
+	return out
+}
+
+// dimensionUnit returns the unit without prefix for a dimension and its scale. A single
+// measure is preferred over measures per time like 'B/s' and those over other fractions
+// like 'Flops/J'. Among units with the same dimension, the one with scale 1 is used, so
+// dimensionless results are ratios. Temperatures are no results of arithmetic.
+func dimensionUnit(d dimension) (UnitValue, float64, bool) {
+	registryLock.RLock()
+	measures := make([]Measure, 0, len(MeasuresMap))
+	for m := range MeasuresMap {
+		if _, _, ok := builtinDimension(m); ok && m != TemperatureC && m != TemperatureF {
+			measures = append(measures, m)
+		}
+	}
+	registryLock.RUnlock()
+	sort.Slice(measures, func(i, j int) bool { return measures[i] < measures[j] })
+
+	denominators := append([]Measure{InvalidMeasure, Time}, measures...)
+	for _, div := range denominators {
+		var found UnitValue
+		var scale float64
+		for _, m := range measures {
+			if m == div {
+				continue
+			}
+			v := UnitValue{prefix: Base, measure: m, divMeasure: div}
+			vd, s, ok := unitDimension(v)
+			if ok && vd == d && (scale == 0 || (scale != 1 && s == 1)) {
+				found, scale = v, s
+			}
+		}
+		if scale != 0 {
+			return found, scale, true
+		}
+	}
+	return invalidUnitValue, 0, false
+}
+
+// arithmeticOperand returns the value of a quantity in the unit of its dimension
+func arithmeticOperand(q Quantity) (float64, dimension, error) {
+	if !q.Valid() {
+		return 0, dimension{}, fmt.Errorf("invalid unit for arithmetic: %w", ErrInvalidMeasure)
+	}
+	v := ValueOf(q.Unit)
+	d, scale, ok := unitDimension(v)
+	if !ok || v.measure == TemperatureC || v.measure == TemperatureF {
+		return 0, dimension{}, fmt.Errorf("no arithmetic for unit '%s': %w", v.Short(), ErrIncompatibleMeasure)
+	}
+	return NewPrefixConverter(v.prefix, Base).ApplyFloat64(q.Value) * scale, d, nil
+}
+
+// arithmeticResult returns the quantity of a value in the unit of a dimension
+func arithmeticResult(value float64, d dimension, a Quantity, b Quantity) (Quantity, error) {
+	v, scale, ok := dimensionUnit(d)
+	if !ok {
+		return Quantity{}, fmt.Errorf("no unit for the result of '%s' and '%s': %w", a.Unit.Short(), b.Unit.Short(), ErrIncompatibleMeasure)
+	}
+	return Quantity{Value: value / scale, Unit: v.Unit()}, nil
+}
+
+// Multiply returns the product of two quantities with the unit derived from their dimensions
+// like 50 W * 2 s = 100 J or 2 V * 3 A = 6 W. The result has no prefix. It returns an error
+// for temperatures, logarithmic and registered measures and if no unit has the dimension of
+// the result.
+func Multiply(a Quantity, b Quantity) (Quantity, error) {
+	va, da, err := arithmeticOperand(a)
+	if err != nil {
+		return Quantity{}, err
+	}
+	vb, db, err := arithmeticOperand(b)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return arithmeticResult(va*vb, da.add(db, 1), a, b)
+}
+
+// Divide returns the quotient of two quantities with the unit derived from their dimensions
+// like 100 J / 2 s = 50 W, 1 GFlops/s / 100 W = 1e7 Flops/J or 2 GB / 8 GB = 0.25 ratio. The
+// result has no prefix. The same errors as for Multiply are returned and for division by 0.
+func Divide(a Quantity, b Quantity) (Quantity, error) {
+	va, da, err := arithmeticOperand(a)
+	if err != nil {
+		return Quantity{}, err
+	}
+	vb, db, err := arithmeticOperand(b)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if vb == 0 {
+		return Quantity{}, fmt.Errorf("division of %s by zero: %w", a.String(), ErrInvalidValue)
+	}
+	return arithmeticResult(va/vb, da.add(db, -1), a, b)
+}
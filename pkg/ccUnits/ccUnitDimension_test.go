@@ -0,0 +1,67 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDimensionConversion(t *testing.T) {
+	for _, c := range []struct {
+		in, out string
+		factor  float64
+	}{
+		{"kJ/s", "W", 1000},
+		{"W", "J/s", 1},
+		{"W/A", "V", 1},
+		{"ratio", "%", 100},
+	} {
+		conv, err := NewConverter(NewUnit(c.in), NewUnit(c.out))
+		if err != nil {
+			t.Errorf("Unexpected error for '%s' to '%s': %v", c.in, c.out, err)
+			continue
+		}
+		if f := conv.Factor(); math.Abs(f-c.factor) > 1e-12 {
+			t.Errorf("Expected factor %v for '%s' to '%s' but got %v", c.factor, c.in, c.out, f)
+		}
+	}
+	for _, c := range [][2]string{{"J", "W"}, {"B/s", "Hz"}, {"cyc/s", "Hz"}, {"dB", "W"}} {
+		if NewUnit(c[0]).Compatible(NewUnit(c[1])) {
+			t.Errorf("Expected '%s' and '%s' to be incompatible", c[0], c[1])
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	for _, c := range []struct {
+		a, b     Quantity
+		divide   bool
+		expected string
+	}{
+		{NewQuantity(50, "W"), NewQuantity(2, "s"), false, "100 J"},
+		{NewQuantity(2, "V"), NewQuantity(3, "A"), false, "6 W"},
+		{NewQuantity(100, "kJ"), NewQuantity(2, "s"), true, "50000 W"},
+		{NewQuantity(1, "GFlops/s"), NewQuantity(100, "W"), true, "1e+07 Flops/J"},
+		{NewQuantity(2, "GB"), NewQuantity(8, "GB"), true, "0.25 ratio"},
+		{NewQuantity(3, "MB"), NewQuantity(2, "ms"), true, "1.5e+09 B/s"},
+	} {
+		op, f := "*", Multiply
+		if c.divide {
+			op, f = "/", Divide
+		}
+		q, err := f(c.a, c.b)
+		if err != nil {
+			t.Errorf("Unexpected error for %s %s %s: %v", c.a.String(), op, c.b.String(), err)
+		} else if q.String() != c.expected {
+			t.Errorf("Expected '%s' for %s %s %s but got '%s'", c.expected, c.a.String(), op, c.b.String(), q.String())
+		}
+	}
+	if _, err := Multiply(NewQuantity(20, "degC"), NewQuantity(1, "s")); err == nil {
+		t.Errorf("Expected error for arithmetic with temperatures")
+	}
+	if _, err := Multiply(NewQuantity(2, "B"), NewQuantity(3, "B")); err == nil {
+		t.Errorf("Expected error for result without unit")
+	}
+	if _, err := Divide(NewQuantity(2, "J"), NewQuantity(0, "s")); err == nil {
+		t.Errorf("Expected error for division by zero")
+	}
+}
@@ -0,0 +1,122 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// Aggregation is the function combining the values of a window when downsampling series,
+// e.g. for the long-term archive tiers
+type Aggregation int
+
+const (
+	AggregateAvg Aggregation = iota // Average of the values like for gauges and rates
+	AggregateSum                    // Sum of the values like for counts per interval
+	AggregateMax                    // Maximum of the values
+)
+
+// String returns the name of the aggregation
+func (a Aggregation) String() string {
+	switch a {
+	case AggregateAvg:
+		return "avg"
+	case AggregateSum:
+		return "sum"
+	case AggregateMax:
+		return "max"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the name of the aggregation, so it is readable in configurations
+func (a Aggregation) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText reads the name of the aggregation
+func (a *Aggregation) UnmarshalText(text []byte) error {
+	for _, agg := range []Aggregation{AggregateAvg, AggregateSum, AggregateMax} {
+		if agg.String() == string(text) {
+			*a = agg
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid aggregation '%s'", string(text))
+}
+
+// DefaultAggregation returns the aggregation of a unit out of the metadata of its measure:
+// counts per interval of counter measures like Flops or Packets are summed, all other values
+// like gauges, rates and percentages are averaged.
+func DefaultAggregation(u Unit) Aggregation {
+	if u == nil || !u.Valid() || u.GetUnitDenominator() != InvalidMeasure {
+		return AggregateAvg
+	}
+	if md, ok := Metadata(u.GetMeasure()); ok && md.Counter {
+		return AggregateSum
+	}
+	return AggregateAvg
+}
+
+// summable checks whether values of a unit can be summed. Rates, percentages, ratios,
+// logarithmic measures, temperatures and frequencies cannot be summed over time.
+func summable(u Unit) bool {
+	if u.GetUnitDenominator() != InvalidMeasure || isPrefixless(u.GetMeasure()) {
+		return false
+	}
+	md, _ := Metadata(u.GetMeasure())
+	return md.Category != CategoryTemperature && md.Category != CategoryFrequency
+}
+
+// Downsample combines the values of each window of the given size into one value with the
+// default aggregation of the unit (see DefaultAggregation()). The last window may be shorter.
+func Downsample(values []float64, window int, u Unit) ([]float64, error) {
+	return DownsampleWith(values, window, u, DefaultAggregation(u))
+}
+
+// DownsampleWith combines the values of each window of the given size into one value with
+// the aggregation. NaN values (gaps) are skipped, windows without values result in NaN. It
+// returns an error for sums of units which cannot be summed like percentages or rates.
+func DownsampleWith(values []float64, window int, u Unit, agg Aggregation) ([]float64, error) {
+	if u == nil || !u.Valid() {
+		return nil, fmt.Errorf("invalid unit for downsampling: %w", ErrInvalidMeasure)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid window size %d: %w", window, ErrInvalidValue)
+	}
+	if agg == AggregateSum && !summable(u) {
+		return nil, fmt.Errorf("values of '%s' cannot be summed: %w", u.Short(), ErrIncompatibleMeasure)
+	}
+	out := make([]float64, 0, (len(values)+window-1)/window)
+	for start := 0; start < len(values); start += window {
+		end := start + window
+		if end > len(values) {
+			end = len(values)
+		}
+		out = append(out, aggregate(values[start:end], agg))
+	}
+	return out, nil
+}
+
+// aggregate combines the values of a window skipping NaN values
+func aggregate(values []float64, agg Aggregation) float64 {
+	res, n := 0.0, 0
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		switch {
+		case agg == AggregateMax && (n == 0 || v > res):
+			res = v
+		case agg != AggregateMax:
+			res += v
+		}
+		n++
+	}
+	switch {
+	case n == 0:
+		return math.NaN()
+	case agg == AggregateAvg:
+		return res / float64(n)
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDownsample(t *testing.T) {
+	values := []float64{1, 2, math.NaN(), 4, 5}
+	for _, c := range []struct {
+		unit     string
+		expected []float64
+	}{
+		{"Flops", []float64{3, 4, 5}}, // Counter: sum
+		{"%", []float64{1.5, 4, 5}},   // Gauge: average
+		{"MB/s", []float64{1.5, 4, 5}},
+	} {
+		out, err := Downsample(values, 2, NewUnit(c.unit))
+		if err != nil || len(out) != len(c.expected) {
+			t.Fatalf("Unexpected result %v for '%s': %v", out, c.unit, err)
+		}
+		for i := range out {
+			if out[i] != c.expected[i] {
+				t.Errorf("Expected %v for '%s' but got %v", c.expected, c.unit, out)
+				break
+			}
+		}
+	}
+	if out, err := DownsampleWith(values, 3, NewUnit("W"), AggregateMax); err != nil || len(out) != 2 || out[0] != 2 || out[1] != 5 {
+		t.Errorf("Expected [2 5] but got %v: %v", out, err)
+	}
+	if out, _ := DownsampleWith([]float64{math.NaN()}, 1, NewUnit("W"), AggregateAvg); !math.IsNaN(out[0]) {
+		t.Errorf("Expected NaN for window without values but got %v", out[0])
+	}
+	for _, unit := range []string{"%", "MB/s", "degC"} {
+		if _, err := DownsampleWith(values, 2, NewUnit(unit), AggregateSum); err == nil {
+			t.Errorf("Expected error for sum of '%s'", unit)
+		}
+	}
+	if _, err := Downsample(values, 0, NewUnit("W")); err == nil {
+		t.Errorf("Expected error for window size 0")
+	}
+}
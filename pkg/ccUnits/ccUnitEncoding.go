@@ -0,0 +1,129 @@
+package ccunits
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Binary encoding of units and quantities for binary codecs like MessagePack, CBOR or gob.
+// The types implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler, which these
+// codecs use without codec-specific code, so units are not stored as strings and re-parsed
+// at every hop. Units of built-in measures are encoded with the stable IDs of the measures,
+// units of registered measures with their short string because the IDs of registered
+// measures depend on the order of the registrations. Units created by NewUnit() get the
+// methods of their UnitValue.
+
+// Kinds of encoded units
+const (
+	encodedUnitIDs    byte = 1 // Prefix (float64), measure and unit denominator (uint16)
+	encodedUnitString byte = 2 // Short string of the unit
+)
+
+// Length of a unit encoded with IDs
+const encodedUnitIDsLen = 1 + 8 + 2 + 2
+
+// appendBinary appends the binary encoding of the unit value to b
+func (u UnitValue) appendBinary(b []byte) []byte {
+	_, builtin := builtinMeasure(u.measure)
+	if _, ok := builtinMeasure(u.divMeasure); !ok && u.divMeasure != InvalidMeasure {
+		builtin = false
+	}
+	if !builtin && u.Valid() {
+		b = append(b, encodedUnitString)
+		return append(b, u.Short()...)
+	}
+	b = append(b, encodedUnitIDs)
+	b = binary.BigEndian.AppendUint64(b, math.Float64bits(float64(u.prefix)))
+	b = binary.BigEndian.AppendUint16(b, uint16(u.measure))
+	return binary.BigEndian.AppendUint16(b, uint16(u.divMeasure))
+}
+
+// decodeUnitValue decodes a unit value and returns the number of used bytes
+func decodeUnitValue(data []byte) (UnitValue, int, error) {
+	if len(data) == 0 {
+		return invalidUnitValue, 0, fmt.Errorf("empty encoded unit: %w", ErrInvalidMeasure)
+	}
+	switch data[0] {
+	case encodedUnitIDs:
+		if len(data) < encodedUnitIDsLen {
+			return invalidUnitValue, 0, fmt.Errorf("truncated encoded unit: %w", ErrInvalidMeasure)
+		}
+		p := Prefix(math.Float64frombits(binary.BigEndian.Uint64(data[1:])))
+		m := Measure(binary.BigEndian.Uint16(data[9:]))
+		div := Measure(binary.BigEndian.Uint16(data[11:]))
+		if m == InvalidMeasure {
+			return invalidUnitValue, encodedUnitIDsLen, nil
+		}
+		u := NewUnitValueFromParts(p, m, div)
+		if !u.Valid() {
+			return invalidUnitValue, 0, fmt.Errorf("unknown measure %d or prefix %g in encoded unit: %w", m, float64(p), ErrInvalidMeasure)
+		}
+		return u, encodedUnitIDsLen, nil
+	case encodedUnitString:
+		u := InternUnit(string(data[1:]))
+		if !u.Valid() {
+			return invalidUnitValue, 0, fmt.Errorf("invalid encoded unit: %w", invalidUnitError(string(data[1:])))
+		}
+		return u, len(data), nil
+	}
+	return invalidUnitValue, 0, fmt.Errorf("unknown kind %d of encoded unit: %w", data[0], ErrInvalidMeasure)
+}
+
+// MarshalBinary encodes the unit value
+func (u UnitValue) MarshalBinary() ([]byte, error) {
+	return u.appendBinary(make([]byte, 0, encodedUnitIDsLen)), nil
+}
+
+// UnmarshalBinary decodes a unit value encoded by MarshalBinary()
+func (u *UnitValue) UnmarshalBinary(data []byte) error {
+	v, n, err := decodeUnitValue(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("trailing data after encoded unit: %w", ErrInvalidMeasure)
+	}
+	*u = v
+	return nil
+}
+
+// MarshalText writes the short string of the unit value like 'MB/s', so it is readable in
+// JSON and YAML
+func (u UnitValue) MarshalText() ([]byte, error) {
+	return []byte(u.Short()), nil
+}
+
+// UnmarshalText parses the unit string. It returns an error for invalid units.
+func (u *UnitValue) UnmarshalText(text []byte) error {
+	v := InternUnit(string(text))
+	if !v.Valid() {
+		return invalidUnitError(string(text))
+	}
+	*u = v
+	return nil
+}
+
+// MarshalBinary encodes the quantity as value (float64) followed by the encoded unit
+func (q Quantity) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 8+encodedUnitIDsLen)
+	b = binary.BigEndian.AppendUint64(b, math.Float64bits(q.Value))
+	if q.Unit == nil {
+		return invalidUnitValue.appendBinary(b), nil
+	}
+	return ValueOf(q.Unit).appendBinary(b), nil
+}
+
+// UnmarshalBinary decodes a quantity encoded by MarshalBinary()
+func (q *Quantity) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("truncated encoded quantity: %w", ErrInvalidMeasure)
+	}
+	var u UnitValue
+	if err := u.UnmarshalBinary(data[8:]); err != nil {
+		return err
+	}
+	q.Value = math.Float64frombits(binary.BigEndian.Uint64(data))
+	q.Unit = &unit{u}
+	return nil
+}
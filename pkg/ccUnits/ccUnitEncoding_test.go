@@ -0,0 +1,103 @@
+package ccunits
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestBinaryEncoding(t *testing.T) {
+	restoreRegistry(t)
+	m, err := RegisterMeasure(MeasureDefinition{Long: "Widgets", Short: "widget"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, q := range []Quantity{
+		NewQuantity(12.5, "GB/s"),
+		NewQuantity(-3, "degF"),
+		NewQuantity(7, "KiB"),
+		{Value: 2, Unit: NewUnitFromParts(Kilo, m, Time)},
+		{Value: 1, Unit: INVALID_UNIT},
+	} {
+		data, err := q.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var out Quantity
+		if err := out.UnmarshalBinary(data); err != nil || out.Value != q.Value || out.Unit.Short() != q.Unit.Short() {
+			t.Errorf("Expected %s after decoding but got %s: %v", q.String(), out.String(), err)
+		}
+	}
+
+	// Codecs like gob and MessagePack use the binary encoding
+	type message struct {
+		Name     string
+		Quantity Quantity
+		Unit     UnitValue
+	}
+	in := message{Name: "mem_bw", Quantity: NewQuantity(12.5, "GB/s"), Unit: NewUnitValue("MHz")}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var out message
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil || out.Quantity.String() != "12.5 GB/s" || out.Unit != in.Unit {
+		t.Errorf("Expected %v after gob round trip but got %v: %v", in, out, err)
+	}
+
+	// JSON uses the short string
+	data, err := json.Marshal(in.Unit)
+	if err != nil || string(data) != `"MHz"` {
+		t.Errorf("Expected \"MHz\" but got %s: %v", string(data), err)
+	}
+	var u UnitValue
+	if err := json.Unmarshal([]byte(`"xyz"`), &u); !errors.Is(err, ErrInvalidMeasure) {
+		t.Errorf("Expected error for invalid unit but got %v", err)
+	}
+
+	for _, data := range [][]byte{{}, {encodedUnitIDs, 1}, {9}, {encodedUnitIDs, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0, 0}} {
+		if err := u.UnmarshalBinary(data); !errors.Is(err, ErrInvalidMeasure) {
+			t.Errorf("Expected error for encoded unit %v but got %v", data, err)
+		}
+	}
+}
+
+func TestTextEncodingRoundTrip(t *testing.T) {
+	for m := range MeasuresMap {
+		if _, ok := builtinMeasure(m); !ok {
+			continue
+		}
+		for _, e := range prefixTableEntries {
+			// Units the parser reads differently like 'mB' (MB) or a prefix of '%' cannot be written as text
+			if (isNonDividable(m) && e.prefix < Base) || (isPrefixless(m) && e.prefix != Base) {
+				continue
+			}
+			for _, div := range []Measure{InvalidMeasure, Time} {
+				in := NewUnitValueFromParts(e.prefix, m, div)
+				text, err := in.MarshalText()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				var out UnitValue
+				if err := out.UnmarshalText(text); err != nil || out != in {
+					t.Errorf("Expected %s after text round trip of '%s' but got %s: %v", in.String(), string(text), out.String(), err)
+				}
+
+				// Also as field of a struct in JSON
+				type message struct {
+					Unit UnitValue `json:"unit"`
+				}
+				data, err := json.Marshal(message{in})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				var msg message
+				if err := json.Unmarshal(data, &msg); err != nil || msg.Unit != in {
+					t.Errorf("Expected %s after JSON round trip of %s but got %s: %v", in.String(), string(data), msg.Unit.String(), err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package ccunits
+
+import "sort"
+
+// binaryPrefix checks whether a prefix is a binary prefix like Kibi
+func binaryPrefix(p Prefix) bool {
+	for _, e := range prefixTableEntries {
+		if e.prefix == p {
+			return e.base == 2 && e.exponent != 0
+		}
+	}
+	return false
+}
+
+// usefulPrefix checks whether a prefix is used with a measure. Measures that cannot be
+// divided get no prefix below Base, binary prefixes are only used for data and percentages,
+// ratios, logarithmic measures and temperatures get no prefix at all.
+func usefulPrefix(p Prefix, m Measure) bool {
+	switch {
+	case isPrefixless(m) || m == TemperatureC || m == TemperatureF:
+		return p == Base
+	case isNonDividable(m) && p < Base:
+		return false
+	case binaryPrefix(p):
+		md, _ := Metadata(m)
+		return md.Category == CategoryData
+	}
+	return true
+}
+
+// EnumerateUnits returns the canonical short strings of all useful combinations of prefix
+// and measure like 'KB', 'MB' or 'GHz', e.g. to populate unit selection dropdowns in UIs.
+// If measures are given, only units of these measures are returned. The units are ordered
+// by measure ID and prefix factor. Prefixes that are not used with a measure like Milli for
+// Bytes or Kibi for Hertz are left out, as well as units whose short string is not parsed
+// back to the same unit.
+func EnumerateUnits(measures ...Measure) []string {
+	registryLock.RLock()
+	prefixes := make([]Prefix, 0, len(PrefixDataMap))
+	for p := range PrefixDataMap {
+		prefixes = append(prefixes, p)
+	}
+	if len(measures) == 0 {
+		measures = make([]Measure, 0, len(MeasuresMap))
+		for m := range MeasuresMap {
+			measures = append(measures, m)
+		}
+		sort.Slice(measures, func(i, j int) bool { return measures[i] < measures[j] })
+	} else {
+		known := make([]Measure, 0, len(measures))
+		for _, m := range measures {
+			if _, ok := MeasuresMap[m]; ok {
+				known = append(known, m)
+			}
+		}
+		measures = known
+	}
+	registryLock.RUnlock()
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+
+	units := make([]string, 0, len(measures)*len(prefixes))
+	for _, m := range measures {
+		for _, p := range prefixes {
+			if !usefulPrefix(p, m) {
+				continue
+			}
+			v := UnitValue{prefix: p, measure: m, divMeasure: InvalidMeasure}
+			if s := v.Short(); parseUnitValue(s) == v {
+				units = append(units, s)
+			}
+		}
+	}
+	return units
+}
@@ -0,0 +1,22 @@
+package ccunits
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnumerateUnits(t *testing.T) {
+	bytes := strings.Join(EnumerateUnits(Bytes), ",")
+	if !strings.HasPrefix(bytes, "B,") || !strings.Contains(bytes, ",KB,KiB,MB,MiB,") || strings.Contains(bytes, "mB") {
+		t.Errorf("Unexpected units for Bytes: %s", bytes)
+	}
+	if units := strings.Join(EnumerateUnits(Frequency, Percentage, Measure(1000)), ","); units != "nHz,uHz,mHz,Hz,KHz,MHz,GHz,THz,PHz,EHz,ZHz,YHz,%" {
+		t.Errorf("Unexpected units %s", units)
+	}
+	all := EnumerateUnits()
+	for _, u := range all {
+		if v := NewUnitValue(u); !v.Valid() || v.Short() != u {
+			t.Errorf("Enumerated unit '%s' is not canonical", u)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package ccunits
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors wrapped by the errors of the package. Callers can check the cause of an
+// error with errors.Is() instead of matching its message.
+var (
+	// ErrInvalidPrefix is returned for unknown prefixes or prefixes not allowed for a measure
+	ErrInvalidPrefix = errors.New("invalid prefix")
+	// ErrInvalidMeasure is returned for unknown measures and invalid units
+	ErrInvalidMeasure = errors.New("invalid measure")
+	// ErrIncompatibleMeasure is returned if values cannot be converted between units
+	ErrIncompatibleMeasure = errors.New("incompatible measures")
+	// ErrOverflow is returned if a converted value does not fit into its type or a value is
+	// outside of the valid range of its measure
+	ErrOverflow = errors.New("value out of range")
+	// ErrInvalidValue is returned for values that cannot be used like non-positive intervals,
+	// divisions by zero or counters that decreased
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrStackedPrefix is returned for unit strings with two prefixes like 'kMB'. It wraps
+	// ErrInvalidPrefix.
+	ErrStackedPrefix = fmt.Errorf("stacked prefixes: %w", ErrInvalidPrefix)
+	// ErrAmbiguous is returned for unit strings which could mean different units
+	ErrAmbiguous = errors.New("ambiguous unit")
+)
@@ -0,0 +1,81 @@
+package ccunits
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	_, errMeasure := ParseUnit("xyz")
+	errPrefix := RegisterPrefixAlias("dozen", Prefix(12))
+	_, errAmbiguous := ParseUnit("mB", WithStrictSI())
+	_, errIncompatible := NewQuantity(1, "W").ConvertTo(NewUnit("B"))
+	_, errOverflow := NewQuantity(math.MaxFloat64, "YB").ConvertToPrefix(Base)
+	_, errInt := NewPrefixConverter(Exa, Base).CheckedInt64(10)
+	_, errStacked := ParseUnit("kMB")
+	_, errStackedHours := ParseUnit("kMh")
+	_, errPrefixless := ParseUnit("kh")
+	for _, c := range []struct {
+		err, target error
+	}{
+		{errMeasure, ErrInvalidMeasure},
+		{errPrefix, ErrInvalidPrefix},
+		{errAmbiguous, ErrAmbiguous},
+		{errIncompatible, ErrIncompatibleMeasure},
+		{errOverflow, ErrOverflow},
+		{errInt, ErrOverflow},
+		{errStacked, ErrStackedPrefix},
+		{errStacked, ErrInvalidPrefix},
+		{errStackedHours, ErrStackedPrefix},
+		{errPrefixless, ErrInvalidPrefix},
+	} {
+		if !errors.Is(c.err, c.target) {
+			t.Errorf("Expected error wrapping '%v' but got '%v'", c.target, c.err)
+		}
+	}
+	if errors.Is(errPrefixless, ErrStackedPrefix) {
+		t.Errorf("Unexpected stacked prefix error for 'kh': %v", errPrefixless)
+	}
+	if v, err := NewPrefixConverter(Kilo, Base).CheckedInt64(-5); err != nil || v != -5000 {
+		t.Errorf("Expected -5000 but got %d (%v)", v, err)
+	}
+}
+
+func TestSentinelErrorsOfAPIs(t *testing.T) {
+	restoreRegistry(t)
+	_, _, errGpu := NormalizeGpuValue(NVML, "unknown_field", 1.0)
+	_, errSource := SourceUnit{}.Convert(1.0)
+	_, errMeasure := RegisterMeasure(MeasureDefinition{Long: "Widgets"})
+	_, errDuplicate := RegisterMeasure(MeasureDefinition{Long: "Octets", Short: "B"})
+	errLoad := LoadDefinitions(filepath.Join(t.TempDir(), "missing.yaml"))
+	for _, c := range []struct {
+		err, target error
+	}{
+		{errGpu, ErrInvalidMeasure},
+		{errSource, ErrInvalidMeasure},
+		{errOf(GetSnmpCounterRate("unknown", 1, 2, time.Second)), ErrInvalidMeasure},
+		{errOf(GetSnmpCounterRate("ifHCInOctets", 1, 2, 0)), ErrInvalidValue},
+		{errOf(GetSnmpCounterRate("ifHCInOctets", 2, 1, time.Second)), ErrInvalidValue},
+		{errOf(GetSnmpInterfaceSpeed("unknown", 1)), ErrInvalidMeasure},
+		{errOf(NewSysfsQuantity("/unknown/file", 3)), ErrInvalidMeasure},
+		{errOf(PagesToBytes(NewQuantity(1, "count"), 0, NewUnit("B"))), ErrInvalidValue},
+		{errOf(BytesToPages(NewQuantity(1, "B"), -1)), ErrInvalidValue},
+		{errOf(NewCgroupQuantity("unknown", "", 1)), ErrInvalidMeasure},
+		{errOf(NewCgroupQuantity("cpu.stat", "unknown", 1)), ErrInvalidMeasure},
+		{errOf(NewPerfEventQuantity("unknown", 1)), ErrInvalidMeasure},
+		{errOf(NewHwmonQuantity("unknown", 1)), ErrInvalidMeasure},
+		{errOf(ConvertPeriod(NewQuantity(0, "Hz"), NewUnit("s"))), ErrInvalidValue},
+		{errOf(Divide(NewQuantity(1, "B"), NewQuantity(0, "s"))), ErrInvalidValue},
+		{errMeasure, ErrInvalidMeasure},
+		{errDuplicate, ErrInvalidMeasure},
+		{errLoad, os.ErrNotExist},
+	} {
+		if !errors.Is(c.err, c.target) {
+			t.Errorf("Expected error wrapping '%v' but got '%v'", c.target, c.err)
+		}
+	}
+}
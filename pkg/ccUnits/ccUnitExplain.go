@@ -0,0 +1,140 @@
+package ccunits
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseExplanation is the trace of parsing a unit string returned by ExplainParse()
+type ParseExplanation struct {
+	Input           string    `json:"input"`
+	Prefix          string    `json:"prefix"`                     // Matched prefix string like 'm' (empty for none)
+	PrefixName      string    `json:"prefix_name"`                // Prefix of the matched string like 'Milli'
+	Measure         string    `json:"measure"`                    // Matched measure string like 'B'
+	MeasureName     string    `json:"measure_name"`               // Measure of the matched string like 'Bytes'
+	Denominator     string    `json:"denominator,omitempty"`      // Matched unit denominator string (optional)
+	DenominatorName string    `json:"denominator_name,omitempty"` // Measure of the unit denominator (optional)
+	Rules           []string  `json:"rules,omitempty"`            // Applied special cases in order
+	Unit            UnitValue `json:"unit"`                       // Parsed unit
+}
+
+// note records an applied special case
+func (t *ParseExplanation) note(format string, args ...interface{}) {
+	t.Rules = append(t.Rules, fmt.Sprintf(format, args...))
+}
+
+// match records the matched prefix and measure strings
+func (t *ParseExplanation) match(prefixStr string, p Prefix, measureStr string, m Measure) {
+	if t == nil {
+		return
+	}
+	t.Prefix, t.PrefixName = prefixStr, ""
+	if len(prefixStr) > 0 {
+		t.PrefixName = p.String()
+	}
+	t.Measure, t.MeasureName = measureStr, m.String()
+}
+
+// matchDenominator records the matched unit denominator string
+func (t *ParseExplanation) matchDenominator(divStr string, div Measure) {
+	if t == nil {
+		return
+	}
+	t.Denominator, t.DenominatorName = divStr, div.String()
+}
+
+// ExplainParse parses a unit string like NewUnit() and returns the matched prefix, measure
+// and unit denominator together with the applied special cases like 'm' read as Mega for
+// Bytes in 'mB', legacy spellings or inverse times. It is meant for debugging why a unit
+// string resulted in an unexpected unit.
+func ExplainParse(unitStr string) ParseExplanation {
+	t := ParseExplanation{Input: unitStr}
+	t.Unit = parseUnitValueTrace(unitStr, &t)
+	if !t.Unit.Valid() {
+		t.note("no valid unit for '%s'", unitStr)
+	}
+	return t
+}
+
+// ConversionExplanation is the trace of a conversion between two units returned by
+// ExplainConversion(). Converted values are value * Factor + Offset, non-linear conversions
+// have no factor and offset (0).
+type ConversionExplanation struct {
+	In           UnitValue `json:"in"`
+	Out          UnitValue `json:"out"`
+	Kind         string    `json:"kind"`          // 'factor', 'temperature' or 'non-linear'
+	PrefixFactor float64   `json:"prefix_factor"` // Factor between the prefixes
+	ScaleRatio   float64   `json:"scale_ratio"`   // Ratio of the scales like 100 from ratios to percentages
+	Factor       float64   `json:"factor"`
+	Offset       float64   `json:"offset"`
+	Steps        []string  `json:"steps"` // Composition of the conversion in order
+}
+
+// step records a part of the conversion
+func (e *ConversionExplanation) step(format string, args ...interface{}) {
+	e.Steps = append(e.Steps, fmt.Sprintf(format, args...))
+}
+
+// ExplainConversion returns how a value is converted from one unit to another like
+// NewConverter(): why the units are convertible and how the factor is composed out of the
+// prefixes and the scales of the measures. If the units are not convertible, the
+// explanation contains the reason and the error of NewConverter() is returned.
+func ExplainConversion(in Unit, out Unit) (ConversionExplanation, error) {
+	vin, vout := ValueOf(in), ValueOf(out)
+	e := ConversionExplanation{In: vin, Out: vout, Kind: converterFactor.String(), ScaleRatio: 1}
+	conv, err := NewConverter(in, out)
+	if err != nil {
+		inDim, _, inOk := unitDimension(vin)
+		outDim, _, outOk := unitDimension(vout)
+		switch {
+		case !vin.Valid() || !vout.Valid():
+			e.step("invalid unit")
+		case inOk && outOk:
+			e.step("dimension %s of '%s' differs from dimension %s of '%s'", inDim.String(), vin.Short(), outDim.String(), vout.Short())
+		default:
+			e.step("'%s' and '%s' have no common dimension and different measures", vin.Short(), vout.Short())
+		}
+		return e, err
+	}
+	kind, _ := converterKindOf(vin, vout)
+	e.Kind = kind.String()
+	switch kind {
+	case converterTempC2F, converterTempF2C:
+		e.Factor, e.Offset = conv.Factor(), conv.Offset()
+		e.step("temperature conversion value * %s + %s including the prefixes", formatExplainFloat(e.Factor), formatExplainFloat(e.Offset))
+		return e, nil
+	case converterNonLinear:
+		e.step("prefix factor %s to base unit '%s'", formatExplainFloat(getPrefixFactor(vin.prefix, Base).factor), vin.Base().Short())
+		e.step("registered conversion from '%s' to '%s'", vin.Base().Short(), vout.Base().Short())
+		e.step("prefix factor %s from base unit to '%s'", formatExplainFloat(getPrefixFactor(Base, vout.prefix).factor), vout.Short())
+		return e, nil
+	}
+	if vin.measure == vout.measure && vin.divMeasure == vout.divMeasure {
+		e.step("same measure '%s'", vin.Base().Short())
+	} else {
+		d, _, _ := unitDimension(vin)
+		e.step("'%s' and '%s' have the same dimension %s", vin.Short(), vout.Short(), d.String())
+	}
+	e.PrefixFactor = getPrefixFactor(vin.prefix, vout.prefix).factor
+	e.step("prefix factor %s from %s to %s", formatExplainFloat(e.PrefixFactor), prefixName(vin.prefix), prefixName(vout.prefix))
+	if r := scaleRatio(vin, vout); r != 1 {
+		e.ScaleRatio = r
+		e.step("scale ratio %s", formatExplainFloat(r))
+	}
+	e.Factor = conv.Factor()
+	e.step("factor %s", formatExplainFloat(e.Factor))
+	return e, nil
+}
+
+// prefixName returns the long string of a prefix or 'no prefix' for Base
+func prefixName(p Prefix) string {
+	if p == Base {
+		return "no prefix"
+	}
+	return p.String()
+}
+
+// formatExplainFloat formats a factor of an explanation in the shortest notation
+func formatExplainFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
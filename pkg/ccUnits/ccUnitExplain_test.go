@@ -0,0 +1,52 @@
+package ccunits
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExplainParse(t *testing.T) {
+	for _, c := range []struct {
+		input   string
+		prefix  string
+		measure string
+		unit    string
+		rule    string
+	}{
+		{"mB", "Milli", "byte", "MB", "read as Mega"},
+		{"MByte/s", "Mega", "byte", "MB/s", ""},
+		{"1/ms", "Milli", "Seconds", "KHz", "inverse"},
+		{"mpercent", "Milli", "Percent", "%", "ignored"},
+		{"kratio", "Kilo", "Ratio", "invalinval", "rejected"},
+		{"MB/s/s", "Mega", "byte", "MB/s", "further unit denominator"},
+		{"xyz", "", "Invalid", "invalinval", "no valid unit"},
+	} {
+		e := ExplainParse(c.input)
+		if e.PrefixName != c.prefix || e.MeasureName != c.measure || e.Unit.Short() != c.unit {
+			t.Errorf("Expected %s %s (%s) for '%s' but got %s %s (%s)", c.prefix, c.measure, c.unit, c.input, e.PrefixName, e.MeasureName, e.Unit.Short())
+		}
+		if rules := strings.Join(e.Rules, "\n"); (len(c.rule) == 0) != (len(rules) == 0) || !strings.Contains(rules, c.rule) {
+			t.Errorf("Expected rule '%s' for '%s' but got %v", c.rule, c.input, e.Rules)
+		}
+	}
+}
+
+func TestExplainConversion(t *testing.T) {
+	e, err := ExplainConversion(NewUnit("ratio"), NewUnit("%"))
+	if err != nil || e.Kind != "factor" || e.PrefixFactor != 1 || e.ScaleRatio != 100 || e.Factor != 100 {
+		t.Errorf("Expected factor 100 from ratio to percent but got %+v: %v", e, err)
+	}
+	e, err = ExplainConversion(NewUnit("kJ/s"), NewUnit("W"))
+	if err != nil || e.Factor != 1000 || !strings.Contains(e.Steps[0], "energy/time") {
+		t.Errorf("Expected factor 1000 from kJ/s to W but got %+v: %v", e, err)
+	}
+	e, err = ExplainConversion(NewUnit("degC"), NewUnit("degF"))
+	if err != nil || e.Kind != "temperature" || e.Factor != 1.8 || e.Offset != 32 {
+		t.Errorf("Expected temperature conversion but got %+v: %v", e, err)
+	}
+	e, err = ExplainConversion(NewUnit("MB"), NewUnit("s"))
+	if !errors.Is(err, ErrIncompatibleMeasure) || len(e.Steps) != 1 || !strings.Contains(e.Steps[0], "differs") {
+		t.Errorf("Expected incompatible dimensions but got %+v: %v", e, err)
+	}
+}
@@ -0,0 +1,451 @@
+package ccunits
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// exprType is the static type of an expression: a quantity of a dimension, a plain number,
+// a temperature or a boolean result of a comparison
+type exprType struct {
+	dim         dimension
+	scalar      bool // Plain number without unit
+	temperature bool // Temperature in degC, only compared, added and subtracted
+	boolean     bool // Result of a comparison or logical operator
+}
+
+// String returns a description of the type for error messages
+func (t exprType) String() string {
+	switch {
+	case t.boolean:
+		return "condition"
+	case t.scalar:
+		return "number"
+	case t.temperature:
+		return "temperature"
+	}
+	if v, _, ok := dimensionUnit(t.dim); ok {
+		return "'" + v.Short() + "'"
+	}
+	return "quantity"
+}
+
+// exprNode is a node of the syntax tree of an expression. Values are kept in the unit of
+// their dimension (like arithmeticOperand) and temperatures in degC.
+type exprNode struct {
+	op          string // Operator or 'num' and 'var' for leaves
+	value       float64
+	name        string
+	left, right *exprNode
+	typ         exprType
+}
+
+// eval computes the value of a node, booleans are 1 and 0
+func (n *exprNode) eval(vars map[string]float64) float64 {
+	b := func(c bool) float64 {
+		if c {
+			return 1
+		}
+		return 0
+	}
+	switch n.op {
+	case "num":
+		return n.value
+	case "var":
+		return vars[n.name]
+	case "neg":
+		return -n.left.eval(vars)
+	}
+	l := n.left.eval(vars)
+	// Short-circuit the logical operators
+	switch {
+	case n.op == "&&" && l == 0:
+		return 0
+	case n.op == "||" && l != 0:
+		return 1
+	}
+	r := n.right.eval(vars)
+	switch n.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	case "<":
+		return b(l < r)
+	case "<=":
+		return b(l <= r)
+	case ">":
+		return b(l > r)
+	case ">=":
+		return b(l >= r)
+	case "==":
+		return b(l == r)
+	case "!=":
+		return b(l != r)
+	}
+	return b(r != 0) // '&&' and '||' with the left side not deciding
+}
+
+// expressionValue returns the value of a quantity in the unit of its dimension or degC for
+// temperatures together with its type
+func expressionValue(q Quantity) (float64, exprType, error) {
+	if !q.Valid() {
+		return 0, exprType{}, fmt.Errorf("invalid unit in expression: %w", ErrInvalidMeasure)
+	}
+	v := ValueOf(q.Unit)
+	if (v.measure == TemperatureC || v.measure == TemperatureF) && v.divMeasure == InvalidMeasure {
+		conv, err := NewConverter(q.Unit, newBaseUnit(Base, TemperatureC))
+		if err != nil {
+			return 0, exprType{}, err
+		}
+		return conv.ApplyFloat64(q.Value), exprType{dim: dimension{dimTemperature: 1}, temperature: true}, nil
+	}
+	value, d, err := arithmeticOperand(q)
+	return value, exprType{dim: d}, err
+}
+
+// exprParser is a recursive descent parser for expressions
+type exprParser struct {
+	input string
+	pos   int
+	vars  map[string]exprType
+	used  map[string]bool // Variables used by the expression
+}
+
+// peek returns the next character after skipping spaces or 0 at the end
+func (p *exprParser) peek() byte {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// operator consumes and returns the first of the operators at the current position
+func (p *exprParser) operator(ops ...string) (string, bool) {
+	p.peek()
+	for _, op := range ops {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			p.pos += len(op)
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// word consumes a name or unit string which ends at spaces, parentheses and operators
+func (p *exprParser) word() string {
+	p.peek()
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(" ()*+-<>=!&|", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// binary creates the node of a binary operator and checks the types of its operands
+func (p *exprParser) binary(op string, l *exprNode, r *exprNode, at int) (*exprNode, error) {
+	n := &exprNode{op: op, left: l, right: r}
+	lt, rt := l.typ, r.typ
+	switch op {
+	case "&&", "||":
+		if !lt.boolean || !rt.boolean {
+			return nil, fmt.Errorf("operator '%s' requires conditions at position %d", op, at)
+		}
+		n.typ = exprType{boolean: true}
+	case "*", "/":
+		if lt.boolean || rt.boolean || (lt.temperature && !rt.scalar) || (rt.temperature && !(lt.scalar && op == "*")) {
+			return nil, fmt.Errorf("cannot apply '%s' to %s and %s at position %d: %w", op, lt.String(), rt.String(), at, ErrIncompatibleMeasure)
+		}
+		sign := int8(1)
+		if op == "/" {
+			sign = -1
+		}
+		n.typ = exprType{dim: lt.dim.add(rt.dim, sign), scalar: lt.scalar && rt.scalar, temperature: lt.temperature || rt.temperature}
+	default:
+		if lt.boolean || rt.boolean || lt != rt {
+			return nil, fmt.Errorf("cannot apply '%s' to %s and %s at position %d: %w", op, lt.String(), rt.String(), at, ErrIncompatibleMeasure)
+		}
+		n.typ = lt
+		if op != "+" && op != "-" {
+			n.typ = exprType{boolean: true}
+		}
+	}
+	return n, nil
+}
+
+// logical parses conditions combined with '||' and '&&', '&&' binds stronger
+func (p *exprParser) logical(ops ...string) (*exprNode, error) {
+	next := p.comparison
+	if ops[0] == "||" {
+		next = func() (*exprNode, error) { return p.logical("&&") }
+	}
+	n, err := next()
+	for err == nil {
+		at := p.pos
+		op, ok := p.operator(ops...)
+		if !ok {
+			return n, nil
+		}
+		var r *exprNode
+		if r, err = next(); err == nil {
+			n, err = p.binary(op, n, r, at)
+		}
+	}
+	return nil, err
+}
+
+// comparison parses a sum optionally compared to another sum
+func (p *exprParser) comparison() (*exprNode, error) {
+	n, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	at := p.pos
+	op, ok := p.operator("<=", ">=", "==", "!=", "<", ">")
+	if !ok {
+		return n, nil
+	}
+	r, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	return p.binary(op, n, r, at)
+}
+
+// sum parses products combined with '+' and '-'
+func (p *exprParser) sum() (*exprNode, error) {
+	n, err := p.product()
+	for err == nil {
+		at := p.pos
+		op, ok := p.operator("+", "-")
+		if !ok {
+			return n, nil
+		}
+		var r *exprNode
+		if r, err = p.product(); err == nil {
+			n, err = p.binary(op, n, r, at)
+		}
+	}
+	return nil, err
+}
+
+// product parses operands combined with '*' and '/'
+func (p *exprParser) product() (*exprNode, error) {
+	n, err := p.unary()
+	for err == nil {
+		at := p.pos
+		op, ok := p.operator("*", "/")
+		if !ok {
+			return n, nil
+		}
+		var r *exprNode
+		if r, err = p.unary(); err == nil {
+			n, err = p.binary(op, n, r, at)
+		}
+	}
+	return nil, err
+}
+
+// unary parses an operand with optional '-'
+func (p *exprParser) unary() (*exprNode, error) {
+	if _, ok := p.operator("-"); ok {
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if n.typ.boolean {
+			return nil, fmt.Errorf("cannot negate a condition at position %d", p.pos)
+		}
+		return &exprNode{op: "neg", left: n, typ: n.typ}, nil
+	}
+	return p.primary()
+}
+
+// primary parses a number with optional unit like '5 GFlops/J', a variable or a sum in
+// parentheses
+func (p *exprParser) primary() (*exprNode, error) {
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end")
+	case c == '(':
+		p.pos++
+		n, err := p.logical("||")
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ')' at position %d", p.pos)
+		}
+		p.pos++
+		return n, nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		numStr := p.word()
+		// Exponents like '1e-3' are split by the word at the sign
+		if (strings.HasSuffix(numStr, "e") || strings.HasSuffix(numStr, "E")) && p.pos < len(p.input) &&
+			(p.input[p.pos] == '-' || p.input[p.pos] == '+') {
+			p.pos++
+			numStr += p.input[p.pos-1:p.pos] + p.word()
+		}
+		value, err := strconv.ParseFloat(numStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' at position %d", numStr, start)
+		}
+		if c := p.peek(); c == 0 || strings.IndexByte("()*/+-<>=!&|", c) >= 0 {
+			return &exprNode{op: "num", value: value, typ: exprType{scalar: true}}, nil
+		}
+		at := p.pos
+		unitStr := p.word()
+		u, err := ParseUnit(unitStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unit at position %d: %w", at, err)
+		}
+		v, t, err := expressionValue(Quantity{Value: value, Unit: u})
+		if err != nil {
+			return nil, err
+		}
+		return &exprNode{op: "num", value: v, typ: t}, nil
+	}
+	start := p.pos
+	name := p.word()
+	if len(name) == 0 {
+		return nil, fmt.Errorf("unexpected '%c' at position %d", p.input[start], start)
+	}
+	t, ok := p.vars[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable '%s' at position %d", name, start)
+	}
+	p.used[name] = true
+	return &exprNode{op: "var", name: name, typ: t}, nil
+}
+
+// Expression is a compiled expression over quantities like 'mem_bw > 0.8 * peak_bw' or
+// 'flops / power < 5 GFlops/J', e.g. for alert rules. The units of the operands are checked
+// when the expression is compiled and the values are converted when it is evaluated.
+type Expression struct {
+	source string
+	root   *exprNode
+	vars   map[string]Unit // Declared units of the used variables
+}
+
+// CompileExpression parses an expression and checks the units of its operands. The
+// variables are declared with their unit; their values may come in any convertible unit.
+// Operands are numbers with optional unit like '0.8' or '90 degC', variables, the operators
+// '+', '-', '*' and '/', the comparisons '<', '<=', '>', '>=', '==' and '!=', the logical
+// operators '&&' and '||' and parentheses. Added, subtracted and compared operands must be
+// convertible into each other; plain numbers only match plain numbers. Temperatures can
+// only be scaled by numbers, not multiplied with other quantities. The result unit of
+// products and quotients is derived from the dimensions like for Multiply.
+func CompileExpression(expr string, vars map[string]Unit) (*Expression, error) {
+	p := exprParser{input: strings.TrimSpace(expr), vars: make(map[string]exprType, len(vars)), used: make(map[string]bool)}
+	for name, u := range vars {
+		_, t, err := expressionValue(Quantity{Unit: u})
+		if err != nil {
+			return nil, fmt.Errorf("invalid unit of variable '%s': %w", name, err)
+		}
+		p.vars[name] = t
+	}
+	root, err := p.logical("||")
+	if err == nil && p.peek() != 0 {
+		err = fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression '%s': %w", expr, err)
+	}
+	used := make(map[string]Unit, len(p.used))
+	for name := range p.used {
+		used[name] = vars[name]
+	}
+	return &Expression{source: expr, root: root, vars: used}, nil
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// IsCondition checks whether the expression is a comparison or a logical combination of
+// comparisons, which is required by Evaluate
+func (e *Expression) IsCondition() bool {
+	return e.root.typ.boolean
+}
+
+// values converts the values of all variables of the expression
+func (e *Expression) values(values map[string]Quantity) (map[string]float64, error) {
+	out := make(map[string]float64, len(e.vars))
+	for name, u := range e.vars {
+		q, ok := values[name]
+		if !ok {
+			return nil, fmt.Errorf("missing value of variable '%s'", name)
+		}
+		if !q.Valid() || !q.Unit.Compatible(u) {
+			return nil, fmt.Errorf("unit of variable '%s' cannot be converted to '%s': %w", name, u.Short(), ErrIncompatibleMeasure)
+		}
+		v, _, err := expressionValue(q)
+		if err != nil {
+			return nil, err
+		}
+		out[name] = v
+	}
+	return out, nil
+}
+
+// Evaluate computes a condition with the values of the variables. The values are
+// converted from their unit, so a variable declared as 'GB/s' may be given in 'MB/s'.
+func (e *Expression) Evaluate(values map[string]Quantity) (bool, error) {
+	if !e.IsCondition() {
+		return false, fmt.Errorf("expression '%s' is no condition", e.source)
+	}
+	vars, err := e.values(values)
+	if err != nil {
+		return false, err
+	}
+	return e.root.eval(vars) != 0, nil
+}
+
+// resultUnit returns the unit of the result of an expression which is no condition and the
+// scale of the unit
+func (e *Expression) resultUnit() (Unit, float64, error) {
+	t := e.root.typ
+	switch {
+	case t.boolean:
+		return invalidUnitValue.Unit(), 0, fmt.Errorf("expression '%s' is a condition: %w", e.source, ErrIncompatibleMeasure)
+	case t.temperature:
+		return newBaseUnit(Base, TemperatureC), 1, nil
+	}
+	v, scale, ok := dimensionUnit(t.dim)
+	if !ok {
+		return invalidUnitValue.Unit(), 0, fmt.Errorf("no unit for the result of '%s': %w", e.source, ErrIncompatibleMeasure)
+	}
+	return v.Unit(), scale, nil
+}
+
+// Unit returns the unit of the result of an expression which is no condition like 'J' for
+// 'power * 2 h'. It has no prefix, temperatures are in degC and plain numbers are ratios.
+// It returns an error for conditions and if no unit has the dimension of the result.
+func (e *Expression) Unit() (Unit, error) {
+	u, _, err := e.resultUnit()
+	return u, err
+}
+
+// Value computes an expression which is no condition with the values of the variables. The
+// result is in the unit returned by Unit().
+func (e *Expression) Value(values map[string]Quantity) (Quantity, error) {
+	u, scale, err := e.resultUnit()
+	if err != nil {
+		return Quantity{}, err
+	}
+	vars, err := e.values(values)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: e.root.eval(vars) / scale, Unit: u}, nil
+}
@@ -0,0 +1,69 @@
+package ccunits
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestExpression(t *testing.T) {
+	vars := map[string]Unit{
+		"mem_bw":  NewUnit("GB/s"),
+		"peak_bw": NewUnit("GB/s"),
+		"flops":   NewUnit("GFlops/s"),
+		"power":   NewUnit("W"),
+		"temp":    NewUnit("degC"),
+	}
+	values := map[string]Quantity{
+		"mem_bw":  NewQuantity(90000, "MB/s"),
+		"peak_bw": NewQuantity(100, "GB/s"),
+		"flops":   NewQuantity(1.2, "TFlops/s"),
+		"power":   NewQuantity(300, "W"),
+		"temp":    NewQuantity(194, "degF"),
+	}
+	for expr, expected := range map[string]bool{
+		"mem_bw > 0.8 * peak_bw":             true,
+		"mem_bw > peak_bw":                   false,
+		"flops / power < 5 GFlops/J":         true,
+		"temp >= 90 degC && (power < 250 W)": false,
+		"temp >= 90 degC || power < 250 W":   true,
+		"mem_bw - 50000 MB/s <= 40e+0 GB/s":  true,
+	} {
+		e, err := CompileExpression(expr, vars)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", expr, err)
+			continue
+		}
+		if ok, err := e.Evaluate(values); err != nil || ok != expected {
+			t.Errorf("Expected %v for '%s' but got %v: %v", expected, expr, ok, err)
+		}
+		if u, err := e.Unit(); !errors.Is(err, ErrIncompatibleMeasure) || u.Valid() {
+			t.Errorf("Expected error and invalid unit for condition '%s' but got '%s': %v", expr, u.Short(), err)
+		}
+	}
+
+	// Units are checked when the expression is compiled
+	for _, expr := range []string{"mem_bw > power", "mem_bw > 0.8", "temp * power > 1 J", "mem_bw > 1 GB/s &&", "(mem_bw > 1 GB/s", "cpu_load > 1"} {
+		if _, err := CompileExpression(expr, vars); err == nil {
+			t.Errorf("Expected error for '%s'", expr)
+		}
+	}
+	if _, err := CompileExpression("mem_bw > power", vars); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected incompatible measures but got %v", err)
+	}
+
+	e, err := CompileExpression("power * 2 h", vars)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	q, err := e.Value(values)
+	if err != nil || math.Abs(q.Value-2.16e6) > 1e-6 || q.Unit.Short() != "J" {
+		t.Errorf("Expected 2.16e+06 J but got %s: %v", q.String(), err)
+	}
+	if _, err := e.Evaluate(values); err == nil {
+		t.Errorf("Expected error for evaluating a value as condition")
+	}
+	if _, err := e.Value(map[string]Quantity{"power": NewQuantity(1, "B")}); err == nil {
+		t.Errorf("Expected error for value with incompatible unit")
+	}
+}
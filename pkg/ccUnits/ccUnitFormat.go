@@ -0,0 +1,319 @@
+package ccunits
+
+import (
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// formatSignificant formats a value with a number of significant digits without exponent
+// and trailing zeros. Zero or less digits use the shortest representation.
+func formatSignificant(v float64, digits int) string {
+	if digits <= 0 || v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	decimals := digits - 1 - int(math.Floor(math.Log10(math.Abs(v))))
+	if decimals < 0 {
+		decimals = 0
+	}
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// engineering splits a value into a mantissa in [1, 1000) rounded to a number of significant
+// digits and an exponent which is a multiple of 3
+func engineering(v float64, digits int) (float64, int) {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v, 0
+	}
+	exp := int(math.Floor(math.Log10(math.Abs(v))/3)) * 3
+	m := v / math.Pow(10, float64(exp))
+	if digits > 0 {
+		scale := math.Pow(10, float64(digits-1-int(math.Floor(math.Log10(math.Abs(m))))))
+		m = math.Round(m*scale) / scale
+	}
+	// Rounding can carry over like 999.96 to 1000
+	if math.Abs(m) >= 1000 {
+		m /= 1000
+		exp += 3
+	}
+	return m, exp
+}
+
+// FormatEngineering formats the quantity in engineering notation like '12.3e6 Flops/s' as an
+// alternative to Humanize(): the value is converted to the unit without prefix and written
+// with an exponent which is a multiple of 3 and a mantissa in [1, 1000). The mantissa is
+// rounded to the number of significant digits (0 for the shortest exact representation).
+// The exponent is left out if it is 0.
+func (q Quantity) FormatEngineering(digits int) string {
+	if !q.Valid() {
+		return q.String()
+	}
+	base := NormalizeToBase(q)
+	m, exp := engineering(base.Value, digits)
+	s := formatSignificant(m, digits)
+	if exp != 0 {
+		s += "e" + strconv.Itoa(exp)
+	}
+	return s + " " + base.Unit.Short()
+}
+
+// TableFormat formats quantities in fixed-width columns, so CLI tools printing tables of mixed
+// metrics like per-node values produce readable columns. The value is right-aligned and the
+// unit symbol padded on the right. Longer values and units are not truncated.
+type TableFormat struct {
+	ValueWidth int // Width of the value column
+	UnitWidth  int // Width of the unit column
+	Precision  int // Number of decimals of the value
+}
+
+// NewTableFormat returns a table format with the columns wide enough for all quantities
+func NewTableFormat(quantities []Quantity, precision int) TableFormat {
+	f := TableFormat{Precision: precision}
+	for _, q := range quantities {
+		if n := len(f.value(q)); n > f.ValueWidth {
+			f.ValueWidth = n
+		}
+		if n := utf8.RuneCountInString(f.unit(q)); n > f.UnitWidth {
+			f.UnitWidth = n
+		}
+	}
+	return f
+}
+
+// value returns the formatted value of a quantity
+func (f TableFormat) value(q Quantity) string {
+	return strconv.FormatFloat(q.Value, 'f', f.Precision, 64)
+}
+
+// unit returns the unit symbol of a quantity
+func (f TableFormat) unit(q Quantity) string {
+	if !q.Valid() {
+		return invalidUnitValue.Short()
+	}
+	return q.Unit.Short()
+}
+
+// padding returns the spaces filling a column of a width with a string of length n
+func padding(width int, n int) string {
+	if n >= width {
+		return ""
+	}
+	return strings.Repeat(" ", width-n)
+}
+
+// Format returns the quantity as value and unit columns separated by a space like
+// '   12.50 GB/s ' for ValueWidth 8, UnitWidth 5 and Precision 2
+func (f TableFormat) Format(q Quantity) string {
+	v, u := f.value(q), f.unit(q)
+	var b strings.Builder
+	b.WriteString(padding(f.ValueWidth, len(v)))
+	b.WriteString(v)
+	b.WriteByte(' ')
+	b.WriteString(u)
+	b.WriteString(padding(f.UnitWidth, utf8.RuneCountInString(u)))
+	return b.String()
+}
+
+// siunitx macros of the prefixes and measures used by FormatLaTeX(). Other prefixes and
+// measures are written as text.
+var latexPrefixes = map[Prefix]string{
+	Yotta: `\yotta`, Zetta: `\zetta`, Exa: `\exa`, Peta: `\peta`, Tera: `\tera`, Giga: `\giga`,
+	Mega: `\mega`, Kilo: `\kilo`, Milli: `\milli`, Micro: `\micro`, Nano: `\nano`,
+	Kibi: `\kibi`, Mebi: `\mebi`, Gibi: `\gibi`, Tebi: `\tebi`, Pebi: `\pebi`, Exbi: `\exbi`,
+	Zebi: `\zebi`, Yobi: `\yobi`,
+}
+
+var latexMeasures = map[Measure]string{
+	Bytes: `\byte`, Percentage: `\percent`, TemperatureC: `\degreeCelsius`,
+	TemperatureF: `\text{\textdegree{}F}`, Frequency: `\hertz`, Time: `\second`, Watt: `\watt`,
+	Joule: `\joule`, Volt: `\volt`, Ampere: `\ampere`,
+}
+
+// latexMeasure returns the siunitx notation of a measure
+func latexMeasure(m Measure) string {
+	if s, ok := latexMeasures[m]; ok {
+		return s
+	}
+	return `\text{` + m.Short() + `}`
+}
+
+// latexUnit returns the siunitx notation of a unit like '\giga\byte\per\second'
+func latexUnit(u UnitValue) string {
+	var b strings.Builder
+	if u.prefix != Base {
+		if s, ok := latexPrefixes[u.prefix]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteString(`\text{` + u.prefix.Prefix() + `}`)
+		}
+	}
+	b.WriteString(latexMeasure(u.measure))
+	if u.divMeasure != InvalidMeasure {
+		b.WriteString(`\per`)
+		b.WriteString(latexMeasure(u.divMeasure))
+	}
+	return b.String()
+}
+
+// FormatLaTeX returns the quantity in the notation of the LaTeX package siunitx like
+// '\SI{12.5}{\giga\byte\per\second}', so automated reports can embed typeset units. Measures
+// without siunitx macro like Flops are written as text, which requires siunitx version 3.
+func (q Quantity) FormatLaTeX() string {
+	if !q.Valid() {
+		return `\SI{` + strconv.FormatFloat(q.Value, 'g', -1, 64) + `}{}`
+	}
+	return `\SI{` + strconv.FormatFloat(q.Value, 'g', -1, 64) + `}{` + latexUnit(ValueOf(q.Unit)) + `}`
+}
+
+// htmlValue returns a value with the exponent as superscript like '1.5&times;10<sup>9</sup>'
+func htmlValue(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	mantissa, exp, ok := strings.Cut(s, "e")
+	if !ok {
+		return s
+	}
+	exp = strings.TrimLeft(strings.TrimPrefix(exp, "+"), "0")
+	if strings.HasPrefix(exp, "-") {
+		exp = "-" + strings.TrimLeft(exp[1:], "0")
+	}
+	return mantissa + "&times;10<sup>" + exp + "</sup>"
+}
+
+// unicodeSymbol returns the typeset symbol of a prefix and measure like 'µs' or '°C'
+func unicodeSymbol(p Prefix, m Measure) string {
+	pre := p.Prefix()
+	if p == Micro {
+		pre = "µ"
+	}
+	measure := m.Short()
+	switch m {
+	case TemperatureC:
+		measure = "°C"
+	case TemperatureF:
+		measure = "°F"
+	}
+	return pre + measure
+}
+
+// htmlSymbol returns the escaped typeset symbol of a prefix and measure
+func htmlSymbol(p Prefix, m Measure) string {
+	return html.EscapeString(unicodeSymbol(p, m))
+}
+
+// FormatHTML returns the quantity as HTML fragment with typeset symbols and the unit
+// denominator as negative power like '12.5&nbsp;GB&nbsp;s<sup>-1</sup>', so automated reports
+// can embed properly typeset units
+func (q Quantity) FormatHTML() string {
+	if !q.Valid() {
+		return htmlValue(q.Value)
+	}
+	u := ValueOf(q.Unit)
+	s := htmlValue(q.Value) + "&nbsp;" + htmlSymbol(u.prefix, u.measure)
+	if u.divMeasure != InvalidMeasure {
+		s += "&nbsp;" + htmlSymbol(Base, u.divMeasure) + "<sup>-1</sup>"
+	}
+	return s
+}
+
+// FormatLocale formats the quantity with the decimal separator and digit grouping of a
+// locale like '1.234,56 GB/s' for 'de_DE' or '1,234.56 GB/s' for 'en_US'. The locale is given
+// as language tag with '-' or '_'; unknown locales are formatted like English. The value is
+// written with the number of decimals, a negative precision uses the shortest representation.
+func (q Quantity) FormatLocale(locale string, precision int) string {
+	p := message.NewPrinter(language.Make(strings.ReplaceAll(locale, "_", "-")))
+	unit := invalidUnitValue.Short()
+	if q.Valid() {
+		unit = q.Unit.Short()
+	}
+	if precision < 0 {
+		return p.Sprintf("%v %s", q.Value, unit)
+	}
+	return p.Sprintf("%.*f %s", precision, q.Value, unit)
+}
+
+// measureName returns the singular or plural name of a measure or the long name if the
+// measure has no names in its metadata
+func measureName(m Measure, plural bool) string {
+	md, _ := Metadata(m)
+	switch {
+	case plural && len(md.Plural) > 0:
+		return md.Plural
+	case !plural && len(md.Singular) > 0:
+		return md.Singular
+	}
+	return m.String()
+}
+
+// longUnitName returns the long name of a unit in singular or plural like 'Megabytes per Second'
+func longUnitName(u UnitValue, plural bool) string {
+	name := measureName(u.measure, plural)
+	if u.prefix != Base {
+		name = u.prefix.String() + strings.ToLower(name)
+	}
+	if u.divMeasure != InvalidMeasure {
+		name += " per " + measureName(u.divMeasure, false)
+	}
+	return name
+}
+
+// FormatLong writes the quantity with the long unit name in singular or plural for prose-style
+// reports like '1 Byte', '2 Bytes', '1.5 Megabytes per Second' or '1 Second'. The singular is
+// used for values of 1 and -1. With lowercase set, the names are written in lowercase like
+// '2 megabytes per second'.
+func (q Quantity) FormatLong(lowercase bool) string {
+	if !q.Valid() {
+		return q.String()
+	}
+	s := strconv.FormatFloat(q.Value, 'g', -1, 64) + " " + longUnitName(ValueOf(q.Unit), math.Abs(q.Value) != 1)
+	if lowercase {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// asciiReplacer replaces the non-ASCII symbols of units and formatted numbers
+var asciiReplacer = strings.NewReplacer(
+	"µ", "u", // Micro sign
+	"μ", "u", // Greek mu
+	"°C", "degC",
+	"°F", "degF",
+	"°", "deg",
+	"Ω", "Ohm",
+	"×", "x",
+	"’", "'",
+	" ", " ", // No-break space
+	" ", " ", // Narrow no-break space
+)
+
+// ToASCII converts formatted units and quantities to pure ASCII like 'us' for 'µs' and 'degC'
+// for '°C', for sinks like legacy RRD tools and log files which cannot handle Unicode. Known
+// symbols and spaces are transliterated, all other non-ASCII characters are replaced by '?'.
+func ToASCII(s string) string {
+	s = asciiReplacer.Replace(s)
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return strings.Map(func(r rune) rune {
+				if r >= utf8.RuneSelf {
+					return '?'
+				}
+				return r
+			}, s)
+		}
+	}
+	return s
+}
+
+// FormatASCII returns the quantity like String() but guaranteed in pure ASCII, e.g. for units
+// registered with Unicode symbols
+func (q Quantity) FormatASCII() string {
+	return ToASCII(q.String())
+}
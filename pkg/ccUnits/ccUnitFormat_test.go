@@ -0,0 +1,119 @@
+package ccunits
+
+import "testing"
+
+func TestFormatEngineering(t *testing.T) {
+	for _, c := range []struct {
+		q        Quantity
+		digits   int
+		expected string
+	}{
+		{NewQuantity(12.3, "MFlops/s"), 3, "12.3e6 Flops/s"},
+		{NewQuantity(12345, "B"), 3, "12.3e3 B"},
+		{NewQuantity(999.96, "W"), 4, "1e3 W"},
+		{NewQuantity(0.25, "ms"), 2, "250e-6 s"},
+		{NewQuantity(-4.5, "GHz"), 0, "-4.5e9 Hz"},
+		{NewQuantity(42, "degC"), 3, "42 degC"},
+		{NewQuantity(0, "B"), 3, "0 B"},
+	} {
+		if s := c.q.FormatEngineering(c.digits); s != c.expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", c.expected, c.q.String(), s)
+		}
+	}
+}
+
+func TestTableFormat(t *testing.T) {
+	quantities := []Quantity{NewQuantity(12.5, "GB/s"), NewQuantity(1234.5678, "W"), NewQuantity(-3, "degC")}
+	f := NewTableFormat(quantities, 2)
+	if f.ValueWidth != 7 || f.UnitWidth != 4 {
+		t.Errorf("Unexpected widths %d and %d", f.ValueWidth, f.UnitWidth)
+	}
+	for i, expected := range []string{"  12.50 GB/s", "1234.57 W   ", "  -3.00 degC"} {
+		if s := f.Format(quantities[i]); s != expected {
+			t.Errorf("Expected '%s' but got '%s'", expected, s)
+		}
+	}
+	if s := (TableFormat{ValueWidth: 2, Precision: 1}).Format(NewQuantity(100, "B")); s != "100.0 B" {
+		t.Errorf("Expected '100.0 B' but got '%s'", s)
+	}
+	if s := (TableFormat{Precision: 0}).Format(Quantity{Value: 7}); s != "7 "+invalidUnitValue.Short() {
+		t.Errorf("Expected '7 %s' for a quantity without unit but got '%s'", invalidUnitValue.Short(), s)
+	}
+}
+
+func TestFormatLaTeXHTML(t *testing.T) {
+	for _, c := range []struct {
+		q           Quantity
+		latex, html string
+	}{
+		{NewQuantity(12.5, "GB/s"), `\SI{12.5}{\giga\byte\per\second}`, "12.5&nbsp;GB&nbsp;s<sup>-1</sup>"},
+		{NewQuantity(1.5e9, "Flops/s"), `\SI{1.5e+09}{\text{Flops}\per\second}`, "1.5&times;10<sup>9</sup>&nbsp;Flops&nbsp;s<sup>-1</sup>"},
+		{NewQuantity(42, "degC"), `\SI{42}{\degreeCelsius}`, "42&nbsp;°C"},
+		{NewQuantity(2e-6, "KiB"), `\SI{2e-06}{\kibi\byte}`, "2&times;10<sup>-6</sup>&nbsp;KiB"},
+	} {
+		if s := c.q.FormatLaTeX(); s != c.latex {
+			t.Errorf("Expected '%s' but got '%s'", c.latex, s)
+		}
+		if s := c.q.FormatHTML(); s != c.html {
+			t.Errorf("Expected '%s' but got '%s'", c.html, s)
+		}
+	}
+}
+
+func TestFormatLocale(t *testing.T) {
+	q := NewQuantity(1234.56, "GB/s")
+	for locale, expected := range map[string]string{
+		"de_DE": "1.234,56 GB/s",
+		"de":    "1.234,56 GB/s",
+		"en-US": "1,234.56 GB/s",
+		"xyz":   "1,234.56 GB/s",
+	} {
+		if s := q.FormatLocale(locale, 2); s != expected {
+			t.Errorf("Expected '%s' for locale '%s' but got '%s'", expected, locale, s)
+		}
+	}
+	if s := NewQuantity(-0.5, "W").FormatLocale("de_DE", -1); s != "-0,5 W" {
+		t.Errorf("Expected '-0,5 W' but got '%s'", s)
+	}
+	if s := (Quantity{Value: 2}).FormatLocale("de_DE", 1); s != "2,0 "+invalidUnitValue.Short() {
+		t.Errorf("Expected '2,0 %s' for a quantity without unit but got '%s'", invalidUnitValue.Short(), s)
+	}
+}
+
+func TestFormatLong(t *testing.T) {
+	for _, c := range []struct {
+		q         Quantity
+		lowercase bool
+		expected  string
+	}{
+		{NewQuantity(1, "B"), false, "1 Byte"},
+		{NewQuantity(2, "B"), false, "2 Bytes"},
+		{NewQuantity(1, "s"), false, "1 Second"},
+		{NewQuantity(1.5, "MB/s"), false, "1.5 Megabytes per Second"},
+		{NewQuantity(-1, "degC"), false, "-1 Degree Celsius"},
+		{NewQuantity(2, "MB/s"), true, "2 megabytes per second"},
+		{NewQuantity(0, "Hz"), true, "0 hertz"},
+	} {
+		if s := c.q.FormatLong(c.lowercase); s != c.expected {
+			t.Errorf("Expected '%s' but got '%s'", c.expected, s)
+		}
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	for input, expected := range map[string]string{
+		"12 µs":     "12 us",
+		"42 °C":     "42 degC",
+		"1 234,5 W": "1 234,5 W",
+		"5 Ω":       "5 Ohm",
+		"3 ☃":       "3 ?",
+		"1.5 GB/s":  "1.5 GB/s",
+	} {
+		if s := ToASCII(input); s != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input, s)
+		}
+	}
+	if s := NewQuantity(1234.5, "W").FormatLocale("fr", 1); ToASCII(s) != "1 234,5 W" {
+		t.Errorf("Expected '1 234,5 W' but got '%s'", ToASCII(s))
+	}
+}
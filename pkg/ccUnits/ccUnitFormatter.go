@@ -0,0 +1,201 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatOption configures a Formatter
+type FormatOption func(*Formatter)
+
+// HumanizeStyle selects how Formatter.Humanize() writes quantities
+type HumanizeStyle int
+
+const (
+	HumanizePrefix      HumanizeStyle = iota // Prefix that fits best like '1.5 GB/s' (default)
+	HumanizeEngineering                      // Engineering notation like '1.5e9 B/s'
+	HumanizeNone                             // Unit of the quantity unchanged
+)
+
+// String returns the name of the humanization style
+func (s HumanizeStyle) String() string {
+	switch s {
+	case HumanizePrefix:
+		return "prefix"
+	case HumanizeEngineering:
+		return "engineering"
+	case HumanizeNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the name of the humanization style, so it is readable in configurations
+func (s HumanizeStyle) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads the name of the humanization style
+func (s *HumanizeStyle) UnmarshalText(text []byte) error {
+	for _, style := range []HumanizeStyle{HumanizePrefix, HumanizeEngineering, HumanizeNone} {
+		if style.String() == string(text) {
+			*s = style
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid humanize style '%s'", string(text))
+}
+
+// Formatter writes quantities with a consistent set of formatting choices. It is created
+// with NewFormatter() and options like WithLongNames() or WithBinaryPrefixes().
+type Formatter struct {
+	long      bool          // Long unit names like 'Megabytes per Second'
+	lowercase bool          // Unit in lowercase
+	binary    bool          // Binary prefixes for Humanize
+	unicode   bool          // Unicode symbols like 'µs', otherwise pure ASCII
+	separator string        // Separator between value and unit
+	precision int           // Number of decimals, negative for the shortest representation
+	style     HumanizeStyle // Style of Humanize
+}
+
+// WithLongNames writes the long unit names in singular or plural like '2 Megabytes per Second'
+// instead of the short symbols like '2 MB/s'
+func WithLongNames() FormatOption {
+	return func(f *Formatter) {
+		f.long = true
+	}
+}
+
+// WithLowercase writes the unit in lowercase like '2 megabytes per second'. It is meant for
+// long names, short symbols like 'MB' and 'mB' become ambiguous.
+func WithLowercase() FormatOption {
+	return func(f *Formatter) {
+		f.lowercase = true
+	}
+}
+
+// WithBinaryPrefixes selects the binary prefixes like 'GiB' for Humanize
+func WithBinaryPrefixes() FormatOption {
+	return func(f *Formatter) {
+		f.binary = true
+	}
+}
+
+// WithUnicode writes the typeset symbols 'µ' for Micro and '°C' and '°F' for temperatures.
+// Without it, the output is pure ASCII (see ToASCII()).
+func WithUnicode() FormatOption {
+	return func(f *Formatter) {
+		f.unicode = true
+	}
+}
+
+// WithSeparator sets the separator between value and unit (default ' '). An empty separator
+// writes quantities like '12.5GB/s'.
+func WithSeparator(sep string) FormatOption {
+	return func(f *Formatter) {
+		f.separator = sep
+	}
+}
+
+// WithPrecision writes the value with a fixed number of decimals. A negative precision
+// (default) uses the shortest representation.
+func WithPrecision(decimals int) FormatOption {
+	return func(f *Formatter) {
+		f.precision = decimals
+	}
+}
+
+// WithHumanizeStyle selects how Humanize() writes quantities (default HumanizePrefix)
+func WithHumanizeStyle(style HumanizeStyle) FormatOption {
+	return func(f *Formatter) {
+		f.style = style
+	}
+}
+
+// NewFormatter creates a formatter. Without options, it writes quantities like String() with
+// short symbols, a space as separator and the shortest representation of the value.
+func NewFormatter(options ...FormatOption) Formatter {
+	f := Formatter{
+		separator: " ",
+		precision: -1,
+	}
+	for _, o := range options {
+		o(&f)
+	}
+	return f
+}
+
+// unit returns the unit of a quantity in the configured notation
+func (f Formatter) unit(q Quantity) string {
+	if !q.Valid() {
+		return invalidUnitValue.Short()
+	}
+	u := ValueOf(q.Unit)
+	switch {
+	case f.long:
+		return longUnitName(u, math.Abs(q.Value) != 1)
+	case f.unicode:
+		s := unicodeSymbol(u.prefix, u.measure)
+		if u.divMeasure != InvalidMeasure {
+			s += "/" + unicodeSymbol(Base, u.divMeasure)
+		}
+		return s
+	}
+	return u.Short()
+}
+
+// Format writes the quantity with the configured options
+func (f Formatter) Format(q Quantity) string {
+	value := strconv.FormatFloat(q.Value, 'g', -1, 64)
+	if f.precision >= 0 {
+		value = strconv.FormatFloat(q.Value, 'f', f.precision, 64)
+	}
+	return f.join(value, q)
+}
+
+// join writes the formatted value and the unit of the quantity
+func (f Formatter) join(value string, q Quantity) string {
+	unit := f.unit(q)
+	if f.lowercase {
+		unit = strings.ToLower(unit)
+	}
+	s := value + f.separator + unit
+	if !f.unicode {
+		s = ToASCII(s)
+	}
+	return s
+}
+
+// Humanize writes the quantity in the configured style: converted to the prefix that fits
+// best (binary prefixes with WithBinaryPrefixes()), in engineering notation with the unit
+// without prefix (see FormatEngineering()) or unchanged
+func (f Formatter) Humanize(q Quantity) string {
+	switch f.style {
+	case HumanizeEngineering:
+		if !q.Valid() {
+			return f.Format(q)
+		}
+		base := NormalizeToBase(q)
+		m, exp := engineering(base.Value, 0)
+		value := strconv.FormatFloat(m, 'f', f.precision, 64)
+		if f.precision < 0 {
+			value = formatSignificant(m, 0)
+		}
+		if exp != 0 {
+			value += "e" + strconv.Itoa(exp)
+		}
+		return f.join(value, base)
+	case HumanizeNone:
+		return f.Format(q)
+	}
+	return f.Format(q.Humanize(f.binary))
+}
+
+// Format writes the quantity with the global formatter configuration (see
+// SetFormatterConfig()) and formatting options like q.Format(WithLongNames(), WithPrecision(1))
+// for '1.5 Megabytes per Second'
+func (q Quantity) Format(options ...FormatOption) string {
+	return DefaultFormatter(options...).Format(q)
+}
@@ -0,0 +1,168 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatterConfig contains the formatting preferences shared by all tools of a site, so they
+// render units consistently without passing options everywhere. The global configuration is
+// read from the environment on first use and can be replaced with SetFormatterConfig().
+type FormatterConfig struct {
+	Humanize       HumanizeStyle `json:"humanize,omitempty" yaml:"humanize,omitempty"`               // Style of Humanize() (default: prefix)
+	BinaryPrefixes bool          `json:"binary_prefixes,omitempty" yaml:"binary_prefixes,omitempty"` // Humanize with binary prefixes like 'GiB'
+	LongNames      bool          `json:"long_names,omitempty" yaml:"long_names,omitempty"`           // Long unit names like 'Gigabytes'
+	Unicode        bool          `json:"unicode,omitempty" yaml:"unicode,omitempty"`                 // Symbols like 'µs' and '°C'
+	Separator      *string       `json:"separator,omitempty" yaml:"separator,omitempty"`             // Separator between value and unit (default ' ')
+	Precision      *int          `json:"precision,omitempty" yaml:"precision,omitempty"`             // Number of decimals (default: shortest representation)
+}
+
+// Options returns the formatting options of the configuration
+func (c FormatterConfig) Options() []FormatOption {
+	options := make([]FormatOption, 0, 6)
+	if c.Humanize != HumanizePrefix {
+		options = append(options, WithHumanizeStyle(c.Humanize))
+	}
+	if c.BinaryPrefixes {
+		options = append(options, WithBinaryPrefixes())
+	}
+	if c.LongNames {
+		options = append(options, WithLongNames())
+	}
+	if c.Unicode {
+		options = append(options, WithUnicode())
+	}
+	if c.Separator != nil {
+		options = append(options, WithSeparator(*c.Separator))
+	}
+	if c.Precision != nil {
+		options = append(options, WithPrecision(*c.Precision))
+	}
+	return options
+}
+
+// Environment variables of the formatter configuration
+const (
+	envFormatHumanize       = "CCUNITS_HUMANIZE"        // 'prefix', 'engineering' or 'none'
+	envFormatBinaryPrefixes = "CCUNITS_BINARY_PREFIXES" // Boolean like 'true' or '1'
+	envFormatLongNames      = "CCUNITS_LONG_NAMES"      // Boolean
+	envFormatUnicode        = "CCUNITS_UNICODE"         // Boolean
+	envFormatSeparator      = "CCUNITS_SEPARATOR"
+	envFormatPrecision      = "CCUNITS_PRECISION" // Number of decimals
+)
+
+// FormatterConfigFromEnv overrides the values of a configuration with the set environment
+// variables CCUNITS_HUMANIZE, CCUNITS_BINARY_PREFIXES, CCUNITS_LONG_NAMES, CCUNITS_UNICODE,
+// CCUNITS_SEPARATOR and CCUNITS_PRECISION. It returns an error for invalid values; the valid
+// values are applied nevertheless.
+func FormatterConfigFromEnv(c FormatterConfig) (FormatterConfig, error) {
+	var errs []string
+	if s, ok := os.LookupEnv(envFormatHumanize); ok {
+		if err := c.Humanize.UnmarshalText([]byte(s)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", envFormatHumanize, err))
+		}
+	}
+	for _, b := range []struct {
+		name  string
+		value *bool
+	}{
+		{envFormatBinaryPrefixes, &c.BinaryPrefixes},
+		{envFormatLongNames, &c.LongNames},
+		{envFormatUnicode, &c.Unicode},
+	} {
+		if s, ok := os.LookupEnv(b.name); ok {
+			v, err := strconv.ParseBool(s)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: invalid boolean '%s'", b.name, s))
+				continue
+			}
+			*b.value = v
+		}
+	}
+	if s, ok := os.LookupEnv(envFormatSeparator); ok {
+		c.Separator = &s
+	}
+	if s, ok := os.LookupEnv(envFormatPrecision); ok {
+		p, err := strconv.Atoi(s)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: invalid precision '%s'", envFormatPrecision, s))
+		} else {
+			c.Precision = &p
+		}
+	}
+	if len(errs) > 0 {
+		return c, fmt.Errorf("invalid formatter configuration in environment: %s", strings.Join(errs, ", "))
+	}
+	return c, nil
+}
+
+// LoadFormatterConfig reads a formatter configuration from a JSON or YAML file (selected by
+// the file extension)
+func LoadFormatterConfig(path string) (FormatterConfig, error) {
+	var c FormatterConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, fmt.Errorf("failed to read formatter configuration: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &c)
+	default:
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return FormatterConfig{}, fmt.Errorf("failed to decode formatter configuration in '%s': %w", path, err)
+	}
+	return c, nil
+}
+
+// globalFormatter is the global formatter configuration and its formatter
+type globalFormatter struct {
+	config    FormatterConfig
+	formatter Formatter
+}
+
+var (
+	formatterDefaults     atomic.Pointer[globalFormatter]
+	formatterDefaultsOnce sync.Once
+)
+
+// loadFormatterDefaults returns the global formatter. On first use, it is created out of the
+// environment; invalid values in the environment are ignored.
+func loadFormatterDefaults() *globalFormatter {
+	formatterDefaultsOnce.Do(func() {
+		c, _ := FormatterConfigFromEnv(FormatterConfig{})
+		formatterDefaults.CompareAndSwap(nil, &globalFormatter{config: c, formatter: NewFormatter(c.Options()...)})
+	})
+	return formatterDefaults.Load()
+}
+
+// SetFormatterConfig replaces the global formatter configuration used by DefaultFormatter()
+// and Quantity.Format(), e.g. with a configuration from LoadFormatterConfig(). It is safe for
+// concurrent use.
+func SetFormatterConfig(c FormatterConfig) {
+	formatterDefaults.Store(&globalFormatter{config: c, formatter: NewFormatter(c.Options()...)})
+}
+
+// GetFormatterConfig returns the global formatter configuration
+func GetFormatterConfig() FormatterConfig {
+	return loadFormatterDefaults().config
+}
+
+// DefaultFormatter returns the formatter of the global configuration. The options override
+// the global configuration for this formatter, like DefaultFormatter(WithPrecision(2)).
+func DefaultFormatter(options ...FormatOption) Formatter {
+	f := loadFormatterDefaults().formatter
+	for _, o := range options {
+		o(&f)
+	}
+	return f
+}
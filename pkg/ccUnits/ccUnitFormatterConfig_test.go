@@ -0,0 +1,35 @@
+package ccunits
+
+import "testing"
+
+func TestFormatterConfig(t *testing.T) {
+	previous := GetFormatterConfig()
+	defer SetFormatterConfig(previous)
+	t.Setenv("CCUNITS_HUMANIZE", "engineering")
+	t.Setenv("CCUNITS_UNICODE", "true")
+	t.Setenv("CCUNITS_PRECISION", "1")
+	c, err := FormatterConfigFromEnv(FormatterConfig{BinaryPrefixes: true})
+	if err != nil || c.Humanize != HumanizeEngineering || !c.Unicode || !c.BinaryPrefixes || c.Precision == nil || *c.Precision != 1 {
+		t.Fatalf("Unexpected configuration %+v: %v", c, err)
+	}
+	t.Setenv("CCUNITS_UNICODE", "maybe")
+	if _, err := FormatterConfigFromEnv(FormatterConfig{}); err == nil {
+		t.Errorf("Expected error for invalid boolean")
+	}
+
+	SetFormatterConfig(c)
+	q := NewQuantity(1500, "MB/s")
+	if s := DefaultFormatter().Humanize(q); s != "1.5e9 B/s" {
+		t.Errorf("Expected '1.5e9 B/s' but got '%s'", s)
+	}
+	if s := q.Format(WithPrecision(0)); s != "1500 MB/s" {
+		t.Errorf("Expected '1500 MB/s' but got '%s'", s)
+	}
+	if s := DefaultFormatter(WithHumanizeStyle(HumanizePrefix)).Humanize(NewQuantity(2048, "KiB")); s != "2.0 MiB" {
+		t.Errorf("Expected '2.0 MiB' but got '%s'", s)
+	}
+	SetFormatterConfig(FormatterConfig{})
+	if s := q.Format(); s != "1500 MB/s" {
+		t.Errorf("Expected '1500 MB/s' but got '%s'", s)
+	}
+}
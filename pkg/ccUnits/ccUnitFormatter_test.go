@@ -0,0 +1,31 @@
+package ccunits
+
+import "testing"
+
+func TestFormatter(t *testing.T) {
+	for _, c := range []struct {
+		q        Quantity
+		options  []FormatOption
+		expected string
+	}{
+		{NewQuantity(12.5, "GB/s"), nil, "12.5 GB/s"},
+		{NewQuantity(1.5, "MB/s"), []FormatOption{WithLongNames(), WithPrecision(1)}, "1.5 Megabytes per Second"},
+		{NewQuantity(2, "MB"), []FormatOption{WithLongNames(), WithLowercase()}, "2 megabytes"},
+		{NewQuantity(12.5, "GB/s"), []FormatOption{WithSeparator("")}, "12.5GB/s"},
+		{Quantity{3, NewUnitFromParts(Micro, Time, InvalidMeasure)}, []FormatOption{WithUnicode()}, "3 µs"},
+		{Quantity{3, NewUnitFromParts(Micro, Time, InvalidMeasure)}, nil, "3 us"},
+		{NewQuantity(42, "degC"), []FormatOption{WithUnicode(), WithPrecision(2)}, "42.00 °C"},
+		{NewQuantity(1, "xyz"), nil, "1 " + invalidUnitValue.Short()},
+	} {
+		if s := c.q.Format(c.options...); s != c.expected {
+			t.Errorf("Expected '%s' but got '%s'", c.expected, s)
+		}
+	}
+	if s := NewFormatter().Format(NewQuantity(5, "xyz")); s != "5 "+invalidUnitValue.Short() {
+		t.Errorf("Expected '5 %s' for an invalid quantity but got '%s'", invalidUnitValue.Short(), s)
+	}
+	f := NewFormatter(WithBinaryPrefixes(), WithPrecision(1))
+	if s := f.Humanize(NewQuantity(3*1024*1024, "B")); s != "3.0 MiB" {
+		t.Errorf("Expected '3.0 MiB' but got '%s'", s)
+	}
+}
@@ -0,0 +1,109 @@
+package ccunits
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// formulaNameRegex matches the left side of a formula like 'ipc' or 'mem_bw [GB/s]'
+var formulaNameRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.]*)\s*(?:\[([^\]]+)\])?$`)
+
+// Formula is a derived metric defined by an expression over other metrics like
+// 'ipc = instructions / cycles'. The unit of the result is inferred from the units of the
+// operands.
+type Formula struct {
+	Name       string      // Name of the derived metric
+	Unit       Unit        // Unit of the result, the declared unit if set
+	Expression *Expression // Compiled expression of the right side
+	declared   bool
+}
+
+// ParseFormula parses a formula like 'bandwidth = bytes / time' with the units of the
+// variables and infers the unit of the result like 'B/s'. Optionally, the unit of the result
+// is declared in brackets like 'bandwidth [GB/s] = bytes / time'; the inferred unit must be
+// convertible into it. It returns an error for conditions, results without unit and results
+// not matching the declared unit, so derived metrics cannot silently produce nonsense units.
+func ParseFormula(def string, vars map[string]Unit) (*Formula, error) {
+	lhs, rhs, ok := strings.Cut(def, "=")
+	if !ok || strings.HasPrefix(rhs, "=") || strings.ContainsAny(lhs, "<>!") {
+		return nil, fmt.Errorf("formula '%s' has no assignment", def)
+	}
+	m := formulaNameRegex.FindStringSubmatch(strings.TrimSpace(lhs))
+	if m == nil {
+		return nil, fmt.Errorf("invalid name of formula '%s'", def)
+	}
+	f := &Formula{Name: m[1]}
+	if _, ok := vars[f.Name]; ok {
+		return nil, fmt.Errorf("formula '%s' redefines variable '%s'", def, f.Name)
+	}
+	e, err := CompileExpression(rhs, vars)
+	if err != nil {
+		return nil, fmt.Errorf("invalid formula '%s': %w", f.Name, err)
+	}
+	f.Expression = e
+	inferred, err := e.Unit()
+	if err != nil {
+		return nil, fmt.Errorf("invalid formula '%s': %w", f.Name, err)
+	}
+	f.Unit = inferred
+	if len(m[2]) > 0 {
+		declared, err := ParseUnit(strings.TrimSpace(m[2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid unit of formula '%s': %w", f.Name, err)
+		}
+		if !inferred.Compatible(declared) {
+			return nil, fmt.Errorf("formula '%s' results in '%s' which cannot be converted to '%s': %w",
+				f.Name, inferred.Short(), declared.Short(), ErrIncompatibleMeasure)
+		}
+		f.Unit, f.declared = declared, true
+	}
+	return f, nil
+}
+
+// ParseFormulas parses formulas in order. The results of earlier formulas can be used as
+// variables by later ones like 'flops_per_joule = flops / energy' after 'energy = power * time'.
+func ParseFormulas(defs []string, vars map[string]Unit) ([]*Formula, error) {
+	all := make(map[string]Unit, len(vars)+len(defs))
+	for name, u := range vars {
+		all[name] = u
+	}
+	formulas := make([]*Formula, 0, len(defs))
+	for _, def := range defs {
+		f, err := ParseFormula(def, all)
+		if err != nil {
+			return nil, err
+		}
+		all[f.Name] = f.Unit
+		formulas = append(formulas, f)
+	}
+	return formulas, nil
+}
+
+// String returns the formula with the unit of the result like 'ipc [Events/cyc] = instructions / cycles'
+func (f *Formula) String() string {
+	return fmt.Sprintf("%s [%s] = %s", f.Name, f.Unit.Short(), strings.TrimSpace(f.Expression.String()))
+}
+
+// Evaluate computes the derived metric with the values of the variables and returns it in
+// the unit of the formula
+func (f *Formula) Evaluate(values map[string]Quantity) (Quantity, error) {
+	q, err := f.Expression.Value(values)
+	if err != nil || !f.declared {
+		return q, err
+	}
+	return q.ConvertTo(f.Unit)
+}
+
+// EvaluateFormulas computes formulas in order and adds the results to the values, so later
+// formulas can use the results of earlier ones
+func EvaluateFormulas(formulas []*Formula, values map[string]Quantity) error {
+	for _, f := range formulas {
+		q, err := f.Evaluate(values)
+		if err != nil {
+			return fmt.Errorf("cannot evaluate formula '%s': %w", f.Name, err)
+		}
+		values[f.Name] = q
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormula(t *testing.T) {
+	events := NewUnit("events")
+	vars := map[string]Unit{
+		"instructions": events,
+		"cycles":       NewUnit("cyc"),
+		"bytes":        NewUnit("GB"),
+		"time":         NewUnit("ms"),
+		"power":        NewUnit("W"),
+		"flops":        NewUnit("GFlops/s"),
+	}
+	formulas, err := ParseFormulas([]string{
+		"ipc = instructions / cycles",
+		"bandwidth [GB/s] = bytes / time",
+		"energy = power * time",
+		"efficiency [GFlops/J] = flops * time / energy",
+	}, vars)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, expected := range []string{"events/cyc", "GB/s", "J", "GFlops/J"} {
+		if u := formulas[i].Unit.Short(); u != expected {
+			t.Errorf("Expected unit '%s' for formula '%s' but got '%s'", expected, formulas[i].Name, u)
+		}
+	}
+	values := map[string]Quantity{
+		"instructions": {Value: 3e9, Unit: events},
+		"cycles":       NewQuantity(2e9, "cyc"),
+		"bytes":        NewQuantity(10, "GB"),
+		"time":         NewQuantity(2, "s"),
+		"power":        NewQuantity(200, "W"),
+		"flops":        NewQuantity(1, "TFlops/s"),
+	}
+	if err := EvaluateFormulas(formulas, values); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for name, expected := range map[string]float64{"ipc": 1.5, "bandwidth": 5, "energy": 400, "efficiency": 5} {
+		if v := values[name].Value; math.Abs(v-expected) > 1e-9 {
+			t.Errorf("Expected %v for '%s' but got %s", expected, name, values[name].String())
+		}
+	}
+
+	for _, def := range []string{
+		"bandwidth [GB/s] = bytes * time", // Result is no bandwidth
+		"x = power > 100 W",               // Condition
+		"x = bytes * bytes",               // No unit for B^2
+		"bytes = power",                   // Redefinition
+		"2x = power",
+		"power * 2",
+	} {
+		if _, err := ParseFormula(def, vars); err == nil {
+			t.Errorf("Expected error for '%s'", def)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package ccunits
+
+// Constants, tables and aliases of the built-in measures and prefixes out of ccUnitBuiltin.yaml
+//go:generate go run ../../cmd/ccunits-enumgen -in ccUnitBuiltin.yaml -out ccUnitBuiltin.go
+
+// Definitions of the prefixes and measures for TypeScript and JSON consumers like the web UI
+// and the lookup of the known spellings used by the parser
+//go:generate go run ../../cmd/ccunits-gen -ts generated/ccUnits.ts -json generated/ccUnits.json -go ccUnitLookup.go
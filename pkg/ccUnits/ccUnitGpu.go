@@ -0,0 +1,137 @@
+package ccunits
+
+import "fmt"
+
+// GpuLibrary identifies the library a GPU telemetry value was read with
+type GpuLibrary int
+
+const (
+	InvalidGpuLibrary GpuLibrary = iota
+	NVML
+	DCGM
+	ROCmSMI
+)
+
+// String returns the name of the GPU library like 'NVML' or 'ROCm-SMI'
+func (l GpuLibrary) String() string {
+	switch l {
+	case NVML:
+		return "NVML"
+	case DCGM:
+		return "DCGM"
+	case ROCmSMI:
+		return "ROCm-SMI"
+	}
+	return "Invalid"
+}
+
+// NvmlFieldUnits maps the NVML device query functions to the unit of the returned
+// values and the unit used by the collectors
+var NvmlFieldUnits map[string]SourceUnit = map[string]SourceUnit{
+	"nvmlDeviceGetPowerUsage":             newSourceUnit(Milli, Watt, Base, Watt),
+	"nvmlDeviceGetEnforcedPowerLimit":     newSourceUnit(Milli, Watt, Base, Watt),
+	"nvmlDeviceGetPowerManagementLimit":   newSourceUnit(Milli, Watt, Base, Watt),
+	"nvmlDeviceGetTotalEnergyConsumption": newSourceUnit(Milli, Joule, Base, Joule),
+	"nvmlDeviceGetMemoryInfo":             newSourceUnit(Base, Bytes, Mebi, Bytes),
+	"nvmlDeviceGetBAR1MemoryInfo":         newSourceUnit(Base, Bytes, Mebi, Bytes),
+	"nvmlDeviceGetClockInfo":              newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"nvmlDeviceGetMaxClockInfo":           newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"nvmlDeviceGetUtilizationRates":       newSourceUnit(Base, Percentage, Base, Percentage),
+	"nvmlDeviceGetFanSpeed":               newSourceUnit(Base, Percentage, Base, Percentage),
+	"nvmlDeviceGetTemperature":            newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+}
+
+// DcgmFieldUnits maps the DCGM field identifiers to the unit of the returned values
+// and the unit used by the collectors
+var DcgmFieldUnits map[string]SourceUnit = map[string]SourceUnit{
+	"DCGM_FI_DEV_POWER_USAGE":              newSourceUnit(Base, Watt, Base, Watt),
+	"DCGM_FI_DEV_POWER_MGMT_LIMIT":         newSourceUnit(Base, Watt, Base, Watt),
+	"DCGM_FI_DEV_TOTAL_ENERGY_CONSUMPTION": newSourceUnit(Milli, Joule, Base, Joule),
+	"DCGM_FI_DEV_FB_TOTAL":                 newSourceUnit(Mebi, Bytes, Mebi, Bytes),
+	"DCGM_FI_DEV_FB_USED":                  newSourceUnit(Mebi, Bytes, Mebi, Bytes),
+	"DCGM_FI_DEV_FB_FREE":                  newSourceUnit(Mebi, Bytes, Mebi, Bytes),
+	"DCGM_FI_DEV_SM_CLOCK":                 newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"DCGM_FI_DEV_MEM_CLOCK":                newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"DCGM_FI_DEV_GPU_UTIL":                 newSourceUnit(Base, Percentage, Base, Percentage),
+	"DCGM_FI_DEV_MEM_COPY_UTIL":            newSourceUnit(Base, Percentage, Base, Percentage),
+	"DCGM_FI_DEV_GPU_TEMP":                 newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"DCGM_FI_DEV_MEMORY_TEMP":              newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"DCGM_FI_DEV_ENC_UTIL":                 newSourceUnit(Base, Percentage, Base, Percentage),
+	"DCGM_FI_DEV_DEC_UTIL":                 newSourceUnit(Base, Percentage, Base, Percentage),
+	"DCGM_FI_DEV_FAN_SPEED":                newSourceUnit(Base, Percentage, Base, Percentage),
+	"DCGM_FI_DEV_ENFORCED_POWER_LIMIT":     newSourceUnit(Base, Watt, Base, Watt),
+	"DCGM_FI_DEV_APP_SM_CLOCK":             newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"DCGM_FI_DEV_APP_MEM_CLOCK":            newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"DCGM_FI_DEV_MAX_SM_CLOCK":             newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"DCGM_FI_DEV_MAX_MEM_CLOCK":            newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"DCGM_FI_DEV_POWER_MGMT_LIMIT_MAX":     newSourceUnit(Base, Watt, Base, Watt),
+	"DCGM_FI_DEV_POWER_MGMT_LIMIT_MIN":     newSourceUnit(Base, Watt, Base, Watt),
+	"DCGM_FI_DEV_SLOWDOWN_TEMP":            newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"DCGM_FI_DEV_SHUTDOWN_TEMP":            newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+}
+
+// RocmSmiFieldUnits maps the ROCm-SMI device query functions and the members of the GPU
+// metrics table (rsmi_dev_gpu_metrics_info_get) to the unit of the returned values and the
+// unit used by the collectors
+var RocmSmiFieldUnits map[string]SourceUnit = map[string]SourceUnit{
+	"rsmi_dev_power_ave_get":           newSourceUnit(Micro, Watt, Base, Watt),
+	"rsmi_dev_power_cap_get":           newSourceUnit(Micro, Watt, Base, Watt),
+	"rsmi_dev_energy_count_get":        newSourceUnit(Micro, Joule, Base, Joule),
+	"rsmi_dev_memory_total_get":        newSourceUnit(Base, Bytes, Mebi, Bytes),
+	"rsmi_dev_memory_usage_get":        newSourceUnit(Base, Bytes, Mebi, Bytes),
+	"rsmi_dev_gpu_clk_freq_get":        newSourceUnit(Base, Frequency, Mega, Frequency),
+	"rsmi_dev_busy_percent_get":        newSourceUnit(Base, Percentage, Base, Percentage),
+	"rsmi_dev_memory_busy_percent_get": newSourceUnit(Base, Percentage, Base, Percentage),
+	"rsmi_dev_temp_metric_get":         newSourceUnit(Milli, TemperatureC, Base, TemperatureC),
+	"rsmi_dev_fan_rpms_get":            newSourceUnit(Base, Rotation, Base, Rotation),
+	"temperature_edge":                 newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"temperature_hotspot":              newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"temperature_mem":                  newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"temperature_vrgfx":                newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"temperature_vrsoc":                newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"temperature_vrmem":                newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"temperature_hbm":                  newSourceUnit(Base, TemperatureC, Base, TemperatureC),
+	"average_gfx_activity":             newSourceUnit(Base, Percentage, Base, Percentage),
+	"average_umc_activity":             newSourceUnit(Base, Percentage, Base, Percentage),
+	"average_mm_activity":              newSourceUnit(Base, Percentage, Base, Percentage),
+	"average_socket_power":             newSourceUnit(Base, Watt, Base, Watt),
+	"average_gfxclk_frequency":         newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"average_socclk_frequency":         newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"average_uclk_frequency":           newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"average_vclk0_frequency":          newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"average_dclk0_frequency":          newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"average_vclk1_frequency":          newSourceUnit(Mega, Frequency, Mega, Frequency),
+	"average_dclk1_frequency":          newSourceUnit(Mega, Frequency, Mega, Frequency),
+}
+
+// GetGpuFieldUnit returns the unit mapping for a field of a GPU library. The field is
+// the name of the query function (NVML, ROCm-SMI) or the field identifier (DCGM).
+func GetGpuFieldUnit(lib GpuLibrary, field string) (SourceUnit, bool) {
+	var table map[string]SourceUnit
+	switch lib {
+	case NVML:
+		table = NvmlFieldUnits
+	case DCGM:
+		table = DcgmFieldUnits
+	case ROCmSMI:
+		table = RocmSmiFieldUnits
+	default:
+		return SourceUnit{}, false
+	}
+	s, ok := table[field]
+	return s, ok
+}
+
+// NormalizeGpuValue converts a value read with a GPU library to the unit used by the
+// collectors. It returns the converted value and a new unit of the result.
+func NormalizeGpuValue(lib GpuLibrary, field string, value interface{}) (interface{}, Unit, error) {
+	s, ok := GetGpuFieldUnit(lib, field)
+	if !ok {
+		return value, invalidUnitValue.Unit(), fmt.Errorf("unknown %s field '%s': %w", lib.String(), field, ErrInvalidMeasure)
+	}
+	out, err := s.Convert(value)
+	if err != nil {
+		return value, invalidUnitValue.Unit(), err
+	}
+	return out, s.Target.Unit(), nil
+}
@@ -0,0 +1,127 @@
+package ccunits
+
+import (
+	"testing"
+)
+
+func TestNormalizeGpuValue(t *testing.T) {
+	type testDefinition struct {
+		lib           GpuLibrary
+		field         string
+		input         interface{}
+		valueExpected interface{}
+		unitExpected  string
+		errorExpected bool
+	}
+
+	gpuTests := []testDefinition{
+		{
+			lib:           NVML,
+			field:         "nvmlDeviceGetPowerUsage",
+			input:         float64(250000),
+			valueExpected: float64(250),
+			unitExpected:  "W",
+		},
+		{
+			lib:           DCGM,
+			field:         "DCGM_FI_DEV_FB_USED",
+			input:         float64(1),
+			valueExpected: float64(1),
+			unitExpected:  "MiB",
+		},
+		{
+			lib:           NVML,
+			field:         "nvmlDeviceGetMemoryInfo",
+			input:         uint64(3 * 1024 * 1024),
+			valueExpected: uint64(3),
+			unitExpected:  "MiB",
+		},
+		{
+			lib:           NVML,
+			field:         "nvmlDeviceGetBAR1MemoryInfo",
+			input:         float64(512 * 1024),
+			valueExpected: float64(0.5),
+			unitExpected:  "MiB",
+		},
+		{
+			lib:           ROCmSMI,
+			field:         "rsmi_dev_memory_usage_get",
+			input:         uint64(1024 * 1024),
+			valueExpected: uint64(1),
+			unitExpected:  "MiB",
+		},
+		{
+			lib:           ROCmSMI,
+			field:         "rsmi_dev_gpu_clk_freq_get",
+			input:         uint64(1500000000),
+			valueExpected: uint64(1500),
+			unitExpected:  "MHz",
+		},
+		{
+			lib:           ROCmSMI,
+			field:         "rsmi_dev_temp_metric_get",
+			input:         int64(45000),
+			valueExpected: int64(45),
+			unitExpected:  "degC",
+		},
+		{
+			lib:           ROCmSMI,
+			field:         "average_socket_power",
+			input:         uint16(180),
+			valueExpected: uint16(180),
+			unitExpected:  "W",
+		},
+		{
+			lib:           NVML,
+			field:         "nvmlDeviceGetUnknown",
+			input:         float64(1),
+			valueExpected: float64(1),
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range gpuTests {
+		value, u, err := NormalizeGpuValue(test.lib, test.field, test.input)
+		if test.errorExpected {
+			if err == nil || u.Valid() {
+				t.Errorf("Expected error and invalid unit for %s field '%s'", test.lib.String(), test.field)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %s field '%s': %v", test.lib.String(), test.field, err)
+			continue
+		}
+		if value != test.valueExpected {
+			t.Errorf("%s field '%s': expected value %v but got %v", test.lib.String(), test.field, test.valueExpected, value)
+		}
+		if u.Short() != test.unitExpected {
+			t.Errorf("%s field '%s': expected unit '%s' but got '%s'", test.lib.String(), test.field, test.unitExpected, u.Short())
+		}
+	}
+}
+
+func TestNormalizeGpuValueUnitCopies(t *testing.T) {
+	// Changing the returned unit must not change the table
+	_, u, err := NormalizeGpuValue(NVML, "nvmlDeviceGetPowerUsage", float64(1000))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	u.SetPrefix(Kilo)
+	if _, v, _ := NormalizeGpuValue(NVML, "nvmlDeviceGetPowerUsage", float64(1000)); v.Short() != "W" {
+		t.Errorf("Expected 'W' but got '%s'", v.Short())
+	}
+}
+
+func TestGpuFieldUnitsValid(t *testing.T) {
+	for lib, table := range map[GpuLibrary]map[string]SourceUnit{NVML: NvmlFieldUnits, DCGM: DcgmFieldUnits, ROCmSMI: RocmSmiFieldUnits} {
+		for field, s := range table {
+			if !s.Valid() || !s.Raw.Compatible(s.Target) {
+				t.Errorf("%s field '%s': invalid mapping from '%s' to '%s'", lib.String(), field, s.Raw.Short(), s.Target.Short())
+			}
+		}
+	}
+	if (SourceUnit{}).Valid() {
+		t.Errorf("Expected empty source unit to be invalid")
+	}
+}
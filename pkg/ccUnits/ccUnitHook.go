@@ -0,0 +1,53 @@
+package ccunits
+
+import "sync/atomic"
+
+// invalidUnitHook is called with unit strings which cannot be parsed
+var invalidUnitHook atomic.Pointer[func(unitStr string)]
+
+// SetInvalidUnitHook sets a callback which is called with the offending string whenever
+// parsing a unit string fails, i.e. NewUnit(), NewUnitValue() or InternUnit() return the
+// invalid unit or ParseUnit() returns an error. Operators can count the calls in metrics to
+// discover misconfigured collectors instead of silently storing values with invalid units.
+// The hook is called for every failed parse, also for cached results and empty strings. It
+// is called concurrently from many goroutines and should return quickly. nil removes the hook.
+func SetInvalidUnitHook(hook func(unitStr string)) {
+	if hook == nil {
+		invalidUnitHook.Store(nil)
+		return
+	}
+	invalidUnitHook.Store(&hook)
+}
+
+// reportInvalidUnit calls the hook for an invalid unit string
+func reportInvalidUnit(unitStr string) {
+	if hook := invalidUnitHook.Load(); hook != nil {
+		(*hook)(unitStr)
+	}
+}
+
+// deprecatedUnitHook is called with legacy spellings of units
+var deprecatedUnitHook atomic.Pointer[func(spelling string, canonical string)]
+
+// SetDeprecatedUnitHook sets a callback which is called with the spelling and the canonical
+// unit string whenever a legacy spelling of older collector versions like 'bytes/sec' is
+// parsed (see LegacySpellings()). Sites can count the calls per spelling to track the
+// migration of their collectors. Like the hook of SetInvalidUnitHook(), it is called for
+// every parse, also for cached results, concurrently from many goroutines. nil removes the hook.
+func SetDeprecatedUnitHook(hook func(spelling string, canonical string)) {
+	if hook == nil {
+		deprecatedUnitHook.Store(nil)
+		return
+	}
+	deprecatedUnitHook.Store(&hook)
+}
+
+// reportDeprecatedUnit calls the hook if a unit string is a legacy spelling. The table of
+// legacy spellings is only checked if a hook is set.
+func reportDeprecatedUnit(unitStr string) {
+	if hook := deprecatedUnitHook.Load(); hook != nil {
+		if canonical, ok := legacySpelling(unitStr); ok {
+			(*hook)(unitStr, canonical)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HttpConvertRequest is the request body of the /convert endpoint. Either a single value or
+// a list of values is converted.
+type HttpConvertRequest struct {
+	Value  *float64  `json:"value,omitempty"`
+	Values []float64 `json:"values,omitempty"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+}
+
+// HttpConvertResponse is the response body of the /convert endpoint
+type HttpConvertResponse struct {
+	Value  *float64  `json:"value,omitempty"`
+	Values []float64 `json:"values,omitempty"`
+	Unit   string    `json:"unit"`
+}
+
+// HttpParseRequest is the request body of the /parse endpoint
+type HttpParseRequest struct {
+	Unit string `json:"unit"`
+}
+
+// HttpParseResponse is the response body of the /parse endpoint
+type HttpParseResponse struct {
+	Valid        bool    `json:"valid"`
+	Short        string  `json:"short,omitempty"`
+	Long         string  `json:"long,omitempty"`
+	Prefix       string  `json:"prefix,omitempty"`
+	PrefixFactor float64 `json:"prefix_factor,omitempty"`
+	Measure      string  `json:"measure,omitempty"`
+	Denominator  string  `json:"denominator,omitempty"`
+	Issues       []Issue `json:"issues,omitempty"`
+}
+
+// httpError is the response body for failed requests
+type httpError struct {
+	Error string `json:"error"`
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decodeRequest checks the method and decodes the JSON request body
+func decodeRequest(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, httpError{Error: "only POST requests are supported"})
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeJSON(w, http.StatusBadRequest, httpError{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+func handleConvert(w http.ResponseWriter, r *http.Request) {
+	var req HttpConvertRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Value == nil && req.Values == nil {
+		writeJSON(w, http.StatusBadRequest, httpError{Error: "request requires 'value' or 'values'"})
+		return
+	}
+	in := NewUnit(req.From)
+	if !in.Valid() {
+		writeJSON(w, http.StatusBadRequest, httpError{Error: fmt.Sprintf("invalid unit '%s'", req.From)})
+		return
+	}
+	out := NewUnit(req.To)
+	if !out.Valid() {
+		writeJSON(w, http.StatusBadRequest, httpError{Error: fmt.Sprintf("invalid unit '%s'", req.To)})
+		return
+	}
+	conv, err := NewConverter(in, out)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, httpError{Error: fmt.Sprintf("cannot convert '%s' to '%s': %v", in.Short(), out.Short(), err)})
+		return
+	}
+	resp := HttpConvertResponse{
+		Unit: out.Short(),
+	}
+	if req.Value != nil {
+		v := conv.ApplyFloat64(*req.Value)
+		resp.Value = &v
+	}
+	if req.Values != nil {
+		resp.Values = append(make([]float64, 0, len(req.Values)), req.Values...)
+		conv.ApplyFloat64s(resp.Values)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func handleParse(w http.ResponseWriter, r *http.Request) {
+	var req HttpParseRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	u := NewUnit(req.Unit)
+	resp := HttpParseResponse{
+		Valid:  u.Valid(),
+		Issues: Lint([]string{req.Unit}),
+	}
+	if resp.Valid {
+		p := u.GetPrefix()
+		m := u.GetMeasure()
+		resp.Short = u.Short()
+		resp.Long = u.String()
+		resp.Prefix = p.Prefix()
+		resp.PrefixFactor = float64(p)
+		resp.Measure = m.Short()
+		if d := u.GetUnitDenominator(); d != InvalidMeasure {
+			resp.Denominator = d.Short()
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func handleCompatibility(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeJSON(w, http.StatusMethodNotAllowed, httpError{Error: "only GET requests are supported"})
+		return
+	}
+	m, err := NewCompatibilityMatrix(r.URL.Query()["unit"]...)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, httpError{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// NewHttpHandler returns a handler serving the endpoints /convert and /parse with JSON
+// request and response bodies and /compatibility with the compatibility matrix, so
+// components not written in Go can use the same unit logic. Use http.StripPrefix to serve
+// the endpoints below a path.
+//
+//	POST /convert {"value": 1234, "from": "MiB/s", "to": "GB/s"} -> {"value": 1.293942784, "unit": "GB/s"}
+//	POST /parse   {"unit": "mB"} -> {"valid": true, "short": "MB", ..., "issues": [...]}
+//	GET  /compatibility?unit=B/s&unit=W -> {"units": ["B/s", "W"], "kinds": [["factor", ""], ["", "factor"]]}
+func NewHttpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/convert", handleConvert)
+	mux.HandleFunc("/parse", handleParse)
+	mux.HandleFunc("/compatibility", handleCompatibility)
+	return mux
+}
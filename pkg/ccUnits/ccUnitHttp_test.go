@@ -0,0 +1,71 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHttpHandler(t *testing.T) {
+	server := httptest.NewServer(NewHttpHandler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/convert", "application/json", strings.NewReader(`{"values": [1000, 2000], "from": "MB/s", "to": "GB/s"}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var conv HttpConvertResponse
+	err = json.NewDecoder(resp.Body).Decode(&conv)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %v", resp.StatusCode, err)
+	}
+	if conv.Unit != "GB/s" || len(conv.Values) != 2 || conv.Values[1] != 2 {
+		t.Errorf("Wrong conversion result: %+v", conv)
+	}
+
+	resp, err = http.Post(server.URL+"/convert", "application/json", strings.NewReader(`{"value": 1, "from": "W", "to": "B"}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for incompatible units but got %d", http.StatusUnprocessableEntity, resp.StatusCode)
+	}
+
+	resp, err = http.Post(server.URL+"/parse", "application/json", strings.NewReader(`{"unit": "mB"}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var parse HttpParseResponse
+	err = json.NewDecoder(resp.Body).Decode(&parse)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !parse.Valid || parse.Short != "MB" || parse.PrefixFactor != 1e6 || len(parse.Issues) != 1 {
+		t.Errorf("Wrong parse result: %+v", parse)
+	}
+
+	resp, err = http.Get(server.URL + "/parse")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d for GET but got %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/compatibility?unit=MB/s&unit=W&unit=kJ/s")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	var matrix CompatibilityMatrix
+	err = json.NewDecoder(resp.Body).Decode(&matrix)
+	resp.Body.Close()
+	if ok, cerr := matrix.Convertible("kW", "J/s"); err != nil || cerr != nil || !ok || len(matrix.Units) != 3 {
+		t.Errorf("Wrong compatibility matrix %+v: %v %v", matrix, err, cerr)
+	}
+}
@@ -0,0 +1,103 @@
+package ccunits
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Units of the hwmon sensor attributes like 'temp1_input' or 'in0_max'.
+// See https://www.kernel.org/doc/html/latest/hwmon/sysfs-interface.html
+
+// hwmonAttributeRegex splits a sensor attribute into its class and item like 'temp' and
+// 'crit_hyst' for 'temp2_crit_hyst'
+var hwmonAttributeRegex = regexp.MustCompile(`^([a-z]+)[0-9]*_([a-z_]+)$`)
+
+// hwmonClass is the unit of the values of a sensor class in sysfs and in lm-sensors
+type hwmonClass struct {
+	sysfs   UnitValue
+	sensors UnitValue
+}
+
+// hwmonClasses contains the sensor classes with numeric values
+var hwmonClasses = map[string]hwmonClass{
+	"temp":   {UnitValue{Milli, TemperatureC, InvalidMeasure}, UnitValue{Base, TemperatureC, InvalidMeasure}},
+	"in":     {UnitValue{Milli, Volt, InvalidMeasure}, UnitValue{Base, Volt, InvalidMeasure}},
+	"curr":   {UnitValue{Milli, Ampere, InvalidMeasure}, UnitValue{Base, Ampere, InvalidMeasure}},
+	"power":  {UnitValue{Micro, Watt, InvalidMeasure}, UnitValue{Base, Watt, InvalidMeasure}},
+	"energy": {UnitValue{Micro, Joule, InvalidMeasure}, UnitValue{Base, Joule, InvalidMeasure}},
+	"fan":    {UnitValue{Base, Rotation, InvalidMeasure}, UnitValue{Base, Rotation, InvalidMeasure}},
+}
+
+// hwmonNonValueItems are the items of attributes which are no values of the sensor class
+// like flags, labels and configuration
+var hwmonNonValueItems = []string{"alarm", "beep", "fault", "enable", "label", "type", "div", "pulses", "accuracy"}
+
+// hwmonUnit returns the unit of a sensor attribute in sysfs or in lm-sensors
+func hwmonUnit(attribute string, sysfs bool) (Unit, bool) {
+	if attribute == "update_interval" {
+		return newBaseUnit(Milli, Time), true
+	}
+	m := hwmonAttributeRegex.FindStringSubmatch(attribute)
+	if m == nil {
+		return invalidUnitValue.Unit(), false
+	}
+	class, ok := hwmonClasses[m[1]]
+	if !ok {
+		return invalidUnitValue.Unit(), false
+	}
+	item := m[2]
+	for _, s := range hwmonNonValueItems {
+		if item == s || strings.HasSuffix(item, "_"+s) {
+			return invalidUnitValue.Unit(), false
+		}
+	}
+	// Averaging intervals of power sensors like 'power1_average_interval'
+	if strings.Contains(item, "interval") {
+		if sysfs {
+			return newBaseUnit(Milli, Time), true
+		}
+		return newBaseUnit(Base, Time), true
+	}
+	if sysfs {
+		return class.sysfs.Unit(), true
+	}
+	return class.sensors.Unit(), true
+}
+
+// HwmonAttributeUnit returns the unit of the values of a hwmon sensor attribute in sysfs
+// like millidegree Celsius for 'temp1_input', millivolts for 'in0_max', microwatts for
+// 'power1_average' and RPM for 'fan2_input'. Attributes which are no values like
+// 'temp1_label', 'temp1_type' or 'in0_alarm' have no unit (false).
+func HwmonAttributeUnit(attribute string) (Unit, bool) {
+	return hwmonUnit(attribute, true)
+}
+
+// SensorsAttributeUnit returns the unit of the values of a sensor attribute reported by
+// lm-sensors ('sensors -u') or libsensors. In contrast to sysfs, they are scaled to the units
+// without prefix like degree Celsius for 'temp1_input' and Volt for 'in0_input'.
+func SensorsAttributeUnit(attribute string) (Unit, bool) {
+	return hwmonUnit(attribute, false)
+}
+
+// NewHwmonQuantity creates a quantity for a raw value read from a hwmon sensor attribute in
+// sysfs. It returns an error for attributes without unit.
+func NewHwmonQuantity(attribute string, value float64) (Quantity, error) {
+	u, ok := HwmonAttributeUnit(attribute)
+	if !ok {
+		return Quantity{Value: value, Unit: u}, fmt.Errorf("unknown unit for hwmon attribute '%s': %w", attribute, ErrInvalidMeasure)
+	}
+	return Quantity{Value: value, Unit: u}, nil
+}
+
+// isHwmonPath checks whether a file is a sensor attribute of a hwmon device like
+// '/sys/class/hwmon/hwmon2/temp1_input' or '/sys/devices/platform/coretemp.0/hwmon/hwmon3/device/temp1_input'
+func isHwmonPath(path string) bool {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "device" {
+		dir = filepath.Dir(dir)
+	}
+	match, err := filepath.Match("hwmon[0-9]*", filepath.Base(dir))
+	return err == nil && match
+}
@@ -0,0 +1,55 @@
+package ccunits
+
+import "testing"
+
+func TestHwmonAttributeUnit(t *testing.T) {
+	for _, c := range []struct {
+		attribute string
+		sysfs     string
+		sensors   string
+	}{
+		{"temp1_input", "mdegC", "degC"},
+		{"temp2_crit_hyst", "mdegC", "degC"},
+		{"in0_max", "mV", "V"},
+		{"curr1_input", "mA", "A"},
+		{"power1_average", "uW", "W"},
+		{"power1_average_interval", "ms", "s"},
+		{"energy1_input", "uJ", "J"},
+		{"fan2_input", "RPM", "RPM"},
+		{"update_interval", "ms", "ms"},
+		{"temp1_label", "", ""},
+		{"temp1_type", "", ""},
+		{"in0_min_alarm", "", ""},
+		{"fan1_div", "", ""},
+		{"pwm1", "", ""},
+		{"name", "", ""},
+	} {
+		for _, f := range []struct {
+			name     string
+			fn       func(string) (Unit, bool)
+			expected string
+		}{
+			{"sysfs", HwmonAttributeUnit, c.sysfs},
+			{"lm-sensors", SensorsAttributeUnit, c.sensors},
+		} {
+			u, ok := f.fn(c.attribute)
+			if ok != (len(f.expected) > 0) || ok && u.Short() != f.expected || !ok && u.Valid() {
+				t.Errorf("Expected unit '%s' for %s attribute '%s' but got '%s' (%v)", f.expected, f.name, c.attribute, u.Short(), ok)
+			}
+		}
+	}
+
+	q, err := NewSysfsQuantity("/sys/devices/platform/coretemp.0/hwmon/hwmon3/temp1_input", 27800)
+	if err == nil {
+		q, err = q.ConvertTo(NewUnit("degC"))
+	}
+	if err != nil || q.Value != 27.8 {
+		t.Errorf("Expected 27.8 degC but got %s: %v", q.String(), err)
+	}
+	if _, err := NewSysfsQuantity("/sys/class/hwmon/hwmon0/temp1_label", 0); err == nil {
+		t.Errorf("Expected error for hwmon label")
+	}
+	if q, err := NewHwmonQuantity("in0_alarm", 1); err == nil || q.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for hwmon alarm but got '%s'", q.String())
+	}
+}
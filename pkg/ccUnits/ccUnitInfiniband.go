@@ -0,0 +1,74 @@
+package ccunits
+
+import (
+	"fmt"
+	"time"
+)
+
+// InfinibandWordSize is the number of bytes of a word counted by the InfiniBand data port
+// counters
+const InfinibandWordSize = 4
+
+// InfinibandPortCounters contains the measures of the InfiniBand port counters by their
+// names in the Performance Management attributes (PortXmitData) and in sysfs
+// (/sys/class/infiniband/<device>/ports/<port>/counters/port_xmit_data). The data counters
+// count 4-byte words.
+var InfinibandPortCounters map[string]Measure = map[string]Measure{
+	"PortXmitData":      InfinibandWords,
+	"PortRcvData":       InfinibandWords,
+	"PortXmitPkts":      Packets,
+	"PortRcvPkts":       Packets,
+	"port_xmit_data":    InfinibandWords,
+	"port_rcv_data":     InfinibandWords,
+	"port_xmit_packets": Packets,
+	"port_rcv_packets":  Packets,
+}
+
+// infinibandCounterUnit returns the unit of the converted values of a port counter
+func infinibandCounterUnit(counter string) (Measure, error) {
+	m, ok := InfinibandPortCounters[counter]
+	if !ok {
+		return InvalidMeasure, fmt.Errorf("unknown InfiniBand port counter '%s': %w", counter, ErrInvalidMeasure)
+	}
+	if m == InfinibandWords {
+		return Bytes, nil
+	}
+	return m, nil
+}
+
+// ConvertInfinibandCounter converts the raw value of an InfiniBand port counter to bytes
+// for the data counters or packets. The conversion is exact for counters up to 2^62 words.
+func ConvertInfinibandCounter(counter string, value uint64) (uint64, Unit, error) {
+	m, err := infinibandCounterUnit(counter)
+	if err != nil {
+		return 0, invalidUnitValue.Unit(), err
+	}
+	if m == Bytes {
+		value *= InfinibandWordSize
+	}
+	return value, m.BaseUnit(), nil
+}
+
+// GetInfinibandCounterRate calculates the rate of an InfiniBand port counter out of two raw
+// readings taken interval apart. The resulting quantity is in bytes or packets per second.
+// It returns an error if the counter decreased, e.g. because it was reset.
+func GetInfinibandCounterRate(counter string, previous, current uint64, interval time.Duration) (Quantity, error) {
+	m, err := infinibandCounterUnit(counter)
+	if err != nil {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, err
+	}
+	if interval <= 0 {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid interval %v for InfiniBand port counter '%s': %w", interval, counter, ErrInvalidValue)
+	}
+	if current < previous {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("InfiniBand port counter '%s' decreased from %d to %d: %w", counter, previous, current, ErrInvalidValue)
+	}
+	diff := float64(current - previous)
+	if m == Bytes {
+		diff *= InfinibandWordSize
+	}
+	return Quantity{
+		Value: diff / interval.Seconds(),
+		Unit:  newUnit(Base, m, Time),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package ccunits
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInfinibandCounters(t *testing.T) {
+	q, err := NewQuantity(1000, "IBW/s").ConvertTo(NewUnit("KB/s"))
+	if err != nil || q.Value != 4 {
+		t.Errorf("Expected 4 KB/s for 1000 IBW/s but got %s: %v", q.String(), err)
+	}
+	if v, u, err := ConvertInfinibandCounter("port_xmit_data", 1<<61); err != nil || v != 1<<63 || u.Short() != "B" {
+		t.Errorf("Expected %d B but got %d %s: %v", uint64(1<<63), v, u.Short(), err)
+	}
+	if v, u, err := ConvertInfinibandCounter("PortRcvPkts", 10); err != nil || v != 10 || u.GetMeasure() != Packets {
+		t.Errorf("Expected 10 packets but got %d %s: %v", v, u.Short(), err)
+	}
+	q, err = GetInfinibandCounterRate("PortXmitData", 1000, 6000, 10*time.Second)
+	if err != nil || q.Value != 2000 || q.Unit.Short() != "B/s" {
+		t.Errorf("Expected 2000 B/s but got %s: %v", q.String(), err)
+	}
+	if q, err := GetInfinibandCounterRate("PortXmitData", 6000, 1000, time.Second); err == nil || q.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for decreasing counter but got '%s'", q.String())
+	}
+	if _, u, err := ConvertInfinibandCounter("port_unknown", 1); err == nil || u.Valid() {
+		t.Errorf("Expected error and invalid unit for unknown counter but got '%s'", u.Short())
+	}
+	for _, s := range []string{"IBW", "IBwords", "InfinibandWords", "infiniband-words", NewUnitValue("IBW").String()} {
+		if u := NewUnit(s); u.GetMeasure() != InfinibandWords {
+			t.Errorf("Expected InfinibandWords for '%s' but got '%s'", s, u.Short())
+		}
+	}
+}
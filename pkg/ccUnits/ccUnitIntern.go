@@ -0,0 +1,92 @@
+package ccunits
+
+// Maximal number of interned unit strings. Collectors use a few dozen unit strings, the
+// limit protects against unbounded growth if arbitrary strings are parsed.
+const maxInternedUnits = 4096
+
+// internedUnits caches the parsed values of unit strings
+var internedUnits = newShardedCache[string, UnitValue](maxInternedUnits, hashString)
+
+// InternUnit returns the canonical value of a unit string. The string is parsed only once,
+// repeated calls return the cached value. Unit values are immutable, so the cached value
+// can be shared without copying.
+func InternUnit(unitStr string) UnitValue {
+	v, ok := internedUnits.Load(unitStr)
+	if !ok {
+		// Do not cache results parsed with a registry changed in the meantime
+		generation := registryGeneration.Load()
+		v = parseUnitValue(unitStr)
+		internedUnits.Store(unitStr, v, func() bool {
+			return generation == registryGeneration.Load()
+		})
+	}
+	if !v.Valid() {
+		reportInvalidUnit(unitStr)
+	} else {
+		reportDeprecatedUnit(unitStr)
+	}
+	return v
+}
+
+// resetInternedUnits drops all interned units. It is called when measures or prefixes are
+// registered because the parse results may change.
+func resetInternedUnits() {
+	internedUnits.Reset()
+	formattedUnits.Reset()
+}
+
+// unitStrings are the long and short strings of a unit
+type unitStrings struct {
+	long  string
+	short string
+}
+
+// formattedUnits caches the strings of unit values, so String() and Short() do not
+// allocate for every sample
+var formattedUnits = newShardedCache[UnitValue, unitStrings](maxInternedUnits, hashUnitValue)
+
+// formatted returns the cached strings of a unit value
+func (u UnitValue) formatted() unitStrings {
+	if s, ok := formattedUnits.Load(u); ok {
+		return s
+	}
+	s := u.format()
+	s.long = shareString(s.long)
+	s.short = shareString(s.short)
+	formattedUnits.Store(u, s, nil)
+	return s
+}
+
+// prefixConversions caches the conversion functions between prefixes
+var prefixConversions = newShardedCache[[2]Prefix, func(value interface{}) interface{}](maxInternedUnits, hashPrefixPair)
+
+// sharedStrings holds the shared copies of the formatted unit strings. It is not dropped by
+// registrations, so the strings of a unit stay the same for the lifetime of the program.
+var sharedStrings = newShardedCache[string, string](maxInternedUnits, hashString)
+
+// shareString returns the shared copy of a string. If the cache is full, the string itself
+// is returned.
+func shareString(s string) string {
+	if v, ok := sharedStrings.Load(s); ok {
+		return v
+	}
+	sharedStrings.Store(s, s, nil)
+	if v, ok := sharedStrings.Load(s); ok {
+		return v
+	}
+	return s
+}
+
+// Canonical returns the canonical short notation of the unit like Short(). The returned
+// strings are interned: all equal units return the same string (same backing memory), so
+// exporters can use them as label values and map keys without copying. Strings are shared
+// for up to 4096 distinct units.
+func (u UnitValue) Canonical() string {
+	return u.formatted().short
+}
+
+// CanonicalUnit parses a unit string and returns its interned canonical short notation like
+// 'MB/s' for 'MByte/s'. See UnitValue.Canonical().
+func CanonicalUnit(unitStr string) string {
+	return InternUnit(unitStr).Canonical()
+}
@@ -0,0 +1,75 @@
+package ccunits
+
+import "strings"
+
+// IpmiSensorUnitCodes maps the IPMI SDR sensor base unit codes (see IPMI v2.0 specification,
+// table 43-15 'Sensor Unit Type Codes') to units. Codes without a matching measure are
+// not contained.
+var IpmiSensorUnitCodes map[uint8]UnitValue = map[uint8]UnitValue{
+	1:  {Base, TemperatureC, InvalidMeasure},
+	2:  {Base, TemperatureF, InvalidMeasure},
+	4:  {Base, Volt, InvalidMeasure},
+	5:  {Base, Ampere, InvalidMeasure},
+	6:  {Base, Watt, InvalidMeasure},
+	7:  {Base, Joule, InvalidMeasure},
+	18: {Base, Rotation, InvalidMeasure},
+	19: {Base, Frequency, InvalidMeasure},
+	20: {Micro, Time, InvalidMeasure},
+	21: {Milli, Time, InvalidMeasure},
+	22: {Base, Time, InvalidMeasure},
+	23: {Base, Minutes, InvalidMeasure},
+	24: {Base, Hours, InvalidMeasure},
+	25: {Base, Days, InvalidMeasure},
+	42: {Base, Cycles, InvalidMeasure},
+	70: {Base, Bytes, InvalidMeasure},
+	71: {Kilo, Bytes, InvalidMeasure},
+	72: {Mega, Bytes, InvalidMeasure},
+	73: {Giga, Bytes, InvalidMeasure},
+	60: {Base, Decibel, InvalidMeasure},
+	85: {Base, Packets, InvalidMeasure},
+}
+
+// IpmiSensorUnitNames maps the unit names printed by ipmitool and ipmi-sensors (FreeIPMI)
+// to units. Both the abbreviated and the non-abbreviated names are contained. The keys are
+// lower case.
+var IpmiSensorUnitNames map[string]UnitValue = map[string]UnitValue{
+	"degrees c": {Base, TemperatureC, InvalidMeasure},
+	"c":         {Base, TemperatureC, InvalidMeasure}, // ipmi-sensors uses 'C' for Celsius, not Coulombs
+	"degrees f": {Base, TemperatureF, InvalidMeasure},
+	"f":         {Base, TemperatureF, InvalidMeasure},
+	"volts":     {Base, Volt, InvalidMeasure},
+	"v":         {Base, Volt, InvalidMeasure},
+	"amps":      {Base, Ampere, InvalidMeasure},
+	"a":         {Base, Ampere, InvalidMeasure},
+	"watts":     {Base, Watt, InvalidMeasure},
+	"w":         {Base, Watt, InvalidMeasure},
+	"joules":    {Base, Joule, InvalidMeasure},
+	"j":         {Base, Joule, InvalidMeasure},
+	"rpm":       {Base, Rotation, InvalidMeasure},
+	"hz":        {Base, Frequency, InvalidMeasure},
+	"minutes":   {Base, Minutes, InvalidMeasure},
+	"hours":     {Base, Hours, InvalidMeasure},
+	"days":      {Base, Days, InvalidMeasure},
+	"db":        {Base, Decibel, InvalidMeasure},
+	"percent":   {Base, Percentage, InvalidMeasure},
+	"%":         {Base, Percentage, InvalidMeasure},
+}
+
+// GetIpmiSensorUnit returns the unit for an IPMI SDR sensor base unit code. Each call returns
+// a new unit, so callers can change it. If the code is unknown, an invalid unit is returned.
+func GetIpmiSensorUnit(code uint8) Unit {
+	if u, ok := IpmiSensorUnitCodes[code]; ok {
+		return u.Unit()
+	}
+	return invalidUnitValue.Unit()
+}
+
+// GetIpmiSensorUnitString returns the unit for an unit name as printed by ipmitool or
+// ipmi-sensors like 'degrees C', 'Volts' or 'RPM'. If the name is unknown, it falls
+// back to NewUnit.
+func GetIpmiSensorUnitString(name string) Unit {
+	if u, ok := IpmiSensorUnitNames[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return u.Unit()
+	}
+	return NewUnit(name)
+}
@@ -0,0 +1,105 @@
+package ccunits
+
+import "testing"
+
+func TestGetIpmiSensorUnit(t *testing.T) {
+	for _, c := range []struct {
+		code     uint8
+		expected string // Empty for unknown codes
+	}{
+		{1, "degC"},
+		{2, "degF"},
+		{4, "V"},
+		{5, "A"},
+		{6, "W"},
+		{7, "J"},
+		{18, "RPM"},
+		{19, "Hz"},
+		{20, "us"},
+		{21, "ms"},
+		{22, "s"},
+		{23, "min"},
+		{24, "h"},
+		{25, "d"},
+		{42, "cyc"},
+		{60, "dB"},
+		{70, "B"},
+		{71, "KB"},
+		{72, "MB"},
+		{73, "GB"},
+		{85, "packets"},
+		{0, ""},
+		{3, ""},
+		{255, ""},
+	} {
+		u := GetIpmiSensorUnit(c.code)
+		if len(c.expected) == 0 {
+			if u.Valid() {
+				t.Errorf("Expected invalid unit for IPMI sensor unit code %d but got '%s'", c.code, u.Short())
+			}
+			continue
+		}
+		if u.Short() != c.expected {
+			t.Errorf("Expected '%s' for IPMI sensor unit code %d but got '%s'", c.expected, c.code, u.Short())
+		}
+	}
+}
+
+func TestGetIpmiSensorUnitString(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		expected string // Empty for invalid units
+	}{
+		{"degrees C", "degC"},
+		{"Degrees F", "degF"},
+		{"C", "degC"},
+		{"F", "degF"},
+		{"Volts", "V"},
+		{" V ", "V"},
+		{"Amps", "A"},
+		{"Watts", "W"},
+		{"W", "W"},
+		{"Joules", "J"},
+		{"RPM", "RPM"},
+		{"Hz", "Hz"},
+		{"minutes", "min"},
+		{"Hours", "h"},
+		{"days", "d"},
+		{"DB", "dB"},
+		{"percent", "%"},
+		{"%", "%"},
+		// Fallback to NewUnit()
+		{"MHz", "MHz"},
+		{"kW", "KW"},
+		{"discrete", ""},
+		{"", ""},
+	} {
+		u := GetIpmiSensorUnitString(c.name)
+		if (len(c.expected) == 0 && u.Valid()) || (len(c.expected) > 0 && u.Short() != c.expected) {
+			t.Errorf("Expected '%s' for IPMI sensor unit '%s' but got '%s'", c.expected, c.name, u.Short())
+		}
+	}
+}
+
+func TestIpmiSensorUnitCopies(t *testing.T) {
+	// Changing a returned unit must not change the tables
+	u := GetIpmiSensorUnit(6)
+	u.SetPrefix(Kilo)
+	v := GetIpmiSensorUnitString("Volts")
+	v.SetPrefix(Milli)
+	if w := GetIpmiSensorUnit(6); w.Short() != "W" {
+		t.Errorf("Expected 'W' for IPMI sensor unit code 6 but got '%s'", w.Short())
+	}
+	if w := GetIpmiSensorUnitString("volts"); w.Short() != "V" {
+		t.Errorf("Expected 'V' for IPMI sensor unit 'volts' but got '%s'", w.Short())
+	}
+	// Also for unknown codes
+	x := GetIpmiSensorUnit(3)
+	x.SetPrefix(Kilo)
+	if y := GetIpmiSensorUnit(3); y.Valid() {
+		t.Errorf("Expected invalid unit for IPMI sensor unit code 3 but got '%s'", y.Short())
+	}
+	if INVALID_UNIT.Short() != "Flops" {
+		t.Errorf("Changing an invalid unit changed INVALID_UNIT to '%s'", INVALID_UNIT.Short())
+	}
+}
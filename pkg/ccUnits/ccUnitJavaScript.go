@@ -0,0 +1,70 @@
+package ccunits
+
+import "fmt"
+
+// Implementations of the JavaScript bindings in ccUnitWasm.go, so the web UI parses, converts
+// and formats units exactly like the backend. They work on Go values and are built on all
+// platforms. All functions return an object with an 'error' field if they fail.
+
+// jsError returns the error object for JavaScript
+func jsError(format string, args ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"error": fmt.Sprintf(format, args...),
+	}
+}
+
+// jsQuantity returns a quantity as object for JavaScript
+func jsQuantity(q Quantity) map[string]interface{} {
+	return map[string]interface{}{
+		"value":  q.Value,
+		"unit":   q.Unit.Short(),
+		"string": q.String(),
+	}
+}
+
+// jsParseUnit implements parse(unit)
+func jsParseUnit(unitStr string) map[string]interface{} {
+	u := NewUnit(unitStr)
+	out := map[string]interface{}{
+		"valid": u.Valid(),
+	}
+	if u.Valid() {
+		p := u.GetPrefix()
+		m := u.GetMeasure()
+		out["short"] = u.Short()
+		out["long"] = u.String()
+		out["prefix"] = p.Prefix()
+		out["prefixFactor"] = float64(p)
+		out["measure"] = m.Short()
+		if d := u.GetUnitDenominator(); d != InvalidMeasure {
+			out["denominator"] = d.Short()
+		}
+	}
+	return out
+}
+
+// jsConvertValue implements convert(value, from, to)
+func jsConvertValue(value float64, from string, to string) map[string]interface{} {
+	q := NewQuantity(value, from)
+	if !q.Valid() {
+		return jsError("invalid unit '%s'", from)
+	}
+	out := NewUnit(to)
+	if !out.Valid() {
+		return jsError("invalid unit '%s'", to)
+	}
+	r, err := q.ConvertTo(out)
+	if err != nil {
+		return jsError("cannot convert '%s' to '%s': %v", q.Unit.Short(), out.Short(), err)
+	}
+	return jsQuantity(r)
+}
+
+// jsHumanizeValue implements humanize(value, unit, binary)
+func jsHumanizeValue(value float64, unitStr string, binary bool) map[string]interface{} {
+	q := NewQuantity(value, unitStr)
+	if !q.Valid() {
+		return jsError("invalid unit '%s'", unitStr)
+	}
+	return jsQuantity(q.Humanize(binary))
+}
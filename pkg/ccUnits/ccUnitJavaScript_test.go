@@ -0,0 +1,72 @@
+package ccunits
+
+import "testing"
+
+func TestJsParseUnit(t *testing.T) {
+	out := jsParseUnit("kByte/s")
+	for key, expected := range map[string]interface{}{
+		"valid":        true,
+		"short":        "KB/s",
+		"prefix":       "K",
+		"prefixFactor": 1e3,
+		"measure":      "B",
+		"denominator":  "s",
+	} {
+		if out[key] != expected {
+			t.Errorf("Expected '%v' for '%s' of 'kByte/s' but got '%v'", expected, key, out[key])
+		}
+	}
+	if _, ok := jsParseUnit("W")["denominator"]; ok {
+		t.Errorf("Unexpected denominator for 'W'")
+	}
+	if out := jsParseUnit("xyz"); out["valid"] != false || len(out) != 1 {
+		t.Errorf("Expected only 'valid: false' for 'xyz' but got %v", out)
+	}
+}
+
+func TestJsConvertValue(t *testing.T) {
+	for _, c := range []struct {
+		value    float64
+		from, to string
+		expected string // String of the result or the error message
+	}{
+		{1500, "MB/s", "GB/s", "1.5 GB/s"},
+		{45, "degC", "degF", "113 degF"},
+		{1, "xyz", "GB", "invalid unit 'xyz'"},
+		{1, "MB", "xyz", "invalid unit 'xyz'"},
+		{1, "MB", "W", "cannot convert 'MB' to 'W': invalid measures in in and out Unit: incompatible measures"},
+	} {
+		out := jsConvertValue(c.value, c.from, c.to)
+		if msg, ok := out["error"]; ok {
+			if msg != c.expected {
+				t.Errorf("Expected '%s' for %v %s to %s but got error '%v'", c.expected, c.value, c.from, c.to, msg)
+			}
+			continue
+		}
+		if out["string"] != c.expected {
+			t.Errorf("Expected '%s' for %v %s to %s but got '%v'", c.expected, c.value, c.from, c.to, out["string"])
+		}
+	}
+	if out := jsConvertValue(2048, "KiB", "MiB"); out["value"] != 2.0 || out["unit"] != "MiB" {
+		t.Errorf("Expected value 2 and unit 'MiB' but got %v", out)
+	}
+}
+
+func TestJsHumanizeValue(t *testing.T) {
+	for _, c := range []struct {
+		value    float64
+		unit     string
+		binary   bool
+		expected string
+	}{
+		{123456789, "B", false, "123.456789 MB"},
+		{1048576, "B", true, "1 MiB"},
+	} {
+		if out := jsHumanizeValue(c.value, c.unit, c.binary); out["string"] != c.expected {
+			t.Errorf("Expected '%s' for %v %s but got %v", c.expected, c.value, c.unit, out)
+		}
+	}
+	if out := jsHumanizeValue(1, "xyz", false); out["error"] != "invalid unit 'xyz'" {
+		t.Errorf("Expected error for invalid unit but got %v", out)
+	}
+}
@@ -0,0 +1,291 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The ClusterCockpit job archive stores the metric data of a job (data.json) per metric and
+// scope. Older archives store the unit as string like 'GB/s', newer ones split it into
+// base unit and prefix like {"base": "B/s", "prefix": "G"}.
+// See https://github.com/ClusterCockpit/cc-specifications/tree/master/datastructures
+
+// JobFloat is a float64 which is encoded as null in JSON if it is NaN
+type JobFloat float64
+
+// MarshalJSON writes NaN values as null
+func (f JobFloat) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(f))
+}
+
+// UnmarshalJSON reads null values as NaN
+func (f *JobFloat) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = JobFloat(math.NaN())
+		return nil
+	}
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = JobFloat(v)
+	return nil
+}
+
+// JobMetricUnit is the unit of a job metric. It reads both the string and the
+// base/prefix notation and writes the notation it was read with.
+type JobMetricUnit struct {
+	Base   string `json:"base"`
+	Prefix string `json:"prefix,omitempty"`
+	legacy bool   // unit was stored as plain string
+}
+
+// UnmarshalJSON reads the unit either as string or as object with base and prefix
+func (u *JobMetricUnit) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		u.Base = s
+		u.Prefix = ""
+		u.legacy = true
+		return nil
+	}
+	var obj struct {
+		Base   string `json:"base"`
+		Prefix string `json:"prefix"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	u.Base = obj.Base
+	u.Prefix = obj.Prefix
+	u.legacy = false
+	return nil
+}
+
+// MarshalJSON writes the unit in the notation it was read with
+func (u JobMetricUnit) MarshalJSON() ([]byte, error) {
+	if u.legacy {
+		return json.Marshal(u.Prefix + u.Base)
+	}
+	obj := struct {
+		Base   string `json:"base"`
+		Prefix string `json:"prefix,omitempty"`
+	}{
+		Base:   u.Base,
+		Prefix: u.Prefix,
+	}
+	return json.Marshal(obj)
+}
+
+// Unit returns the parsed unit
+func (u JobMetricUnit) Unit() Unit {
+	return NewUnit(u.Prefix + u.Base)
+}
+
+// setUnit stores a unit in the notation of the job metric unit
+func (u *JobMetricUnit) setUnit(out Unit) {
+	if u.legacy {
+		u.Base = out.Short()
+		u.Prefix = ""
+		return
+	}
+	base := newUnit(Base, out.GetMeasure(), out.GetUnitDenominator())
+	p := out.GetPrefix()
+	u.Base = base.Short()
+	u.Prefix = p.Prefix()
+}
+
+// JobStatistics are the statistics of a single series
+type JobStatistics struct {
+	Min JobFloat `json:"min"`
+	Avg JobFloat `json:"avg"`
+	Max JobFloat `json:"max"`
+}
+
+// JobSeries is the series of a job metric for a single host and scope ID
+type JobSeries struct {
+	Hostname   string         `json:"hostname"`
+	Id         *string        `json:"id,omitempty"`
+	Statistics *JobStatistics `json:"statistics,omitempty"`
+	Data       []JobFloat     `json:"data"`
+}
+
+// JobStatisticsSeries are the statistics over all series of a job metric
+type JobStatisticsSeries struct {
+	Mean        []JobFloat         `json:"mean"`
+	Median      []JobFloat         `json:"median"`
+	Min         []JobFloat         `json:"min"`
+	Max         []JobFloat         `json:"max"`
+	Percentiles map[int][]JobFloat `json:"percentiles,omitempty"`
+}
+
+// JobMetric is the data of a metric for a single scope
+type JobMetric struct {
+	Unit             JobMetricUnit        `json:"unit"`
+	Timestep         int                  `json:"timestep"`
+	Series           []JobSeries          `json:"series"`
+	StatisticsSeries *JobStatisticsSeries `json:"statisticsSeries,omitempty"`
+}
+
+// JobData is the metric data of a job archive (data.json). It maps the metric name and
+// the scope (node, socket, core, ...) to the job metric.
+type JobData map[string]map[string]*JobMetric
+
+// convertJobFloats applies a converter to all values
+func convertJobFloats(values []JobFloat, conv Converter) {
+	applyFloats(conv, values)
+}
+
+// convert converts all values of the job metric and stores the new unit
+func (m *JobMetric) convert(conv Converter, out Unit) {
+	for i := range m.Series {
+		s := &m.Series[i]
+		convertJobFloats(s.Data, conv)
+		if s.Statistics != nil {
+			s.Statistics.Min = JobFloat(conv.ApplyFloat64(float64(s.Statistics.Min)))
+			s.Statistics.Avg = JobFloat(conv.ApplyFloat64(float64(s.Statistics.Avg)))
+			s.Statistics.Max = JobFloat(conv.ApplyFloat64(float64(s.Statistics.Max)))
+		}
+	}
+	if m.StatisticsSeries != nil {
+		convertJobFloats(m.StatisticsSeries.Mean, conv)
+		convertJobFloats(m.StatisticsSeries.Median, conv)
+		convertJobFloats(m.StatisticsSeries.Min, conv)
+		convertJobFloats(m.StatisticsSeries.Max, conv)
+		for _, p := range m.StatisticsSeries.Percentiles {
+			convertJobFloats(p, conv)
+		}
+	}
+	m.Unit.setUnit(out)
+}
+
+// countJobFloats returns the number of values which are not NaN
+func countJobFloats(values []JobFloat) int {
+	n := 0
+	for _, v := range values {
+		if !math.IsNaN(float64(v)) {
+			n++
+		}
+	}
+	return n
+}
+
+// points returns the number of values of the job metric which are changed by a conversion
+func (m *JobMetric) points() int {
+	n := 0
+	for _, s := range m.Series {
+		n += countJobFloats(s.Data)
+		if s.Statistics != nil {
+			n += countJobFloats([]JobFloat{s.Statistics.Min, s.Statistics.Avg, s.Statistics.Max})
+		}
+	}
+	if m.StatisticsSeries != nil {
+		n += countJobFloats(m.StatisticsSeries.Mean)
+		n += countJobFloats(m.StatisticsSeries.Median)
+		n += countJobFloats(m.StatisticsSeries.Min)
+		n += countJobFloats(m.StatisticsSeries.Max)
+		for _, p := range m.StatisticsSeries.Percentiles {
+			n += countJobFloats(p)
+		}
+	}
+	return n
+}
+
+// jobConversion is the planned conversion of a job metric
+type jobConversion struct {
+	name   string
+	scope  string
+	metric *JobMetric
+	in     Unit
+	conv   Converter
+	out    Unit
+}
+
+// planNormalize checks the conversions of all metrics of the job data with an entry in
+// targetUnits (see Normalize()). They are sorted by metric name and scope.
+func planNormalize(jobData JobData, targetUnits map[string]Unit) ([]jobConversion, error) {
+	conversions := make([]jobConversion, 0)
+
+	metrics := make([]string, 0, len(jobData))
+	for name := range jobData {
+		metrics = append(metrics, name)
+	}
+	sort.Strings(metrics)
+
+	for _, name := range metrics {
+		out, ok := targetUnits[name]
+		if !ok {
+			continue
+		}
+		if out == nil || !out.Valid() {
+			return nil, fmt.Errorf("invalid target unit for metric '%s': %w", name, ErrInvalidMeasure)
+		}
+		scopes := make([]string, 0, len(jobData[name]))
+		for scope := range jobData[name] {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+		for _, scope := range scopes {
+			m := jobData[name][scope]
+			if m == nil {
+				continue
+			}
+			in := m.Unit.Unit()
+			if !in.Valid() {
+				return nil, fmt.Errorf("invalid unit '%s' for metric '%s' (scope %s): %w", m.Unit.Prefix+m.Unit.Base, name, scope, ErrInvalidMeasure)
+			}
+			conv, err := NewConverter(in, out)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert metric '%s' (scope %s) from '%s' to '%s': %w", name, scope, in.Short(), out.Short(), err)
+			}
+			conversions = append(conversions, jobConversion{name: name, scope: scope, metric: m, in: in, conv: conv, out: out})
+		}
+	}
+	return conversions, nil
+}
+
+// Normalize converts all series of the metrics in the job data with an entry in targetUnits
+// to the target unit and rewrites the stored unit. Metrics without entry are not changed.
+// All conversions are checked before any data is changed, so in case of an error the job
+// data is left unchanged.
+func Normalize(jobData JobData, targetUnits map[string]Unit) error {
+	return NormalizeAudited(jobData, targetUnits, nil)
+}
+
+// NormalizeAudited normalizes the job data like Normalize() and records every applied
+// conversion with the number of converted values in the audit log (nil for none). Nothing is
+// recorded in case of an error.
+func NormalizeAudited(jobData JobData, targetUnits map[string]Unit, log *AuditLog) error {
+	conversions, err := planNormalize(jobData, targetUnits)
+	if err != nil {
+		return err
+	}
+	for _, c := range conversions {
+		if log != nil {
+			log.Record(c.name, c.scope, c.in, c.out, c.conv, c.metric.points())
+		}
+		c.metric.convert(c.conv, c.out)
+	}
+	return nil
+}
+
+// PlanNormalize is the dry-run of Normalize(): it returns the conversions which would be
+// applied to the job data without changing it, sorted by metric name and scope. Metrics
+// already stored in the target unit are contained with factor 1. It returns the same errors
+// as Normalize().
+func PlanNormalize(jobData JobData, targetUnits map[string]Unit) ([]AuditEntry, error) {
+	conversions, err := planNormalize(jobData, targetUnits)
+	if err != nil {
+		return nil, err
+	}
+	log := NewAuditLog()
+	for _, c := range conversions {
+		log.Record(c.name, c.scope, c.in, c.out, c.conv, c.metric.points())
+	}
+	return log.Entries(), nil
+}
@@ -0,0 +1,89 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestNormalizeJobData(t *testing.T) {
+	input := `{
+		"mem_bw": {
+			"node": {
+				"unit": "MB/s",
+				"timestep": 60,
+				"series": [{"hostname": "n1", "statistics": {"min": 1000, "avg": 1500, "max": 2000}, "data": [1000, null, 2000]}]
+			}
+		},
+		"flops_any": {
+			"socket": {
+				"unit": {"base": "Flops/s", "prefix": "G"},
+				"timestep": 60,
+				"series": [{"hostname": "n1", "id": "0", "data": [1, 2]}]
+			}
+		},
+		"cpu_load": {
+			"node": {
+				"unit": {"base": ""},
+				"timestep": 60,
+				"series": [{"hostname": "n1", "data": [1, 2]}]
+			}
+		}
+	}`
+	var jobData JobData
+	if err := json.Unmarshal([]byte(input), &jobData); err != nil {
+		t.Fatalf("Failed to decode job data: %v", err)
+	}
+
+	err := Normalize(jobData, map[string]Unit{
+		"mem_bw":    NewUnit("GB/s"),
+		"flops_any": NewUnit("MFlops/s"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	memBw := jobData["mem_bw"]["node"]
+	if memBw.Series[0].Data[0] != 1 || !math.IsNaN(float64(memBw.Series[0].Data[1])) || memBw.Series[0].Statistics.Max != 2 {
+		t.Errorf("Wrong converted values for mem_bw: %v", memBw.Series[0])
+	}
+	flops := jobData["flops_any"]["socket"]
+	if flops.Series[0].Data[1] != 2000 {
+		t.Errorf("Wrong converted values for flops_any: %v", flops.Series[0].Data)
+	}
+
+	out, err := json.Marshal(jobData)
+	if err != nil {
+		t.Fatalf("Failed to encode job data: %v", err)
+	}
+	var check map[string]map[string]struct {
+		Unit   json.RawMessage `json:"unit"`
+		Series []struct {
+			Data []interface{} `json:"data"`
+		} `json:"series"`
+	}
+	if err := json.Unmarshal(out, &check); err != nil {
+		t.Fatalf("Failed to decode encoded job data: %v", err)
+	}
+	if u := string(check["mem_bw"]["node"].Unit); u != `"GB/s"` {
+		t.Errorf("Expected legacy unit string '\"GB/s\"' but got '%s'", u)
+	}
+	if u := string(check["flops_any"]["socket"].Unit); u != `{"base":"Flops/s","prefix":"M"}` {
+		t.Errorf("Expected unit object with base and prefix but got '%s'", u)
+	}
+	if check["mem_bw"]["node"].Series[0].Data[1] != nil {
+		t.Errorf("Expected NaN to be encoded as null")
+	}
+
+	// Incompatible units must not change anything
+	err = Normalize(jobData, map[string]Unit{
+		"mem_bw":    NewUnit("MB/s"),
+		"flops_any": NewUnit("W"),
+	})
+	if err == nil {
+		t.Errorf("Expected error for incompatible target unit")
+	}
+	if jobData["mem_bw"]["node"].Series[0].Data[0] != 1 {
+		t.Errorf("Job data changed although normalization failed")
+	}
+}
@@ -0,0 +1,211 @@
+package ccunits
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonTarget is the target unit of the values addressed by a JSON path
+type jsonTarget struct {
+	path     string
+	segments []string
+	out      Unit
+}
+
+// JSONTransformer normalizes third-party JSON telemetry without per-source code: it walks
+// nested JSON documents, converts the numeric values addressed by JSON paths to their target
+// units and rewrites the accompanying unit fields. It is not changed after creation and safe
+// for concurrent use.
+type JSONTransformer struct {
+	targets   []jsonTarget
+	unitField string
+}
+
+// NewJSONTransformer creates a transformer out of JSON paths and their target units like
+// {"nodes.*.power.value": "W"}. A path consists of object keys and array indexes separated by
+// dots, '*' matches all keys of an object or all elements of an array. The unit of a value
+// is read from the unitField (default 'unit') of the object containing the value. It returns
+// an error for invalid target units and empty paths.
+func NewJSONTransformer(targets map[string]string, unitField string) (*JSONTransformer, error) {
+	if len(unitField) == 0 {
+		unitField = "unit"
+	}
+	t := &JSONTransformer{
+		targets:   make([]jsonTarget, 0, len(targets)),
+		unitField: unitField,
+	}
+	for path, unitStr := range targets {
+		if len(path) == 0 {
+			return nil, fmt.Errorf("empty JSON path for target unit '%s'", unitStr)
+		}
+		out := NewUnit(unitStr)
+		if !out.Valid() {
+			return nil, fmt.Errorf("invalid target unit '%s' for JSON path '%s': %w", unitStr, path, ErrInvalidMeasure)
+		}
+		t.targets = append(t.targets, jsonTarget{path: path, segments: strings.Split(path, "."), out: out})
+	}
+	sort.Slice(t.targets, func(i, j int) bool { return t.targets[i].path < t.targets[j].path })
+	return t, nil
+}
+
+// jsonValueKey identifies a value in a document by its container and key or index
+type jsonValueKey struct {
+	container uintptr
+	key       string
+	index     int
+}
+
+// jsonValue is a numeric value found at a JSON path
+type jsonValue struct {
+	key   jsonValueKey
+	value float64
+	owner map[string]interface{} // Object containing the unit field
+	set   func(v interface{})
+}
+
+// jsonNumber returns the value of a decoded JSON number
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// collectJSONValues appends the numeric values at the path below a node. Arrays of numbers at
+// the end of the path are collected element-wise.
+func collectJSONValues(node interface{}, path []string, owner map[string]interface{}, key jsonValueKey, set func(v interface{}), values []jsonValue) []jsonValue {
+	if len(path) == 0 {
+		if f, ok := jsonNumber(node); ok {
+			return append(values, jsonValue{key: key, value: f, owner: owner, set: set})
+		}
+		if a, ok := node.([]interface{}); ok {
+			for i, v := range a {
+				if f, ok := jsonNumber(v); ok {
+					i := i
+					values = append(values, jsonValue{
+						key:   jsonValueKey{container: reflect.ValueOf(a).Pointer(), index: i},
+						value: f,
+						owner: owner,
+						set:   func(v interface{}) { a[i] = v },
+					})
+				}
+			}
+		}
+		return values
+	}
+	seg, rest := path[0], path[1:]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		keys := []string{seg}
+		if seg == "*" {
+			keys = make([]string, 0, len(n))
+			for k := range n {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+		}
+		for _, k := range keys {
+			child, ok := n[k]
+			if !ok {
+				continue
+			}
+			k := k
+			values = collectJSONValues(child, rest, n,
+				jsonValueKey{container: reflect.ValueOf(n).Pointer(), key: k, index: -1},
+				func(v interface{}) { n[k] = v }, values)
+		}
+	case []interface{}:
+		first, last := 0, len(n)-1
+		if seg != "*" {
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(n) {
+				return values
+			}
+			first, last = i, i
+		}
+		for i := first; i <= last; i++ {
+			i := i
+			values = collectJSONValues(n[i], rest, owner,
+				jsonValueKey{container: reflect.ValueOf(n).Pointer(), index: i},
+				func(v interface{}) { n[i] = v }, values)
+		}
+	}
+	return values
+}
+
+// jsonConversion is the planned conversion of a value
+type jsonConversion struct {
+	value jsonValue
+	conv  Converter
+	out   Unit
+}
+
+// Transform converts the values of a decoded JSON document (like from json.Unmarshal() into
+// an interface{}) in place and rewrites their unit fields. It returns the number of converted
+// values. Values without unit field and non-numeric values at the paths are not changed. All
+// conversions are checked before the document is changed, so it is left unchanged if a unit
+// is invalid or cannot be converted, a value matches several paths or values sharing a unit
+// field have different target units.
+func (t *JSONTransformer) Transform(doc interface{}) (int, error) {
+	conversions := make([]jsonConversion, 0)
+	seen := make(map[jsonValueKey]string)
+	unitFields := make(map[uintptr]Unit)
+	for _, target := range t.targets {
+		for _, v := range collectJSONValues(doc, target.segments, nil, jsonValueKey{index: -1}, nil, nil) {
+			if v.owner == nil {
+				continue
+			}
+			unitStr, ok := v.owner[t.unitField].(string)
+			if !ok {
+				continue
+			}
+			if path, ok := seen[v.key]; ok {
+				return 0, fmt.Errorf("value matched by JSON paths '%s' and '%s'", path, target.path)
+			}
+			seen[v.key] = target.path
+			in := NewUnit(unitStr)
+			if !in.Valid() {
+				return 0, fmt.Errorf("invalid unit '%s' at JSON path '%s': %w", unitStr, target.path, ErrInvalidMeasure)
+			}
+			conv, err := NewConverter(in, target.out)
+			if err != nil {
+				return 0, fmt.Errorf("cannot convert value at JSON path '%s' from '%s' to '%s': %w", target.path, in.Short(), target.out.Short(), err)
+			}
+			owner := reflect.ValueOf(v.owner).Pointer()
+			if out, ok := unitFields[owner]; ok && !sameUnit(out, target.out) {
+				return 0, fmt.Errorf("values sharing the unit field at JSON path '%s' have the target units '%s' and '%s'", target.path, out.Short(), target.out.Short())
+			}
+			unitFields[owner] = target.out
+			conversions = append(conversions, jsonConversion{value: v, conv: conv, out: target.out})
+		}
+	}
+	for _, c := range conversions {
+		c.value.set(c.conv.ApplyFloat64(c.value.value))
+		c.value.owner[t.unitField] = c.out.Short()
+	}
+	return len(conversions), nil
+}
+
+// TransformBytes decodes a JSON document, transforms it like Transform() and encodes it
+// again. Numbers which are not converted keep their representation, object keys are sorted.
+func (t *JSONTransformer) TransformBytes(data []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var doc interface{}
+	if err := d.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON document: %w", err)
+	}
+	if _, err := t.Transform(doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
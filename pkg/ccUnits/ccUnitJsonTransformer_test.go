@@ -0,0 +1,52 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSONTransformer(t *testing.T) {
+	tr, err := NewJSONTransformer(map[string]string{
+		"nodes.*.power.value": "W",
+		"nodes.*.mem.data":    "GiB",
+		"jobs.0.runtime":      "h",
+	}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	input := `{"nodes":{"n1":{"power":{"value":1500,"unit":"mW"},"mem":{"data":[1024,2048,null],"unit":"MiB"},"id":12345678901234567890},` +
+		`"n2":{"power":{"value":2,"unit":"KW","label":"x"}}},"jobs":[{"runtime":5400,"unit":"s"},{"runtime":60,"unit":"s"}]}`
+	out, err := tr.TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `{"jobs":[{"runtime":1.5,"unit":"h"},{"runtime":60,"unit":"s"}],"nodes":{"n1":{"id":12345678901234567890,"mem":{"data":[1,2,null],"unit":"GiB"},"power":{"unit":"W","value":1.5}},` +
+		`"n2":{"power":{"label":"x","unit":"W","value":2000}}}}`
+	if string(out) != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, out)
+	}
+
+	// Errors must not change the document
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"nodes":{"n1":{"power":{"value":1500,"unit":"mW"}},"n2":{"power":{"value":1,"unit":"GB"}}}}`), &doc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tr.Transform(doc); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+	if v := doc.(map[string]interface{})["nodes"].(map[string]interface{})["n1"].(map[string]interface{})["power"].(map[string]interface{})["value"]; v != 1500.0 {
+		t.Errorf("Document changed although the transformation failed: %v", v)
+	}
+
+	tr, err = NewJSONTransformer(map[string]string{"min": "W", "max": "KW"}, "units")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := tr.TransformBytes([]byte(`{"min":1,"max":2,"units":"W"}`)); err == nil {
+		t.Errorf("Expected error for different target units of a unit field")
+	}
+	if _, err := NewJSONTransformer(map[string]string{"value": "xyz"}, ""); !errors.Is(err, ErrInvalidMeasure) {
+		t.Errorf("Expected ErrInvalidMeasure but got %v", err)
+	}
+}
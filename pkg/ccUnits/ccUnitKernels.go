@@ -0,0 +1,111 @@
+package ccunits
+
+// Kernels for converting large buffers of values. The loops work on chunks of 8 values with
+// constant indices, so the bounds checks are done once per chunk and the independent
+// multiplications can be pipelined (or vectorized by the compiler). The remaining values
+// are converted one by one.
+
+// Number of values converted per iteration of the kernels
+const kernelChunk = 8
+
+// scaleFloats multiplies all values with a factor
+func scaleFloats[F ~float64](values []F, factor F) {
+	i := 0
+	for ; i+kernelChunk <= len(values); i += kernelChunk {
+		v := values[i : i+kernelChunk : i+kernelChunk]
+		v[0] *= factor
+		v[1] *= factor
+		v[2] *= factor
+		v[3] *= factor
+		v[4] *= factor
+		v[5] *= factor
+		v[6] *= factor
+		v[7] *= factor
+	}
+	for ; i < len(values); i++ {
+		values[i] *= factor
+	}
+}
+
+// multiplyInts multiplies all values with an integer factor
+func multiplyInts[I ~int64 | ~uint64](values []I, factor I) {
+	i := 0
+	for ; i+kernelChunk <= len(values); i += kernelChunk {
+		v := values[i : i+kernelChunk : i+kernelChunk]
+		v[0] *= factor
+		v[1] *= factor
+		v[2] *= factor
+		v[3] *= factor
+		v[4] *= factor
+		v[5] *= factor
+		v[6] *= factor
+		v[7] *= factor
+	}
+	for ; i < len(values); i++ {
+		values[i] *= factor
+	}
+}
+
+// divideInts divides all values by an integer divisor
+func divideInts[I ~int64 | ~uint64](values []I, divisor I) {
+	i := 0
+	for ; i+kernelChunk <= len(values); i += kernelChunk {
+		v := values[i : i+kernelChunk : i+kernelChunk]
+		v[0] /= divisor
+		v[1] /= divisor
+		v[2] /= divisor
+		v[3] /= divisor
+		v[4] /= divisor
+		v[5] /= divisor
+		v[6] /= divisor
+		v[7] /= divisor
+	}
+	for ; i < len(values); i++ {
+		values[i] /= divisor
+	}
+}
+
+// applyFloats converts all floating point values in place
+func applyFloats[F ~float64](c Converter, values []F) {
+	if c.kind == converterFactor {
+		scaleFloats(values, F(c.pf.factor))
+		return
+	}
+	for i, v := range values {
+		values[i] = F(c.applyFormula(float64(v)))
+	}
+}
+
+// ApplyFloat64s converts all values in place. It is the bulk version of ApplyFloat64() for
+// large buffers like the series of a job archive.
+func (c Converter) ApplyFloat64s(values []float64) {
+	applyFloats(c, values)
+}
+
+// ApplyInt64s converts all values in place. It is the bulk version of ApplyInt64().
+func (c Converter) ApplyInt64s(values []int64) {
+	switch {
+	case c.kind == converterFactor && c.pf.mul > 0:
+		multiplyInts(values, c.pf.mul)
+	case c.kind == converterFactor && c.pf.div > 0:
+		divideInts(values, c.pf.div)
+	default:
+		for i, v := range values {
+			values[i] = c.ApplyInt64(v)
+		}
+	}
+}
+
+// ApplyUint64s converts all values in place. It is the bulk version of ApplyUint64().
+func (c Converter) ApplyUint64s(values []uint64) {
+	switch {
+	case c.kind == converterFactor && c.pf.mul > 0:
+		multiplyInts(values, uint64(c.pf.mul))
+	case c.kind == converterFactor && c.pf.div > 0:
+		divideInts(values, uint64(c.pf.div))
+	default:
+		for i, v := range values {
+			values[i] = c.ApplyUint64(v)
+		}
+	}
+}
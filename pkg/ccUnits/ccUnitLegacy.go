@@ -0,0 +1,75 @@
+package ccunits
+
+import "fmt"
+
+// builtinLegacySpellings are the unit spellings of older collector versions with their
+// canonical unit. Spellings which cannot be expressed as unit like '4K_Pages' are missing.
+var builtinLegacySpellings = map[string]string{
+	"sec":        "s",
+	"seconds":    "s",
+	"bytes":      "B",
+	"Bytes":      "B",
+	"MByte":      "MB",
+	"GBytes":     "GB",
+	"bytes/sec":  "B/s",
+	"Mbyte/s":    "MB/s",
+	"watts":      "W",
+	"Watt":       "W",
+	"percent":    "%",
+	"Percent":    "%",
+	"Celsius":    "degC",
+	"Fahrenheit": "degF",
+}
+
+// legacySpellings are the built-in and registered legacy spellings. They are guarded by
+// the registryLock.
+var legacySpellings = copyLegacySpellings(builtinLegacySpellings)
+
+// copyLegacySpellings returns a copy of a table of legacy spellings
+func copyLegacySpellings(spellings map[string]string) map[string]string {
+	out := make(map[string]string, len(spellings))
+	for s, c := range spellings {
+		out[s] = c
+	}
+	return out
+}
+
+// legacySpelling returns the canonical unit string of a legacy spelling
+func legacySpelling(unitStr string) (string, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	c, ok := legacySpellings[unitStr]
+	return c, ok
+}
+
+// RegisterLegacySpelling adds a historical spelling of a unit like 'MBytes/sec' for 'MB/s',
+// so it is accepted by the parser and reported as deprecated by the hook set with
+// SetDeprecatedUnitHook() and by Lint(). It returns an error if the unit string is invalid
+// or itself a legacy spelling.
+func RegisterLegacySpelling(spelling string, unitStr string) error {
+	if len(spelling) == 0 {
+		return fmt.Errorf("empty legacy spelling for unit '%s': %w", unitStr, ErrInvalidMeasure)
+	}
+	u := parseUnitValue(unitStr)
+	if !u.Valid() {
+		return fmt.Errorf("invalid unit for legacy spelling '%s': %w", spelling, invalidUnitError(unitStr))
+	}
+	canonical := u.Short()
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	// Chains of legacy spellings could form cycles
+	if _, ok := legacySpellings[canonical]; ok || canonical == spelling {
+		return fmt.Errorf("unit '%s' for legacy spelling '%s' is a legacy spelling itself: %w", canonical, spelling, ErrInvalidMeasure)
+	}
+	legacySpellings[spelling] = canonical
+	registryChanged()
+	return nil
+}
+
+// LegacySpellings returns all legacy spellings with their canonical unit strings like
+// 'bytes/sec' -> 'B/s'
+func LegacySpellings() map[string]string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return copyLegacySpellings(legacySpellings)
+}
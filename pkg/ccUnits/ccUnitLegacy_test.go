@@ -0,0 +1,44 @@
+package ccunits
+
+import "testing"
+
+func TestLegacySpellings(t *testing.T) {
+	restoreRegistry(t)
+	for spelling, canonical := range LegacySpellings() {
+		if u := NewUnit(spelling); u.Short() != canonical {
+			t.Errorf("Expected '%s' for legacy spelling '%s' but got '%s'", canonical, spelling, u.Short())
+		}
+		if u, err := ParseUnit(spelling, WithStrictSI()); err != nil || u.Short() != canonical {
+			t.Errorf("Expected '%s' for legacy spelling '%s' in strict mode but got '%s': %v", canonical, spelling, u.Short(), err)
+		}
+	}
+
+	reported := make(map[string]string)
+	SetDeprecatedUnitHook(func(spelling string, canonical string) { reported[spelling] = canonical })
+	defer SetDeprecatedUnitHook(nil)
+	NewUnit("Celsius")
+	NewUnit("degC")
+	if _, err := ParseUnit("bytes/sec", WithLocale("de")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(reported) != 2 || reported["Celsius"] != "degC" || reported["bytes/sec"] != "B/s" {
+		t.Errorf("Expected deprecation notices for 'Celsius' and 'bytes/sec' but got %v", reported)
+	}
+
+	if err := RegisterLegacySpelling("MBytes/sec", "MByte/s"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u := NewUnit("MBytes/sec"); u.Short() != "MB/s" || reported["MBytes/sec"] != "MB/s" {
+		t.Errorf("Expected registered legacy spelling to be parsed as 'MB/s' but got '%s'", u.Short())
+	}
+	for _, c := range [][2]string{{"", "B"}, {"xbytes", "xyz"}, {"s", "sec"}} {
+		if err := RegisterLegacySpelling(c[0], c[1]); err == nil {
+			t.Errorf("Expected error for legacy spelling '%s' of '%s'", c[0], c[1])
+		}
+	}
+
+	issues := Lint([]string{"B/s", "bytes/sec"})
+	if len(issues) != 1 || issues[0].Kind != IssueDeprecated || issues[0].Suggestion != "B/s" {
+		t.Errorf("Expected deprecated issue for 'bytes/sec' but got %v", issues)
+	}
+}
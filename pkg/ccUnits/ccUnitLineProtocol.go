@@ -0,0 +1,277 @@
+package ccunits
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Streaming filter for the InfluxDB line protocol and the cc metric message format (line
+// protocol with the unit as tag). See
+// https://docs.influxdata.com/influxdb/v2/reference/syntax/line-protocol/
+
+// lpEscaper escapes tag values
+var lpEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `)
+
+// LineProtocolFilter rewrites the value field and the unit tag of metrics in line protocol
+// to the target unit of the normalizer like 'mem_used,unit=MiB value=2048' to
+// 'mem_used,unit=GiB value=2'. Integer values keep their type and are only rewritten if the
+// conversion has an exact integer factor. Everything else, like other fields, comments,
+// metrics without unit or target unit and lines which cannot be parsed, is passed through
+// byte by byte.
+// The filter is not changed after creation and safe for concurrent use.
+type LineProtocolFilter struct {
+	normalizer *Normalizer
+	unitTag    []byte
+	field      []byte
+}
+
+// NewLineProtocolFilter creates a filter with the normalizer. The unit is taken from the tag
+// unitTag (default: unit) and the value from the field 'value'.
+func NewLineProtocolFilter(n *Normalizer, unitTag string) *LineProtocolFilter {
+	if len(unitTag) == 0 {
+		unitTag = "unit"
+	}
+	return &LineProtocolFilter{
+		normalizer: n,
+		unitTag:    []byte(unitTag),
+		field:      []byte("value"),
+	}
+}
+
+// lpIndexUnescaped returns the index of the first unescaped byte of chars in s or -1. Double
+// quotes are skipped if quoted is set (string field values).
+func lpIndexUnescaped(s []byte, chars string, quoted bool) int {
+	inQuotes := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quoted && s[i] == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && strings.IndexByte(chars, s[i]) >= 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// lpUnescape removes the backslashes of escaped characters
+func lpUnescape(s []byte) string {
+	if bytes.IndexByte(s, '\\') < 0 {
+		return string(s)
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// lpFind returns the start and end of the value of key in a list like 'a=1,b=2' starting at
+// offset or -1 if the key is not in the list
+func lpFind(list []byte, offset int, key []byte, quoted bool) (int, int) {
+	for pos := offset; pos < len(list); {
+		end := len(list)
+		if i := lpIndexUnescaped(list[pos:], ",", quoted); i >= 0 {
+			end = pos + i
+		}
+		if eq := lpIndexUnescaped(list[pos:end], "=", false); eq >= 0 && lpUnescape(list[pos:pos+eq]) == string(key) {
+			return pos + eq + 1, end
+		}
+		pos = end + 1
+	}
+	return -1, -1
+}
+
+// lpNumber parses a numeric field value like '1.5', '12i' or '12u'
+func lpNumber(s []byte) (float64, bool) {
+	if len(s) == 0 {
+		return 0, false
+	}
+	switch s[len(s)-1] {
+	case 'i':
+		v, err := strconv.ParseInt(string(s[:len(s)-1]), 10, 64)
+		return float64(v), err == nil
+	case 'u':
+		v, err := strconv.ParseUint(string(s[:len(s)-1]), 10, 64)
+		return float64(v), err == nil
+	}
+	v, err := strconv.ParseFloat(string(s), 64)
+	return v, err == nil
+}
+
+// AppendLine appends the rewritten line (without line ending) to dst. Lines which are not
+// rewritten are appended unchanged.
+func (f *LineProtocolFilter) AppendLine(dst []byte, line []byte) []byte {
+	keyEnd := lpIndexUnescaped(line, " ", false)
+	if len(line) == 0 || line[0] == '#' || keyEnd < 0 {
+		return append(dst, line...)
+	}
+	// The measurement is followed by the tags
+	tagStart := lpIndexUnescaped(line[:keyEnd], ",", false) + 1
+	if tagStart == 0 {
+		return append(dst, line...)
+	}
+	unitStart, unitEnd := lpFind(line[:keyEnd], tagStart, f.unitTag, false)
+	if unitStart < 0 {
+		return append(dst, line...)
+	}
+	fieldStart := keyEnd + 1
+	for fieldStart < len(line) && line[fieldStart] == ' ' {
+		fieldStart++
+	}
+	fieldEnd := len(line)
+	if i := lpIndexUnescaped(line[fieldStart:], " ", true); i >= 0 {
+		fieldEnd = fieldStart + i
+	}
+	valueStart, valueEnd := lpFind(line[:fieldEnd], fieldStart, f.field, true)
+	if valueStart < 0 {
+		return append(dst, line...)
+	}
+	value, ok := lpNumber(line[valueStart:valueEnd])
+	if !ok {
+		return append(dst, line...)
+	}
+	in := InternUnit(lpUnescape(line[unitStart:unitEnd]))
+	if !in.Valid() {
+		return append(dst, line...)
+	}
+	// Look up the target directly, so passed through metrics do not allocate
+	t, ok := f.normalizer.targets[normalizerKeyOf(in)]
+	if !ok || t == in {
+		return append(dst, line...)
+	}
+	start := len(dst)
+	dst = append(dst, line[:unitStart]...)
+	dst = append(dst, lpEscaper.Replace(t.Short())...)
+	dst = append(dst, line[unitEnd:valueStart]...)
+	dst, ok = lpAppendValue(dst, line[valueStart:valueEnd], value, in, t)
+	if !ok {
+		return append(dst[:start], line...)
+	}
+	return append(dst, line[valueEnd:]...)
+}
+
+// lpAppendValue appends the field value converted from unit in to out. Integer values like
+// '2i' or '500u' are only converted with an exact integer factor and keep their suffix,
+// because InfluxDB rejects a field written as integer and as float. It returns false if the
+// value cannot be converted.
+func lpAppendValue(dst []byte, raw []byte, value float64, in UnitValue, out UnitValue) ([]byte, bool) {
+	suffix := raw[len(raw)-1]
+	if suffix != 'i' && suffix != 'u' {
+		q, err := Quantity{Value: value, Unit: in.Unit()}.ConvertTo(out.Unit())
+		if err != nil {
+			return dst, false
+		}
+		return strconv.AppendFloat(dst, q.Value, 'g', -1, 64), true
+	}
+	conv, err := NewConverter(in.Unit(), out.Unit())
+	if err != nil {
+		return dst, false
+	}
+	var v interface{}
+	if suffix == 'i' {
+		v, _ = strconv.ParseInt(string(raw[:len(raw)-1]), 10, 64)
+	} else {
+		v, _ = strconv.ParseUint(string(raw[:len(raw)-1]), 10, 64)
+	}
+	r, ok := conv.applyExactInteger(v)
+	if !ok {
+		return dst, false
+	}
+	switch r := r.(type) {
+	case int64:
+		return append(strconv.AppendInt(dst, r, 10), 'i'), true
+	case uint64:
+		return append(strconv.AppendUint(dst, r, 10), 'u'), true
+	}
+	return dst, false
+}
+
+// appendLine appends the rewritten line including its line ending to dst
+func (f *LineProtocolFilter) appendLine(dst []byte, line []byte) []byte {
+	content := bytes.TrimRight(line, "\r\n")
+	dst = f.AppendLine(dst, content)
+	return append(dst, line[len(content):]...)
+}
+
+// LineProtocolWriter rewrites the metrics written to it with a LineProtocolFilter and writes
+// them to the underlying writer. Incomplete lines are buffered until the line ending is
+// written or Flush() is called.
+type LineProtocolWriter struct {
+	filter  *LineProtocolFilter
+	w       io.Writer
+	pending []byte
+	buf     []byte
+}
+
+// NewLineProtocolWriter creates a writer which rewrites the metrics with the filter
+func NewLineProtocolWriter(w io.Writer, f *LineProtocolFilter) *LineProtocolWriter {
+	return &LineProtocolWriter{filter: f, w: w}
+}
+
+// Write rewrites the complete lines of p and writes them to the underlying writer
+func (w *LineProtocolWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	end := bytes.LastIndexByte(w.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	w.buf = w.buf[:0]
+	for data := w.pending[:end+1]; len(data) > 0; {
+		i := bytes.IndexByte(data, '\n')
+		w.buf = w.filter.appendLine(w.buf, data[:i+1])
+		data = data[i+1:]
+	}
+	w.pending = append(w.pending[:0], w.pending[end+1:]...)
+	if _, err := w.w.Write(w.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush rewrites and writes a buffered incomplete line
+func (w *LineProtocolWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	w.buf = w.filter.appendLine(w.buf[:0], w.pending)
+	w.pending = w.pending[:0]
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// LineProtocolReader rewrites the metrics read from the underlying reader with a
+// LineProtocolFilter
+type LineProtocolReader struct {
+	filter *LineProtocolFilter
+	r      *bufio.Reader
+	buf    []byte
+	off    int
+	err    error
+}
+
+// NewLineProtocolReader creates a reader which rewrites the metrics with the filter
+func NewLineProtocolReader(r io.Reader, f *LineProtocolFilter) *LineProtocolReader {
+	return &LineProtocolReader{filter: f, r: bufio.NewReader(r)}
+}
+
+// Read reads rewritten metrics into p
+func (r *LineProtocolReader) Read(p []byte) (int, error) {
+	for r.off == len(r.buf) {
+		if r.err != nil {
+			return 0, r.err
+		}
+		line, err := r.r.ReadBytes('\n')
+		r.buf, r.off, r.err = r.filter.appendLine(r.buf[:0], line), 0, err
+	}
+	n := copy(p, r.buf[r.off:])
+	r.off += n
+	return n, nil
+}
@@ -0,0 +1,68 @@
+package ccunits
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineProtocolFilter(t *testing.T) {
+	n, err := NewNormalizer("GiB", "s", "GB/s")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f := NewLineProtocolFilter(n, "")
+	in := strings.Join([]string{
+		"mem_used,hostname=h1,unit=MiB value=2048 1700000000000000000",
+		"runtime,type=node,unit=h value=2i,count=3i 1700000000000000000",
+		"mem_bw,unit=MB/s,type=socket value=500u",
+		"power,unit=W value=300",             // No target unit
+		"mem_used,unit=GiB value=1",          // Already in target unit
+		`log,unit=MiB value="1024, text" 17`, // String value
+		"# comment,unit=MiB value=1",
+		"mem_used value=1024",
+		"invalid line",
+		"",
+		"swap,unit=MiB value=512",
+		"mem_total,unit=TiB value=3u",
+		"mem_free,unit=TiB value=-1i",
+	}, "\n")
+	expected := strings.Join([]string{
+		"mem_used,hostname=h1,unit=GiB value=2 1700000000000000000",
+		"runtime,type=node,unit=s value=7200i,count=3i 1700000000000000000",
+		"mem_bw,unit=MB/s,type=socket value=500u", // No exact integer factor
+		"power,unit=W value=300",
+		"mem_used,unit=GiB value=1",
+		`log,unit=MiB value="1024, text" 17`,
+		"# comment,unit=MiB value=1",
+		"mem_used value=1024",
+		"invalid line",
+		"",
+		"swap,unit=GiB value=0.5",
+		"mem_total,unit=GiB value=3072u",
+		"mem_free,unit=GiB value=-1024i",
+	}, "\n")
+
+	out, err := io.ReadAll(NewLineProtocolReader(strings.NewReader(in), f))
+	if err != nil || string(out) != expected {
+		t.Errorf("Expected\n%s\nbut reader returned\n%s\n%v", expected, string(out), err)
+	}
+
+	// Write in small chunks to split lines
+	var buf bytes.Buffer
+	w := NewLineProtocolWriter(&buf, f)
+	for data := []byte(in); len(data) > 0; {
+		size := 7
+		if size > len(data) {
+			size = len(data)
+		}
+		if _, err := w.Write(data[:size]); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		data = data[size:]
+	}
+	if err := w.Flush(); err != nil || buf.String() != expected {
+		t.Errorf("Expected\n%s\nbut writer returned\n%s\n%v", expected, buf.String(), err)
+	}
+}
@@ -0,0 +1,191 @@
+package ccunits
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueKind classifies a problem with a unit string
+type IssueKind int
+
+const (
+	IssueInvalid       IssueKind = iota // Unit string cannot be parsed
+	IssueAmbiguous                      // Unit string is parsed but maybe not as intended
+	IssueNonCanonical                   // Unit string is valid but not in the canonical short notation
+	IssueStackedPrefix                  // Unit string has two prefixes like 'kMB'
+	IssueDeprecated                     // Unit string is a legacy spelling of older collector versions
+)
+
+// String returns a description of the issue kind
+func (k IssueKind) String() string {
+	switch k {
+	case IssueInvalid:
+		return "invalid"
+	case IssueAmbiguous:
+		return "ambiguous"
+	case IssueNonCanonical:
+		return "non-canonical"
+	case IssueStackedPrefix:
+		return "stacked-prefix"
+	case IssueDeprecated:
+		return "deprecated"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the description of the issue kind, so it is readable in JSON output
+func (k IssueKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads the description of the issue kind
+func (k *IssueKind) UnmarshalText(text []byte) error {
+	for _, kind := range []IssueKind{IssueInvalid, IssueAmbiguous, IssueNonCanonical, IssueStackedPrefix, IssueDeprecated} {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid issue kind '%s'", string(text))
+}
+
+// Issue is a problem with a unit string found by Lint()
+type Issue struct {
+	Index      int       `json:"index"` // Position of the unit string in the checked batch
+	Input      string    `json:"input"`
+	Kind       IssueKind `json:"kind"`
+	Message    string    `json:"message"`
+	Suggestion string    `json:"suggestion,omitempty"` // Suggested replacement (optional)
+}
+
+// matchMeasures returns all measures matching a measure string and whether the matched
+// measure covers the whole string. Registered aliases are exact matches.
+func matchMeasures(s string) ([]Measure, bool) {
+	registryLock.RLock()
+	m, ok := measureAliases[s]
+	registryLock.RUnlock()
+	if ok {
+		return []Measure{m}, true
+	}
+	matches := make([]Measure, 0)
+	full := false
+	for _, matcher := range getParserTables().measures {
+		match := matcher.regex.FindString(s)
+		if len(match) > 0 {
+			matches = append(matches, matcher.measure)
+			full = full || len(match) == len(s)
+		}
+	}
+	return matches, full
+}
+
+// lintMeasure checks a measure string like the 'Bytes' part of 'kBytes/s'
+func lintMeasure(s string) (string, bool) {
+	matches, full := matchMeasures(s)
+	switch {
+	case len(matches) > 1:
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m.String())
+		}
+		return fmt.Sprintf("measure '%s' matches multiple measures (%s)", s, strings.Join(names, ", ")), false
+	case len(matches) == 1 && !full:
+		return fmt.Sprintf("only a part of '%s' is recognized as measure %s", s, matches[0].String()), false
+	}
+	return "", true
+}
+
+// stackedSuggestion returns the unit with the combined prefix for a unit string with stacked
+// prefixes like 'GB' for 'kMB' or an empty string if no prefix has the combined factor or
+// the result cannot be parsed
+func stackedSuggestion(unitStr string, first string, second string) string {
+	p, ok := combinedPrefix(NewPrefix(first), NewPrefix(second))
+	if !ok {
+		return ""
+	}
+	v := NewUnitValue(unitStr[len(first)+len(second):])
+	if !v.Valid() {
+		return ""
+	}
+	if short := v.WithPrefix(p).Short(); NewUnitValue(short) == v.WithPrefix(p) {
+		return short
+	}
+	return ""
+}
+
+// lintUnit checks a single unit string
+func lintUnit(unitStr string) (IssueKind, string, string, bool) {
+	if len(strings.TrimSpace(unitStr)) == 0 {
+		return IssueInvalid, "empty unit", "", false
+	}
+	if canonical, ok := legacySpelling(unitStr); ok {
+		return IssueDeprecated, fmt.Sprintf("legacy spelling '%s' of older collector versions", unitStr), canonical, false
+	}
+	u := NewUnit(unitStr)
+	if first, second, ok := stackedPrefixes(unitStr); ok && !u.Valid() {
+		return IssueStackedPrefix, fmt.Sprintf("stacked prefixes '%s' and '%s' in '%s'", first, second, unitStr), stackedSuggestion(unitStr, first, second), false
+	}
+	if prefixStr, m, ok := prefixedPrefixless(unitStr); ok && !u.Valid() {
+		return IssueInvalid, fmt.Sprintf("prefix '%s' is not allowed for %s in '%s'", prefixStr, m.String(), unitStr), "", false
+	}
+	if !u.Valid() {
+		return IssueInvalid, fmt.Sprintf("unknown unit '%s'", unitStr), "", false
+	}
+	short := u.Short()
+	if _, ok := cutInverse(unitStr); ok {
+		return IssueNonCanonical, fmt.Sprintf("inverse unit '%s' is not in the canonical notation", unitStr), short, false
+	}
+
+	// Split the unit string the same way NewUnit does to check the parts
+	pre, measureStr := splitPrefix(unitStr)
+	measures := strings.Split(measureStr, "/")
+	if NewMeasure(measures[0]) == InvalidMeasure && len(pre) > 0 {
+		measures[0] = pre + measures[0]
+		pre = ""
+	}
+	for _, m := range measures {
+		if msg, ok := lintMeasure(m); !ok {
+			return IssueAmbiguous, msg, short, false
+		}
+	}
+	if len(measures) > 2 {
+		return IssueAmbiguous, fmt.Sprintf("only the first unit denominator of '%s' is used", unitStr), short, false
+	}
+	if len(measures) > 1 && u.GetUnitDenominator() == InvalidMeasure {
+		return IssueAmbiguous, fmt.Sprintf("unknown unit denominator '%s' is ignored", measures[1]), short, false
+	}
+	if len(pre) > 0 {
+		m := u.GetMeasure()
+		switch {
+		case isNonDividable(m) && NewPrefix(pre) == Milli:
+			return IssueAmbiguous, fmt.Sprintf("prefix '%s' is read as Mega because %s cannot be divided", pre, m.String()), short, false
+		case isPrefixless(m) && !rejectsPrefix(m):
+			return IssueAmbiguous, fmt.Sprintf("prefix '%s' is ignored for %s", pre, m.String()), short, false
+		}
+	}
+	if short != unitStr {
+		return IssueNonCanonical, fmt.Sprintf("unit '%s' is not in the canonical notation", unitStr), short, false
+	}
+	return IssueInvalid, "", "", true
+}
+
+// Lint checks a batch of unit strings like the units in metric configurations. It flags
+// invalid, ambiguous and non-canonical spellings with the canonical short notation as
+// suggested fix. Only the most severe issue is reported for each unit string.
+func Lint(unitStrs []string) []Issue {
+	issues := make([]Issue, 0)
+	for i, unitStr := range unitStrs {
+		kind, msg, suggestion, ok := lintUnit(unitStr)
+		if ok {
+			continue
+		}
+		issues = append(issues, Issue{
+			Index:      i,
+			Input:      unitStr,
+			Kind:       kind,
+			Message:    msg,
+			Suggestion: suggestion,
+		})
+	}
+	return issues
+}
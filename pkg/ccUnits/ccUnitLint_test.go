@@ -0,0 +1,38 @@
+package ccunits
+
+import "testing"
+
+func TestLint(t *testing.T) {
+	input := []string{"MB/s", "MByte/s", "mB", "xyz", "", "foobar", "packets", "GFlops/s", "s^-1", "kMB/s", "mms", "kh", "kMh", "Gd/s"}
+	expected := map[int]struct {
+		kind       IssueKind
+		suggestion string
+	}{
+		1:  {IssueNonCanonical, "MB/s"},
+		2:  {IssueAmbiguous, "MB"},
+		3:  {IssueInvalid, ""},
+		4:  {IssueInvalid, ""},
+		5:  {IssueAmbiguous, "Flops"},
+		8:  {IssueNonCanonical, "Hz"},
+		9:  {IssueStackedPrefix, "GB/s"},
+		10: {IssueStackedPrefix, "us"},
+		11: {IssueInvalid, ""},
+		12: {IssueStackedPrefix, ""}, // 'Gh' is no valid unit
+		13: {IssueInvalid, ""},
+	}
+	issues := Lint(input)
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues but got %d: %v", len(expected), len(issues), issues)
+	}
+	for _, issue := range issues {
+		e, ok := expected[issue.Index]
+		if !ok {
+			t.Errorf("Unexpected issue for '%s': %s", issue.Input, issue.Message)
+			continue
+		}
+		if issue.Kind != e.kind || issue.Suggestion != e.suggestion {
+			t.Errorf("Expected %s issue with suggestion '%s' for '%s' but got %s issue with suggestion '%s'",
+				e.kind.String(), e.suggestion, issue.Input, issue.Kind.String(), issue.Suggestion)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Code generated by ccunits-gen. DO NOT EDIT.
+
+package ccunits
+
+// lookupMeasure returns the built-in measure of a known spelling
+func lookupMeasure(s string) (Measure, bool) {
+	switch s {
+	case "B", "BYTE", "BYTES", "Byte", "Bytes", "byte", "bytes":
+		return Measure(1), true // byte
+	case "FLOP", "FLOPS", "Flop", "Flops", "flop", "flops":
+		return Measure(2), true // Flops
+	case "%", "Percent", "Percents", "percent", "percents":
+		return Measure(3), true // Percent
+	case "degC":
+		return Measure(4), true // DegreeC
+	case "degF":
+		return Measure(5), true // DegreeF
+	case "RPM":
+		return Measure(6), true // RPM
+	case "HERTZ", "Hertz", "Hz", "hertz":
+		return Measure(7), true // Hertz
+	case "SECOND", "SECONDS", "Second", "Seconds", "s", "second", "seconds":
+		return Measure(8), true // Seconds
+	case "W", "WATT", "WATTS", "Watt", "Watts", "watt", "watts":
+		return Measure(9), true // Watts
+	case "J", "JOULE", "JOULES", "Joule", "Joules", "joule", "joules":
+		return Measure(10), true // Joules
+	case "CYCLE", "CYCLES", "Cycle", "Cycles", "cyc", "cycle", "cycles":
+		return Measure(11), true // Cycles
+	case "REQUEST", "REQUESTS", "Request", "Requests", "request", "requests":
+		return Measure(12), true // Requests
+	case "PACKET", "PACKETS", "Packet", "Packets", "packet", "packets":
+		return Measure(13), true // Packets
+	case "EVENT", "EVENTS", "Event", "Events", "event", "events":
+		return Measure(14), true // Events
+	case "V", "VOLT", "VOLTS", "Volt", "Volts", "volt", "volts":
+		return Measure(15), true // Volts
+	case "A", "AMPERE", "AMPERES", "Ampere", "Amperes", "ampere", "amperes":
+		return Measure(16), true // Ampere
+	case "COUNT", "COUNTS", "Count", "Counts", "count", "counts":
+		return Measure(17), true // Count
+	case "RATIO", "RATIOS", "Ratio", "Ratios", "ratio", "ratios":
+		return Measure(18), true // Ratio
+	case "Decibel", "Decibels", "dB", "decibel", "decibels":
+		return Measure(19), true // Decibel
+	case "DecibelMilliwatt", "DecibelMilliwatts", "Decibelmilliwatt", "Decibelmilliwatts", "dBm", "decibelmilliwatt", "decibelmilliwatts":
+		return Measure(20), true // DecibelMilliwatt
+	case "Minute", "Minutes", "min", "minute", "minutes":
+		return Measure(21), true // Minutes
+	case "Hour", "Hours", "h", "hour", "hours":
+		return Measure(22), true // Hours
+	case "Day", "Days", "d", "day", "days":
+		return Measure(23), true // Days
+	case "IBW", "InfinibandWord", "InfinibandWords", "Infinibandword", "Infinibandwords", "infinibandword", "infinibandwords":
+		return Measure(24), true // InfinibandWords
+	}
+	return InvalidMeasure, false
+}
+
+// lookupPrefix returns the built-in prefix of a known spelling
+func lookupPrefix(s string) (Prefix, bool) {
+	switch s {
+	case "n":
+		return Prefix(1e-09), true // Nano
+	case "u", "µ", "μ":
+		return Prefix(1e-06), true // Micro
+	case "m":
+		return Prefix(0.001), true // Milli
+	case "":
+		return Prefix(1), true // Base
+	case "K", "k":
+		return Prefix(1000), true // Kilo
+	case "Ki", "ki":
+		return Prefix(1024), true // Kibi
+	case "M":
+		return Prefix(1e+06), true // Mega
+	case "Mi":
+		return Prefix(1.048576e+06), true // Mebi
+	case "G", "g":
+		return Prefix(1e+09), true // Giga
+	case "Gi", "gi":
+		return Prefix(1.073741824e+09), true // Gibi
+	case "T", "t":
+		return Prefix(1e+12), true // Tera
+	case "Ti", "ti":
+		return Prefix(1.099511627776e+12), true // Tebi
+	case "P", "p":
+		return Prefix(1e+15), true // Peta
+	case "Pi", "pi":
+		return Prefix(1.125899906842624e+15), true // Pebi
+	case "E", "e":
+		return Prefix(1e+18), true // Exa
+	case "Ei", "ei":
+		return Prefix(1.152921504606847e+18), true // Exbi
+	case "Z", "z":
+		return Prefix(1e+21), true // Zetta
+	case "Zi", "zi":
+		return Prefix(1.1805916207174113e+21), true // Zebi
+	case "Y", "y":
+		return Prefix(1e+24), true // Yotta
+	case "Yi", "yi":
+		return Prefix(1.2089258196146292e+24), true // Yobi
+	}
+	return InvalidPrefix, false
+}
@@ -0,0 +1,53 @@
+package ccunits
+
+import (
+	"strings"
+	"testing"
+)
+
+// regexMeasure matches a measure string with the regular expressions only
+func regexMeasure(s string) Measure {
+	for _, matcher := range getParserTables().measures {
+		if matcher.regex.MatchString(s) {
+			return matcher.measure
+		}
+	}
+	return InvalidMeasure
+}
+
+// regexPrefix matches a prefix string with the regular expressions only
+func regexPrefix(s string) Prefix {
+	for _, matcher := range getParserTables().prefixes {
+		if matcher.regex.MatchString(s) {
+			return matcher.prefix
+		}
+	}
+	return InvalidPrefix
+}
+
+// TestLookupMatchesRegex checks that the generated lookup returns the same results as the
+// regular expressions
+func TestLookupMatchesRegex(t *testing.T) {
+	for _, data := range MeasuresMap {
+		for _, name := range []string{data.Short, data.Long} {
+			for _, s := range []string{name, name + "s", strings.ToLower(name), strings.ToUpper(name), "x" + name} {
+				if m, ok := lookupMeasure(s); ok && m != regexMeasure(s) {
+					t.Errorf("Lookup of measure '%s' returns %d instead of %d", s, m, regexMeasure(s))
+				}
+			}
+		}
+	}
+	for _, s := range strings.Split(" k K m M g G t T p P e E z Z y Y u n ki Ki Mi mi Gi gi i Kilo", " ") {
+		if p, ok := lookupPrefix(s); ok && p != regexPrefix(s) {
+			t.Errorf("Lookup of prefix '%s' returns %v instead of %v", s, p, regexPrefix(s))
+		}
+	}
+}
+
+func BenchmarkParseUnitValue(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		parseUnitValue("MB/s")
+		parseUnitValue("kevents")
+		parseUnitValue("GiByte")
+	}
+}
@@ -0,0 +1,123 @@
+package ccunits
+
+// Measure is a measure like Bytes or Seconds. The built-in measures with their stable IDs are
+// generated out of ccUnitBuiltin.yaml, see ccUnitBuiltin.go.
+type Measure int
+
+type MeasureData struct {
+	Long         string
+	Short        string
+	Regex        string
+	NonDividable bool // Measure cannot be divided into fractions, so Milli, Micro and Nano are not used
+}
+
+// Different names and regex used for input and output
+var InvalidMeasureLong string = "Invalid"
+var InvalidMeasureShort string = "inval"
+
+// String returns the long string for the measure like 'Percent' or 'Seconds'
+func (m *Measure) String() string {
+	if data, ok := builtinMeasure(*m); ok {
+		return data.Long
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if data, ok := MeasuresMap[*m]; ok {
+		return data.Long
+	}
+	return InvalidMeasureLong
+}
+
+// Short returns the short string for the measure like 'B' (Bytes), 's' (Time) or 'W' (Watt). Is is recommened to use Short() over String().
+func (m *Measure) Short() string {
+	if data, ok := builtinMeasure(*m); ok {
+		return data.Short
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if data, ok := MeasuresMap[*m]; ok {
+		return data.Short
+	}
+	return InvalidMeasureShort
+}
+
+// BaseUnit returns the canonical unit of the measure without prefix and unit denominator
+// like 'B' for Bytes, 'Hz' for Frequency or 'W' for Watt. Unknown measures result in the
+// invalid unit.
+func (m Measure) BaseUnit() Unit {
+	return NewUnitFromParts(Base, m, InvalidMeasure)
+}
+
+// isNonDividable checks whether a measure cannot be divided into fractions like Bytes or
+// Flops. These measures are not used with the prefixes Milli, Micro and Nano.
+func isNonDividable(m Measure) bool {
+	if data, ok := builtinMeasure(m); ok {
+		return data.NonDividable
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if data, ok := MeasuresMap[m]; ok {
+		return data.NonDividable
+	}
+	return false
+}
+
+// isTimeMeasure checks whether a measure is a duration like seconds or the time scales
+// minutes, hours and days
+func isTimeMeasure(m Measure) bool {
+	return m == Time || m == Minutes || m == Hours || m == Days
+}
+
+// isPrefixless checks whether a measure is used without prefix like percentages, ratios,
+// logarithmic measures like dB and the time scales minutes, hours and days. Prefixes of
+// percentages are ignored, for the other measures they are rejected (see rejectsPrefix).
+func isPrefixless(m Measure) bool {
+	return m == Percentage || m == Ratio || m == Decibel || m == DecibelMilliwatt || m == Minutes || m == Hours || m == Days
+}
+
+// rejectsPrefix checks whether a unit string with a prefix in front of a prefixless measure
+// is invalid. A prefix like in 'kratio', 'kdB' or 'kh' would silently scale the value, only
+// the prefixes of percentages are ignored like in the original parser.
+func rejectsPrefix(m Measure) bool {
+	return isPrefixless(m) && m != Percentage
+}
+
+// NewMeasure creates a new measure out of a string representing a measure like 'Bytes', 'Flops' and 'precent'.
+// It checks the registered aliases first, then the known spellings of the built-in measures (generated
+// by ccunits-gen) and uses regular expressions for matching afterwards. The regular expressions are
+// checked in the order of the measures.
+func NewMeasure(unit string) Measure {
+	registryLock.RLock()
+	m, ok := measureAliases[unit]
+	registryLock.RUnlock()
+	if ok {
+		return m
+	}
+	if m, ok := lookupMeasure(unit); ok {
+		return m
+	}
+	for _, matcher := range getParserTables().measures {
+		if matcher.regex.MatchString(unit) {
+			return matcher.measure
+		}
+	}
+	return InvalidMeasure
+}
+
+// matchesWholeMeasure checks whether a string is a spelling of the measure m as a whole,
+// either a registered alias, a known spelling or a full match of the regular expression.
+// The regular expressions also match the beginning of words like 'words' for Watts.
+func matchesWholeMeasure(s string, m Measure) bool {
+	registryLock.RLock()
+	sm, ok := measureSpelling(s)
+	registryLock.RUnlock()
+	if ok {
+		return sm == m
+	}
+	for _, matcher := range getParserTables().measures {
+		if matcher.measure == m && matcher.regex.FindString(s) == s {
+			return true
+		}
+	}
+	return false
+}
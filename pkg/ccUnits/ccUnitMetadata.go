@@ -0,0 +1,147 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// MeasureCategory is the semantic category of a measure like 'data' or 'power'
+type MeasureCategory string
+
+const (
+	CategoryData        MeasureCategory = "data"
+	CategoryCompute     MeasureCategory = "compute"
+	CategoryRatio       MeasureCategory = "ratio"
+	CategoryTemperature MeasureCategory = "temperature"
+	CategoryFrequency   MeasureCategory = "frequency"
+	CategoryTime        MeasureCategory = "time"
+	CategoryPower       MeasureCategory = "power"
+	CategoryEnergy      MeasureCategory = "energy"
+	CategoryNetwork     MeasureCategory = "network"
+	CategoryElectrical  MeasureCategory = "electrical"
+	CategoryCount       MeasureCategory = "count"
+)
+
+// MeasureMetadata describes a measure for UIs, e.g. for tooltips, axis labels and the
+// default color of plots, and for prose with the singular and plural names
+type MeasureMetadata struct {
+	Description string          `json:"description" yaml:"description"`
+	Singular    string          `json:"singular,omitempty" yaml:"singular,omitempty"` // Name for one unit like 'Byte' (optional)
+	Plural      string          `json:"plural,omitempty" yaml:"plural,omitempty"`     // Name for other amounts like 'Bytes' (optional)
+	Category    MeasureCategory `json:"category" yaml:"category"`
+	Color       string          `json:"color,omitempty" yaml:"color,omitempty"`             // Default color hint like '#1f77b4'
+	TypicalMin  float64         `json:"typical_min,omitempty" yaml:"typical_min,omitempty"` // Typical range of values without prefix
+	TypicalMax  float64         `json:"typical_max,omitempty" yaml:"typical_max,omitempty"`
+	ValidMin    *float64        `json:"valid_min,omitempty" yaml:"valid_min,omitempty"`       // Lower bound of valid values without prefix (optional)
+	ValidMax    *float64        `json:"valid_max,omitempty" yaml:"valid_max,omitempty"`       // Upper bound of valid values without prefix (optional)
+	NonNegative bool            `json:"non_negative,omitempty" yaml:"non_negative,omitempty"` // Values and rates of count-like measures cannot be negative
+	Counter     bool            `json:"counter,omitempty" yaml:"counter,omitempty"`           // Values are counts per interval which are summed over time
+}
+
+// validBound returns a pointer to a bound of the valid range
+func validBound(v float64) *float64 {
+	return &v
+}
+
+// Metadata of the registered measures. The metadata of the built-in measures is returned
+// by builtinMetadata().
+var measureMetadata = make(map[Measure]MeasureMetadata)
+
+// Metadata returns the metadata of a measure and whether metadata is available. All built-in
+// measures have metadata, registered measures only if it is part of their definition.
+func Metadata(m Measure) (MeasureMetadata, bool) {
+	if md, ok := builtinMetadata(m); ok {
+		return md, true
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	md, ok := measureMetadata[m]
+	return md, ok
+}
+
+// Validate checks whether the value of a quantity is a possible value of its measure, so
+// ingest can flag garbage readings like a utilization of 300 % or a temperature below absolute
+// zero. The value is compared to the valid range of the measure after converting it to the
+// unit without prefix. Quantities with unit denominator are only checked for NaN, infinity
+// and negative values of non-negative measures.
+func Validate(q Quantity) error {
+	if !q.Valid() {
+		return fmt.Errorf("invalid unit of quantity %v: %w", q.Value, ErrInvalidMeasure)
+	}
+	if math.IsNaN(q.Value) || math.IsInf(q.Value, 0) {
+		return fmt.Errorf("invalid value %v of '%s': %w", q.Value, q.Unit.Short(), ErrInvalidValue)
+	}
+	md, ok := Metadata(q.Unit.GetMeasure())
+	if !ok {
+		return nil
+	}
+	if md.NonNegative && q.Value < 0 {
+		return fmt.Errorf("negative value %v of '%s': %w", q.Value, q.Unit.Short(), ErrOverflow)
+	}
+	if q.Unit.GetUnitDenominator() != InvalidMeasure {
+		return nil
+	}
+	v := NormalizeToBase(q).Value
+	switch {
+	case md.ValidMin != nil && v < *md.ValidMin:
+		return fmt.Errorf("value %v of '%s' is below the minimum %v: %w", q.Value, q.Unit.Short(), *md.ValidMin, ErrOverflow)
+	case md.ValidMax != nil && v > *md.ValidMax:
+		return fmt.Errorf("value %v of '%s' is above the maximum %v: %w", q.Value, q.Unit.Short(), *md.ValidMax, ErrOverflow)
+	}
+	return nil
+}
+
+// NegativePolicy selects how negative values of non-negative measures are handled, e.g.
+// rates computed over a counter reset
+type NegativePolicy int
+
+const (
+	NegativeAllow  NegativePolicy = iota // Negative values are passed through
+	NegativeReject                       // Negative values are rejected with an error
+	NegativeClamp                        // Negative values are replaced by 0
+)
+
+// String returns the name of the policy
+func (p NegativePolicy) String() string {
+	switch p {
+	case NegativeAllow:
+		return "allow"
+	case NegativeReject:
+		return "reject"
+	case NegativeClamp:
+		return "clamp"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the name of the policy, so it is readable in configurations
+func (p NegativePolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText reads the name of the policy
+func (p *NegativePolicy) UnmarshalText(text []byte) error {
+	for _, policy := range []NegativePolicy{NegativeAllow, NegativeReject, NegativeClamp} {
+		if policy.String() == string(text) {
+			*p = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid negative policy '%s'", string(text))
+}
+
+// CheckNonNegative applies the policy to a quantity of a non-negative measure like Bytes,
+// Packets, Requests or Flops (also as rate like 'MB/s'). Quantities of other measures and
+// non-negative values are returned unchanged.
+func CheckNonNegative(q Quantity, policy NegativePolicy) (Quantity, error) {
+	if policy == NegativeAllow || q.Value >= 0 || !q.Valid() {
+		return q, nil
+	}
+	if md, ok := Metadata(q.Unit.GetMeasure()); !ok || !md.NonNegative {
+		return q, nil
+	}
+	if policy == NegativeClamp {
+		return Quantity{Value: 0, Unit: q.Unit}, nil
+	}
+	return q, fmt.Errorf("negative value %v of '%s': %w", q.Value, q.Unit.Short(), ErrOverflow)
+}
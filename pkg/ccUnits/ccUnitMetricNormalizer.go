@@ -0,0 +1,218 @@
+package ccunits
+
+import "fmt"
+
+// MetricNormalizerRule is the unit policy for a metric
+type MetricNormalizerRule struct {
+	ExpectedUnit string            `json:"expected_unit,omitempty"` // Unit the metric is expected in. Used if a metric comes without unit (optional)
+	TargetUnit   string            `json:"target_unit"`             // Unit the metric is converted to
+	Negative     NegativePolicy    `json:"negative,omitempty"`      // Handling of negative values of non-negative measures like 'reject' or 'clamp' (optional)
+	Convention   PercentConvention `json:"convention,omitempty"`    // Scale of ratio and percentage values like 'ratio' or 'percent' independent of the unit (optional)
+}
+
+// metricNormalizerRule is the parsed unit policy for a metric
+type metricNormalizerRule struct {
+	expected   UnitValue // Invalid if the rule has no expected unit
+	target     UnitValue
+	negative   NegativePolicy
+	convention PercentConvention
+}
+
+// PercentConvention is the scale a metric reports utilizations in. Some collectors report
+// them as 0-1 ratios and others as 0-100 percent, sometimes with the wrong unit.
+type PercentConvention int
+
+const (
+	PercentByUnit  PercentConvention = iota // The unit of the metric tells the scale
+	PercentAsRatio                          // Values are 0-1 ratios independent of the unit
+	PercentAsPct                            // Values are 0-100 percent independent of the unit
+)
+
+// String returns the name of the convention
+func (c PercentConvention) String() string {
+	switch c {
+	case PercentByUnit:
+		return "unit"
+	case PercentAsRatio:
+		return "ratio"
+	case PercentAsPct:
+		return "percent"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the name of the convention, so it is readable in configurations
+func (c PercentConvention) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText reads the name of the convention
+func (c *PercentConvention) UnmarshalText(text []byte) error {
+	for _, convention := range []PercentConvention{PercentByUnit, PercentAsRatio, PercentAsPct} {
+		if convention.String() == string(text) {
+			*c = convention
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid percent convention '%s'", string(text))
+}
+
+// unit returns the unit of the convention or nil for PercentByUnit
+func (c PercentConvention) unit() Unit {
+	switch c {
+	case PercentAsRatio:
+		return newBaseUnit(Base, Ratio)
+	case PercentAsPct:
+		return newBaseUnit(Base, Percentage)
+	}
+	return nil
+}
+
+// MetricNormalizer applies per-metric unit rules to metric values
+type MetricNormalizer struct {
+	rules map[string]metricNormalizerRule
+}
+
+// NewMetricNormalizer creates a new metric normalizer out of the rules for the metrics
+// addressed by their name. It returns an error if a unit in the rules is invalid or the
+// expected unit cannot be converted to the target unit.
+func NewMetricNormalizer(rules map[string]MetricNormalizerRule) (*MetricNormalizer, error) {
+	n := &MetricNormalizer{
+		rules: make(map[string]metricNormalizerRule),
+	}
+	for name, rule := range rules {
+		r := metricNormalizerRule{
+			expected:   invalidUnitValue,
+			target:     NewUnitValue(rule.TargetUnit),
+			negative:   rule.Negative,
+			convention: rule.Convention,
+		}
+		if !r.target.Valid() {
+			return nil, fmt.Errorf("invalid target unit '%s' for metric '%s': %w", rule.TargetUnit, name, ErrInvalidMeasure)
+		}
+		if u := r.convention.unit(); u != nil && !u.Compatible(r.target.Unit()) {
+			return nil, fmt.Errorf("percent convention '%s' requires a ratio or percentage as target unit for metric '%s': %w", r.convention.String(), name, ErrIncompatibleMeasure)
+		}
+		if len(rule.ExpectedUnit) > 0 {
+			r.expected = NewUnitValue(rule.ExpectedUnit)
+			if !r.expected.Valid() {
+				return nil, fmt.Errorf("invalid expected unit '%s' for metric '%s': %w", rule.ExpectedUnit, name, ErrInvalidMeasure)
+			}
+			if _, err := GetUnitUnitFactor(r.expected.Unit(), r.target.Unit()); err != nil {
+				return nil, fmt.Errorf("expected unit '%s' cannot be converted to target unit '%s' for metric '%s': %w", rule.ExpectedUnit, rule.TargetUnit, name, ErrIncompatibleMeasure)
+			}
+		}
+		n.rules[name] = r
+	}
+	return n, nil
+}
+
+// plan returns the rule of a metric, the unit of its values and the converter to the target
+// unit of the rule. The converter is nil for metrics without rule.
+func (n *MetricNormalizer) plan(name string, unitStr string) (metricNormalizerRule, Unit, *Converter, error) {
+	rule, hasRule := n.rules[name]
+	var in Unit
+	if len(unitStr) > 0 {
+		in = NewUnit(unitStr)
+		if !in.Valid() {
+			return rule, in, nil, fmt.Errorf("invalid unit '%s' for metric '%s': %w", unitStr, name, ErrInvalidMeasure)
+		}
+	} else if hasRule && rule.expected.Valid() {
+		in = rule.expected.Unit()
+	} else if hasRule && rule.convention != PercentByUnit {
+		in = rule.convention.unit()
+	} else {
+		return rule, invalidUnitValue.Unit(), nil, fmt.Errorf("no unit for metric '%s': %w", name, ErrInvalidMeasure)
+	}
+	if !hasRule {
+		return rule, in, nil, nil
+	}
+	if u := rule.convention.unit(); u != nil && (in.GetMeasure() == Ratio || in.GetMeasure() == Percentage) {
+		in = u
+	}
+	conv, err := NewConverter(in, rule.target.Unit())
+	if err != nil {
+		return rule, in, nil, fmt.Errorf("cannot convert metric '%s' from '%s' to '%s': %w", name, in.Short(), rule.target.Short(), err)
+	}
+	return rule, in, &conv, nil
+}
+
+// Normalize applies the unit policy to a metric value. If the unit string is empty, the
+// expected unit of the rule is used. Values of metrics without rule are not converted but
+// the unit is parsed. If the rule sets a percent convention, ratio and percentage values are
+// read in its scale independent of their unit, so mixed sources normalize correctly.
+// Negative values of non-negative measures are rejected or clamped to 0 if set by the rule.
+// It returns the (converted) value and the resulting unit.
+func (n *MetricNormalizer) Normalize(name string, value float64, unitStr string) (float64, Unit, error) {
+	rule, in, conv, err := n.plan(name, unitStr)
+	if err != nil || conv == nil {
+		return value, in, err
+	}
+	q, err := CheckNonNegative(Quantity{Value: conv.ApplyFloat64(value), Unit: rule.target.Unit()}, rule.negative)
+	if err != nil {
+		return value, in, fmt.Errorf("invalid value of metric '%s': %w", name, err)
+	}
+	return q.Value, q.Unit, nil
+}
+
+// NormalizeValue applies the unit policy like Normalize() to a value of any numeric type.
+// Integer values keep their type if the conversion has an exact integer factor like from
+// KByte to Byte and the result fits into the type, otherwise they are converted to float64.
+// Negative integers are converted to float64 if the rule rejects or clamps them. It returns
+// an error for non-numeric values.
+func (n *MetricNormalizer) NormalizeValue(name string, value interface{}, unitStr string) (interface{}, Unit, error) {
+	rule, in, conv, err := n.plan(name, unitStr)
+	if err != nil || conv == nil {
+		return value, in, err
+	}
+	if v, ok := conv.applyExactInteger(value); ok && (rule.negative == NegativeAllow || !isNegative(v)) {
+		return v, rule.target.Unit(), nil
+	}
+	f, ok := numericValue(value)
+	if !ok {
+		return value, in, fmt.Errorf("value of metric '%s' is not numeric: %w", name, ErrInvalidValue)
+	}
+	return n.Normalize(name, f, unitStr)
+}
+
+// HasRule checks whether the normalizer has a rule for a metric
+func (n *MetricNormalizer) HasRule(name string) bool {
+	_, ok := n.rules[name]
+	return ok
+}
+
+// numericValue returns a value of any numeric type as float64
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// isNegative checks whether a value of a signed integer type is negative
+func isNegative(value interface{}) bool {
+	switch v := value.(type) {
+	case int:
+		return v < 0
+	case int32:
+		return v < 0
+	case int64:
+		return v < 0
+	}
+	return false
+}
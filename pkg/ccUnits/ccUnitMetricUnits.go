@@ -0,0 +1,126 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// metricUnitPattern is the expected unit of the metrics matching a glob pattern
+type metricUnitPattern struct {
+	pattern string
+	unit    UnitValue
+}
+
+// MetricUnits maps metric names to their expected units, so collectors can query the unit of
+// a metric and a missing unit can be filled from the policy at ingest instead of storing the
+// metric unitless. Metrics are addressed by name or by glob patterns like 'mem_*' or
+// 'nv_*_power' (see path.Match). It is not changed after creation and safe for concurrent use.
+type MetricUnits struct {
+	names    map[string]UnitValue
+	patterns []metricUnitPattern // Most specific pattern first
+}
+
+// wildcards returns the number of wildcard characters of a glob pattern
+func wildcards(pattern string) int {
+	return strings.Count(pattern, "*") + strings.Count(pattern, "?") + strings.Count(pattern, "[")
+}
+
+// NewMetricUnits creates the mapping out of the expected units by metric name or glob
+// pattern. If several patterns match a metric, the one with the most literal characters
+// wins. It returns an error for invalid units and malformed patterns.
+func NewMetricUnits(units map[string]string) (*MetricUnits, error) {
+	m := &MetricUnits{
+		names: make(map[string]UnitValue),
+	}
+	for pattern, unitStr := range units {
+		u := InternUnit(unitStr)
+		if !u.Valid() {
+			return nil, fmt.Errorf("invalid unit '%s' for metric '%s': %w", unitStr, pattern, ErrInvalidMeasure)
+		}
+		if wildcards(pattern) == 0 && !strings.Contains(pattern, "\\") {
+			m.names[pattern] = u
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid metric pattern '%s': %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, metricUnitPattern{pattern: pattern, unit: u})
+	}
+	sort.Slice(m.patterns, func(i, j int) bool {
+		a, b := m.patterns[i].pattern, m.patterns[j].pattern
+		if la, lb := len(a)-wildcards(a), len(b)-wildcards(b); la != lb {
+			return la > lb
+		}
+		return a < b
+	})
+	return m, nil
+}
+
+// LoadMetricUnits reads the expected units by metric name or pattern from a JSON or YAML
+// file (selected by the file extension)
+func LoadMetricUnits(path string) (*MetricUnits, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metric units: %w", err)
+	}
+	var units map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &units)
+	default:
+		err = json.Unmarshal(data, &units)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metric units in '%s': %w", path, err)
+	}
+	return NewMetricUnits(units)
+}
+
+// Lookup returns the expected unit of a metric or false if neither its name nor a pattern
+// is known
+func (m *MetricUnits) Lookup(name string) (Unit, bool) {
+	if u, ok := m.names[name]; ok {
+		return u.Unit(), true
+	}
+	for _, p := range m.patterns {
+		if match, _ := path.Match(p.pattern, name); match {
+			return p.unit.Unit(), true
+		}
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// Fill returns the unit of a metric value: the parsed unit string or, if it is empty, the
+// expected unit of the metric. It returns an error if the unit string is invalid, cannot be
+// converted to the expected unit or is missing for a metric without expected unit.
+func (m *MetricUnits) Fill(name string, unitStr string) (Unit, error) {
+	expected, ok := m.Lookup(name)
+	if len(unitStr) == 0 {
+		if !ok {
+			return expected, fmt.Errorf("no unit for metric '%s': %w", name, ErrInvalidMeasure)
+		}
+		return expected, nil
+	}
+	u := NewUnit(unitStr)
+	if !u.Valid() {
+		return u, fmt.Errorf("invalid unit '%s' for metric '%s': %w", unitStr, name, ErrInvalidMeasure)
+	}
+	if ok && !u.Compatible(expected) {
+		return u, fmt.Errorf("unit '%s' of metric '%s' cannot be converted to the expected unit '%s': %w", unitStr, name, expected.Short(), ErrIncompatibleMeasure)
+	}
+	return u, nil
+}
+
+// Validate checks the unit string of a metric value against the expected unit of the metric
+// (see Fill())
+func (m *MetricUnits) Validate(name string, unitStr string) error {
+	_, err := m.Fill(name, unitStr)
+	return err
+}
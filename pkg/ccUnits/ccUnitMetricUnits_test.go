@@ -0,0 +1,60 @@
+package ccunits
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMetricUnits(t *testing.T) {
+	m, err := NewMetricUnits(map[string]string{
+		"mem_*":       "B",
+		"mem_bw":      "MB/s",
+		"nv_*_power":  "W",
+		"nv_*":        "count",
+		"cpu_load":    "ratio",
+		"*_temp":      "degC",
+		"ib_?cv_data": "IBW",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for name, expected := range map[string]string{
+		"mem_used":     "B",
+		"mem_bw":       "MB/s",
+		"nv_mem_power": "W",
+		"nv_util":      "count",
+		"core_temp":    "degC",
+		"ib_rcv_data":  "IBW",
+		"flops_any":    "",
+	} {
+		u, ok := m.Lookup(name)
+		if ok != (len(expected) > 0) || ok && u.Short() != expected || !ok && u.Valid() {
+			t.Errorf("Expected unit '%s' for metric '%s' but got '%s' (%v)", expected, name, u.Short(), ok)
+		}
+	}
+
+	if u, err := m.Fill("mem_used", ""); err != nil || u.Short() != "B" {
+		t.Errorf("Expected filled unit 'B' but got '%s': %v", u.Short(), err)
+	}
+	if u, err := m.Fill("mem_used", "GiB"); err != nil || u.Short() != "GiB" {
+		t.Errorf("Expected unit 'GiB' but got '%s': %v", u.Short(), err)
+	}
+	if err := m.Validate("mem_used", "W"); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected error for incompatible unit but got %v", err)
+	}
+	if u, err := m.Fill("flops_any", ""); !errors.Is(err, ErrInvalidMeasure) || u.Valid() {
+		t.Errorf("Expected error and invalid unit for missing unit but got '%s': %v", u.Short(), err)
+	}
+	if u, err := m.Fill("mem_used", "xyz"); !errors.Is(err, ErrInvalidMeasure) || u.Valid() {
+		t.Errorf("Expected error and invalid unit for invalid unit but got '%s': %v", u.Short(), err)
+	}
+	if err := m.Validate("flops_any", ""); !errors.Is(err, ErrInvalidMeasure) {
+		t.Errorf("Expected error for missing unit but got %v", err)
+	}
+	if _, err := NewMetricUnits(map[string]string{"mem_[": "B"}); err == nil {
+		t.Errorf("Expected error for malformed pattern")
+	}
+	if _, err := NewMetricUnits(map[string]string{"mem_used": "xyz"}); err == nil {
+		t.Errorf("Expected error for invalid unit")
+	}
+}
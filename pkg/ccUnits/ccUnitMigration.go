@@ -0,0 +1,215 @@
+package ccunits
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MigrationRule migrates the values of a metric from one unit to another
+type MigrationRule struct {
+	Metric string `json:"metric"` // Metric name
+	From   string `json:"from"`   // Unit the metric is currently stored in
+	To     string `json:"to"`     // Unit the metric should be stored in
+}
+
+// MetricMigration is the parsed migration of a metric with the converter for the values
+type MetricMigration struct {
+	Metric  string
+	From    Unit
+	To      Unit
+	Convert func(value interface{}) interface{}
+	conv    Converter
+}
+
+// MigrationAction describes what happens to a metric during the migration
+type MigrationAction int
+
+const (
+	MigrationConvert   MigrationAction = iota // Values are converted and the unit is rewritten
+	MigrationUnchanged                        // Metric is already stored in the target unit
+	MigrationSkip                             // No rule for the metric
+	MigrationMismatch                         // Stored unit does not match the rule
+)
+
+// String returns a description of the migration action
+func (a MigrationAction) String() string {
+	switch a {
+	case MigrationConvert:
+		return "convert"
+	case MigrationUnchanged:
+		return "unchanged"
+	case MigrationSkip:
+		return "skip"
+	case MigrationMismatch:
+		return "mismatch"
+	}
+	return "invalid"
+}
+
+// MarshalText writes the description of the migration action, so it is readable in JSON reports
+func (a MigrationAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// MigrationReportEntry is the planned migration of a single metric
+type MigrationReportEntry struct {
+	Metric  string          `json:"metric"`
+	Unit    string          `json:"unit"`               // Currently stored unit
+	NewUnit string          `json:"new_unit,omitempty"` // Unit after migration
+	Action  MigrationAction `json:"action"`
+	Factor  float64         `json:"factor,omitempty"`  // Factor of the conversion
+	Example string          `json:"example,omitempty"` // Conversion of the value 1 like '1 MB/s -> 0.001 GB/s'
+}
+
+// MigrationReport is the result of a dry-run of the migration
+type MigrationReport []MigrationReportEntry
+
+// String returns the report with one line per metric
+func (r MigrationReport) String() string {
+	var b strings.Builder
+	for _, e := range r {
+		switch e.Action {
+		case MigrationConvert:
+			fmt.Fprintf(&b, "%s: %s %s -> %s (%s)\n", e.Metric, e.Action.String(), e.Unit, e.NewUnit, e.Example)
+		case MigrationMismatch:
+			fmt.Fprintf(&b, "%s: %s stored unit %s, rule expects %s\n", e.Metric, e.Action.String(), e.Unit, e.NewUnit)
+		default:
+			fmt.Fprintf(&b, "%s: %s %s\n", e.Metric, e.Action.String(), e.Unit)
+		}
+	}
+	return b.String()
+}
+
+// MigrationEngine holds the migrations for the metrics of a metric store
+type MigrationEngine struct {
+	migrations map[string]MetricMigration
+	audit      *AuditLog
+}
+
+// sameUnit checks whether two units have the same prefix, measure and unit denominator
+func sameUnit(a, b Unit) bool {
+	return ValueOf(a) == ValueOf(b)
+}
+
+// NewMigrationEngine creates the migrations for the rules. It returns an error if a unit
+// is invalid, the units of a rule are not convertible or there are two rules for a metric.
+func NewMigrationEngine(rules []MigrationRule) (*MigrationEngine, error) {
+	e := &MigrationEngine{
+		migrations: make(map[string]MetricMigration),
+	}
+	for _, rule := range rules {
+		if _, ok := e.migrations[rule.Metric]; ok {
+			return nil, fmt.Errorf("duplicate migration rule for metric '%s'", rule.Metric)
+		}
+		from := NewUnit(rule.From)
+		if !from.Valid() {
+			return nil, fmt.Errorf("invalid unit '%s' in migration rule for metric '%s': %w", rule.From, rule.Metric, ErrInvalidMeasure)
+		}
+		to := NewUnit(rule.To)
+		if !to.Valid() {
+			return nil, fmt.Errorf("invalid unit '%s' in migration rule for metric '%s': %w", rule.To, rule.Metric, ErrInvalidMeasure)
+		}
+		conv, err := GetUnitUnitFactor(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("cannot migrate metric '%s' from '%s' to '%s': %w", rule.Metric, rule.From, rule.To, err)
+		}
+		c, err := NewConverter(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("cannot migrate metric '%s' from '%s' to '%s': %w", rule.Metric, rule.From, rule.To, err)
+		}
+		e.migrations[rule.Metric] = MetricMigration{
+			Metric:  rule.Metric,
+			From:    from,
+			To:      to,
+			Convert: conv,
+			conv:    c,
+		}
+	}
+	return e, nil
+}
+
+// SetAuditLog records every value converted by Migrate() in the audit log (nil for none). It
+// has to be set before the engine is used.
+func (e *MigrationEngine) SetAuditLog(log *AuditLog) {
+	e.audit = log
+}
+
+// GetMigration returns the migration for a metric
+func (e *MigrationEngine) GetMigration(metric string) (MetricMigration, bool) {
+	m, ok := e.migrations[metric]
+	return m, ok
+}
+
+// plan determines the migration action for a metric stored in a unit
+func (e *MigrationEngine) plan(metric, unitStr string) (MetricMigration, MigrationAction) {
+	m, ok := e.migrations[metric]
+	if !ok {
+		return m, MigrationSkip
+	}
+	u := NewUnit(unitStr)
+	if !u.Valid() {
+		return m, MigrationMismatch
+	}
+	if sameUnit(u, m.From) {
+		return m, MigrationConvert
+	}
+	if sameUnit(u, m.To) {
+		return m, MigrationUnchanged
+	}
+	return m, MigrationMismatch
+}
+
+// RewriteUnit returns the unit string a metric should be stored with after the migration.
+// The second return value is false if the metric does not need to be migrated.
+func (e *MigrationEngine) RewriteUnit(metric, unitStr string) (string, bool) {
+	m, action := e.plan(metric, unitStr)
+	if action != MigrationConvert {
+		return unitStr, false
+	}
+	return m.To.Short(), true
+}
+
+// Migrate converts a value of a metric stored in a unit. It returns the converted value and
+// the new unit string. Metrics without rule or already in the target unit are returned
+// unchanged. If the stored unit does not match the rule, an error is returned.
+func (e *MigrationEngine) Migrate(metric, unitStr string, value interface{}) (interface{}, string, error) {
+	m, action := e.plan(metric, unitStr)
+	switch action {
+	case MigrationConvert:
+		if e.audit != nil {
+			e.audit.Record(metric, "", m.From, m.To, m.conv, 1)
+		}
+		return m.Convert(value), m.To.Short(), nil
+	case MigrationMismatch:
+		return value, unitStr, fmt.Errorf("stored unit '%s' of metric '%s' does not match migration from '%s': %w", unitStr, metric, m.From.Short(), ErrIncompatibleMeasure)
+	}
+	return value, unitStr, nil
+}
+
+// DryRun plans the migration for metrics addressed by their name with the currently stored
+// unit without changing anything. The report is sorted by metric name.
+func (e *MigrationEngine) DryRun(metrics map[string]string) MigrationReport {
+	report := make(MigrationReport, 0, len(metrics))
+	for metric, unitStr := range metrics {
+		m, action := e.plan(metric, unitStr)
+		entry := MigrationReportEntry{
+			Metric: metric,
+			Unit:   unitStr,
+			Action: action,
+		}
+		switch action {
+		case MigrationConvert:
+			entry.NewUnit = m.To.Short()
+			entry.Factor = m.conv.Factor()
+			entry.Example = fmt.Sprintf("1 %s -> %v %s", m.From.Short(), m.Convert(1.0), m.To.Short())
+		case MigrationMismatch:
+			entry.NewUnit = m.From.Short()
+		}
+		report = append(report, entry)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Metric < report[j].Metric
+	})
+	return report
+}
@@ -0,0 +1,134 @@
+package ccunits
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetMigration(t *testing.T) {
+	e, err := NewMigrationEngine([]MigrationRule{
+		{Metric: "mem_bw", From: "MB/s", To: "GB/s"},
+		{Metric: "cpu_power", From: "mW", To: "W"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create migration engine: %v", err)
+	}
+	m, ok := e.GetMigration("mem_bw")
+	if !ok || m.Metric != "mem_bw" || m.From.Short() != "MB/s" || m.To.Short() != "GB/s" {
+		t.Errorf("Unexpected migration %v for metric 'mem_bw' (%v)", m, ok)
+	}
+	if v := m.Convert(2500.0); v != 2.5 {
+		t.Errorf("Expected 2500 MB/s = 2.5 GB/s but got %v", v)
+	}
+	for _, metric := range []string{"cpu_load", "", "MEM_BW"} {
+		if _, ok := e.GetMigration(metric); ok {
+			t.Errorf("Unexpected migration for metric '%s'", metric)
+		}
+	}
+
+	for _, rules := range [][]MigrationRule{
+		{{Metric: "mem_bw", From: "xyz", To: "GB/s"}},
+		{{Metric: "mem_bw", From: "MB/s", To: "xyz"}},
+		{{Metric: "mem_bw", From: "MB/s", To: "W"}},
+		{{Metric: "mem_bw", From: "MB/s", To: "GB/s"}, {Metric: "mem_bw", From: "KB/s", To: "GB/s"}},
+	} {
+		if _, err := NewMigrationEngine(rules); err == nil {
+			t.Errorf("Expected error for migration rules %v", rules)
+		}
+	}
+}
+
+func TestRewriteUnit(t *testing.T) {
+	e, err := NewMigrationEngine([]MigrationRule{
+		{Metric: "mem_bw", From: "MB/s", To: "GB/s"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create migration engine: %v", err)
+	}
+	for _, c := range []struct {
+		metric   string
+		unit     string
+		expected string
+		rewrite  bool
+	}{
+		{"mem_bw", "MB/s", "GB/s", true},
+		{"mem_bw", "MByte/s", "GB/s", true},
+		{"mem_bw", "GB/s", "GB/s", false},   // Already migrated
+		{"mem_bw", "KB/s", "KB/s", false},   // Does not match the rule
+		{"mem_bw", "xyz", "xyz", false},     // Unknown unit
+		{"mem_bw", "", "", false},           // No unit
+		{"cpu_load", "MB/s", "MB/s", false}, // No rule
+	} {
+		if u, ok := e.RewriteUnit(c.metric, c.unit); u != c.expected || ok != c.rewrite {
+			t.Errorf("Expected '%s' (%v) for metric '%s' in '%s' but got '%s' (%v)", c.expected, c.rewrite, c.metric, c.unit, u, ok)
+		}
+	}
+
+	if _, _, err := e.Migrate("mem_bw", "xyz", 1.0); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected error for unknown unit but got %v", err)
+	}
+	if v, u, err := e.Migrate("cpu_load", "xyz", 1.0); err != nil || v != 1.0 || u != "xyz" {
+		t.Errorf("Expected unchanged value of metric without rule but got %v %s: %v", v, u, err)
+	}
+	report := e.DryRun(map[string]string{"mem_bw": "xyz", "cpu_load": "xyz"})
+	if len(report) != 2 || report[0].Action != MigrationSkip || report[1].Action != MigrationMismatch || report[1].NewUnit != "MB/s" {
+		t.Errorf("Unexpected report for unknown units:\n%s", report.String())
+	}
+}
+
+func TestChainedMigrations(t *testing.T) {
+	first, err := NewMigrationEngine([]MigrationRule{
+		{Metric: "mem_bw", From: "KB/s", To: "MB/s"},
+		{Metric: "mem_used", From: "KiB", To: "MiB"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create migration engine: %v", err)
+	}
+	second, err := NewMigrationEngine([]MigrationRule{
+		{Metric: "mem_bw", From: "MB/s", To: "GB/s"},
+		{Metric: "mem_used", From: "MiB", To: "GiB"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create migration engine: %v", err)
+	}
+	for _, c := range []struct {
+		metric   string
+		unit     string
+		value    interface{}
+		expected interface{}
+		outUnit  string
+	}{
+		{"mem_bw", "KB/s", 2500000.0, 2.5, "GB/s"},
+		{"mem_bw", "MB/s", 2500.0, 2.5, "GB/s"}, // Only the second migration applies
+		{"mem_used", "KiB", int64(3 * 1024 * 1024), int64(3), "GiB"},
+	} {
+		v, u, err := first.Migrate(c.metric, c.unit, c.value)
+		if err != nil {
+			t.Errorf("Unexpected error in first migration of metric '%s' in '%s': %v", c.metric, c.unit, err)
+			continue
+		}
+		if _, ok := first.RewriteUnit(c.metric, u); ok {
+			t.Errorf("Expected no rewrite of already migrated metric '%s' in '%s'", c.metric, u)
+		}
+		v, u, err = second.Migrate(c.metric, u, v)
+		if err != nil || v != c.expected || u != c.outUnit {
+			t.Errorf("Expected %v %s for metric '%s' in '%s' but got %v %s: %v", c.expected, c.outUnit, c.metric, c.unit, v, u, err)
+		}
+		// Running the second migration again does not change migrated values
+		if v2, u2, err := second.Migrate(c.metric, u, v); err != nil || v2 != v || u2 != u {
+			t.Errorf("Expected unchanged %v %s after second run but got %v %s: %v", v, u, v2, u2, err)
+		}
+		// Values migrated by the whole chain do not match the first migration anymore
+		if _, ok := first.RewriteUnit(c.metric, u); ok {
+			t.Errorf("Expected no rewrite of metric '%s' in '%s' by the first migration", c.metric, u)
+		}
+		if _, _, err := first.Migrate(c.metric, u, v); !errors.Is(err, ErrIncompatibleMeasure) {
+			t.Errorf("Expected error for metric '%s' in '%s' in the first migration but got %v", c.metric, u, err)
+		}
+	}
+
+	// The second migration does not accept values the first one did not migrate yet
+	if _, _, err := second.Migrate("mem_bw", "KB/s", 1.0); err == nil {
+		t.Errorf("Expected error for metric not migrated by the first engine")
+	}
+}
@@ -0,0 +1,73 @@
+package ccunits
+
+import "fmt"
+
+// normalizerKey selects the target unit of a quantity. Units with a dimension are selected
+// by it, so 'MB', 'KiB' and 'B' share the target of data volumes and 'degF' the target of
+// temperatures. Units of logarithmic and registered measures are selected by their measures.
+type normalizerKey struct {
+	dim        dimension
+	measure    Measure
+	divMeasure Measure
+}
+
+// normalizerKeyOf returns the key of a unit
+func normalizerKeyOf(v UnitValue) normalizerKey {
+	if d, _, ok := unitDimension(v); ok {
+		return normalizerKey{dim: d}
+	}
+	return normalizerKey{measure: v.measure, divMeasure: v.divMeasure}
+}
+
+// Normalizer converts quantities to the target unit of their kind like all data volumes to
+// GiB, all times to seconds and all temperatures to degC. In contrast to the MetricNormalizer
+// the policy does not depend on the metric, so it can be shared by all ingestion components.
+// It is not changed after creation and safe for concurrent use.
+type Normalizer struct {
+	targets map[normalizerKey]UnitValue
+}
+
+// NewNormalizer creates a normalizer out of target units like 'GiB', 's', 'degC' and 'GB/s'.
+// Each target unit applies to all units convertible into it. It returns an error for invalid
+// target units and for two target units of the same kind like 'GB' and 'MiB'.
+func NewNormalizer(targetUnits ...string) (*Normalizer, error) {
+	n := &Normalizer{targets: make(map[normalizerKey]UnitValue, len(targetUnits))}
+	for _, unitStr := range targetUnits {
+		u, err := ParseUnit(unitStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target unit '%s': %w", unitStr, err)
+		}
+		key := normalizerKeyOf(ValueOf(u))
+		if other, ok := n.targets[key]; ok {
+			return nil, fmt.Errorf("target units '%s' and '%s' are of the same kind: %w", other.Short(), u.Short(), ErrAmbiguous)
+		}
+		n.targets[key] = ValueOf(u)
+	}
+	return n, nil
+}
+
+// Target returns a new target unit for a unit or false if the normalizer has no target unit
+// of its kind
+func (n *Normalizer) Target(u Unit) (Unit, bool) {
+	if u == nil || !u.Valid() {
+		return invalidUnitValue.Unit(), false
+	}
+	if t, ok := n.targets[normalizerKeyOf(ValueOf(u))]; ok {
+		return t.Unit(), true
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// Normalize converts a quantity to the target unit of its kind. Quantities without target
+// unit are returned unchanged. It returns an error for quantities with invalid unit or if the
+// converted value exceeds the range of float64.
+func (n *Normalizer) Normalize(q Quantity) (Quantity, error) {
+	if !q.Valid() {
+		return q, fmt.Errorf("invalid unit for normalization: %w", ErrInvalidMeasure)
+	}
+	t, ok := n.Target(q.Unit)
+	if !ok || t.Equal(q.Unit) {
+		return q, nil
+	}
+	return q.ConvertTo(t)
+}
@@ -0,0 +1,44 @@
+package ccunits
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNormalizer(t *testing.T) {
+	n, err := NewNormalizer("GiB", "s", "degC", "GB/s")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, c := range []struct {
+		in       Quantity
+		expected Quantity
+	}{
+		{NewQuantity(1024, "MiB"), NewQuantity(1, "GiB")},
+		{NewQuantity(1.5, "h"), NewQuantity(5400, "s")},
+		{NewQuantity(212, "degF"), NewQuantity(100, "degC")},
+		{NewQuantity(500, "MB/s"), NewQuantity(0.5, "GB/s")},
+		{NewQuantity(300, "W"), NewQuantity(300, "W")}, // No target unit
+	} {
+		q, err := n.Normalize(c.in)
+		if err != nil || math.Abs(q.Value-c.expected.Value) > 1e-9 || !q.Unit.Equal(c.expected.Unit) {
+			t.Errorf("Expected %s for %s but got %s: %v", c.expected.String(), c.in.String(), q.String(), err)
+		}
+	}
+	if u, ok := n.Target(NewUnit("W")); ok || u.Valid() {
+		t.Errorf("Expected no target unit for 'W' but got '%s'", u.Short())
+	}
+	// Changing a returned unit must not change the targets
+	q, _ := n.Normalize(NewQuantity(1024, "MiB"))
+	q.Unit.SetPrefix(Kibi)
+	if u, _ := n.Target(NewUnit("MB")); u.Short() != "GiB" {
+		t.Errorf("Expected target unit 'GiB' but got '%s'", u.Short())
+	}
+	if _, err := NewNormalizer("GB", "MiB"); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("Expected error for target units of the same kind but got %v", err)
+	}
+	if _, err := NewNormalizer("xyz"); !errors.Is(err, ErrInvalidMeasure) {
+		t.Errorf("Expected error for invalid target unit but got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package ccunits
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Number is the constraint of the numeric types supported by the generic converters like
+// ConvertNumber(). Downstream packages can use it for unit-generic numeric code.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// numberKind describes a numeric type: integer or floating point, signed and size in bits
+type numberKind struct {
+	integer bool
+	signed  bool
+	bits    int
+}
+
+// kindOf returns the kind of a numeric type
+func kindOf[T Number]() numberKind {
+	var one, zero T = 1, 0
+	zero--
+	return numberKind{
+		integer: one/2 == 0,
+		signed:  zero < 0,
+		bits:    int(unsafe.Sizeof(one)) * 8,
+	}
+}
+
+// AsFloat64 converts a number to float64
+func AsFloat64[T Number](v T) float64 {
+	return float64(v)
+}
+
+// FromFloat64 converts a float64 to a number. For integer types, the value is rounded to the
+// nearest integer (halfway away from zero) and saturated at the limits of the type; NaN
+// results in 0.
+func FromFloat64[T Number](f float64) T {
+	k := kindOf[T]()
+	if !k.integer {
+		return T(f)
+	}
+	if math.IsNaN(f) {
+		return 0
+	}
+	r := math.Round(f)
+	if k.signed {
+		limit := math.Ldexp(1, k.bits-1) // 2^(bits-1) is exact in float64
+		switch {
+		case r >= limit:
+			return T(int64(uint64(1)<<(k.bits-1) - 1))
+		case r < -limit:
+			return T(-int64(uint64(1)<<(k.bits-1)-1) - 1)
+		}
+		return T(int64(r))
+	}
+	switch {
+	case r <= 0:
+		return 0
+	case r >= math.Ldexp(1, k.bits):
+		return T(uint64(math.MaxUint64) >> (64 - k.bits))
+	}
+	return T(uint64(r))
+}
+
+// ConvertNumber converts a value of any numeric type with a converter and returns it with
+// the same type like Converter.Apply(). Integer values are converted by exact integer factors
+// like ApplyInt64() if possible, otherwise the result is rounded with FromFloat64().
+func ConvertNumber[T Number](c Converter, v T) T {
+	k := kindOf[T]()
+	switch {
+	case !k.integer:
+		return T(c.ApplyFloat64(float64(v)))
+	case c.kind != converterFactor || (c.pf.mul == 0 && c.pf.div == 0):
+		return FromFloat64[T](c.ApplyFloat64(float64(v)))
+	case k.signed:
+		return T(c.ApplyInt64(int64(v)))
+	}
+	return T(c.ApplyUint64(uint64(v)))
+}
+
+// ConvertNumbers converts all values of a slice of any numeric type in place like
+// ConvertNumber()
+func ConvertNumbers[T Number](c Converter, values []T) {
+	for i, v := range values {
+		values[i] = ConvertNumber(c, v)
+	}
+}
@@ -0,0 +1,49 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFromFloat64(t *testing.T) {
+	if v := FromFloat64[int16](1234.5); v != 1235 {
+		t.Errorf("Expected 1235 but got %d", v)
+	}
+	if v := FromFloat64[int8](-1000); v != math.MinInt8 {
+		t.Errorf("Expected %d but got %d", math.MinInt8, v)
+	}
+	if v := FromFloat64[uint8](-1.5); v != 0 {
+		t.Errorf("Expected 0 but got %d", v)
+	}
+	if v := FromFloat64[int64](1e30); v != math.MaxInt64 {
+		t.Errorf("Expected %d but got %d", int64(math.MaxInt64), v)
+	}
+	if v := FromFloat64[uint64](1e30); v != math.MaxUint64 {
+		t.Errorf("Expected %d but got %d", uint64(math.MaxUint64), v)
+	}
+	if v := FromFloat64[int](math.NaN()); v != 0 {
+		t.Errorf("Expected 0 for NaN but got %d", v)
+	}
+	if v := FromFloat64[float32](0.25); v != 0.25 || AsFloat64(v) != 0.25 {
+		t.Errorf("Expected 0.25 but got %v", v)
+	}
+}
+
+func TestConvertNumber(t *testing.T) {
+	conv := NewPrefixConverter(Kilo, Base)
+	if v := ConvertNumber(conv, int32(3)); v != 3000 {
+		t.Errorf("Expected 3000 but got %d", v)
+	}
+	if v := ConvertNumber(NewPrefixConverter(Base, Kilo), uint16(1500)); v != 1 {
+		t.Errorf("Expected 1 but got %d", v)
+	}
+	values := []float32{1, 2.5}
+	ConvertNumbers(conv, values)
+	if values[0] != 1000 || values[1] != 2500 {
+		t.Errorf("Unexpected converted values %v", values)
+	}
+	c2f, _ := NewConverter(NewUnit("degC"), NewUnit("degF"))
+	if v := ConvertNumber(c2f, int8(100)); v != math.MaxInt8 {
+		t.Errorf("Expected saturated %d but got %d", math.MaxInt8, v)
+	}
+}
@@ -0,0 +1,139 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// Units of the metric members in OTF2 traces written by Score-P. The values of a metric
+// member are in its unit string times base^exponent like 's' with decimal exponent -9 for
+// nanoseconds. See the OTF2 definition MetricMember.
+
+// Otf2Base is the base of the exponent of an OTF2 metric member (OTF2_Base)
+type Otf2Base uint8
+
+const (
+	Otf2BaseBinary  Otf2Base = 0 // OTF2_BASE_BINARY
+	Otf2BaseDecimal Otf2Base = 1 // OTF2_BASE_DECIMAL
+)
+
+// otf2Units contains the unit strings of Score-P which are not parsed by NewUnit() like '#'
+// for counts of hardware counter events
+var otf2Units map[string]UnitValue = map[string]UnitValue{
+	"#":    {Base, Events, InvalidMeasure},
+	"ns":   {Nano, Time, InvalidMeasure},
+	"us":   {Micro, Time, InvalidMeasure},
+	"usec": {Micro, Time, InvalidMeasure},
+}
+
+// Otf2MetricMember is the unit information of an OTF2 metric member definition
+type Otf2MetricMember struct {
+	Name     string
+	Unit     string // Unit string like 's', 'bytes' or '#'
+	Base     Otf2Base
+	Exponent int64
+}
+
+// number returns the integer of the base
+func (b Otf2Base) number() (int64, error) {
+	switch b {
+	case Otf2BaseBinary:
+		return 2, nil
+	case Otf2BaseDecimal:
+		return 10, nil
+	}
+	return 0, fmt.Errorf("invalid OTF2 base %d: %w", b, ErrInvalidPrefix)
+}
+
+// parse returns the unit of the unit string without the exponent
+func (m Otf2MetricMember) parse() (UnitValue, error) {
+	if u, ok := otf2Units[m.Unit]; ok {
+		return u, nil
+	}
+	u := InternUnit(m.Unit)
+	if !u.Valid() {
+		return invalidUnitValue, fmt.Errorf("invalid unit of OTF2 metric '%s': %w", m.Name, invalidUnitError(m.Unit))
+	}
+	return u, nil
+}
+
+// CCUnit returns the unit of the values of the metric member with the exponent applied to
+// the prefix of the unit string, like 'ns' for 's' with decimal exponent -9 or 'KiB' for
+// 'bytes' with binary exponent 10. It returns an error if there is no prefix for the
+// exponent or the measure has no such prefix like Milli for Bytes; Quantity() also converts
+// these values.
+func (m Otf2MetricMember) CCUnit() (Unit, error) {
+	u, err := m.parse()
+	if err != nil {
+		return invalidUnitValue.Unit(), err
+	}
+	base, err := m.Base.number()
+	if err != nil {
+		return invalidUnitValue.Unit(), err
+	}
+	if m.Exponent == 0 {
+		return u.Unit(), nil
+	}
+	for _, e := range prefixTableEntries {
+		if e.prefix != u.prefix {
+			continue
+		}
+		if e.exponent != 0 && e.base != base {
+			break
+		}
+		exponent := int64(e.exponent) + m.Exponent
+		for _, p := range prefixTableEntries {
+			if int64(p.exponent) != exponent || (p.base != base && exponent != 0) {
+				continue
+			}
+			// No prefixes like Milli for Bytes and no prefixes at all for percentages
+			if (exponent < 0 && isNonDividable(u.measure)) || (exponent != 0 && isPrefixless(u.measure)) {
+				break
+			}
+			return u.WithPrefix(p.prefix).Unit(), nil
+		}
+	}
+	return invalidUnitValue.Unit(), fmt.Errorf("no prefix for '%s' with exponent %d of OTF2 metric '%s': %w", m.Unit, m.Exponent, m.Name, ErrInvalidPrefix)
+}
+
+// Quantity creates a quantity for a value of the metric member. If there is no prefix for
+// the exponent, the value is scaled to the unit string.
+func (m Otf2MetricMember) Quantity(value float64) (Quantity, error) {
+	if u, err := m.CCUnit(); err == nil {
+		return Quantity{Value: value, Unit: u}, nil
+	}
+	u, err := m.parse()
+	if err != nil {
+		return Quantity{Value: value, Unit: invalidUnitValue.Unit()}, err
+	}
+	base, err := m.Base.number()
+	if err != nil {
+		return Quantity{Value: value, Unit: invalidUnitValue.Unit()}, err
+	}
+	return Quantity{Value: value * math.Pow(float64(base), float64(m.Exponent)), Unit: u.Unit()}, nil
+}
+
+// NewOtf2MetricMember creates the unit information of an OTF2 metric member for a unit.
+// The prefix of the unit is written as exponent, so 'MB/s' results in the unit string
+// 'B/s' with decimal exponent 6 and 'GiB' in 'B' with binary exponent 30. Events are
+// written as '#' like by Score-P.
+func NewOtf2MetricMember(name string, u Unit) (Otf2MetricMember, error) {
+	v := ValueOf(u)
+	if !v.Valid() {
+		return Otf2MetricMember{Name: name}, fmt.Errorf("invalid unit for OTF2 metric '%s': %w", name, ErrInvalidMeasure)
+	}
+	m := Otf2MetricMember{Name: name, Unit: v.Base().Short(), Base: Otf2BaseDecimal}
+	if v.measure == Events && v.divMeasure == InvalidMeasure {
+		m.Unit = "#"
+	}
+	for _, e := range prefixTableEntries {
+		if e.prefix == v.prefix {
+			if e.base == 2 {
+				m.Base = Otf2BaseBinary
+			}
+			m.Exponent = int64(e.exponent)
+			return m, nil
+		}
+	}
+	return m, fmt.Errorf("no OTF2 exponent for prefix of '%s': %w", v.Short(), ErrInvalidPrefix)
+}
@@ -0,0 +1,50 @@
+package ccunits
+
+import "testing"
+
+func TestOtf2MetricMember(t *testing.T) {
+	for _, c := range []struct {
+		member   Otf2MetricMember
+		value    float64
+		expected string
+	}{
+		{Otf2MetricMember{"time", "s", Otf2BaseDecimal, -9}, 5, "5 ns"},
+		{Otf2MetricMember{"mem", "bytes", Otf2BaseBinary, 10}, 5, "5 KiB"},
+		{Otf2MetricMember{"PAPI_TOT_CYC", "#", Otf2BaseDecimal, 0}, 5, "5 events"},
+		{Otf2MetricMember{"bw", "MB/s", Otf2BaseDecimal, 3}, 5, "5 GB/s"},
+		{Otf2MetricMember{"energy", "J", Otf2BaseDecimal, 1}, 5, "50 J"},    // No prefix for 10^1
+		{Otf2MetricMember{"io", "bytes", Otf2BaseDecimal, -3}, 5000, "5 B"}, // No Milli for Bytes
+	} {
+		q, err := c.member.Quantity(c.value)
+		if err != nil || q.String() != c.expected {
+			t.Errorf("Expected %s for OTF2 metric '%s' but got %s: %v", c.expected, c.member.Name, q.String(), err)
+		}
+	}
+	if u, err := (Otf2MetricMember{"bw", "KB", Otf2BaseBinary, 10}).CCUnit(); err == nil || u.Valid() {
+		t.Errorf("Expected error and invalid unit for binary exponent of decimal prefix but got '%s'", u.Short())
+	}
+	if u, err := (Otf2MetricMember{"x", "instructions", Otf2BaseDecimal, 0}).CCUnit(); err == nil || u.Valid() {
+		t.Errorf("Expected error and invalid unit for invalid unit but got '%s'", u.Short())
+	}
+	if q, err := (Otf2MetricMember{"x", "instructions", Otf2BaseDecimal, 0}).Quantity(1); err == nil || q.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for invalid unit but got '%s'", q.String())
+	}
+
+	for _, c := range []struct {
+		unit     Unit
+		expected Otf2MetricMember
+	}{
+		{NewUnit("MB/s"), Otf2MetricMember{"m", "B/s", Otf2BaseDecimal, 6}},
+		{NewUnit("GiB"), Otf2MetricMember{"m", "B", Otf2BaseBinary, 30}},
+		{NewUnit("kevents"), Otf2MetricMember{"m", "#", Otf2BaseDecimal, 3}},
+		{NewUnitFromParts(Nano, Time, InvalidMeasure), Otf2MetricMember{"m", "s", Otf2BaseDecimal, -9}},
+	} {
+		m, err := NewOtf2MetricMember("m", c.unit)
+		if err != nil || m != c.expected {
+			t.Errorf("Expected %+v for '%s' but got %+v: %v", c.expected, c.unit.Short(), m, err)
+		}
+		if u, err := m.CCUnit(); err != nil || u.Short() != c.unit.Short() {
+			t.Errorf("Expected '%s' after round trip but got '%s': %v", c.unit.Short(), u.Short(), err)
+		}
+	}
+}
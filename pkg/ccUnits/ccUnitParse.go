@@ -0,0 +1,287 @@
+package ccunits
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseOption configures the parser of ParseUnit()
+type ParseOption func(*parseConfig)
+
+// parseConfig is the configuration of the parser
+type parseConfig struct {
+	strictSI         bool               // Only exact names, no fuzzy matching
+	measureNames     map[string]Measure // Localized measure names (optional)
+	noMilliHeuristic bool               // Milli for non-dividable measures is invalid instead of Mega
+}
+
+// WithStrictSI accepts only the exact short and long names of prefixes and measures (and
+// registered aliases) like 'kB/s' or 'Megabyte/Seconds' instead of fuzzy spellings like
+// 'Mbyt/sec'. Prefixes below Base are invalid for measures that cannot be divided.
+// The SI symbols 'k' and 'µ' are accepted for Kilo and Micro.
+func WithStrictSI() ParseOption {
+	return func(c *parseConfig) {
+		c.strictSI = true
+	}
+}
+
+// WithLocale additionally accepts the measure names of a language like 'Sekunden' for 'de'
+// or 'octets' ('Mo' for MegaBytes) for 'fr'. The locale is given as language tag like 'de'
+// or 'fr_FR'; locales without localized names are ignored.
+func WithLocale(locale string) ParseOption {
+	return func(c *parseConfig) {
+		lang := strings.ToLower(locale)
+		if i := strings.IndexAny(lang, "-_."); i >= 0 {
+			lang = lang[:i]
+		}
+		c.measureNames = localizedMeasureNames[lang]
+	}
+}
+
+// WithoutMilliByteHeuristic disables the special case that reads the prefix 'm' as Mega for
+// measures that cannot be divided like 'mB'. Such units are invalid instead.
+func WithoutMilliByteHeuristic() ParseOption {
+	return func(c *parseConfig) {
+		c.noMilliHeuristic = true
+	}
+}
+
+// localizedMeasureNames contains the measure names of other languages
+var localizedMeasureNames = map[string]map[string]Measure{
+	"de": {
+		"Sekunde":    Time,
+		"Sekunden":   Time,
+		"Prozent":    Percentage,
+		"Zyklen":     Cycles,
+		"Anfragen":   Requests,
+		"Pakete":     Packets,
+		"Ereignisse": Events,
+		"Anzahl":     Count,
+	},
+	"fr": {
+		"o":          Bytes,
+		"octet":      Bytes,
+		"octets":     Bytes,
+		"seconde":    Time,
+		"secondes":   Time,
+		"pourcent":   Percentage,
+		"requêtes":   Requests,
+		"paquets":    Packets,
+		"événements": Events,
+	},
+}
+
+// siPrefixSymbols are the SI symbols accepted in strict mode besides the short names
+var siPrefixSymbols = map[string]Prefix{
+	"k": Kilo,
+	"µ": Micro,
+}
+
+// strictMeasure returns the measure with the exact short or long name or registered alias
+func strictMeasure(s string) Measure {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if m, ok := measureAliases[s]; ok {
+		return m
+	}
+	for m, data := range MeasuresMap {
+		if s == data.Short || s == data.Long {
+			return m
+		}
+	}
+	return InvalidMeasure
+}
+
+// strictPrefix returns the prefix with the exact short or long name, SI symbol or registered alias
+func strictPrefix(s string) Prefix {
+	if p, ok := siPrefixSymbols[s]; ok {
+		return p
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if p, ok := prefixAliases[s]; ok {
+		return p
+	}
+	for p, data := range PrefixDataMap {
+		if s == data.Short || (len(s) > 0 && s == data.Long) {
+			return p
+		}
+	}
+	return InvalidPrefix
+}
+
+// measure parses a measure string with the configuration
+func (c *parseConfig) measure(s string) Measure {
+	if m, ok := c.measureNames[s]; ok {
+		return m
+	}
+	if c.strictSI {
+		return strictMeasure(s)
+	}
+	return NewMeasure(s)
+}
+
+// strictPrefixCandidates returns the long and short names, SI symbols and aliases of all prefixes
+func strictPrefixCandidates() []string {
+	candidates := make([]string, 0, 2*len(PrefixDataMap))
+	for s := range siPrefixSymbols {
+		candidates = append(candidates, s)
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	for _, data := range PrefixDataMap {
+		candidates = append(candidates, data.Short, data.Long)
+	}
+	for s := range prefixAliases {
+		candidates = append(candidates, s)
+	}
+	return candidates
+}
+
+// split splits a unit string into prefix and measure. In strict mode, the prefix names are
+// tried until the rest is an exact measure name, so 'Megabyte' is split into 'Mega' and
+// 'byte'. Localized measure names are not split, so the 'p' of 'paquets' is not a prefix.
+func (c *parseConfig) split(unitStr string) (string, string) {
+	measureStr, _, _ := strings.Cut(unitStr, "/")
+	if _, ok := c.measureNames[measureStr]; ok {
+		return "", unitStr
+	}
+	if c.strictSI {
+		if strictMeasure(measureStr) != InvalidMeasure {
+			return "", unitStr
+		}
+		for _, p := range strictPrefixCandidates() {
+			if len(p) > 0 && strings.HasPrefix(measureStr, p) && strictMeasure(measureStr[len(p):]) != InvalidMeasure {
+				return p, unitStr[len(p):]
+			}
+		}
+	}
+	return splitPrefix(unitStr)
+}
+
+// parse parses a unit string like parseUnitValue() with the configuration
+func (c *parseConfig) parse(unitStr string) (UnitValue, error) {
+	if canonical, ok := legacySpelling(unitStr); ok {
+		return c.parse(canonical)
+	}
+	if timeStr, ok := cutInverse(unitStr); ok {
+		t, err := c.parse(timeStr)
+		if err != nil {
+			return invalidUnitValue, err
+		}
+		if f := inverseTime(t); f.Valid() {
+			return f, nil
+		}
+		return invalidUnitValue, inverseTimeError(unitStr, t)
+	}
+	prefixStr, measureStr := c.split(unitStr)
+	measureStr, divStr, hasDiv := strings.Cut(measureStr, "/")
+	var pre Prefix
+	if c.strictSI {
+		pre = strictPrefix(prefixStr)
+	} else {
+		pre = NewPrefix(prefixStr)
+	}
+	m := c.measure(measureStr)
+	if m == InvalidMeasure && len(prefixStr) > 0 {
+		if t := c.measure(prefixStr + measureStr); t != InvalidMeasure {
+			m = t
+			pre = Base
+		}
+	}
+	if m == InvalidMeasure {
+		return invalidUnitValue, invalidUnitError(unitStr)
+	}
+	if pre == InvalidPrefix {
+		return invalidUnitValue, fmt.Errorf("invalid unit '%s': %w", unitStr, ErrInvalidPrefix)
+	}
+	if _, named := c.measureNames[measureStr]; (pre == Micro || pre == Nano) && !named && !c.strictSI && !matchesWholeMeasure(measureStr, m) {
+		return invalidUnitValue, fmt.Errorf("invalid unit '%s': %w", unitStr, ErrInvalidMeasure)
+	}
+	div := InvalidMeasure
+	if hasDiv {
+		if strings.Contains(divStr, "/") && c.strictSI {
+			return invalidUnitValue, fmt.Errorf("multiple unit denominators in '%s': %w", unitStr, ErrAmbiguous)
+		}
+		divStr, _, _ = strings.Cut(divStr, "/")
+		div = c.measure(divStr)
+		if div == InvalidMeasure && c.strictSI {
+			return invalidUnitValue, fmt.Errorf("invalid unit denominator '%s' in '%s': %w", divStr, unitStr, ErrInvalidMeasure)
+		}
+	}
+	switch {
+	case isNonDividable(m) && pre < Base:
+		if c.strictSI || (pre == Milli && c.noMilliHeuristic) {
+			return invalidUnitValue, fmt.Errorf("prefix '%s' is not allowed for %s in '%s': %w", prefixStr, m.String(), unitStr, ErrAmbiguous)
+		}
+		if pre != Milli {
+			return invalidUnitValue, fmt.Errorf("prefix '%s' is not allowed for %s in '%s': %w", prefixStr, m.String(), unitStr, ErrInvalidPrefix)
+		}
+		pre = Mega
+	case isPrefixless(m) && pre != Base && rejectsPrefix(m):
+		return invalidUnitValue, fmt.Errorf("prefix '%s' is not allowed for %s in '%s': %w", prefixStr, m.String(), unitStr, ErrInvalidPrefix)
+	case isPrefixless(m):
+		pre = Base
+	}
+	return UnitValue{
+		prefix:     pre,
+		measure:    m,
+		divMeasure: div,
+	}, nil
+}
+
+// invalidUnitError returns the error for a unit string without valid measure. Strings with
+// stacked prefixes like 'kMB', a prefix of a prefixless measure like 'kratio' or a fractional
+// prefix of a non-dividable measure like 'ubytes' get a specific error.
+func invalidUnitError(unitStr string) error {
+	if timeStr, ok := cutInverse(unitStr); ok {
+		if t := parseUnitValue(timeStr); t.Valid() {
+			return inverseTimeError(unitStr, t)
+		}
+	}
+	if first, second, ok := stackedPrefixes(unitStr); ok {
+		return fmt.Errorf("invalid unit '%s' with prefixes '%s' and '%s': %w", unitStr, first, second, ErrStackedPrefix)
+	}
+	if prefixStr, m, ok := prefixedPrefixless(unitStr); ok {
+		return fmt.Errorf("prefix '%s' is not allowed for %s in '%s': %w", prefixStr, m.String(), unitStr, ErrInvalidPrefix)
+	}
+	if prefixStr, m, ok := fractionalPrefix(unitStr); ok {
+		return fmt.Errorf("prefix '%s' is not allowed for %s in '%s': %w", prefixStr, m.String(), unitStr, ErrInvalidPrefix)
+	}
+	return fmt.Errorf("invalid unit '%s': %w", unitStr, ErrInvalidMeasure)
+}
+
+// inverseTimeError returns the error for an inverse unit string like '1/B' whose unit t has
+// no frequency. Inverse time scales like '1/h' get a specific error because a frequency
+// with the factor 1/3600 cannot be expressed by a prefix.
+func inverseTimeError(unitStr string, t UnitValue) error {
+	if t.divMeasure == InvalidMeasure && isTimeMeasure(t.measure) && t.measure != Time {
+		return fmt.Errorf("inverse time scale '%s' is no frequency, use a rate like 'count/%s' instead of '%s': %w", t.Short(), t.Short(), unitStr, ErrIncompatibleMeasure)
+	}
+	return fmt.Errorf("unit '%s' is no inverse time: %w", unitStr, ErrIncompatibleMeasure)
+}
+
+// ParseUnit parses a unit string like NewUnit() but returns an error for invalid units. The
+// parsing can be configured per call with options like WithStrictSI(), WithLocale() or
+// WithoutMilliByteHeuristic(), so different pipelines can use different rules without
+// global state. Without options, the result is equal to NewUnit().
+func ParseUnit(unitStr string, opts ...ParseOption) (Unit, error) {
+	if len(opts) == 0 {
+		u := NewUnit(unitStr)
+		if !u.Valid() {
+			return u, invalidUnitError(unitStr)
+		}
+		return u, nil
+	}
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	v, err := c.parse(unitStr)
+	if err != nil {
+		reportInvalidUnit(unitStr)
+	} else {
+		reportDeprecatedUnit(unitStr)
+	}
+	return &unit{v}, err
+}
@@ -0,0 +1,40 @@
+package ccunits
+
+import "testing"
+
+func TestParseUnit(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		opts     []ParseOption
+		expected string // Short notation or empty if invalid
+	}{
+		{"Mbyte/s", nil, "MB/s"},
+		{"xyz", nil, ""},
+		{"mB", nil, "MB"},
+		{"mB", []ParseOption{WithoutMilliByteHeuristic()}, ""},
+		{"ms", []ParseOption{WithoutMilliByteHeuristic()}, "ms"},
+		{"kB/s", []ParseOption{WithStrictSI()}, "KB/s"},
+		{"Megabyte/Seconds", []ParseOption{WithStrictSI()}, "MB/s"},
+		{"GHz", []ParseOption{WithStrictSI()}, "GHz"},
+		{"packets", []ParseOption{WithStrictSI()}, "packets"},
+		{"Mbyt/sec", []ParseOption{WithStrictSI()}, ""},
+		{"foobar", []ParseOption{WithStrictSI()}, ""},
+		{"mB", []ParseOption{WithStrictSI()}, ""},
+		{"Mo", []ParseOption{WithLocale("fr_FR")}, "MB"},
+		{"paquets/secondes", []ParseOption{WithLocale("fr")}, "packets/s"},
+		{"kEreignisse", []ParseOption{WithLocale("de-DE")}, "Kevents"},
+		{"Sekunden", []ParseOption{WithLocale("de"), WithStrictSI()}, "s"},
+		{"1/ms", []ParseOption{WithStrictSI()}, "KHz"},
+		{"B^-1", []ParseOption{WithStrictSI()}, ""},
+	} {
+		u, err := ParseUnit(c.input, c.opts...)
+		switch {
+		case len(c.expected) == 0 && err == nil:
+			t.Errorf("Expected error for '%s' but got '%s'", c.input, u.Short())
+		case len(c.expected) > 0 && err != nil:
+			t.Errorf("Unexpected error for '%s': %v", c.input, err)
+		case len(c.expected) > 0 && u.Short() != c.expected:
+			t.Errorf("Expected '%s' for '%s' but got '%s'", c.expected, c.input, u.Short())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package ccunits
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Units of the Linux perf_event counters by their names in 'perf list' like 'cycles',
+// 'instructions' or 'task-clock'. The values are the raw counter values read with
+// perf_event_open(), so the software clocks are in nanoseconds ('perf stat' prints them in
+// msec).
+
+// PerfEventUnits contains the units of the hardware and software events of perf_event
+var PerfEventUnits map[string]UnitValue = map[string]UnitValue{
+	// Hardware events
+	"cycles":                  {Base, Cycles, InvalidMeasure},
+	"cpu-cycles":              {Base, Cycles, InvalidMeasure},
+	"bus-cycles":              {Base, Cycles, InvalidMeasure},
+	"ref-cycles":              {Base, Cycles, InvalidMeasure},
+	"stalled-cycles-frontend": {Base, Cycles, InvalidMeasure},
+	"idle-cycles-frontend":    {Base, Cycles, InvalidMeasure},
+	"stalled-cycles-backend":  {Base, Cycles, InvalidMeasure},
+	"idle-cycles-backend":     {Base, Cycles, InvalidMeasure},
+	"instructions":            {Base, Events, InvalidMeasure},
+	"cache-references":        {Base, Events, InvalidMeasure},
+	"cache-misses":            {Base, Events, InvalidMeasure},
+	"branches":                {Base, Events, InvalidMeasure},
+	"branch-instructions":     {Base, Events, InvalidMeasure},
+	"branch-misses":           {Base, Events, InvalidMeasure},
+	// Software events
+	"cpu-clock":        {Nano, Time, InvalidMeasure},
+	"task-clock":       {Nano, Time, InvalidMeasure},
+	"duration_time":    {Nano, Time, InvalidMeasure},
+	"user_time":        {Nano, Time, InvalidMeasure},
+	"system_time":      {Nano, Time, InvalidMeasure},
+	"context-switches": {Base, Events, InvalidMeasure},
+	"cs":               {Base, Events, InvalidMeasure},
+	"cpu-migrations":   {Base, Events, InvalidMeasure},
+	"migrations":       {Base, Events, InvalidMeasure},
+	"page-faults":      {Base, Events, InvalidMeasure},
+	"faults":           {Base, Events, InvalidMeasure},
+	"minor-faults":     {Base, Events, InvalidMeasure},
+	"major-faults":     {Base, Events, InvalidMeasure},
+	"alignment-faults": {Base, Events, InvalidMeasure},
+	"emulation-faults": {Base, Events, InvalidMeasure},
+}
+
+// perfCacheEventRegex matches the hardware cache events like 'L1-dcache-load-misses' or
+// 'dTLB-stores'
+var perfCacheEventRegex = regexp.MustCompile(`^(L1-dcache|L1-icache|LLC|dTLB|iTLB|branch|node)-(loads|load-misses|stores|store-misses|prefetches|prefetch-misses)$`)
+
+// PerfEventUnit returns the unit of a perf_event counter like cycles for 'cycles',
+// nanoseconds for 'task-clock' and events for 'instructions', 'cache-misses' or
+// 'context-switches'. Event modifiers like ':u' or ':k' are ignored. Unknown events have no
+// unit (false).
+func PerfEventUnit(event string) (Unit, bool) {
+	event, _, _ = strings.Cut(event, ":")
+	if u, ok := PerfEventUnits[event]; ok {
+		return u.Unit(), true
+	}
+	if perfCacheEventRegex.MatchString(event) {
+		return newBaseUnit(Base, Events), true
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// NewPerfEventQuantity creates a quantity for a raw value of a perf_event counter. It returns
+// an error for unknown events.
+func NewPerfEventQuantity(event string, value float64) (Quantity, error) {
+	u, ok := PerfEventUnit(event)
+	if !ok {
+		return Quantity{Value: value, Unit: u}, fmt.Errorf("unknown unit for perf event '%s': %w", event, ErrInvalidMeasure)
+	}
+	return Quantity{Value: value, Unit: u}, nil
+}
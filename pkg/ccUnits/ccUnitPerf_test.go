@@ -0,0 +1,48 @@
+package ccunits
+
+import "testing"
+
+func TestPerfEventUnit(t *testing.T) {
+	for event, expected := range map[string]string{
+		"cycles":                "cyc",
+		"cycles:u":              "cyc",
+		"instructions":          "events",
+		"cache-misses":          "events",
+		"L1-dcache-load-misses": "events",
+		"dTLB-stores":           "events",
+		"context-switches":      "events",
+		"task-clock":            "ns",
+		"msr/tsc/":              "",
+		"L2-dcache-loads":       "",
+	} {
+		u, ok := PerfEventUnit(event)
+		if ok != (len(expected) > 0) || ok && u.Short() != expected {
+			t.Errorf("Expected unit '%s' for perf event '%s' but got '%s' (%v)", expected, event, u.Short(), ok)
+		}
+	}
+	q, err := NewPerfEventQuantity("task-clock", 2.5e9)
+	if err == nil {
+		q, err = q.ConvertTo(NewUnit("s"))
+	}
+	if err != nil || q.Value != 2.5 {
+		t.Errorf("Expected 2.5 s but got %s: %v", q.String(), err)
+	}
+	if q, err := NewPerfEventQuantity("unknown", 1); err == nil || q.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for unknown perf event but got '%s'", q.String())
+	}
+}
+
+func TestPerfEventUnitsParse(t *testing.T) {
+	// The short names like 'ns' of task-clock parse back to the same unit
+	for event, u := range PerfEventUnits {
+		if v := NewUnitValue(u.Short()); !v.Equal(u) {
+			t.Errorf("Expected '%s' of perf event '%s' to parse to itself but got '%s'", u.Short(), event, v.Short())
+		}
+	}
+	// Changing a returned unit must not change the table
+	u, _ := PerfEventUnit("task-clock")
+	u.SetPrefix(Milli)
+	if v, _ := PerfEventUnit("task-clock"); v.Short() != "ns" {
+		t.Errorf("Expected 'ns' for perf event 'task-clock' but got '%s'", v.Short())
+	}
+}
@@ -0,0 +1,156 @@
+package ccunits
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Prefix is the factor of a prefix like Kilo (1e3) or Kibi (1024). The built-in prefixes are
+// generated out of ccUnitBuiltin.yaml, see ccUnitBuiltin.go.
+type Prefix float64
+
+// PrefixUnitSplitRegexStr describes how a unit string is split into prefix and measure. The
+// split is done by splitPrefix without regular expressions.
+const PrefixUnitSplitRegexStr = `^([kKmMgGtTpPeEzZyYunµμ]?[i]?)(.*)`
+
+// prefixLetters are the letters of the prefixes in PrefixUnitSplitRegexStr. Micro is written
+// as 'u' or as micro sign, so unit strings like 'us' or 'µs' produced by Short() are parsed.
+const prefixLetters = "kKmMgGtTpPeEzZyYunµμ"
+
+// Symbols of the registered prefixes and prefix aliases, longest first
+var prefixSymbols []string = sortedPrefixSymbols()
+
+// splitPrefix splits a unit string into the prefix and the measure part. Registered prefix
+// symbols are checked first, otherwise the prefix is one of the letters of
+// PrefixUnitSplitRegexStr optionally followed by 'i' for binary prefixes. Like the '.' of
+// the regular expression, the measure part ends at a newline.
+func splitPrefix(unitStr string) (string, string) {
+	if i := strings.IndexByte(unitStr, '\n'); i >= 0 {
+		unitStr = unitStr[:i]
+	}
+	registryLock.RLock()
+	symbols := prefixSymbols
+	registryLock.RUnlock()
+	for _, s := range symbols {
+		if strings.HasPrefix(unitStr, s) {
+			return s, unitStr[len(s):]
+		}
+	}
+	i := 0
+	if r, n := utf8.DecodeRuneInString(unitStr); n > 0 && strings.ContainsRune(prefixLetters, r) {
+		i += n
+	}
+	if len(unitStr) > i && unitStr[i] == 'i' {
+		i++
+	}
+	return unitStr[:i], unitStr[i:]
+}
+
+// stackedPrefixes detects unit strings with two prefixes in front of the measure like 'kMB'
+// or 'GGHz'. It returns the two prefix strings if the string is no unit as a whole but the
+// part after the first prefix is a unit with prefix.
+func stackedPrefixes(unitStr string) (string, string, bool) {
+	first, rest := splitPrefix(unitStr)
+	if len(first) == 0 || NewPrefix(first) == InvalidPrefix {
+		return "", "", false
+	}
+	measureStr, _, _ := strings.Cut(rest, "/")
+	if NewMeasure(measureStr) != InvalidMeasure || NewMeasure(first+measureStr) != InvalidMeasure {
+		return "", "", false
+	}
+	second, measureStr := splitPrefix(measureStr)
+	if len(second) == 0 || NewPrefix(second) == InvalidPrefix || NewMeasure(measureStr) == InvalidMeasure {
+		return "", "", false
+	}
+	return first, second, true
+}
+
+// prefixedPrefixless detects unit strings with a prefix in front of a measure without
+// prefixes like 'kratio'. It returns the prefix string and the measure.
+func prefixedPrefixless(unitStr string) (string, Measure, bool) {
+	prefixStr, measureStr := splitPrefix(unitStr)
+	measureStr, _, _ = strings.Cut(measureStr, "/")
+	m := NewMeasure(measureStr)
+	if len(prefixStr) == 0 || NewPrefix(prefixStr) == InvalidPrefix || !rejectsPrefix(m) || NewMeasure(prefixStr+measureStr) != InvalidMeasure {
+		return "", InvalidMeasure, false
+	}
+	return prefixStr, m, true
+}
+
+// fractionalPrefix detects unit strings with the prefix Micro or Nano in front of a measure
+// that cannot be divided into fractions like 'ubytes'. It returns the prefix string and the
+// measure.
+func fractionalPrefix(unitStr string) (string, Measure, bool) {
+	prefixStr, measureStr := splitPrefix(unitStr)
+	measureStr, _, _ = strings.Cut(measureStr, "/")
+	m := NewMeasure(measureStr)
+	p := NewPrefix(prefixStr)
+	if len(prefixStr) == 0 || p == InvalidPrefix || p >= Milli || !isNonDividable(m) || !matchesWholeMeasure(measureStr, m) || NewMeasure(prefixStr+measureStr) != InvalidMeasure {
+		return "", InvalidMeasure, false
+	}
+	return prefixStr, m, true
+}
+
+// combinedPrefix returns the prefix with the product of the factors of two prefixes like
+// Giga for Kilo and Mega, e.g. to suggest a replacement for stacked prefixes
+func combinedPrefix(a Prefix, b Prefix) (Prefix, bool) {
+	p := Prefix(float64(a) * float64(b))
+	return p, knownPrefix(p)
+}
+
+type PrefixData struct {
+	Long  string
+	Short string
+	Regex string
+}
+
+// Different names and regex used for input and output
+var InvalidPrefixLong string = "Invalid"
+var InvalidPrefixShort string = "inval"
+
+// String returns the long string for the prefix like 'Kilo' or 'Mega'
+func (p *Prefix) String() string {
+	if data, ok := builtinPrefix(*p); ok {
+		return data.Long
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if data, ok := PrefixDataMap[*p]; ok {
+		return data.Long
+	}
+	return InvalidMeasureLong
+}
+
+// Prefix returns the short string for the prefix like 'K', 'M' or 'G'. Is is recommened to use Prefix() over String().
+func (p *Prefix) Prefix() string {
+	if data, ok := builtinPrefix(*p); ok {
+		return data.Short
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	if data, ok := PrefixDataMap[*p]; ok {
+		return data.Short
+	}
+	return InvalidMeasureShort
+}
+
+// NewPrefix creates a new prefix out of a string representing a unit like 'k', 'K', 'M' or 'G'.
+// It checks the registered aliases first, then the known spellings of the built-in prefixes (generated
+// by ccunits-gen) and uses regular expressions for matching afterwards.
+func NewPrefix(prefix string) Prefix {
+	registryLock.RLock()
+	p, ok := prefixAliases[prefix]
+	registryLock.RUnlock()
+	if ok {
+		return p
+	}
+	if p, ok := lookupPrefix(prefix); ok {
+		return p
+	}
+	for _, matcher := range getParserTables().prefixes {
+		if matcher.regex.MatchString(prefix) {
+			return matcher.prefix
+		}
+	}
+	return InvalidPrefix
+}
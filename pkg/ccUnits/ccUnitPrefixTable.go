@@ -0,0 +1,107 @@
+package ccunits
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// prefixFactor is the factor to convert values from one prefix to another. If the factor is
+// an integer (or the inverse of an integer), integer values are multiplied (or divided)
+// without the detour via float64.
+type prefixFactor struct {
+	factor float64
+	mul    int64 // Factor as integer if exact, otherwise 0
+	div    int64 // Inverse of the factor as integer if exact, otherwise 0
+}
+
+// prefixFactorTable contains the factors between all built-in prefixes indexed like
+// prefixTableEntries. It is built on first use.
+var prefixFactorTable [][]prefixFactor
+var prefixFactorTableOnce sync.Once
+
+// prefixTableIndex returns the index of a built-in prefix in the factor table
+func prefixTableIndex(p Prefix) (int, bool) {
+	for i, e := range prefixTableEntries {
+		if e.prefix == p {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// exactPrefix returns the exact value of a built-in prefix as rational number
+func exactPrefix(base int64, exponent int) *big.Rat {
+	if exponent < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(-exponent)), nil))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(exponent)), nil))
+}
+
+// newPrefixFactor creates the factor out of the exact ratio of two prefixes
+func newPrefixFactor(ratio *big.Rat) prefixFactor {
+	f, _ := ratio.Float64()
+	pf := prefixFactor{factor: f}
+	if ratio.IsInt() && ratio.Num().IsInt64() {
+		pf.mul = ratio.Num().Int64()
+	}
+	inv := new(big.Rat).Inv(ratio)
+	if inv.IsInt() && inv.Num().IsInt64() {
+		pf.div = inv.Num().Int64()
+	}
+	return pf
+}
+
+// buildPrefixFactorTable computes the factors between all built-in prefixes
+func buildPrefixFactorTable() {
+	exact := make([]*big.Rat, len(prefixTableEntries))
+	for i, e := range prefixTableEntries {
+		exact[i] = exactPrefix(e.base, e.exponent)
+	}
+	prefixFactorTable = make([][]prefixFactor, len(prefixTableEntries))
+	for i := range prefixTableEntries {
+		prefixFactorTable[i] = make([]prefixFactor, len(prefixTableEntries))
+		for j := range prefixTableEntries {
+			prefixFactorTable[i][j] = newPrefixFactor(new(big.Rat).Quo(exact[i], exact[j]))
+		}
+	}
+}
+
+// getPrefixFactor returns the factor to convert values from one prefix to another. The
+// factors between built-in prefixes are looked up, others are computed.
+func getPrefixFactor(in Prefix, out Prefix) prefixFactor {
+	i, inOk := prefixTableIndex(in)
+	j, outOk := prefixTableIndex(out)
+	if inOk && outOk {
+		prefixFactorTableOnce.Do(buildPrefixFactorTable)
+		return prefixFactorTable[i][j]
+	}
+	f := float64(in) / float64(out)
+	pf := prefixFactor{factor: f}
+	if f >= 1 && f < math.MaxInt64 && f == math.Trunc(f) {
+		pf.mul = int64(f)
+	}
+	return pf
+}
+
+// applyInt64 converts an integer value
+func (pf prefixFactor) applyInt64(v int64) int64 {
+	switch {
+	case pf.mul > 0:
+		return v * pf.mul
+	case pf.div > 0:
+		return v / pf.div
+	}
+	return int64(float64(v) * pf.factor)
+}
+
+// applyUint64 converts an unsigned integer value
+func (pf prefixFactor) applyUint64(v uint64) uint64 {
+	switch {
+	case pf.mul > 0:
+		return v * uint64(pf.mul)
+	case pf.div > 0:
+		return v / uint64(pf.div)
+	}
+	return uint64(float64(v) * pf.factor)
+}
@@ -0,0 +1,65 @@
+package ccunits
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// referenceSplitRegex returns the regular expression formerly used to split the prefix from
+// the measure including the registered prefix symbols
+func referenceSplitRegex() *regexp.Regexp {
+	if len(prefixSymbols) == 0 {
+		return regexp.MustCompile(PrefixUnitSplitRegexStr)
+	}
+	symbols := make([]string, 0, len(prefixSymbols))
+	for _, s := range prefixSymbols {
+		symbols = append(symbols, regexp.QuoteMeta(s))
+	}
+	return regexp.MustCompile(fmt.Sprintf(`^(%s|[kKmMgGtTpPeEzZyYunµμ]?[i]?)(.*)`, strings.Join(symbols, "|")))
+}
+
+func TestSplitPrefixCompatibility(t *testing.T) {
+	corpus := []string{
+		"", "B", "kB", "KB", "KiB", "kiB", "MB/s", "MiB/s", "mB", "mi", "i", "ii", "Mii",
+		"GFlops/s", "Flops", "%", "percent", "Mpercent", "degC", "°C", "degF", "RPM", "Hz", "kHz", "MHz",
+		"s", "ms", "us", "ns", "W", "mW", "J", "uJ", "cyc", "cycles", "requests", "packets", "events",
+		"Pevents", "EiB", "ZB", "YiB", "V", "mV", "A", "mA", "count", "foobar", "xyz", "kB\ns",
+		"k", "K/s", "/", "B/", "h", "hB", "octets", "kilobytes", "Kbit", "p", "e", "eB", "Ei", "Yi",
+		"µs", "μs", "nJ", "u", "µ", "ui", "min", "ns/op",
+	}
+	reference := referenceSplitRegex()
+	for _, s := range corpus {
+		matches := reference.FindStringSubmatch(s)
+		prefix, measure := splitPrefix(s)
+		if prefix != matches[1] || measure != matches[2] {
+			t.Errorf("Split of '%s' is ('%s', '%s') but expected ('%s', '%s')", s, prefix, measure, matches[1], matches[2])
+		}
+	}
+}
+
+func BenchmarkNewUnit(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewUnit("MiB/s")
+	}
+}
+
+func TestPrefixPrefixFactor(t *testing.T) {
+	if f := GetPrefixPrefixFactor(Nano, Giga)(1.0); f != 1e-18 {
+		t.Errorf("Expected factor 1e-18 from Nano to Giga but got %v", f)
+	}
+	if f := GetPrefixPrefixFactor(Giga, Nano)(1.0); f != 1e18 {
+		t.Errorf("Expected factor 1e18 from Giga to Nano but got %v", f)
+	}
+	if f := GetPrefixPrefixFactor(Gibi, Mega)(1.0); f != 1073.741824 {
+		t.Errorf("Expected factor 1073.741824 from Gibi to Mega but got %v", f)
+	}
+	// Integers are multiplied without the detour via float64
+	if v := GetPrefixPrefixFactor(Kilo, Base)(int64(9007199254740993)); v != int64(9007199254740993000) {
+		t.Errorf("Expected exact integer conversion but got %v", v)
+	}
+	if v := GetPrefixPrefixFactor(Mega, Giga)(uint64(1500)); v != uint64(1) {
+		t.Errorf("Expected 1 for 1500 M in G but got %v", v)
+	}
+}
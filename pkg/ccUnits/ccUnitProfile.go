@@ -0,0 +1,166 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnitProfileConfig describes the unit conventions of a site or cluster
+type UnitProfileConfig struct {
+	BinaryPrefixes bool     `json:"binary_prefixes,omitempty" yaml:"binary_prefixes,omitempty"` // Humanize with binary prefixes like 'GiB'
+	TargetUnits    []string `json:"target_units,omitempty" yaml:"target_units,omitempty"`       // Normalization targets like 'GiB' or 'degC' (see NewNormalizer())
+	LongNames      bool     `json:"long_names,omitempty" yaml:"long_names,omitempty"`           // Long unit names like 'Gigabytes'
+	Unicode        bool     `json:"unicode,omitempty" yaml:"unicode,omitempty"`                 // Symbols like 'µs' and '°C'
+	Separator      *string  `json:"separator,omitempty" yaml:"separator,omitempty"`             // Separator between value and unit (default ' ')
+	Precision      *int     `json:"precision,omitempty" yaml:"precision,omitempty"`             // Number of decimals (default: shortest representation)
+}
+
+// UnitProfile bundles the unit conventions of a site or cluster: the prefixes for
+// humanization, the normalization targets and the formatting. It is not changed after
+// creation and safe for concurrent use.
+type UnitProfile struct {
+	name       string
+	config     UnitProfileConfig
+	normalizer *Normalizer
+	formatter  Formatter
+}
+
+// NewUnitProfile creates a named profile. It returns an error for invalid target units.
+func NewUnitProfile(name string, config UnitProfileConfig) (*UnitProfile, error) {
+	n, err := NewNormalizer(config.TargetUnits...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid unit profile '%s': %w", name, err)
+	}
+	f := FormatterConfig{
+		BinaryPrefixes: config.BinaryPrefixes,
+		LongNames:      config.LongNames,
+		Unicode:        config.Unicode,
+		Separator:      config.Separator,
+		Precision:      config.Precision,
+	}
+	return &UnitProfile{
+		name:       name,
+		config:     config,
+		normalizer: n,
+		formatter:  NewFormatter(f.Options()...),
+	}, nil
+}
+
+// Name returns the name of the profile
+func (p *UnitProfile) Name() string {
+	return p.name
+}
+
+// Config returns the configuration of the profile
+func (p *UnitProfile) Config() UnitProfileConfig {
+	return p.config
+}
+
+// Normalizer returns the normalizer with the target units of the profile
+func (p *UnitProfile) Normalizer() *Normalizer {
+	return p.normalizer
+}
+
+// Formatter returns the formatter of the profile
+func (p *UnitProfile) Formatter() Formatter {
+	return p.formatter
+}
+
+// Normalize converts a quantity to the target unit of its kind (see Normalizer.Normalize())
+func (p *UnitProfile) Normalize(q Quantity) (Quantity, error) {
+	return p.normalizer.Normalize(q)
+}
+
+// Humanize converts the quantity to the prefix that fits best with the prefixes of the profile
+func (p *UnitProfile) Humanize(q Quantity) Quantity {
+	return q.Humanize(p.config.BinaryPrefixes)
+}
+
+// Format writes the quantity with the formatting of the profile
+func (p *UnitProfile) Format(q Quantity) string {
+	return p.formatter.Format(q)
+}
+
+// UnitProfiles is a set of named profiles, e.g. one per cluster, from which the profile is
+// selected at runtime. It is not changed after creation and safe for concurrent use.
+type UnitProfiles struct {
+	profiles map[string]*UnitProfile
+	fallback *UnitProfile
+}
+
+// defaultUnitProfile is used for names without profile: SI prefixes, no normalization
+// targets and the default formatting
+var defaultUnitProfile = &UnitProfile{
+	name:       "default",
+	normalizer: &Normalizer{targets: make(map[normalizerKey]UnitValue)},
+	formatter:  NewFormatter(),
+}
+
+// NewUnitProfiles creates the profiles out of their configurations by name like
+// 'clusterA' with binary prefixes and 'GiB' and 'clusterB' with SI prefixes and 'GB'. It
+// returns an error if a configuration is invalid.
+func NewUnitProfiles(configs map[string]UnitProfileConfig) (*UnitProfiles, error) {
+	ps := &UnitProfiles{
+		profiles: make(map[string]*UnitProfile, len(configs)),
+		fallback: defaultUnitProfile,
+	}
+	for name, config := range configs {
+		p, err := NewUnitProfile(name, config)
+		if err != nil {
+			return nil, err
+		}
+		ps.profiles[name] = p
+	}
+	return ps, nil
+}
+
+// LoadUnitProfiles reads the configurations of profiles by name from a JSON or YAML file
+// (selected by the file extension) and creates the profiles
+func LoadUnitProfiles(path string) (*UnitProfiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unit profiles: %w", err)
+	}
+	var configs map[string]UnitProfileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &configs)
+	default:
+		err = json.Unmarshal(data, &configs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode unit profiles in '%s': %w", path, err)
+	}
+	return NewUnitProfiles(configs)
+}
+
+// Lookup returns the profile with the name or false if there is none
+func (ps *UnitProfiles) Lookup(name string) (*UnitProfile, bool) {
+	p, ok := ps.profiles[name]
+	return p, ok
+}
+
+// Get returns the profile with the name. Names without profile get the default profile
+// with SI prefixes, no normalization targets and the default formatting.
+func (ps *UnitProfiles) Get(name string) *UnitProfile {
+	if p, ok := ps.profiles[name]; ok {
+		return p
+	}
+	return ps.fallback
+}
+
+// Names returns the sorted names of the profiles
+func (ps *UnitProfiles) Names() []string {
+	names := make([]string, 0, len(ps.profiles))
+	for name := range ps.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
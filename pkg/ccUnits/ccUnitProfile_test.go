@@ -0,0 +1,58 @@
+package ccunits
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnitProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	config := `
+clusterA:
+  binary_prefixes: true
+  target_units: [GiB, degC]
+  precision: 1
+clusterB:
+  target_units: [GB, degF]
+  separator: ""
+  precision: 2
+`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ps, err := LoadUnitProfiles(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if names := ps.Names(); len(names) != 2 || names[0] != "clusterA" || names[1] != "clusterB" {
+		t.Errorf("Expected profiles clusterA and clusterB but got %v", names)
+	}
+
+	q := NewQuantity(1.5e9, "B")
+	for _, c := range []struct {
+		profile    string
+		normalized string
+		humanized  string
+	}{
+		{"clusterA", "1.4 GiB", "1.4 GiB"},
+		{"clusterB", "1.50GB", "1.50GB"},
+		{"clusterC", "1.5e+09 B", "1.5 GB"}, // Default profile
+	} {
+		p := ps.Get(c.profile)
+		n, err := p.Normalize(q)
+		if err != nil || p.Format(n) != c.normalized {
+			t.Errorf("Expected '%s' for profile %s but got '%s': %v", c.normalized, c.profile, p.Format(n), err)
+		}
+		if s := p.Format(p.Humanize(q)); s != c.humanized {
+			t.Errorf("Expected humanized '%s' for profile %s but got '%s'", c.humanized, c.profile, s)
+		}
+	}
+	if _, ok := ps.Lookup("clusterC"); ok {
+		t.Errorf("Expected no profile clusterC")
+	}
+	if _, err := NewUnitProfiles(map[string]UnitProfileConfig{"x": {TargetUnits: []string{"GB", "GiB"}}}); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("Expected error for target units of the same kind but got %v", err)
+	}
+}
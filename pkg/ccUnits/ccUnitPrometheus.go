@@ -0,0 +1,317 @@
+package ccunits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Reader for the Prometheus text exposition format (and the '# UNIT' lines of OpenMetrics)
+// which derives the unit of the samples.
+// See https://prometheus.io/docs/instrumenting/exposition_formats/ and
+// https://prometheus.io/docs/practices/naming/#base-units
+
+// prometheusUnitSuffixes maps the Prometheus base unit suffixes of metric names to units
+var prometheusUnitSuffixes = []struct {
+	suffix string
+	unit   UnitValue
+}{
+	{"_seconds", UnitValue{Base, Time, InvalidMeasure}},
+	{"_bytes", UnitValue{Base, Bytes, InvalidMeasure}},
+	{"_joules", UnitValue{Base, Joule, InvalidMeasure}},
+	{"_watts", UnitValue{Base, Watt, InvalidMeasure}},
+	{"_celsius", UnitValue{Base, TemperatureC, InvalidMeasure}},
+	{"_volts", UnitValue{Base, Volt, InvalidMeasure}},
+	{"_amperes", UnitValue{Base, Ampere, InvalidMeasure}},
+	{"_hertz", UnitValue{Base, Frequency, InvalidMeasure}},
+	{"_percent", UnitValue{Base, Percentage, InvalidMeasure}},
+	{"_rpm", UnitValue{Base, Rotation, InvalidMeasure}},
+	{"_requests", UnitValue{Base, Requests, InvalidMeasure}},
+	{"_packets", UnitValue{Base, Packets, InvalidMeasure}},
+	{"_events", UnitValue{Base, Events, InvalidMeasure}},
+	{"_cycles", UnitValue{Base, Cycles, InvalidMeasure}},
+	{"_flops", UnitValue{Base, Flops, InvalidMeasure}},
+}
+
+// Suffixes of the series of histograms and summaries
+var prometheusCountSuffixes = []string{"_count", "_bucket"}
+var prometheusSeriesSuffixes = []string{"_sum", "_count", "_bucket", "_created", "_total"}
+
+// Unit in the HELP text like 'Memory used in bytes' or 'Temperature (degC)'. It is compiled
+// on first use.
+var prometheusHelpUnitRegex *regexp.Regexp
+var prometheusHelpUnitRegexOnce sync.Once
+
+// PrometheusSample is a sample of the Prometheus text exposition format
+type PrometheusSample struct {
+	Name      string            // Metric name
+	Labels    map[string]string // Labels of the sample
+	Quantity  Quantity          // Value and derived unit. The unit is invalid if it cannot be derived
+	Timestamp int64             // Timestamp in milliseconds (0 if not set)
+}
+
+// PrometheusReader reads samples from the Prometheus text exposition format
+type PrometheusReader struct {
+	scanner *bufio.Scanner
+	help    map[string]string
+	types   map[string]string
+	units   map[string]string
+	sample  PrometheusSample
+	err     error
+	line    int
+}
+
+// NewPrometheusReader creates a new reader for the Prometheus text exposition format
+func NewPrometheusReader(r io.Reader) *PrometheusReader {
+	return &PrometheusReader{
+		scanner: bufio.NewScanner(r),
+		help:    make(map[string]string),
+		types:   make(map[string]string),
+		units:   make(map[string]string),
+	}
+}
+
+// baseName returns the name of the metric family a series belongs to like 'req_duration_seconds'
+// for 'req_duration_seconds_bucket'. Only suffixes of families with a TYPE are removed.
+func (r *PrometheusReader) baseName(name string) (string, string) {
+	for _, s := range prometheusSeriesSuffixes {
+		if strings.HasSuffix(name, s) {
+			base := strings.TrimSuffix(name, s)
+			if _, ok := r.types[base]; ok {
+				return base, s
+			}
+		}
+	}
+	return name, ""
+}
+
+// deriveUnit derives the unit of a series from the UNIT line, the name suffixes and the HELP text
+func (r *PrometheusReader) deriveUnit(name string) Unit {
+	base, suffix := r.baseName(name)
+	for _, s := range prometheusCountSuffixes {
+		if suffix == s && r.types[base] != "counter" {
+			return newBaseUnit(Base, Count)
+		}
+	}
+	if u, ok := r.units[base]; ok {
+		if unit := NewUnit(u); unit.Valid() {
+			return unit
+		}
+	}
+	n := strings.TrimSuffix(base, "_total")
+	for _, s := range prometheusUnitSuffixes {
+		if strings.HasSuffix(n, s.suffix) {
+			return s.unit.Unit()
+		}
+	}
+	if help, ok := r.help[base]; ok {
+		prometheusHelpUnitRegexOnce.Do(func() {
+			prometheusHelpUnitRegex = regexp.MustCompile(`(?:\bin |\()([A-Za-z%°/]+)\)?\.?\s*$`)
+		})
+		if m := prometheusHelpUnitRegex.FindStringSubmatch(help); m != nil {
+			if unit, ok := prometheusHelpUnit(m[1]); ok {
+				return unit
+			}
+		}
+	}
+	return invalidUnitValue.Unit()
+}
+
+// prometheusHelpUnit parses the unit in a HELP text. Only the Prometheus base unit names
+// like 'bytes' and exact short names like 'degC' or 'MB/s' are accepted, because the
+// fuzzy unit parser would read words like 'flight' as a unit.
+func prometheusHelpUnit(word string) (Unit, bool) {
+	for _, s := range prometheusUnitSuffixes {
+		if strings.EqualFold(word, s.suffix[1:]) {
+			return s.unit.Unit(), true
+		}
+	}
+	unit := NewUnit(word)
+	if unit.Valid() && unit.Short() == word {
+		return unit, true
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// parseComment reads the HELP, TYPE and UNIT lines
+func (r *PrometheusReader) parseComment(line string) {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 4 {
+		return
+	}
+	switch fields[1] {
+	case "HELP":
+		r.help[fields[2]] = fields[3]
+	case "TYPE":
+		r.types[fields[2]] = strings.TrimSpace(fields[3])
+	case "UNIT":
+		r.units[fields[2]] = strings.TrimSpace(fields[3])
+	}
+}
+
+// labelScratchPool holds the buffers for unescaping label values, so reading many samples
+// does not allocate a new buffer for every escaped value
+var labelScratchPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 64)
+		return &b
+	},
+}
+
+// unescapeLabelValue reads a label value starting after the opening quote. It returns the
+// value and the index of the closing quote. Values without escape sequences are returned
+// without copying.
+func unescapeLabelValue(s string, i int) (string, int) {
+	start := i
+	for ; i < len(s) && s[i] != '"' && s[i] != '\\'; i++ {
+	}
+	if i >= len(s) || s[i] == '"' {
+		return s[start:i], i
+	}
+	scratch := labelScratchPool.Get().(*[]byte)
+	buf := append((*scratch)[:0], s[start:i]...)
+	for ; i < len(s) && s[i] != '"'; i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				buf = append(buf, '\n')
+			default:
+				buf = append(buf, s[i])
+			}
+			continue
+		}
+		buf = append(buf, s[i])
+	}
+	value := string(buf)
+	*scratch = buf
+	labelScratchPool.Put(scratch)
+	return value, i
+}
+
+// parseLabels parses the labels of a sample like '{method="post",code="200"}'. It returns
+// the labels and the rest of the line.
+func parseLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	i := 1
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return labels, "", fmt.Errorf("unterminated label set")
+		}
+		if s[i] == '}' {
+			return labels, s[i+1:], nil
+		}
+		eq := strings.IndexByte(s[i:], '=')
+		if eq < 0 || i+eq+1 >= len(s) || s[i+eq+1] != '"' {
+			return labels, "", fmt.Errorf("invalid label")
+		}
+		key := strings.TrimSpace(s[i : i+eq])
+		value, end := unescapeLabelValue(s, i+eq+2)
+		if end >= len(s) {
+			return labels, "", fmt.Errorf("unterminated label value")
+		}
+		labels[key] = value
+		i = end + 1
+	}
+}
+
+// nextField returns the next space separated field of a sample line and the rest of it
+func nextField(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t")
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], s[i:]
+	}
+	return s, ""
+}
+
+// parseSample parses a sample line like 'name{labels} value [timestamp]'
+func (r *PrometheusReader) parseSample(line string) (PrometheusSample, error) {
+	var err error
+	sample := PrometheusSample{
+		Labels: make(map[string]string),
+	}
+	rest := line
+	if i := strings.IndexAny(line, "{ \t"); i >= 0 {
+		sample.Name = line[:i]
+		rest = line[i:]
+	} else {
+		return sample, fmt.Errorf("missing value")
+	}
+	if strings.HasPrefix(rest, "{") {
+		sample.Labels, rest, err = parseLabels(rest)
+		if err != nil {
+			return sample, err
+		}
+	}
+	valueStr, rest := nextField(rest)
+	if len(valueStr) == 0 {
+		return sample, fmt.Errorf("missing value")
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return sample, fmt.Errorf("invalid value '%s'", valueStr)
+	}
+	if timestampStr, _ := nextField(rest); len(timestampStr) > 0 {
+		sample.Timestamp, err = strconv.ParseInt(timestampStr, 10, 64)
+		if err != nil {
+			return sample, fmt.Errorf("invalid timestamp '%s'", timestampStr)
+		}
+	}
+	sample.Quantity = Quantity{
+		Value: value,
+		Unit:  r.deriveUnit(sample.Name),
+	}
+	return sample, nil
+}
+
+// Next reads the next sample. It returns false at the end of the input or if an error
+// occurred. The error can be retrieved with Err().
+func (r *PrometheusReader) Next() bool {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			r.parseComment(line)
+			continue
+		}
+		sample, err := r.parseSample(line)
+		if err != nil {
+			r.err = fmt.Errorf("line %d: %w", r.line, err)
+			return false
+		}
+		r.sample = sample
+		return true
+	}
+	r.err = r.scanner.Err()
+	return false
+}
+
+// Sample returns the sample read by the last call of Next()
+func (r *PrometheusReader) Sample() PrometheusSample {
+	return r.sample
+}
+
+// Err returns the error that stopped reading
+func (r *PrometheusReader) Err() error {
+	return r.err
+}
+
+// ReadPrometheusSamples reads all samples from the Prometheus text exposition format
+func ReadPrometheusSamples(in io.Reader) ([]PrometheusSample, error) {
+	samples := make([]PrometheusSample, 0)
+	r := NewPrometheusReader(in)
+	for r.Next() {
+		samples = append(samples, r.Sample())
+	}
+	return samples, r.Err()
+}
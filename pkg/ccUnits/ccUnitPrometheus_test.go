@@ -0,0 +1,91 @@
+package ccunits
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadPrometheusSamples(t *testing.T) {
+	input := `# HELP node_memory_MemFree_bytes Memory information field MemFree_bytes.
+# TYPE node_memory_MemFree_bytes gauge
+node_memory_MemFree_bytes 1.2e+09
+# TYPE node_network_receive_bytes_total counter
+node_network_receive_bytes_total{device="eth0"} 4096 1700000000000
+# HELP http_request_duration_seconds A histogram of the request duration.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.5",path="/a \"b\""} 3
+http_request_duration_seconds_sum 1.5
+http_request_duration_seconds_count 3
+# HELP cpu_temp Temperature of the CPU in degC
+# TYPE cpu_temp gauge
+cpu_temp{cpu="0"} 45
+# HELP requests_in_flight Number of requests in flight
+requests_in_flight 2
+# TYPE gpu_power gauge
+# UNIT gpu_power watts
+gpu_power 120
+unknown_metric 1
+`
+	samples, err := ReadPrometheusSamples(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []struct {
+		name  string
+		short string
+	}{
+		{"node_memory_MemFree_bytes", "B"},
+		{"node_network_receive_bytes_total", "B"},
+		{"http_request_duration_seconds_bucket", "count"},
+		{"http_request_duration_seconds_sum", "s"},
+		{"http_request_duration_seconds_count", "count"},
+		{"cpu_temp", "degC"},
+		{"requests_in_flight", ""},
+		{"gpu_power", "W"},
+		{"unknown_metric", ""},
+	}
+	if len(samples) != len(expected) {
+		t.Fatalf("Expected %d samples but got %d", len(expected), len(samples))
+	}
+	for i, e := range expected {
+		s := samples[i]
+		if s.Name != e.name {
+			t.Errorf("Expected sample '%s' but got '%s'", e.name, s.Name)
+		}
+		if e.short == "" {
+			if s.Quantity.Unit.Valid() {
+				t.Errorf("Expected invalid unit for '%s' but got '%s'", s.Name, s.Quantity.Unit.Short())
+			}
+		} else if s.Quantity.Unit.Short() != e.short {
+			t.Errorf("Expected unit '%s' for '%s' but got '%s'", e.short, s.Name, s.Quantity.Unit.Short())
+		}
+	}
+	if samples[1].Labels["device"] != "eth0" || samples[1].Timestamp != 1700000000000 {
+		t.Errorf("Wrong labels or timestamp: %v %d", samples[1].Labels, samples[1].Timestamp)
+	}
+	if samples[2].Labels["path"] != `/a "b"` {
+		t.Errorf("Wrong escaped label value: %s", samples[2].Labels["path"])
+	}
+	// The units are not shared between samples
+	samples[0].Quantity.Unit.SetPrefix(Kilo)
+	if samples[1].Quantity.Unit.Short() != "B" {
+		t.Errorf("Changing the unit of one sample changed another one to '%s'", samples[1].Quantity.Unit.Short())
+	}
+	samples[len(samples)-1].Quantity.Unit.SetPrefix(Kilo)
+	if INVALID_UNIT.Short() != "Flops" {
+		t.Errorf("Changing an invalid unit changed INVALID_UNIT to '%s'", INVALID_UNIT.Short())
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, rest, err := parseLabels(`{path="C:\\tmp",msg="a \"b\"\nc",code="200"} 1`)
+	if err != nil {
+		t.Fatalf("Failed to parse labels: %v", err)
+	}
+	if labels["path"] != `C:\tmp` || labels["msg"] != "a \"b\"\nc" || labels["code"] != "200" || rest != " 1" {
+		t.Errorf("Unexpected labels %q and rest %q", labels, rest)
+	}
+	if _, _, err := parseLabels(`{code="200`); err == nil {
+		t.Errorf("Expected error for unterminated label value")
+	}
+}
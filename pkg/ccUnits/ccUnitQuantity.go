@@ -0,0 +1,184 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// Quantity is a value together with its unit like 12.5 GB/s or 300 W
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// NewQuantity creates a new quantity out of a value and a string representing a unit
+func NewQuantity(value float64, unitStr string) Quantity {
+	return Quantity{
+		Value: value,
+		Unit:  NewUnit(unitStr),
+	}
+}
+
+// Valid checks whether the unit of the quantity is valid
+func (q Quantity) Valid() bool {
+	return q.Unit != nil && q.Unit.Valid()
+}
+
+// String returns the value and the short string of the unit like '12.5 GB/s'
+func (q Quantity) String() string {
+	if q.Unit == nil {
+		return fmt.Sprintf("%g %s", q.Value, invalidUnitValue.Short())
+	}
+	return fmt.Sprintf("%g %s", q.Value, q.Unit.Short())
+}
+
+// ConvertTo converts the quantity to the given unit. It returns an error if the units
+// are not convertible or the converted value exceeds the range of float64.
+func (q Quantity) ConvertTo(out Unit) (Quantity, error) {
+	if !q.Valid() || out == nil || !out.Valid() {
+		return q, fmt.Errorf("invalid unit for conversion: %w", ErrInvalidMeasure)
+	}
+	conv, err := NewConverter(q.Unit, out)
+	if err != nil {
+		return q, err
+	}
+	return checkedQuantity(q, conv.ApplyFloat64(q.Value), out)
+}
+
+// checkedQuantity returns the converted quantity or an error if a finite value was
+// converted to an infinite one
+func checkedQuantity(q Quantity, value float64, out Unit) (Quantity, error) {
+	if math.IsInf(value, 0) && !math.IsInf(q.Value, 0) {
+		return q, fmt.Errorf("cannot convert %s to '%s': %w", q.String(), out.Short(), ErrOverflow)
+	}
+	return Quantity{Value: value, Unit: out}, nil
+}
+
+// ConvertToPrefix converts the quantity to the same unit with a different prefix
+func (q Quantity) ConvertToPrefix(out Prefix) (Quantity, error) {
+	if !q.Valid() {
+		return q, fmt.Errorf("invalid unit for conversion: %w", ErrInvalidMeasure)
+	}
+	_, outUnit := GetUnitPrefixFactor(q.Unit, out)
+	if !outUnit.Valid() {
+		return q, fmt.Errorf("invalid prefix for conversion: %w", ErrInvalidPrefix)
+	}
+	return checkedQuantity(q, NewPrefixConverter(q.Unit.GetPrefix(), out).ApplyFloat64(q.Value), outUnit)
+}
+
+// NormalizeToBase converts a quantity to its unit without prefix like '1.5 GB/s' to
+// '1.5e+09 B/s' or '250 ms' to '0.25 s', e.g. before values are persisted. Quantities with
+// invalid units are returned unchanged.
+func NormalizeToBase(q Quantity) Quantity {
+	if !q.Valid() || q.Unit.GetPrefix() == Base {
+		return q
+	}
+	out, err := q.ConvertToPrefix(Base)
+	if err != nil {
+		return q
+	}
+	return out
+}
+
+// ConvertPeriod converts between a frequency and its period like 2.4 GHz to 0.4167 ns cycle
+// time or 0.5 ms to 2 KHz, e.g. to translate cycle counts into wall-clock estimates. The
+// quantity must be a frequency or a time (including minutes, hours and days) and out a time
+// or a frequency respectively, both without unit denominator. A value of 0 has no period
+// and returns an error.
+func ConvertPeriod(q Quantity, out Unit) (Quantity, error) {
+	if !q.Valid() || out == nil || !out.Valid() {
+		return q, fmt.Errorf("invalid unit for conversion: %w", ErrInvalidMeasure)
+	}
+	in, o := ValueOf(q.Unit), ValueOf(out)
+	if in.divMeasure != InvalidMeasure || o.divMeasure != InvalidMeasure ||
+		!((in.measure == Frequency && isTimeMeasure(o.measure)) || (isTimeMeasure(in.measure) && o.measure == Frequency)) {
+		return q, fmt.Errorf("cannot convert '%s' to the period '%s': %w", in.Short(), o.Short(), ErrIncompatibleMeasure)
+	}
+	if q.Value == 0 {
+		return q, fmt.Errorf("no period for %s: %w", q.String(), ErrInvalidValue)
+	}
+	// The period is computed between Hz and seconds and converted to the unit of out
+	base, period := newBaseUnit(Base, Frequency), newBaseUnit(Base, Time)
+	if in.measure != Frequency {
+		base, period = period, base
+	}
+	b, err := q.ConvertTo(base)
+	if err != nil {
+		return q, err
+	}
+	return Quantity{Value: 1 / b.Value, Unit: period}.ConvertTo(out)
+}
+
+// EffectiveFrequency computes the achieved clock rate out of a number of cycles like from a
+// hardware counter and the time it took, like 4.8e9 cyc in 2 s to 2.4 GHz. The result is a
+// frequency with the prefix that fits best (see Humanize).
+func EffectiveFrequency(cycles Quantity, duration Quantity) (Quantity, error) {
+	if !cycles.Valid() || !duration.Valid() {
+		return cycles, fmt.Errorf("invalid unit for frequency: %w", ErrInvalidMeasure)
+	}
+	c, d := ValueOf(cycles.Unit), ValueOf(duration.Unit)
+	if c.measure != Cycles || c.divMeasure != InvalidMeasure {
+		return cycles, fmt.Errorf("invalid cycles unit '%s': %w", c.Short(), ErrIncompatibleMeasure)
+	}
+	if d.measure != Time || d.divMeasure != InvalidMeasure {
+		return cycles, fmt.Errorf("invalid time unit '%s': %w", d.Short(), ErrIncompatibleMeasure)
+	}
+	seconds := NewPrefixConverter(d.prefix, Base).ApplyFloat64(duration.Value)
+	if seconds <= 0 {
+		return cycles, fmt.Errorf("invalid duration %s: %w", duration.String(), ErrInvalidValue)
+	}
+	hz := NewPrefixConverter(c.prefix, Base).ApplyFloat64(cycles.Value) / seconds
+	return Quantity{Value: hz, Unit: newBaseUnit(Base, Frequency)}.Humanize(false), nil
+}
+
+// PercentOfPeak returns a measured quantity as percentage of a theoretical peak like
+// 450 GFlops/s of a machine peak of 3 TFlops/s as 15 %, e.g. for efficiency panels and
+// roofline coloring. The measured quantity must be convertible to the unit of the peak. It
+// returns an error for incompatible units and peaks which are not positive.
+func PercentOfPeak(measured Quantity, peak Quantity) (Quantity, error) {
+	percent := newBaseUnit(Base, Percentage)
+	if !measured.Valid() || !peak.Valid() {
+		return Quantity{Value: 0, Unit: percent}, fmt.Errorf("invalid unit for percentage of peak: %w", ErrInvalidMeasure)
+	}
+	if !(peak.Value > 0) || math.IsInf(peak.Value, 0) {
+		return Quantity{Value: 0, Unit: percent}, fmt.Errorf("invalid peak %s: %w", peak.String(), ErrInvalidValue)
+	}
+	m, err := measured.ConvertTo(peak.Unit)
+	if err != nil {
+		return Quantity{Value: 0, Unit: percent}, fmt.Errorf("cannot compare %s to the peak %s: %w", measured.String(), peak.String(), err)
+	}
+	return Quantity{Value: 100 * m.Value / peak.Value, Unit: percent}, nil
+}
+
+// Prefixes used by Humanize
+var humanizeDecimalPrefixes = []Prefix{Nano, Micro, Milli, Base, Kilo, Mega, Giga, Tera, Peta, Exa, Zetta, Yotta}
+var humanizeBinaryPrefixes = []Prefix{Base, Kibi, Mebi, Gibi, Tebi, Pebi, Exbi, Zebi, Yobi}
+
+// Humanize converts the quantity to the largest prefix which keeps the absolute value >= 1
+// like '123456789 B' to '123.456789 MB'. With binary set, the binary prefixes are used.
+// Measures that cannot be divided get no prefix below Base and percentages, ratios and logarithmic measures are not changed.
+func (q Quantity) Humanize(binary bool) Quantity {
+	if !q.Valid() || q.Value == 0 || isPrefixless(q.Unit.GetMeasure()) {
+		return q
+	}
+	prefixes := humanizeDecimalPrefixes
+	if binary {
+		prefixes = humanizeBinaryPrefixes
+	}
+	nonDividable := isNonDividable(q.Unit.GetMeasure())
+	scaled := math.Abs(q.Value) * float64(q.Unit.GetPrefix())
+	best := InvalidPrefix
+	for _, p := range prefixes {
+		if nonDividable && p < Base {
+			continue
+		}
+		if best == InvalidPrefix || scaled >= float64(p) {
+			best = p
+		}
+	}
+	out, err := q.ConvertToPrefix(best)
+	if err != nil {
+		return q
+	}
+	return out
+}
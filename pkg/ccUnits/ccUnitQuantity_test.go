@@ -0,0 +1,212 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNormalizeToBase(t *testing.T) {
+	for input, expected := range map[Quantity]string{
+		NewQuantity(1.5, "GB/s"): "1.5e+09 B/s",
+		NewQuantity(250, "ms"):   "0.25 s",
+		NewQuantity(3, "KiB"):    "3072 B",
+		NewQuantity(42, "degC"):  "42 degC",
+	} {
+		if out := NormalizeToBase(input).String(); out != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input.String(), out)
+		}
+	}
+	if q := NewQuantity(1, "xyz"); NormalizeToBase(q) != q {
+		t.Errorf("Expected unchanged quantity with invalid unit")
+	}
+	if s := (Quantity{Value: 5}).String(); s != "5 "+invalidUnitValue.Short() {
+		t.Errorf("Expected '5 %s' for a quantity without unit but got '%s'", invalidUnitValue.Short(), s)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for q, valid := range map[Quantity]bool{
+		NewQuantity(42, "%"):         true,
+		NewQuantity(300, "%"):        false,
+		NewQuantity(-300, "degC"):    false,
+		NewQuantity(-40, "degF"):     true,
+		NewQuantity(2.4, "GHz"):      true,
+		NewQuantity(-1, "Hz"):        false,
+		NewQuantity(-5, "W"):         true,
+		NewQuantity(300, "%/s"):      true,
+		NewQuantity(1, "xyz"):        false,
+		NewQuantity(math.NaN(), "W"): false,
+	} {
+		if err := Validate(q); (err == nil) != valid {
+			t.Errorf("Unexpected result of Validate() for '%s': %v", q.String(), err)
+		}
+	}
+}
+
+func TestCheckNonNegative(t *testing.T) {
+	q := NewQuantity(-12, "MB/s")
+	if Validate(q) == nil {
+		t.Errorf("Expected error for negative rate '%s'", q.String())
+	}
+	if _, err := CheckNonNegative(q, NegativeReject); err == nil {
+		t.Errorf("Expected error for negative rate '%s'", q.String())
+	}
+	if out, err := CheckNonNegative(q, NegativeClamp); err != nil || out.Value != 0 {
+		t.Errorf("Expected clamped value but got '%s': %v", out.String(), err)
+	}
+	if out, err := CheckNonNegative(NewQuantity(-5, "degC"), NegativeReject); err != nil || out.Value != -5 {
+		t.Errorf("Expected unchanged temperature but got '%s': %v", out.String(), err)
+	}
+
+	var rule MetricNormalizerRule
+	if err := json.Unmarshal([]byte(`{"target_unit": "GB/s", "negative": "clamp"}`), &rule); err != nil {
+		t.Fatalf("Failed to decode rule: %v", err)
+	}
+	n, err := NewMetricNormalizer(map[string]MetricNormalizerRule{"mem_bw": rule})
+	if err != nil {
+		t.Fatalf("Failed to create normalizer: %v", err)
+	}
+	if v, _, err := n.Normalize("mem_bw", -1200, "MB/s"); err != nil || v != 0 {
+		t.Errorf("Expected clamped value but got %v: %v", v, err)
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	n, err := NewMetricNormalizer(map[string]MetricNormalizerRule{
+		"mem_used": {TargetUnit: "B", Negative: NegativeClamp},
+		"mem_bw":   {TargetUnit: "GB/s"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create normalizer: %v", err)
+	}
+	for _, c := range []struct {
+		name     string
+		value    interface{}
+		unit     string
+		expected interface{}
+	}{
+		{"mem_used", int32(3), "kB", int32(3000)},
+		{"mem_used", int32(3), "GB", 3e9},
+		{"mem_used", uint(2), "KiB", uint(2048)},
+		{"mem_used", uint64(20), "EiB", 20 * math.Pow(2, 60)},
+		{"mem_used", int64(-3), "kB", 0.0},
+		{"mem_used", float32(1.5), "kB", 1500.0},
+		{"mem_bw", int64(-2500), "MB/s", -2.5},
+		{"mem_bw", int64(2), "TB/s", int64(2000)},
+	} {
+		if v, _, err := n.NormalizeValue(c.name, c.value, c.unit); err != nil || v != c.expected {
+			t.Errorf("Expected %v (%T) for %v %s but got %v (%T): %v", c.expected, c.expected, c.value, c.unit, v, v, err)
+		}
+	}
+	if _, _, err := n.NormalizeValue("mem_used", "3", "kB"); err == nil {
+		t.Errorf("Expected error for non-numeric value")
+	}
+	if _, u, err := n.NormalizeValue("mem_bw", 1, ""); err == nil || u.Valid() {
+		t.Errorf("Expected error and invalid unit for missing unit but got '%s'", u.Short())
+	}
+	// Changing a returned unit must not change the rules
+	_, u, _ := n.NormalizeValue("mem_used", int32(3), "kB")
+	u.SetPrefix(Giga)
+	if _, v, _ := n.Normalize("mem_used", 3, "kB"); v.Short() != "B" {
+		t.Errorf("Expected target unit 'B' but got '%s'", v.Short())
+	}
+}
+
+func TestConvertPeriod(t *testing.T) {
+	for _, c := range []struct {
+		q        Quantity
+		out      Unit
+		expected float64
+	}{
+		{NewQuantity(2.4, "GHz"), NewUnitFromParts(Nano, Time, InvalidMeasure), 0.41666667},
+		{NewQuantity(0.5, "ms"), NewUnit("KHz"), 2},
+		{NewQuantity(1, "s"), NewUnit("Hz"), 1},
+		{NewQuantity(2, "min"), NewUnit("mHz"), 8.3333333},
+	} {
+		q, err := ConvertPeriod(c.q, c.out)
+		if err != nil || math.Abs(q.Value-c.expected) > 1e-6 || !q.Unit.Equal(c.out) {
+			t.Errorf("Expected %v %s for %s but got %s: %v", c.expected, c.out.Short(), c.q.String(), q.String(), err)
+		}
+	}
+	for _, c := range []struct {
+		q   Quantity
+		out string
+	}{
+		{NewQuantity(1, "GHz"), "MHz"},
+		{NewQuantity(1, "MB/s"), "s"},
+		{NewQuantity(0, "GHz"), "ns"},
+	} {
+		if _, err := ConvertPeriod(c.q, NewUnit(c.out)); err == nil {
+			t.Errorf("Expected error for converting %s to '%s'", c.q.String(), c.out)
+		}
+	}
+}
+
+func TestEffectiveFrequency(t *testing.T) {
+	q, err := EffectiveFrequency(NewQuantity(4.8, "Gcyc"), NewQuantity(2000, "ms"))
+	if err != nil || math.Abs(q.Value-2.4) > 1e-12 || q.Unit.Short() != "GHz" {
+		t.Errorf("Expected 2.4 GHz but got %s: %v", q.String(), err)
+	}
+	if _, err := EffectiveFrequency(NewQuantity(1, "cyc"), NewQuantity(0, "s")); err == nil {
+		t.Errorf("Expected error for duration 0")
+	}
+	if _, err := EffectiveFrequency(NewQuantity(1, "B"), NewQuantity(1, "s")); err == nil {
+		t.Errorf("Expected error for cycles in 'B'")
+	}
+}
+
+func TestPercentOfPeak(t *testing.T) {
+	q, err := PercentOfPeak(NewQuantity(450, "GFlops/s"), NewQuantity(3, "TFlops/s"))
+	if err != nil || math.Abs(q.Value-15) > 1e-12 || q.Unit.Short() != "%" {
+		t.Errorf("Expected 15 %% but got %s: %v", q.String(), err)
+	}
+	if _, err := PercentOfPeak(NewQuantity(1, "GB/s"), NewQuantity(1, "GFlops/s")); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+	if _, err := PercentOfPeak(NewQuantity(1, "W"), NewQuantity(0, "W")); err == nil {
+		t.Errorf("Expected error for peak 0")
+	}
+}
+
+func TestPages(t *testing.T) {
+	q, err := PagesToBytes(NewQuantity(1024, "count"), PageSize2MiB, NewUnit("GiB"))
+	if err != nil || q.Value != 2 || q.Unit.Short() != "GiB" {
+		t.Errorf("Expected 2 GiB but got %s: %v", q.String(), err)
+	}
+	q, err = BytesToPages(NewQuantity(8, "MiB/s"), PageSize4KiB)
+	if err != nil || q.Value != 2048 || q.Unit.Short() != "count/s" {
+		t.Errorf("Expected 2048 count/s but got %s: %v", q.String(), err)
+	}
+	if _, err := PagesToBytes(NewQuantity(1, "count/s"), PageSize4KiB, NewUnit("MB")); err == nil {
+		t.Errorf("Expected error for different unit denominators")
+	}
+	if _, err := BytesToPages(NewQuantity(1, "W"), PageSize4KiB); err == nil {
+		t.Errorf("Expected error for converting 'W' to pages")
+	}
+}
+
+func TestPercentConvention(t *testing.T) {
+	if q, err := NewQuantity(0.25, "ratio").ConvertTo(NewUnit("%")); err != nil || q.Value != 25 {
+		t.Errorf("Expected 0.25 ratio = 25 %% but got %v: %v", q.Value, err)
+	}
+	var rule MetricNormalizerRule
+	if err := json.Unmarshal([]byte(`{"target_unit": "%", "convention": "ratio"}`), &rule); err != nil {
+		t.Fatalf("Failed to decode rule: %v", err)
+	}
+	n, err := NewMetricNormalizer(map[string]MetricNormalizerRule{"cpu_util": rule})
+	if err != nil {
+		t.Fatalf("Failed to create normalizer: %v", err)
+	}
+	// The source reports ratios with the unit '%' or without unit
+	for _, unitStr := range []string{"%", "ratio", ""} {
+		if v, u, err := n.Normalize("cpu_util", 0.5, unitStr); err != nil || v != 50 || u.Short() != "%" {
+			t.Errorf("Expected 50 %% for unit '%s' but got %v %s: %v", unitStr, v, u.Short(), err)
+		}
+	}
+	rule.TargetUnit = "W"
+	if _, err := NewMetricNormalizer(map[string]MetricNormalizerRule{"power": rule}); err == nil {
+		t.Errorf("Expected error for percent convention with target unit 'W'")
+	}
+}
@@ -0,0 +1,131 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Range is a closed interval of quantities with a shared unit like 20 to 85 degC, e.g. for
+// metric bounds, expected operating ranges and alert bands
+type Range struct {
+	Min Quantity
+	Max Quantity // In the unit of Min
+}
+
+// NewRange creates the range between two quantities. The maximum is converted to the unit of
+// the minimum. It returns an error for invalid or incompatible units and a minimum larger
+// than the maximum.
+func NewRange(min Quantity, max Quantity) (Range, error) {
+	if !min.Valid() || !max.Valid() {
+		return Range{}, fmt.Errorf("invalid unit for range: %w", ErrInvalidMeasure)
+	}
+	m, err := max.ConvertTo(min.Unit)
+	if err != nil {
+		return Range{}, fmt.Errorf("cannot create range from %s to %s: %w", min.String(), max.String(), err)
+	}
+	if math.IsNaN(min.Value) || math.IsNaN(m.Value) || min.Value > m.Value {
+		return Range{}, fmt.Errorf("invalid range from %s to %s: %w", min.String(), max.String(), ErrInvalidValue)
+	}
+	return Range{Min: min, Max: m}, nil
+}
+
+// Valid checks whether the range has a valid unit
+func (r Range) Valid() bool {
+	return r.Min.Valid()
+}
+
+// Unit returns the shared unit of the range
+func (r Range) Unit() Unit {
+	if !r.Valid() {
+		return invalidUnitValue.Unit()
+	}
+	return r.Min.Unit
+}
+
+// String returns the range like '20..85 degC'
+func (r Range) String() string {
+	return fmt.Sprintf("%g..%s", r.Min.Value, r.Max.String())
+}
+
+// ConvertTo converts both bounds of the range to another unit
+func (r Range) ConvertTo(out Unit) (Range, error) {
+	min, err := r.Min.ConvertTo(out)
+	if err != nil {
+		return r, err
+	}
+	max, err := r.Max.ConvertTo(out)
+	if err != nil {
+		return r, err
+	}
+	return Range{Min: min, Max: max}, nil
+}
+
+// Contains checks whether a quantity lies within the range including the bounds. It returns
+// an error if the quantity cannot be converted to the unit of the range.
+func (r Range) Contains(q Quantity) (bool, error) {
+	if !r.Valid() {
+		return false, fmt.Errorf("invalid unit for range: %w", ErrInvalidMeasure)
+	}
+	v, err := q.ConvertTo(r.Min.Unit)
+	if err != nil {
+		return false, err
+	}
+	return v.Value >= r.Min.Value && v.Value <= r.Max.Value, nil
+}
+
+// Intersect returns the intersection of two ranges in the unit of r, like 20..85 degC and
+// 50..100 degC to 50..85 degC. It returns false if the ranges do not overlap and an error if
+// their units cannot be converted into each other.
+func (r Range) Intersect(o Range) (Range, bool, error) {
+	if !r.Valid() {
+		return r, false, fmt.Errorf("invalid unit for range: %w", ErrInvalidMeasure)
+	}
+	c, err := o.ConvertTo(r.Min.Unit)
+	if err != nil {
+		return r, false, err
+	}
+	min, max := math.Max(r.Min.Value, c.Min.Value), math.Min(r.Max.Value, c.Max.Value)
+	if min > max {
+		return Range{}, false, nil
+	}
+	return Range{Min: Quantity{Value: min, Unit: r.Min.Unit}, Max: Quantity{Value: max, Unit: r.Min.Unit}}, true, nil
+}
+
+// ParseRange parses a range like '20..85 degC' or '500 MB/s..2 GB/s' (the maximum is
+// converted to the unit of the minimum). The unit of the maximum applies to a minimum without
+// unit.
+func ParseRange(s string) (Range, error) {
+	minStr, maxStr, ok := strings.Cut(s, "..")
+	if !ok {
+		return Range{}, fmt.Errorf("invalid range '%s': %w", s, ErrInvalidValue)
+	}
+	max, err := ParseQuantity(maxStr)
+	if err != nil {
+		return Range{}, err
+	}
+	minStr = strings.TrimSpace(minStr)
+	if n := numberPrefixLen(minStr); n > 0 && n == len(minStr) {
+		minStr += " " + max.Unit.Short()
+	}
+	min, err := ParseQuantity(minStr)
+	if err != nil {
+		return Range{}, err
+	}
+	return NewRange(min, max)
+}
+
+// MarshalText writes the range like String(), e.g. '20..85 degC'
+func (r Range) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText parses a range with ParseRange()
+func (r *Range) UnmarshalText(text []byte) error {
+	v, err := ParseRange(string(text))
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
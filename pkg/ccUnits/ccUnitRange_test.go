@@ -0,0 +1,84 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	r, err := NewRange(NewQuantity(500, "MB/s"), NewQuantity(2, "GB/s"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.String() != "500..2000 MB/s" {
+		t.Errorf("Expected '500..2000 MB/s' but got '%s'", r.String())
+	}
+	for q, e := range map[Quantity]bool{
+		NewQuantity(1, "GB/s"):   true,
+		NewQuantity(500, "MB/s"): true,
+		NewQuantity(3, "GB/s"):   false,
+		NewQuantity(0.4, "GB/s"): false,
+	} {
+		if ok, err := r.Contains(q); err != nil || ok != e {
+			t.Errorf("Expected %v for %s but got %v (%v)", e, q.String(), ok, err)
+		}
+	}
+	if _, err := r.Contains(NewQuantity(1, "W")); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+	c, err := r.ConvertTo(NewUnit("GB/s"))
+	if err != nil || c.String() != "0.5..2 GB/s" {
+		t.Errorf("Expected '0.5..2 GB/s' but got '%s' (%v)", c.String(), err)
+	}
+
+	if _, err := NewRange(NewQuantity(2, "GB/s"), NewQuantity(1, "GB/s")); err == nil {
+		t.Errorf("Expected error for minimum larger than maximum")
+	}
+	if _, err := NewRange(NewQuantity(2, "GB/s"), NewQuantity(1, "W")); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected ErrIncompatibleMeasure but got %v", err)
+	}
+	if u := (Range{}).Unit(); u.Valid() {
+		t.Errorf("Expected invalid unit for empty range but got '%s'", u.Short())
+	}
+}
+
+func TestRangeIntersect(t *testing.T) {
+	a, _ := ParseRange("20..85 degC")
+	b, _ := ParseRange("50 degC..100 degC")
+	i, ok, err := a.Intersect(b)
+	if err != nil || !ok || i.String() != "50..85 degC" {
+		t.Errorf("Expected '50..85 degC' but got '%s' (%v, %v)", i.String(), ok, err)
+	}
+	f, err := ParseRange("32..122 degF")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	i, ok, err = b.Intersect(f)
+	if err != nil || !ok || math.Abs(i.Max.Value-50) > 1e-9 || math.Abs(i.Min.Value-50) > 1e-9 {
+		t.Errorf("Expected '50..50 degC' but got '%s' (%v, %v)", i.String(), ok, err)
+	}
+	c, _ := ParseRange("0..10 degC")
+	if _, ok, err := b.Intersect(c); ok || err != nil {
+		t.Errorf("Expected no intersection (%v)", err)
+	}
+}
+
+func TestRangeText(t *testing.T) {
+	var config struct {
+		Band Range `json:"band"`
+	}
+	if err := json.Unmarshal([]byte(`{"band": "100..300 W"}`), &config); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := json.Marshal(config)
+	if err != nil || string(data) != `{"band":"100..300 W"}` {
+		t.Errorf("Unexpected encoding '%s' (%v)", data, err)
+	}
+	for _, s := range []string{"100 W", "100..xyz", "a..3 W"} {
+		if _, err := ParseRange(s); err == nil {
+			t.Errorf("Expected error for '%s'", s)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// RaplCounter describes an energy counter of the running average power limit (RAPL)
+// interface, which counts in fixed energy units and wraps around
+type RaplCounter struct {
+	EnergyUnit float64 // Energy of one count in Joule like 1e-6 for microjoules
+	Range      uint64  // Number of values before the counter wraps around to 0 (0 for no wraparound)
+}
+
+// NewPowercapCounter creates the counter of a powercap zone (/sys/devices/virtual/powercap)
+// with the energy in microjoules in 'energy_uj' and its maximum in 'max_energy_range_uj'
+func NewPowercapCounter(maxEnergyRangeUJ uint64) RaplCounter {
+	return RaplCounter{
+		EnergyUnit: 1e-6,
+		Range:      maxEnergyRangeUJ + 1,
+	}
+}
+
+// NewRaplMsrCounter creates the counter of the 32 bit energy status MSRs like
+// MSR_PKG_ENERGY_STATUS. The energy unit is 1/2^ESU Joule with the energy status units (ESU)
+// in bits 12:8 of MSR_RAPL_POWER_UNIT (AMD: MSR_RAPL_PWR_UNIT). Model-specific units like the
+// fixed DRAM energy unit of some server processors are set directly in a RaplCounter.
+func NewRaplMsrCounter(powerUnitMsr uint64) RaplCounter {
+	esu := (powerUnitMsr >> 8) & 0x1f
+	return RaplCounter{
+		EnergyUnit: math.Ldexp(1, -int(esu)),
+		Range:      1 << 32,
+	}
+}
+
+// diff returns the number of counts between two readings. A single wraparound of the
+// counter between the readings is handled.
+func (c RaplCounter) diff(previous, current uint64) (uint64, error) {
+	if current >= previous {
+		return current - previous, nil
+	}
+	if c.Range == 0 || previous >= c.Range {
+		return 0, fmt.Errorf("RAPL energy counter decreased from %d to %d: %w", previous, current, ErrInvalidValue)
+	}
+	return c.Range - previous + current, nil
+}
+
+// Energy returns the energy consumed between two raw readings of the counter in Joule
+func (c RaplCounter) Energy(previous, current uint64) (Quantity, error) {
+	d, err := c.diff(previous, current)
+	if err != nil {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, err
+	}
+	return Quantity{
+		Value: float64(d) * c.EnergyUnit,
+		Unit:  newBaseUnit(Base, Joule),
+	}, nil
+}
+
+// Power returns the average power between two raw readings of the counter taken interval
+// apart in Watt
+func (c RaplCounter) Power(previous, current uint64, interval time.Duration) (Quantity, error) {
+	if interval <= 0 {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid interval %v for RAPL energy counter: %w", interval, ErrInvalidValue)
+	}
+	e, err := c.Energy(previous, current)
+	if err != nil {
+		return e, err
+	}
+	return Quantity{
+		Value: e.Value / interval.Seconds(),
+		Unit:  newBaseUnit(Base, Watt),
+	}, nil
+}
@@ -0,0 +1,38 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRaplCounter(t *testing.T) {
+	powercap := NewPowercapCounter(262143328850)
+	msr := NewRaplMsrCounter(0xa0e03) // ESU 14: 61 uJ
+	for _, c := range []struct {
+		counter  RaplCounter
+		previous uint64
+		current  uint64
+		expected float64
+	}{
+		{powercap, 1000000, 151000000, 150},
+		{powercap, 262143328851 - 50000000, 100000000, 150}, // Wraparound
+		{msr, 1 << 20, 1<<20 + 3<<14, 3},
+		{msr, 1<<32 - 1<<14, 1 << 15, 3}, // Wraparound
+	} {
+		q, err := c.counter.Power(c.previous, c.current, 2*time.Second)
+		if err != nil || math.Abs(q.Value-c.expected/2) > 1e-9 || q.Unit.Short() != "W" {
+			t.Errorf("Expected %v W for %d -> %d but got %s: %v", c.expected/2, c.previous, c.current, q.String(), err)
+		}
+		e, err := c.counter.Energy(c.previous, c.current)
+		if err != nil || math.Abs(e.Value-c.expected) > 1e-9 || e.Unit.Short() != "J" {
+			t.Errorf("Expected %v J for %d -> %d but got %s: %v", c.expected, c.previous, c.current, e.String(), err)
+		}
+	}
+	if e, err := (RaplCounter{EnergyUnit: 1e-6}).Energy(10, 5); err == nil || e.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for decreasing counter without wraparound but got '%s'", e.String())
+	}
+	if p, err := powercap.Power(1, 2, 0); err == nil || p.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for invalid interval but got '%s'", p.String())
+	}
+}
@@ -0,0 +1,93 @@
+package ccunits
+
+import "strings"
+
+// Redfish uses UCUM (Unified Code for Units of Measure) case-sensitive codes for the units
+// in sensors, metric definitions and metric reports like 'By', 'Cel', 'kW' or 'MiBy/s'.
+// See https://ucum.org/ucum
+
+// redfishMeasureMap maps the UCUM atoms used by Redfish to measures
+var redfishMeasureMap map[string]Measure = map[string]Measure{
+	"By":         Bytes,
+	"Cel":        TemperatureC,
+	"[degF]":     TemperatureF,
+	"%":          Percentage,
+	"Hz":         Frequency,
+	"s":          Time,
+	"W":          Watt,
+	"J":          Joule,
+	"V":          Volt,
+	"A":          Ampere,
+	"{rev}/min":  Rotation,
+	"{RPM}":      Rotation,
+	"RPM":        Rotation,
+	"{packet}":   Packets,
+	"{packets}":  Packets,
+	"{request}":  Requests,
+	"{requests}": Requests,
+	"{event}":    Events,
+	"{events}":   Events,
+	"{cycle}":    Cycles,
+	"{cycles}":   Cycles,
+}
+
+// redfishPrefixList maps the UCUM prefixes to prefixes. Binary prefixes are listed first
+// because they have to be checked before the decimal ones.
+var redfishPrefixList = []struct {
+	symbol string
+	prefix Prefix
+}{
+	{"Ki", Kibi}, {"Mi", Mebi}, {"Gi", Gibi}, {"Ti", Tebi}, {"Pi", Pebi}, {"Ei", Exbi},
+	{"k", Kilo}, {"M", Mega}, {"G", Giga}, {"T", Tera}, {"P", Peta}, {"E", Exa}, {"Z", Zetta}, {"Y", Yotta},
+	{"m", Milli}, {"u", Micro}, {"n", Nano},
+}
+
+// parseRedfishAtom parses a single UCUM unit atom with optional prefix like 'kW' or 'MiBy'.
+// Annotations in curly braces following a unit atom like 'By{transferred}' are ignored.
+func parseRedfishAtom(atom string) (Prefix, Measure) {
+	if m, ok := redfishMeasureMap[atom]; ok {
+		return Base, m
+	}
+	if i := strings.Index(atom, "{"); i > 0 && strings.HasSuffix(atom, "}") {
+		atom = atom[:i]
+		if m, ok := redfishMeasureMap[atom]; ok {
+			return Base, m
+		}
+	}
+	for _, p := range redfishPrefixList {
+		if strings.HasPrefix(atom, p.symbol) {
+			if m, ok := redfishMeasureMap[atom[len(p.symbol):]]; ok {
+				return p.prefix, m
+			}
+		}
+	}
+	return InvalidPrefix, InvalidMeasure
+}
+
+// NewRedfishUnit creates a new unit out of a unit string used by Redfish like 'By', 'Cel',
+// 'kW' or 'MiBy/s'. The UCUM notation is checked first. If it is not a known UCUM unit,
+// the unit string is parsed with NewUnit.
+func NewRedfishUnit(unitStr string) Unit {
+	s := strings.TrimSpace(unitStr)
+	if m, ok := redfishMeasureMap[s]; ok {
+		return m.BaseUnit()
+	}
+	num, den, hasDen := strings.Cut(s, "/")
+	p, m := parseRedfishAtom(num)
+	div := InvalidMeasure
+	if hasDen {
+		var dp Prefix
+		dp, div = parseRedfishAtom(den)
+		// The unit denominator supports only the Base prefix
+		if dp != Base {
+			div = InvalidMeasure
+		}
+	}
+	if p == InvalidPrefix || m == InvalidMeasure || (hasDen && div == InvalidMeasure) {
+		return NewUnit(unitStr)
+	}
+	if isNonDividable(m) && p < Base {
+		return NewUnit(unitStr)
+	}
+	return newUnit(p, m, div)
+}
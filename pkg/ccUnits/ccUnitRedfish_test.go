@@ -0,0 +1,41 @@
+package ccunits
+
+import (
+	"testing"
+)
+
+func TestNewRedfishUnit(t *testing.T) {
+	redfishTests := map[string]string{
+		"By":        "B",
+		"KiBy":      "KiB",
+		"MiBy/s":    "MiB/s",
+		"Cel":       "degC",
+		"[degF]":    "degF",
+		"kW":        "KW",
+		"mA":        "mA",
+		"V":         "V",
+		"ms":        "ms",
+		"%":         "%",
+		"{rev}/min": "RPM",
+		"By{total}": "B",
+		"Watts":     "W",
+		"MHz":       "MHz",
+	}
+
+	for input, expected := range redfishTests {
+		u := NewRedfishUnit(input)
+		if !u.Valid() {
+			t.Errorf("Redfish unit '%s' should be valid", input)
+			continue
+		}
+		if u.Short() != expected {
+			t.Errorf("Redfish unit '%s': expected '%s' but got '%s'", input, expected, u.Short())
+		}
+	}
+
+	for _, input := range []string{"mBy", "min", "By/ms"} {
+		if u := NewRedfishUnit(input); u.Valid() && u.GetPrefix() < Base {
+			t.Errorf("Redfish unit '%s' should not result in '%s'", input, u.Short())
+		}
+	}
+}
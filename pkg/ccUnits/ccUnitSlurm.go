@@ -0,0 +1,122 @@
+package ccunits
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Slurm uses binary multipliers for the size suffixes in TRES strings. A size without
+// suffix is in MiB.
+var slurmSizeSuffixes map[byte]Prefix = map[byte]Prefix{
+	'K': Kibi,
+	'M': Mebi,
+	'G': Gibi,
+	'T': Tebi,
+	'P': Pebi,
+}
+
+// SlurmTresUnits contains the units of the Slurm trackable resources (TRES) types. Full types
+// with name like 'gres/gpumem' are looked up before the type without name like 'fs' of
+// 'fs/disk'. TRES types not contained are counted resources like 'cpu', 'node', 'billing'
+// or 'gres/gpu'.
+var SlurmTresUnits map[string]UnitValue = map[string]UnitValue{
+	"mem":         {Mebi, Bytes, InvalidMeasure},
+	"vmem":        {Mebi, Bytes, InvalidMeasure},
+	"fs":          {Mebi, Bytes, InvalidMeasure},
+	"bb":          {Mebi, Bytes, InvalidMeasure},
+	"gres/gpumem": {Mebi, Bytes, InvalidMeasure},
+	"energy":      {Base, Joule, InvalidMeasure},
+	"pages":       {Base, Count, InvalidMeasure},
+}
+
+// parseSlurmDuration parses a Slurm duration like '2-04:30:00', '04:30:00' or '30:00'
+// (the format used for the cpu time in TRES usage strings) into seconds
+func parseSlurmDuration(value string) (float64, error) {
+	days := 0.0
+	d, rest, hasDays := strings.Cut(value, "-")
+	if hasDays {
+		v, err := strconv.ParseFloat(d, 64)
+		if err != nil {
+			return 0, err
+		}
+		days = v
+		value = rest
+	}
+	fields := strings.Split(value, ":")
+	if len(fields) > 3 {
+		return 0, fmt.Errorf("too many fields in duration '%s'", value)
+	}
+	seconds := 0.0
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return 0, err
+		}
+		seconds = seconds*60 + v
+	}
+	if hasDays {
+		// With days, the format may be days-hours or days-hours:minutes
+		switch len(fields) {
+		case 1:
+			seconds *= 3600
+		case 2:
+			seconds *= 60
+		}
+	}
+	return days*86400 + seconds, nil
+}
+
+// parseSlurmTresValue parses a single TRES value into a quantity
+func parseSlurmTresValue(tresType, value string) (Quantity, error) {
+	name, _, _ := strings.Cut(tresType, "/")
+	if strings.Contains(value, ":") {
+		seconds, err := parseSlurmDuration(value)
+		if err != nil {
+			return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid duration '%s' for TRES '%s': %w", value, tresType, ErrInvalidValue)
+		}
+		return Quantity{Value: seconds, Unit: newBaseUnit(Base, Time)}, nil
+	}
+	u, ok := SlurmTresUnits[tresType]
+	if !ok {
+		u, ok = SlurmTresUnits[name]
+	}
+	if !ok {
+		u = UnitValue{Base, Count, InvalidMeasure}
+	}
+	if len(value) > 0 && u.measure == Bytes {
+		if p, ok := slurmSizeSuffixes[value[len(value)-1]]; ok {
+			value = value[:len(value)-1]
+			u = u.WithPrefix(p)
+		}
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid value '%s' for TRES '%s': %w", value, tresType, ErrInvalidValue)
+	}
+	return Quantity{Value: v, Unit: u.Unit()}, nil
+}
+
+// ParseSlurmTres parses a Slurm TRES string like 'cpu=64,mem=64G,node=1,billing=128,gres/gpu=4'
+// into quantities addressed by the TRES type. Sizes are in binary prefixes (a 'G' suffix is
+// GiB), the cpu time of TRES usage strings like 'cpu=01:02:03' is converted to seconds and
+// counted resources have the Count measure.
+func ParseSlurmTres(tres string) (map[string]Quantity, error) {
+	out := make(map[string]Quantity)
+	for _, entry := range strings.Split(tres, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		tresType, value, found := strings.Cut(entry, "=")
+		if !found {
+			return out, fmt.Errorf("invalid TRES entry '%s': %w", entry, ErrInvalidValue)
+		}
+		q, err := parseSlurmTresValue(tresType, value)
+		if err != nil {
+			return out, err
+		}
+		out[tresType] = q
+	}
+	return out, nil
+}
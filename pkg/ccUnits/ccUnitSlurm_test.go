@@ -0,0 +1,71 @@
+package ccunits
+
+import (
+	"testing"
+)
+
+func TestParseSlurmTres(t *testing.T) {
+	tres, err := ParseSlurmTres("cpu=64,mem=64G,node=2,billing=128,gres/gpu=4,energy=1500,gres/gpumem=40G")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"cpu":         "64 count",
+		"mem":         "64 GiB",
+		"node":        "2 count",
+		"billing":     "128 count",
+		"gres/gpu":    "4 count",
+		"energy":      "1500 J",
+		"gres/gpumem": "40 GiB",
+	}
+	for k, v := range expected {
+		q, ok := tres[k]
+		if !ok {
+			t.Errorf("Missing TRES '%s'", k)
+			continue
+		}
+		if q.String() != v {
+			t.Errorf("TRES '%s': expected '%s' but got '%s'", k, v, q.String())
+		}
+	}
+
+	usage, err := ParseSlurmTres("cpu=1-02:03:04,mem=1024K,fs/disk=2048")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q := usage["cpu"]; q.Value != 93784 || q.Unit.Short() != "s" {
+		t.Errorf("TRES 'cpu': expected '93784 s' but got '%s'", q.String())
+	}
+	if q := usage["fs/disk"]; q.Value != 2048 || q.Unit.Short() != "MiB" {
+		t.Errorf("TRES 'fs/disk': expected '2048 MiB' but got '%s'", q.String())
+	}
+
+	if _, err := ParseSlurmTres("cpu=64,mem"); err == nil {
+		t.Errorf("Expected error for invalid TRES string")
+	}
+	for tresType, value := range map[string]string{"mem": "1x", "cpu": "", "billing": "1:xx:00", "gres/gpu": "4G", "user": "1:02:03:04"} {
+		if q, err := parseSlurmTresValue(tresType, value); err == nil || q.Unit.Valid() {
+			t.Errorf("Expected error and invalid unit for TRES '%s=%s' but got '%s'", tresType, value, q.String())
+		}
+	}
+}
+
+func TestSlurmTresUnitCopies(t *testing.T) {
+	// Changing the unit of a parsed quantity must not change the table
+	a, err := ParseSlurmTres("mem=2048")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a["mem"].Unit.SetPrefix(Gibi)
+	b, err := ParseSlurmTres("mem=2048,cpu=4")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q := b["mem"]; q.String() != "2048 MiB" {
+		t.Errorf("TRES 'mem': expected '2048 MiB' but got '%s'", q.String())
+	}
+	b["cpu"].Unit.SetPrefix(Kilo)
+	if c, _ := ParseSlurmTres("node=1"); c["node"].String() != "1 count" {
+		t.Errorf("TRES 'node': expected '1 count' but got '%s'", c["node"].String())
+	}
+}
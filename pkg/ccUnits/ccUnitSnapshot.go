@@ -0,0 +1,119 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MeasureSnapshot is a measure in a RegistrySnapshot
+type MeasureSnapshot struct {
+	ID           int              `json:"id" yaml:"id"`
+	Long         string           `json:"long" yaml:"long"`
+	Short        string           `json:"short" yaml:"short"`
+	Regex        string           `json:"regex,omitempty" yaml:"regex,omitempty"`
+	NonDividable bool             `json:"non_dividable,omitempty" yaml:"non_dividable,omitempty"`
+	Builtin      bool             `json:"builtin" yaml:"builtin"` // Built-in or registered at runtime
+	Metadata     *MeasureMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// PrefixSnapshot is a prefix in a RegistrySnapshot
+type PrefixSnapshot struct {
+	Long    string  `json:"long" yaml:"long"`
+	Short   string  `json:"short" yaml:"short"`
+	Factor  float64 `json:"factor" yaml:"factor"`
+	Builtin bool    `json:"builtin" yaml:"builtin"` // Built-in or registered at runtime
+}
+
+// RegistrySnapshot is the content of the registry at one point in time: the built-in and
+// registered measures, prefixes, aliases, legacy spellings and non-linear conversions. All
+// lists are sorted, so snapshots of different deployments can be diffed.
+type RegistrySnapshot struct {
+	Measures        []MeasureSnapshot `json:"measures" yaml:"measures"`                                     // Sorted by ID
+	Prefixes        []PrefixSnapshot  `json:"prefixes" yaml:"prefixes"`                                     // Sorted by factor
+	MeasureAliases  map[string]string `json:"measure_aliases,omitempty" yaml:"measure_aliases,omitempty"`   // Alias -> short name of the measure
+	PrefixAliases   map[string]string `json:"prefix_aliases,omitempty" yaml:"prefix_aliases,omitempty"`     // Alias -> short name of the prefix
+	LegacySpellings map[string]string `json:"legacy_spellings,omitempty" yaml:"legacy_spellings,omitempty"` // Spelling -> canonical unit
+	Conversions     []string          `json:"conversions,omitempty" yaml:"conversions,omitempty"`           // Non-linear conversions like 'dBm -> W'
+}
+
+// TakeRegistrySnapshot returns the current content of the registry
+func TakeRegistrySnapshot() RegistrySnapshot {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	s := RegistrySnapshot{
+		Measures:        make([]MeasureSnapshot, 0, len(MeasuresMap)),
+		Prefixes:        make([]PrefixSnapshot, 0, len(PrefixDataMap)),
+		MeasureAliases:  make(map[string]string, len(measureAliases)),
+		PrefixAliases:   make(map[string]string, len(prefixAliases)),
+		LegacySpellings: copyLegacySpellings(legacySpellings),
+		Conversions:     make([]string, 0, len(conversions)),
+	}
+	for m, data := range MeasuresMap {
+		_, builtin := builtinMeasure(m)
+		ms := MeasureSnapshot{
+			ID:           int(m),
+			Long:         data.Long,
+			Short:        data.Short,
+			Regex:        data.Regex,
+			NonDividable: data.NonDividable,
+			Builtin:      builtin,
+		}
+		// Metadata() takes the lock
+		if md, ok := builtinMetadata(m); ok {
+			ms.Metadata = &md
+		} else if md, ok := measureMetadata[m]; ok {
+			ms.Metadata = &md
+		}
+		s.Measures = append(s.Measures, ms)
+	}
+	sort.Slice(s.Measures, func(i, j int) bool { return s.Measures[i].ID < s.Measures[j].ID })
+	for p, data := range PrefixDataMap {
+		_, builtin := builtinPrefix(p)
+		s.Prefixes = append(s.Prefixes, PrefixSnapshot{
+			Long:    data.Long,
+			Short:   data.Short,
+			Factor:  float64(p),
+			Builtin: builtin,
+		})
+	}
+	sort.Slice(s.Prefixes, func(i, j int) bool { return s.Prefixes[i].Factor < s.Prefixes[j].Factor })
+	for alias, m := range measureAliases {
+		s.MeasureAliases[alias] = MeasuresMap[m].Short
+	}
+	for alias, p := range prefixAliases {
+		s.PrefixAliases[alias] = PrefixDataMap[p].Short
+	}
+	for c := range conversions {
+		s.Conversions = append(s.Conversions, fmt.Sprintf("%s -> %s", MeasuresMap[c[0]].Short, MeasuresMap[c[1]].Short))
+	}
+	sort.Strings(s.Conversions)
+	return s
+}
+
+// SaveRegistrySnapshot writes the current content of the registry to a JSON or YAML file
+// (selected by the file extension '.yaml' or '.yml'), so the effective unit configuration
+// of a deployment can be archived and diffed
+func SaveRegistrySnapshot(path string) error {
+	s := TakeRegistrySnapshot()
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(&s)
+	default:
+		data, err = json.MarshalIndent(&s, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode registry snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write registry snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package ccunits
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRegistrySnapshot(t *testing.T) {
+	restoreRegistry(t)
+	m, err := RegisterMeasure(MeasureDefinition{Long: "Snapshots", Short: "snap", Aliases: []string{"snapshots"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := TakeRegistrySnapshot()
+	found := false
+	for i, ms := range s.Measures {
+		if i > 0 && s.Measures[i-1].ID >= ms.ID {
+			t.Errorf("Measures are not sorted by ID")
+		}
+		switch ms.ID {
+		case int(Bytes):
+			if !ms.Builtin || ms.Short != "B" || ms.Metadata == nil {
+				t.Errorf("Expected built-in measure Bytes with metadata but got %+v", ms)
+			}
+		case int(m):
+			found = true
+			if ms.Builtin || ms.Short != "snap" {
+				t.Errorf("Expected registered measure 'snap' but got %+v", ms)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Registered measure is missing in the snapshot")
+	}
+	if s.MeasureAliases["snapshots"] != "snap" || s.LegacySpellings["bytes/sec"] != "B/s" {
+		t.Errorf("Expected alias and legacy spelling in snapshot but got %v and %v", s.MeasureAliases, s.LegacySpellings)
+	}
+	if i := sort.SearchStrings(s.Conversions, "dBm -> W"); i == len(s.Conversions) || s.Conversions[i] != "dBm -> W" {
+		t.Errorf("Expected non-linear conversions in snapshot but got %v", s.Conversions)
+	}
+
+	dir := t.TempDir()
+	if err := SaveRegistrySnapshot(filepath.Join(dir, "registry.yaml")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "registry.json")
+	if err := SaveRegistrySnapshot(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var loaded RegistrySnapshot
+	if err := json.Unmarshal(data, &loaded); err != nil || !reflect.DeepEqual(loaded.Measures, s.Measures) || !reflect.DeepEqual(loaded.Prefixes, s.Prefixes) {
+		t.Errorf("Saved snapshot differs from the registry: %v", err)
+	}
+}
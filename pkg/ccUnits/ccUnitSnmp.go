@@ -0,0 +1,73 @@
+package ccunits
+
+import (
+	"fmt"
+	"time"
+)
+
+// SnmpCounter describes an interface counter of the SNMP IF-MIB (RFC 2863)
+type SnmpCounter struct {
+	Measure Measure // Measure of the counted value
+	Bits    uint    // Width of the counter (32 for Counter32, 64 for Counter64)
+}
+
+// SnmpInterfaceCounters contains the measures and widths of the IF-MIB interface counters
+var SnmpInterfaceCounters map[string]SnmpCounter = map[string]SnmpCounter{
+	"ifInOctets":           {Measure: Bytes, Bits: 32},
+	"ifOutOctets":          {Measure: Bytes, Bits: 32},
+	"ifInUcastPkts":        {Measure: Packets, Bits: 32},
+	"ifOutUcastPkts":       {Measure: Packets, Bits: 32},
+	"ifInDiscards":         {Measure: Packets, Bits: 32},
+	"ifOutDiscards":        {Measure: Packets, Bits: 32},
+	"ifInErrors":           {Measure: Packets, Bits: 32},
+	"ifOutErrors":          {Measure: Packets, Bits: 32},
+	"ifInUnknownProtos":    {Measure: Packets, Bits: 32},
+	"ifHCInOctets":         {Measure: Bytes, Bits: 64},
+	"ifHCOutOctets":        {Measure: Bytes, Bits: 64},
+	"ifHCInUcastPkts":      {Measure: Packets, Bits: 64},
+	"ifHCOutUcastPkts":     {Measure: Packets, Bits: 64},
+	"ifHCInMulticastPkts":  {Measure: Packets, Bits: 64},
+	"ifHCOutMulticastPkts": {Measure: Packets, Bits: 64},
+	"ifHCInBroadcastPkts":  {Measure: Packets, Bits: 64},
+	"ifHCOutBroadcastPkts": {Measure: Packets, Bits: 64},
+}
+
+// GetSnmpCounterRate calculates the rate of an IF-MIB interface counter out of two readings
+// taken interval apart. A single wraparound of a 32 bit counter between the readings is
+// handled. The resulting quantity is in bytes or packets per second. It returns an error if a
+// 64 bit counter or a previous reading out of the 32 bit range decreased, e.g. because the
+// counter was reset, since a 64 bit counter does not wrap around within a polling interval.
+func GetSnmpCounterRate(counter string, previous, current uint64, interval time.Duration) (Quantity, error) {
+	c, ok := SnmpInterfaceCounters[counter]
+	if !ok {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("unknown SNMP interface counter '%s': %w", counter, ErrInvalidMeasure)
+	}
+	if interval <= 0 {
+		return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("invalid interval %v for SNMP counter '%s': %w", interval, counter, ErrInvalidValue)
+	}
+	diff := current - previous
+	if current < previous {
+		if c.Bits >= 64 || previous >= uint64(1)<<c.Bits {
+			return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("SNMP counter '%s' decreased from %d to %d: %w", counter, previous, current, ErrInvalidValue)
+		}
+		diff = (uint64(1) << c.Bits) - previous + current
+	}
+	u := newUnit(Base, c.Measure, Time)
+	return Quantity{
+		Value: float64(diff) / interval.Seconds(),
+		Unit:  u,
+	}, nil
+}
+
+// GetSnmpInterfaceSpeed returns the speed of an interface as quantity in bytes per second.
+// The value is either the ifSpeed (in bit/s) or the ifHighSpeed (in Mbit/s) of an interface.
+func GetSnmpInterfaceSpeed(name string, value uint64) (Quantity, error) {
+	u := newUnit(Base, Bytes, Time)
+	switch name {
+	case "ifSpeed":
+		return Quantity{Value: float64(value) / 8, Unit: u}, nil
+	case "ifHighSpeed":
+		return Quantity{Value: float64(value) * Mega / 8, Unit: u}, nil
+	}
+	return Quantity{Value: 0, Unit: invalidUnitValue.Unit()}, fmt.Errorf("unknown SNMP interface speed '%s': %w", name, ErrInvalidMeasure)
+}
@@ -0,0 +1,125 @@
+package ccunits
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetSnmpCounterRate(t *testing.T) {
+	type testDefinition struct {
+		counter       string
+		previous      uint64
+		current       uint64
+		interval      time.Duration
+		valueExpected float64
+		unitExpected  string
+		errorExpected bool
+	}
+
+	snmpTests := []testDefinition{
+		{
+			counter:       "ifInOctets",
+			previous:      1000,
+			current:       3000,
+			interval:      2 * time.Second,
+			valueExpected: 1000,
+			unitExpected:  "B/s",
+		},
+		{
+			// Counter32 wrapped around between the readings
+			counter:       "ifInOctets",
+			previous:      1<<32 - 1000,
+			current:       1000,
+			interval:      time.Second,
+			valueExpected: 2000,
+			unitExpected:  "B/s",
+		},
+		{
+			counter:       "ifHCOutUcastPkts",
+			previous:      1 << 40,
+			current:       1<<40 + 500,
+			interval:      10 * time.Second,
+			valueExpected: 50,
+			unitExpected:  "packets/s",
+		},
+		{
+			// Counter64 decreased, e.g. after a reset of the interface
+			counter:       "ifHCInOctets",
+			previous:      1<<64 - 1000,
+			current:       1000,
+			interval:      time.Second,
+			errorExpected: true,
+		},
+		{
+			counter:       "ifHCInOctets",
+			previous:      5000,
+			current:       10,
+			interval:      time.Second,
+			errorExpected: true,
+		},
+		{
+			// Previous reading out of the range of a Counter32
+			counter:       "ifOutOctets",
+			previous:      1 << 33,
+			current:       10,
+			interval:      time.Second,
+			errorExpected: true,
+		},
+		{
+			counter:       "ifInOctets",
+			previous:      1000,
+			current:       3000,
+			interval:      0,
+			errorExpected: true,
+		},
+		{
+			counter:       "ifInOctets",
+			previous:      1000,
+			current:       3000,
+			interval:      -time.Second,
+			errorExpected: true,
+		},
+		{
+			counter:       "ifUnknown",
+			previous:      1000,
+			current:       3000,
+			interval:      time.Second,
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range snmpTests {
+		q, err := GetSnmpCounterRate(test.counter, test.previous, test.current, test.interval)
+		if test.errorExpected {
+			if err == nil || q.Unit.Valid() {
+				t.Errorf("Expected error and invalid unit for counter '%s' from %d to %d in %v but got %s", test.counter, test.previous, test.current, test.interval, q.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for counter '%s': %v", test.counter, err)
+			continue
+		}
+		if q.Value != test.valueExpected || q.Unit.Short() != test.unitExpected {
+			t.Errorf("Expected '%v %s' for counter '%s' but got '%s'", test.valueExpected, test.unitExpected, test.counter, q.String())
+		}
+	}
+}
+
+func TestGetSnmpInterfaceSpeed(t *testing.T) {
+	for name, c := range map[string]struct {
+		value    uint64
+		expected float64
+	}{
+		"ifSpeed":     {value: 1e9, expected: 125e6},
+		"ifHighSpeed": {value: 100000, expected: 12.5e9},
+	} {
+		q, err := GetSnmpInterfaceSpeed(name, c.value)
+		if err != nil || q.Value != c.expected || q.Unit.Short() != "B/s" {
+			t.Errorf("Expected '%v B/s' for %s %d but got '%s': %v", c.expected, name, c.value, q.String(), err)
+		}
+	}
+	if q, err := GetSnmpInterfaceSpeed("ifMtu", 1500); err == nil || q.Unit.Valid() {
+		t.Errorf("Expected error and invalid unit for unknown interface speed 'ifMtu' but got '%s'", q.String())
+	}
+}
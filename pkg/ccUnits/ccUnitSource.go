@@ -0,0 +1,38 @@
+package ccunits
+
+import "fmt"
+
+// SourceUnit describes the unit a telemetry source reports a value in (Raw) and
+// the unit the value should be reported with by the collectors (Target).
+type SourceUnit struct {
+	Raw    UnitValue
+	Target UnitValue
+}
+
+// newSourceUnit creates a source unit mapping out of the raw and target unit parts.
+// It is used by the mapping tables of the different telemetry sources.
+func newSourceUnit(rawPrefix Prefix, rawMeasure Measure, targetPrefix Prefix, targetMeasure Measure) SourceUnit {
+	return SourceUnit{
+		Raw:    UnitValue{rawPrefix, rawMeasure, InvalidMeasure},
+		Target: UnitValue{targetPrefix, targetMeasure, InvalidMeasure},
+	}
+}
+
+// Valid checks whether both the raw and the target unit are valid
+func (s SourceUnit) Valid() bool {
+	return s.Raw.Valid() && s.Target.Valid()
+}
+
+// Convert converts a value reported by the source in the raw unit to the target unit.
+// It returns the converted value or the unchanged value and an error if the units are
+// not convertible.
+func (s SourceUnit) Convert(value interface{}) (interface{}, error) {
+	if !s.Valid() {
+		return value, fmt.Errorf("invalid source unit mapping: %w", ErrInvalidMeasure)
+	}
+	conv, err := NewConverter(s.Raw.Unit(), s.Target.Unit())
+	if err != nil {
+		return value, err
+	}
+	return conv.Apply(value), nil
+}
@@ -0,0 +1,116 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// convertQuantities converts the values of quantities to a common unit. NaN values are left
+// out.
+func convertQuantities(quantities []Quantity, out Unit) ([]float64, error) {
+	values := make([]float64, 0, len(quantities))
+	for _, q := range quantities {
+		c, err := q.ConvertTo(out)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %s to '%s': %w", q.String(), out.Short(), err)
+		}
+		if !math.IsNaN(c.Value) {
+			values = append(values, c.Value)
+		}
+	}
+	return values, nil
+}
+
+// Quantile returns the p-quantile (0 <= p <= 1) of quantities with possibly different units
+// like 'ms' and 's'. The values are converted to the unit out first, the result is in the
+// unit out. It interpolates linearly between the closest ranks. NaN values are left out.
+func Quantile(quantities []Quantity, p float64, out Unit) (Quantity, error) {
+	if out == nil || !out.Valid() {
+		return Quantity{}, fmt.Errorf("invalid unit for quantile: %w", ErrInvalidMeasure)
+	}
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return Quantity{}, fmt.Errorf("invalid quantile %v: %w", p, ErrInvalidValue)
+	}
+	values, err := convertQuantities(quantities, out)
+	if err != nil {
+		return Quantity{}, err
+	}
+	if len(values) == 0 {
+		return Quantity{}, fmt.Errorf("no values for quantile: %w", ErrInvalidValue)
+	}
+	sort.Float64s(values)
+	rank := p * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	v := values[lower]
+	if lower+1 < len(values) {
+		v += (rank - float64(lower)) * (values[lower+1] - v)
+	}
+	return Quantity{Value: v, Unit: out}, nil
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) of quantities in the unit out like
+// the P95 or P99 of latencies. See Quantile().
+func Percentile(quantities []Quantity, p float64, out Unit) (Quantity, error) {
+	return Quantile(quantities, p/100, out)
+}
+
+// IntegrateRate accumulates a series of rates sampled at the timestamps into the volume, like
+// the data moved by a job out of its bandwidth or the consumed energy out of its power. The
+// rates are integrated with the trapezoidal rule in the unit of the first rate; intervals
+// with a NaN value at either end are left out. Rates per time unit like 'GB/s' or 'MB/min'
+// result in the unit without denominator ('GB', 'MB'), other rates in the unit derived from
+// their dimension with the prefix of the rate like 'kJ' for 'kW'. It returns an error if the
+// rates are not convertible, the timestamps are not increasing or the rate has no volume.
+func IntegrateRate(rates []Quantity, timestamps []time.Time) (Quantity, error) {
+	if len(rates) != len(timestamps) {
+		return Quantity{}, fmt.Errorf("%d rates but %d timestamps: %w", len(rates), len(timestamps), ErrInvalidValue)
+	}
+	if len(rates) < 2 {
+		return Quantity{}, fmt.Errorf("at least two rates are required for integration: %w", ErrInvalidValue)
+	}
+	if !rates[0].Valid() {
+		return Quantity{}, fmt.Errorf("invalid unit of rate %s: %w", rates[0].String(), ErrInvalidMeasure)
+	}
+	u := rates[0].Unit
+	values := make([]float64, len(rates))
+	for i, q := range rates {
+		c, err := q.ConvertTo(u)
+		if err != nil {
+			return Quantity{}, fmt.Errorf("cannot convert %s to '%s': %w", q.String(), u.Short(), err)
+		}
+		values[i] = c.Value
+	}
+	sum := 0.0
+	for i := 1; i < len(values); i++ {
+		dt := timestamps[i].Sub(timestamps[i-1]).Seconds()
+		if dt <= 0 {
+			return Quantity{}, fmt.Errorf("timestamp %d is not after the previous one: %w", i, ErrInvalidValue)
+		}
+		if !math.IsNaN(values[i-1]) && !math.IsNaN(values[i]) {
+			sum += (values[i-1] + values[i]) / 2 * dt
+		}
+	}
+
+	v := ValueOf(u)
+	if isTimeMeasure(v.divMeasure) {
+		_, seconds, _ := builtinDimension(v.divMeasure)
+		return Quantity{Value: sum / seconds, Unit: v.WithDenominator(InvalidMeasure).Unit()}, nil
+	}
+	// Only measures per time like power have a volume, not dimensionless ones like '%',
+	// 'ratio' or 'count'
+	d, _, ok := unitDimension(v)
+	if !ok || d[dimTime] >= 0 {
+		return Quantity{}, fmt.Errorf("no volume for rates in '%s': %w", u.Short(), ErrIncompatibleMeasure)
+	}
+	q, err := Multiply(Quantity{Value: sum, Unit: u}, Quantity{Value: 1, Unit: newBaseUnit(Base, Time)})
+	if err != nil {
+		return Quantity{}, fmt.Errorf("no volume for rates in '%s': %w", u.Short(), ErrIncompatibleMeasure)
+	}
+	// Keep the prefix of the rate like 'kJ' for 'kW'
+	if p, err := q.ConvertToPrefix(v.prefix); err == nil {
+		q = p
+	}
+	return q, nil
+}
@@ -0,0 +1,78 @@
+package ccunits
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	quantities := []Quantity{
+		NewQuantity(0.002, "s"),
+		NewQuantity(1, "ms"),
+		NewQuantity(4, "ms"),
+		{Value: 3000, Unit: NewUnitFromParts(Micro, Time, InvalidMeasure)},
+		NewQuantity(math.NaN(), "ms"),
+	}
+	ms := NewUnit("ms")
+	for _, c := range []struct {
+		p        float64
+		expected float64
+	}{
+		{0, 1},
+		{50, 2.5},
+		{95, 3.85},
+		{100, 4},
+	} {
+		q, err := Percentile(quantities, c.p, ms)
+		if err != nil || math.Abs(q.Value-c.expected) > 1e-9 || q.Unit.Short() != "ms" {
+			t.Errorf("Expected P%v = %v ms but got %s: %v", c.p, c.expected, q.String(), err)
+		}
+	}
+	if q, err := Quantile(quantities[:1], 0.99, ms); err != nil || q.Value != 2 {
+		t.Errorf("Expected 2 ms for a single value but got %s: %v", q.String(), err)
+	}
+	if _, err := Quantile(quantities, 1.5, ms); err == nil {
+		t.Errorf("Expected error for quantile 1.5")
+	}
+	if _, err := Quantile(append(quantities, NewQuantity(1, "GB")), 0.5, ms); err == nil {
+		t.Errorf("Expected error for inconvertible unit")
+	}
+	if _, err := Quantile(nil, 0.5, ms); err == nil {
+		t.Errorf("Expected error for no values")
+	}
+}
+
+func TestIntegrateRate(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	ts := []time.Time{start, start.Add(10 * time.Second), start.Add(20 * time.Second), start.Add(30 * time.Second)}
+	for _, c := range []struct {
+		rates    []Quantity
+		expected string
+	}{
+		{[]Quantity{NewQuantity(1, "GB/s"), NewQuantity(1, "GB/s"), NewQuantity(3, "GB/s"), NewQuantity(3, "GB/s")}, "60 GB"},
+		{[]Quantity{NewQuantity(1, "GB/s"), NewQuantity(1000, "MB/s"), NewQuantity(math.NaN(), "GB/s"), NewQuantity(1, "GB/s")}, "10 GB"},
+		{[]Quantity{NewQuantity(6, "MB/min"), NewQuantity(6, "MB/min"), NewQuantity(6, "MB/min"), NewQuantity(6, "MB/min")}, "3 MB"},
+		{[]Quantity{NewQuantity(200, "W"), NewQuantity(200, "W"), NewQuantity(200, "W"), NewQuantity(200, "W")}, "6000 J"},
+		{[]Quantity{NewQuantity(2, "kW"), NewQuantity(2, "kW"), NewQuantity(2, "kW"), NewQuantity(2, "kW")}, "60 KJ"},
+	} {
+		q, err := IntegrateRate(c.rates, ts)
+		if err != nil || q.String() != c.expected {
+			t.Errorf("Expected %s but got %s: %v", c.expected, q.String(), err)
+		}
+	}
+	if _, err := IntegrateRate([]Quantity{NewQuantity(1, "degC"), NewQuantity(1, "degC")}, ts[:2]); err == nil {
+		t.Errorf("Expected error for temperatures")
+	}
+	for _, unitStr := range []string{"%", "ratio", "count"} {
+		if q, err := IntegrateRate([]Quantity{NewQuantity(50, unitStr), NewQuantity(50, unitStr)}, ts[:2]); err == nil {
+			t.Errorf("Expected error for dimensionless rates in '%s' but got %s", unitStr, q.String())
+		}
+	}
+	if _, err := IntegrateRate([]Quantity{NewQuantity(1, "W"), NewQuantity(1, "W")}, []time.Time{ts[1], ts[0]}); err == nil {
+		t.Errorf("Expected error for decreasing timestamps")
+	}
+	if _, err := IntegrateRate([]Quantity{NewQuantity(1, "W"), NewQuantity(1, "B/s")}, ts[:2]); err == nil {
+		t.Errorf("Expected error for incompatible rates")
+	}
+}
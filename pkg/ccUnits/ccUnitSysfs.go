@@ -0,0 +1,144 @@
+package ccunits
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// SysfsFileUnit assigns the implicit unit of the values to /proc and /sys files
+// matching a pattern. The patterns use the syntax of filepath.Match. If Keys is set,
+// the unit only applies to the fields of the file whose key matches one of the patterns.
+type SysfsFileUnit struct {
+	Pattern string
+	Keys    []string
+	Unit    UnitValue
+}
+
+// SysfsFileUnits contains the implicit units of common /proc and /sys files. The list
+// is checked in order, the first matching pattern determines the unit. The sensor attributes
+// of hwmon devices are checked before by HwmonAttributeUnit().
+var SysfsFileUnits []SysfsFileUnit = []SysfsFileUnit{
+	// The 'kB' in meminfo files are KiB, only the huge page fields without 'kB' are counts
+	{Pattern: "/proc/meminfo", Keys: []string{"HugePages_*"}, Unit: UnitValue{Base, Count, InvalidMeasure}},
+	{Pattern: "/proc/meminfo", Unit: UnitValue{Kibi, Bytes, InvalidMeasure}},
+	{Pattern: "/sys/devices/system/node/node*/meminfo", Keys: []string{"HugePages_*"}, Unit: UnitValue{Base, Count, InvalidMeasure}},
+	{Pattern: "/sys/devices/system/node/node*/meminfo", Unit: UnitValue{Kibi, Bytes, InvalidMeasure}},
+	// Only the memory fields of status files have a unit, others are counts like 'Threads'
+	{Pattern: "/proc/[0-9]*/status", Keys: []string{"Vm*", "Rss*"}, Unit: UnitValue{Kibi, Bytes, InvalidMeasure}},
+	// cpufreq reports in kHz
+	{Pattern: "/sys/devices/system/cpu/cpu*/cpufreq/*_freq", Unit: UnitValue{Kilo, Frequency, InvalidMeasure}},
+	{Pattern: "/sys/devices/system/cpu/cpufreq/policy*/*_freq", Unit: UnitValue{Kilo, Frequency, InvalidMeasure}},
+	// thermal zones report in millidegree Celsius
+	{Pattern: "/sys/class/thermal/thermal_zone*/temp", Unit: UnitValue{Milli, TemperatureC, InvalidMeasure}},
+	// powercap (RAPL) reports in microjoules and microwatts
+	{Pattern: "/sys/class/powercap/*/*_uj", Unit: UnitValue{Micro, Joule, InvalidMeasure}},
+	{Pattern: "/sys/class/powercap/*/*_uw", Unit: UnitValue{Micro, Watt, InvalidMeasure}},
+	// network interface statistics
+	{Pattern: "/sys/class/net/*/statistics/*_bytes", Unit: UnitValue{Base, Bytes, InvalidMeasure}},
+	{Pattern: "/sys/class/net/*/statistics/*_packets", Unit: UnitValue{Base, Packets, InvalidMeasure}},
+}
+
+// GetSysfsFileUnit returns the implicit unit of the values in a /proc or /sys file. Each call
+// returns a new unit, so callers can change it. Files whose unit depends on the field like
+// /proc/[pid]/status have no unit, use GetSysfsFieldUnit() for them.
+func GetSysfsFileUnit(path string) (Unit, bool) {
+	return GetSysfsFieldUnit(path, "")
+}
+
+// GetSysfsFieldUnit returns the implicit unit of a field like 'VmRSS' in a /proc or /sys file
+// with one field per line. An empty key matches only patterns without keys.
+func GetSysfsFieldUnit(path, key string) (Unit, bool) {
+	if isHwmonPath(path) {
+		return HwmonAttributeUnit(filepath.Base(path))
+	}
+	for _, f := range SysfsFileUnits {
+		if match, err := filepath.Match(f.Pattern, path); err == nil && match {
+			if len(f.Keys) > 0 && !matchSysfsKey(f.Keys, key) {
+				continue
+			}
+			return f.Unit.Unit(), true
+		}
+	}
+	return invalidUnitValue.Unit(), false
+}
+
+// matchSysfsKey checks whether a non-empty key matches one of the key patterns
+func matchSysfsKey(patterns []string, key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+	for _, p := range patterns {
+		if match, err := filepath.Match(p, key); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSysfsQuantity creates a quantity for a value read from a /proc or /sys file with
+// the implicit unit of the file. It returns an error if the unit of the file is unknown.
+func NewSysfsQuantity(path string, value float64) (Quantity, error) {
+	return NewSysfsFieldQuantity(path, "", value)
+}
+
+// NewSysfsFieldQuantity creates a quantity for the value of a field like 'VmRSS' read from
+// a /proc or /sys file. It returns an error if the unit of the field is unknown.
+func NewSysfsFieldQuantity(path, key string, value float64) (Quantity, error) {
+	u, ok := GetSysfsFieldUnit(path, key)
+	if !ok {
+		if len(key) > 0 {
+			return Quantity{Value: value, Unit: u}, fmt.Errorf("unknown unit for field '%s' of file '%s': %w", key, path, ErrInvalidMeasure)
+		}
+		return Quantity{Value: value, Unit: u}, fmt.Errorf("unknown unit for file '%s': %w", path, ErrInvalidMeasure)
+	}
+	return Quantity{Value: value, Unit: u}, nil
+}
+
+// Common page sizes in bytes for PagesToBytes() and BytesToPages()
+const (
+	PageSize4KiB  int64 = 4 << 10  // Default page size on x86_64
+	PageSize64KiB int64 = 64 << 10 // Default page size on some aarch64 and ppc64le systems
+	PageSize2MiB  int64 = 2 << 20  // Huge pages
+	PageSize1GiB  int64 = 1 << 30  // Gigantic huge pages
+)
+
+// PagesToBytes converts a number of pages like from /proc/vmstat (a count, also as rate like
+// 'count/s') to the bytes unit out given the page size in bytes, so page-based metrics can be
+// compared with RSS metrics. The unit denominator of out must match the one of pages.
+func PagesToBytes(pages Quantity, pageSize int64, out Unit) (Quantity, error) {
+	if !pages.Valid() || out == nil || !out.Valid() {
+		return pages, fmt.Errorf("invalid unit for conversion: %w", ErrInvalidMeasure)
+	}
+	if pageSize <= 0 {
+		return pages, fmt.Errorf("invalid page size %d: %w", pageSize, ErrInvalidValue)
+	}
+	in, o := ValueOf(pages.Unit), ValueOf(out)
+	if in.measure != Count || o.measure != Bytes || in.divMeasure != o.divMeasure {
+		return pages, fmt.Errorf("cannot convert '%s' pages to '%s': %w", in.Short(), o.Short(), ErrIncompatibleMeasure)
+	}
+	count := NewPrefixConverter(in.prefix, Base).ApplyFloat64(pages.Value)
+	return Quantity{
+		Value: NewPrefixConverter(Base, o.prefix).ApplyFloat64(count * float64(pageSize)),
+		Unit:  out,
+	}, nil
+}
+
+// BytesToPages converts bytes (also as rate like 'MB/s') to the number of pages given the page
+// size in bytes. The result is a count with the unit denominator of the input like 'count/s'.
+func BytesToPages(q Quantity, pageSize int64) (Quantity, error) {
+	if !q.Valid() {
+		return q, fmt.Errorf("invalid unit for conversion: %w", ErrInvalidMeasure)
+	}
+	if pageSize <= 0 {
+		return q, fmt.Errorf("invalid page size %d: %w", pageSize, ErrInvalidValue)
+	}
+	in := ValueOf(q.Unit)
+	if in.measure != Bytes {
+		return q, fmt.Errorf("cannot convert '%s' to pages: %w", in.Short(), ErrIncompatibleMeasure)
+	}
+	bytes := NewPrefixConverter(in.prefix, Base).ApplyFloat64(q.Value)
+	return Quantity{
+		Value: bytes / float64(pageSize),
+		Unit:  NewUnitValueFromParts(Base, Count, in.divMeasure).Unit(),
+	}, nil
+}
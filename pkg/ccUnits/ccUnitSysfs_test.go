@@ -0,0 +1,110 @@
+package ccunits
+
+import "testing"
+
+func TestGetSysfsFileUnit(t *testing.T) {
+	for _, c := range []struct {
+		path     string
+		expected string // Empty for files without unit
+	}{
+		{"/proc/meminfo", "KiB"},
+		{"/sys/devices/system/node/node1/meminfo", "KiB"},
+		{"/proc/4711/status", ""},
+		{"/sys/devices/system/cpu/cpu12/cpufreq/scaling_cur_freq", "KHz"},
+		{"/sys/devices/system/cpu/cpufreq/policy0/cpuinfo_max_freq", "KHz"},
+		{"/sys/class/thermal/thermal_zone0/temp", "mdegC"},
+		{"/sys/class/powercap/intel-rapl:0/energy_uj", "uJ"},
+		{"/sys/class/powercap/intel-rapl:0/constraint_0_power_limit_uw", "uW"},
+		{"/sys/class/net/eth0/statistics/rx_bytes", "B"},
+		{"/sys/class/net/ib0/statistics/tx_packets", "packets"},
+		{"/sys/class/hwmon/hwmon1/temp1_input", "mdegC"},
+		{"/sys/class/hwmon/hwmon1/device/fan1_input", "RPM"},
+		{"/proc/self/status", ""},
+		{"/proc/vmstat", ""},
+		{"/sys/class/net/eth0/statistics/rx_errors", ""},
+		{"/sys/class/hwmon/hwmon1/name", ""},
+		{"meminfo", ""},
+	} {
+		u, ok := GetSysfsFileUnit(c.path)
+		if ok != (len(c.expected) > 0) || ok && u.Short() != c.expected {
+			t.Errorf("Expected unit '%s' for file '%s' but got '%s' (%v)", c.expected, c.path, u.Short(), ok)
+		}
+	}
+}
+
+func TestGetSysfsFieldUnit(t *testing.T) {
+	for _, c := range []struct {
+		path     string
+		key      string
+		expected string // Empty for fields without unit
+	}{
+		{"/proc/4711/status", "VmRSS", "KiB"},
+		{"/proc/4711/status", "VmHWM", "KiB"},
+		{"/proc/4711/status", "RssAnon", "KiB"},
+		{"/proc/4711/status", "Threads", ""},
+		{"/proc/4711/status", "voluntary_ctxt_switches", ""},
+		{"/proc/4711/status", "", ""},
+		{"/proc/self/status", "VmRSS", ""},
+		{"/proc/meminfo", "MemFree", "KiB"},
+		{"/proc/meminfo", "Hugepagesize", "KiB"},
+		{"/proc/meminfo", "HugePages_Total", "count"},
+		{"/proc/meminfo", "HugePages_Free", "count"},
+		{"/sys/devices/system/node/node0/meminfo", "HugePages_Surp", "count"},
+		{"/sys/devices/system/node/node0/meminfo", "MemUsed", "KiB"},
+		{"/sys/class/hwmon/hwmon1/temp1_input", "", "mdegC"},
+	} {
+		u, ok := GetSysfsFieldUnit(c.path, c.key)
+		if ok != (len(c.expected) > 0) || ok && u.Short() != c.expected {
+			t.Errorf("Expected unit '%s' for field '%s' of file '%s' but got '%s' (%v)", c.expected, c.key, c.path, u.Short(), ok)
+		}
+	}
+	q, err := NewSysfsFieldQuantity("/proc/4711/status", "VmRSS", 2048)
+	if err == nil {
+		q, err = q.ConvertTo(NewUnit("MiB"))
+	}
+	if err != nil || q.Value != 2 {
+		t.Errorf("Expected '2 MiB' for field 'VmRSS' but got '%s': %v", q.String(), err)
+	}
+	if q, err := NewSysfsFieldQuantity("/proc/4711/status", "Threads", 8); err == nil || q.Unit.Valid() {
+		t.Errorf("Expected error for field 'Threads' but got '%s'", q.String())
+	}
+}
+
+func TestNewSysfsQuantity(t *testing.T) {
+	for _, c := range []struct {
+		path          string
+		value         float64
+		out           string
+		expected      float64
+		errorExpected bool
+	}{
+		{"/proc/meminfo", 2097152, "GiB", 2, false},
+		{"/sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq", 2400000, "GHz", 2.4, false},
+		{"/sys/class/thermal/thermal_zone2/temp", 45000, "degC", 45, false},
+		{"/sys/class/powercap/intel-rapl:1/energy_uj", 2500000, "J", 2.5, false},
+		{"/proc/loadavg", 1, "", 0, true},
+	} {
+		q, err := NewSysfsQuantity(c.path, c.value)
+		if c.errorExpected {
+			if err == nil || q.Unit.Valid() || q.Value != c.value {
+				t.Errorf("Expected error for file '%s' but got '%s'", c.path, q.String())
+			}
+			continue
+		}
+		if err == nil {
+			q, err = q.ConvertTo(NewUnit(c.out))
+		}
+		if err != nil || q.Value != c.expected {
+			t.Errorf("Expected '%v %s' for file '%s' but got '%s': %v", c.expected, c.out, c.path, q.String(), err)
+		}
+	}
+}
+
+func TestSysfsFileUnitCopies(t *testing.T) {
+	// Changing a returned unit must not change the table
+	u, _ := GetSysfsFileUnit("/proc/meminfo")
+	u.SetPrefix(Gibi)
+	if v, _ := GetSysfsFileUnit("/proc/meminfo"); v.Short() != "KiB" {
+		t.Errorf("Expected 'KiB' for file '/proc/meminfo' but got '%s'", v.Short())
+	}
+}
@@ -0,0 +1,68 @@
+package ccunits
+
+import (
+	"regexp"
+	"sort"
+	"sync/atomic"
+)
+
+// The compiled regular expressions of the measures and prefixes are built lazily on the
+// first parse, so programs that import the package but rarely parse units do not pay for
+// them at startup. They are rebuilt after measures or prefixes are registered, which is
+// detected by the registry generation.
+
+// measureMatcher is the compiled regular expression of a measure
+type measureMatcher struct {
+	measure Measure
+	regex   *regexp.Regexp
+}
+
+// prefixMatcher is the compiled regular expression of a prefix
+type prefixMatcher struct {
+	prefix Prefix
+	regex  *regexp.Regexp
+}
+
+// parserTables contains the compiled regular expressions ordered by measure and prefix
+type parserTables struct {
+	generation uint64 // Registry generation the tables are built from
+	measures   []measureMatcher
+	prefixes   []prefixMatcher
+}
+
+var currentParserTables atomic.Pointer[parserTables]
+
+// buildParserTables compiles the regular expressions of all measures and prefixes
+func buildParserTables() *parserTables {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	t := &parserTables{
+		generation: registryGeneration.Load(),
+		measures:   make([]measureMatcher, 0, len(MeasuresMap)),
+		prefixes:   make([]prefixMatcher, 0, len(PrefixDataMap)),
+	}
+	for m, data := range MeasuresMap {
+		if len(data.Regex) > 0 {
+			t.measures = append(t.measures, measureMatcher{m, regexp.MustCompile(data.Regex)})
+		}
+	}
+	sort.Slice(t.measures, func(i, j int) bool { return t.measures[i].measure < t.measures[j].measure })
+	for p, data := range PrefixDataMap {
+		if len(data.Regex) > 0 {
+			t.prefixes = append(t.prefixes, prefixMatcher{p, regexp.MustCompile(data.Regex)})
+		}
+	}
+	sort.Slice(t.prefixes, func(i, j int) bool { return t.prefixes[i].prefix < t.prefixes[j].prefix })
+	return t
+}
+
+// getParserTables returns the parser tables and builds them on first use or after a
+// registration. Concurrent calls may build the tables multiple times but all results are equal.
+func getParserTables() *parserTables {
+	if t := currentParserTables.Load(); t != nil && t.generation == registryGeneration.Load() {
+		return t
+	}
+	t := buildParserTables()
+	currentParserTables.Store(t)
+	return t
+}
@@ -0,0 +1,91 @@
+package ccunits
+
+import (
+	"fmt"
+	"math"
+)
+
+// Plausible range of epoch timestamps in seconds (2000-01-01 to 2100-01-01). Timestamps
+// outside are most likely given in another resolution.
+const (
+	minPlausibleEpoch float64 = 946684800
+	maxPlausibleEpoch float64 = 4102444800
+)
+
+// timestampPrefixes are the resolutions of epoch timestamps
+var timestampPrefixes = []Prefix{Base, Milli, Micro, Nano}
+
+// timestampSeconds returns a timestamp in seconds or an error if the unit is no timestamp
+// resolution
+func timestampSeconds(q Quantity) (float64, error) {
+	if !q.Valid() {
+		return 0, fmt.Errorf("invalid unit for timestamp: %w", ErrInvalidMeasure)
+	}
+	u := ValueOf(q.Unit)
+	if u.measure != Time || u.divMeasure != InvalidMeasure {
+		return 0, fmt.Errorf("invalid timestamp unit '%s': %w", u.Short(), ErrIncompatibleMeasure)
+	}
+	for _, p := range timestampPrefixes {
+		if u.prefix == p {
+			return NewPrefixConverter(p, Base).ApplyFloat64(q.Value), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid timestamp resolution '%s': %w", u.Short(), ErrIncompatibleMeasure)
+}
+
+// CheckTimestamp checks whether a quantity is a plausible epoch timestamp in seconds,
+// milliseconds, microseconds or nanoseconds. Timestamps before 2000 or after 2100 are most
+// likely given in another resolution than their unit says.
+func CheckTimestamp(q Quantity) error {
+	sec, err := timestampSeconds(q)
+	if err != nil {
+		return err
+	}
+	if sec < minPlausibleEpoch || sec > maxPlausibleEpoch || math.IsNaN(sec) {
+		return fmt.Errorf("implausible timestamp %v for resolution '%s': %w", q.Value, q.Unit.Short(), ErrOverflow)
+	}
+	return nil
+}
+
+// ConvertTimestamp converts an epoch timestamp to another resolution like milliseconds to
+// seconds. It returns an error if the timestamp is not plausible (see CheckTimestamp()).
+// Nanosecond timestamps exceed the precision of float64, use ConvertTimestampInt64() to keep
+// them exact.
+func ConvertTimestamp(q Quantity, out Unit) (Quantity, error) {
+	if err := CheckTimestamp(q); err != nil {
+		return q, err
+	}
+	if _, err := timestampSeconds(Quantity{Unit: out}); err != nil {
+		return q, err
+	}
+	// Dividing by the integer factor keeps e.g. 1700000000123 ms exactly 1700000000.123 s
+	pf := getPrefixFactor(q.Unit.GetPrefix(), out.GetPrefix())
+	v := q.Value * pf.factor
+	if pf.div > 0 {
+		v = q.Value / float64(pf.div)
+	}
+	return Quantity{Value: v, Unit: out}, nil
+}
+
+// ConvertTimestampInt64 converts an integer epoch timestamp between resolutions like
+// nanoseconds to milliseconds without the detour via float64. It returns an error if the
+// timestamp is not plausible.
+func ConvertTimestampInt64(ts int64, in Unit, out Unit) (int64, error) {
+	if _, err := ConvertTimestamp(Quantity{Value: float64(ts), Unit: in}, out); err != nil {
+		return ts, err
+	}
+	return NewPrefixConverter(in.GetPrefix(), out.GetPrefix()).ApplyInt64(ts), nil
+}
+
+// DetectTimestampUnit returns the resolution of an epoch timestamp out of its magnitude,
+// e.g. for sources with mixed resolutions. It returns an error if the timestamp is not
+// plausible in any resolution.
+func DetectTimestampUnit(ts float64) (Unit, error) {
+	for _, p := range timestampPrefixes {
+		u := newBaseUnit(p, Time)
+		if CheckTimestamp(Quantity{Value: ts, Unit: u}) == nil {
+			return u, nil
+		}
+	}
+	return invalidUnitValue.Unit(), fmt.Errorf("implausible timestamp %v: %w", ts, ErrOverflow)
+}
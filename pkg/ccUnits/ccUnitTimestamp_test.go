@@ -0,0 +1,35 @@
+package ccunits
+
+import "testing"
+
+func TestTimestamps(t *testing.T) {
+	ms := NewUnit("ms")
+	ns := NewUnitFromParts(Nano, Time, InvalidMeasure)
+	q, err := ConvertTimestamp(Quantity{Value: 1700000000123, Unit: ms}, NewUnit("s"))
+	if err != nil || q.Value != 1700000000.123 || q.Unit.Short() != "s" {
+		t.Errorf("Expected 1700000000.123 s but got %s: %v", q.String(), err)
+	}
+	if ts, err := ConvertTimestampInt64(1700000000123456789, ns, ms); err != nil || ts != 1700000000123 {
+		t.Errorf("Expected 1700000000123 ms but got %d: %v", ts, err)
+	}
+	// Seconds tagged as milliseconds
+	if _, err := ConvertTimestamp(Quantity{Value: 1700000000, Unit: ms}, NewUnit("s")); err == nil {
+		t.Errorf("Expected error for implausible timestamp")
+	}
+	if err := CheckTimestamp(NewQuantity(1700000000, "MB")); err == nil {
+		t.Errorf("Expected error for timestamp in 'MB'")
+	}
+	for ts, expected := range map[float64]Prefix{
+		1.7e9:  Base,
+		1.7e12: Milli,
+		1.7e15: Micro,
+		1.7e18: Nano,
+	} {
+		if u, err := DetectTimestampUnit(ts); err != nil || u.GetPrefix() != expected {
+			t.Errorf("Expected prefix %s for %v but got '%s': %v", expected.String(), ts, u.Short(), err)
+		}
+	}
+	if u, err := DetectTimestampUnit(12345); err == nil || u.Valid() {
+		t.Errorf("Expected error and invalid unit for implausible timestamp but got '%s'", u.Short())
+	}
+}
@@ -0,0 +1,58 @@
+package ccunits
+
+import "fmt"
+
+// Resource is a hardware resource of a node for per-resource normalization. Its name is used
+// as counted entity in the derived unit like 'GB/(socket*s)'.
+type Resource string
+
+const (
+	ResourceSocket     Resource = "socket"
+	ResourceNUMADomain Resource = "numa"
+	ResourceCore       Resource = "core"
+)
+
+// Topology contains the number of resources of a node (or of all nodes a quantity is
+// aggregated over)
+type Topology struct {
+	Sockets     int
+	NUMADomains int
+	Cores       int
+}
+
+// count returns the number of a resource
+func (t Topology) count(r Resource) (int, error) {
+	n := 0
+	switch r {
+	case ResourceSocket:
+		n = t.Sockets
+	case ResourceNUMADomain:
+		n = t.NUMADomains
+	case ResourceCore:
+		n = t.Cores
+	default:
+		return 0, fmt.Errorf("unknown resource '%s': %w", string(r), ErrInvalidMeasure)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid number %d of resource '%s': %w", n, string(r), ErrInvalidValue)
+	}
+	return n, nil
+}
+
+// PerResource divides an aggregate quantity like the memory bandwidth or the Flops rate of a
+// node by the number of sockets, NUMA domains or cores of the topology, so nodes with
+// different topologies can be compared fairly. The result has a derived unit with the resource
+// as counted entity like '12.5 GB/(socket*s)'.
+func PerResource(q Quantity, topo Topology, r Resource) (DerivedQuantity, error) {
+	if !q.Valid() {
+		return DerivedQuantity{}, fmt.Errorf("invalid unit for per-resource normalization: %w", ErrInvalidMeasure)
+	}
+	n, err := topo.count(r)
+	if err != nil {
+		return DerivedQuantity{}, err
+	}
+	return DerivedQuantity{
+		Value: q.Value / float64(n),
+		Unit:  NewDerivedUnit(q.Unit).PerEntity(string(r)),
+	}, nil
+}
@@ -0,0 +1,43 @@
+//go:build js && wasm
+
+package ccunits
+
+import "syscall/js"
+
+// JavaScript bindings for the web UI. The functions check the types of the JavaScript
+// arguments and call the implementations in ccUnitJavaScript.go.
+
+// jsParse implements parse(unit)
+func jsParse(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return jsError("parse requires a unit string")
+	}
+	return jsParseUnit(args[0].String())
+}
+
+// jsConvert implements convert(value, from, to)
+func jsConvert(this js.Value, args []js.Value) interface{} {
+	if len(args) != 3 || args[0].Type() != js.TypeNumber || args[1].Type() != js.TypeString || args[2].Type() != js.TypeString {
+		return jsError("convert requires a value, a source unit and a target unit")
+	}
+	return jsConvertValue(args[0].Float(), args[1].String(), args[2].String())
+}
+
+// jsHumanize implements humanize(value, unit, binary)
+func jsHumanize(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 || args[0].Type() != js.TypeNumber || args[1].Type() != js.TypeString {
+		return jsError("humanize requires a value and a unit")
+	}
+	return jsHumanizeValue(args[0].Float(), args[1].String(), len(args) > 2 && args[2].Truthy())
+}
+
+// RegisterJavaScript exposes the functions parse(unit), convert(value, from, to) and
+// humanize(value, unit, binary) as global JavaScript object with the given name.
+// The functions are never released, so the WebAssembly module has to keep running.
+func RegisterJavaScript(name string) {
+	js.Global().Set(name, js.ValueOf(map[string]interface{}{
+		"parse":    js.FuncOf(jsParse),
+		"convert":  js.FuncOf(jsConvert),
+		"humanize": js.FuncOf(jsHumanize),
+	}))
+}
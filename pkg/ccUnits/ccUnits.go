@@ -0,0 +1,559 @@
+// Unit system for cluster monitoring metrics like bytes, flops and events
+package ccunits
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// UnitValue is a unit as small comparable value out of prefix, measure and unit denominator.
+// It can be compared with == and used as map key. The Unit interface is implemented by a
+// thin wrapper around it.
+type UnitValue struct {
+	prefix     Prefix
+	measure    Measure
+	divMeasure Measure
+}
+
+// unit implements the Unit interface on top of a UnitValue
+type unit struct {
+	UnitValue
+}
+
+type Unit interface {
+	Valid() bool
+	String() string
+	Short() string
+	AddUnitDenominator(div Measure)
+	GetPrefix() Prefix
+	GetMeasure() Measure
+	GetUnitDenominator() Measure
+	SetPrefix(p Prefix)
+	WithPrefix(p Prefix) Unit
+	WithDenominator(div Measure) Unit
+	Base() Unit
+	Clone() Unit
+	Equal(other Unit) bool
+	Compatible(other Unit) bool
+	IsRate() bool
+	IsDataVolume() bool
+	IsTemperature() bool
+	IsPercentage() bool
+	IsEnergy() bool
+}
+
+// INVALID_UNIT is the result of NewUnit("foobar"). It is created without parsing, so the
+// parser tables are not built at startup.
+var INVALID_UNIT Unit = &unit{UnitValue{
+	prefix:     Base,
+	measure:    Flops,
+	divMeasure: InvalidMeasure,
+}}
+
+// invalidUnitValue is the zero value of a unit with invalid prefix, measure and unit denominator
+var invalidUnitValue = UnitValue{
+	prefix:     InvalidPrefix,
+	measure:    InvalidMeasure,
+	divMeasure: InvalidMeasure,
+}
+
+// Valid checks whether a unit is a valid unit. A unit is valid if it has at least a prefix and a measure. The unit denominator is optional.
+func (u UnitValue) Valid() bool {
+	return u.prefix != InvalidPrefix && u.measure != InvalidMeasure
+}
+
+// String returns the long string for the unit like 'KiloHertz' or 'MegaBytes'
+func (u UnitValue) String() string {
+	return u.formatted().long
+}
+
+// Short returns the short string for the unit like 'kHz' or 'MByte'. Is is recommened to use Short() over String().
+func (u UnitValue) Short() string {
+	return u.formatted().short
+}
+
+// format creates the long and short strings for the unit
+func (u UnitValue) format() unitStrings {
+	if u.divMeasure != InvalidMeasure {
+		return unitStrings{
+			long:  u.prefix.String() + u.measure.String() + "/" + u.divMeasure.String(),
+			short: u.prefix.Prefix() + u.measure.Short() + "/" + u.divMeasure.Short(),
+		}
+	}
+	return unitStrings{
+		long:  u.prefix.String() + u.measure.String(),
+		short: u.prefix.Prefix() + u.measure.Short(),
+	}
+}
+
+func (u UnitValue) GetPrefix() Prefix {
+	return u.prefix
+}
+
+func (u UnitValue) GetMeasure() Measure {
+	return u.measure
+}
+
+func (u UnitValue) GetUnitDenominator() Measure {
+	return u.divMeasure
+}
+
+// Hash returns a hash of the unit value which is equal for equal values. It does not depend
+// on the process, so it can be used to shard aggregations of many units across processes.
+// Parsed values are canonical: all spellings of a unit like 'MB/s' and 'MByte/s' result in
+// the same value and all invalid unit strings in the same invalid value, so values can be
+// used as map keys instead of unit strings.
+func (u UnitValue) Hash() uint64 {
+	// FNV-1a over the bits of prefix, measure and unit denominator
+	h := uint64(14695981039346656037)
+	for _, v := range [3]uint64{math.Float64bits(float64(u.prefix)), uint64(u.measure), uint64(u.divMeasure)} {
+		for i := 0; i < 64; i += 8 {
+			h ^= (v >> i) & 0xff
+			h *= 1099511628211
+		}
+	}
+	return h
+}
+
+// Unit returns the unit value as Unit. Changes of the returned unit do not affect the value.
+func (u UnitValue) Unit() Unit {
+	return &unit{u}
+}
+
+// ValueOf returns the comparable value of a unit
+func ValueOf(u Unit) UnitValue {
+	if u == nil {
+		return invalidUnitValue
+	}
+	if v, ok := u.(*unit); ok {
+		return v.UnitValue
+	}
+	return UnitValue{
+		prefix:     u.GetPrefix(),
+		measure:    u.GetMeasure(),
+		divMeasure: u.GetUnitDenominator(),
+	}
+}
+
+// WithPrefix returns a copy of the unit value with a different prefix
+func (u UnitValue) WithPrefix(p Prefix) UnitValue {
+	u.prefix = p
+	return u
+}
+
+// Base returns the unit value without prefix like 'B/s' for 'MB/s'. The unit denominator is
+// kept. The invalid unit value stays invalid.
+func (u UnitValue) Base() UnitValue {
+	if !u.Valid() {
+		return invalidUnitValue
+	}
+	u.prefix = Base
+	return u
+}
+
+// WithDenominator returns a copy of the unit value with a unit denominator like Time for
+// deriving the bandwidth 'kB/s' out of the data volume 'kB'. InvalidMeasure removes the
+// unit denominator.
+func (u UnitValue) WithDenominator(div Measure) UnitValue {
+	u.divMeasure = div
+	return u
+}
+
+// Equal checks whether two unit values are the same unit. Different spellings like 'MB/s'
+// and 'MByte/s' result in equal values.
+func (u UnitValue) Equal(other UnitValue) bool {
+	return u == other
+}
+
+// Compatible checks whether values can be converted from one unit to the other, i.e. both
+// units are valid and have the same measure and unit denominator. Temperatures in Celsius
+// and Fahrenheit, ratios and percentages and measures with a registered conversion like dBm
+// and W are compatible.
+func (u UnitValue) Compatible(other UnitValue) bool {
+	if !u.Valid() || !other.Valid() {
+		return false
+	}
+	_, err := converterKindOf(u, other)
+	return err == nil
+}
+
+// AddUnitDenominator adds a unit denominator to an exising unit. Can be used if you want to derive e.g. data volume to bandwidths.
+// The data volume is in a Byte unit like 'kByte' and by dividing it by the runtime in seconds, we get the bandwidth. We can use the
+// data volume unit and add 'Second' as unit denominator.
+// Using AddUnitDenominator is discouraged because it changes units which may be shared with
+// other code, use WithDenominator instead.
+func (u *unit) AddUnitDenominator(div Measure) {
+	u.divMeasure = div
+}
+
+// SetPrefix changes the prefix of the unit.
+// Using SetPrefix is discouraged because it changes units which may be shared with other
+// code, use WithPrefix instead.
+func (u *unit) SetPrefix(p Prefix) {
+	u.prefix = p
+}
+
+// WithPrefix returns a new unit with a different prefix. The unit itself is not changed.
+func (u *unit) WithPrefix(p Prefix) Unit {
+	return &unit{u.UnitValue.WithPrefix(p)}
+}
+
+// WithDenominator returns a new unit with a unit denominator. The unit itself is not changed.
+func (u *unit) WithDenominator(div Measure) Unit {
+	return &unit{u.UnitValue.WithDenominator(div)}
+}
+
+// Base returns the unit without prefix like 'B/s' for 'MB/s' (see UnitValue.Base())
+func (u *unit) Base() Unit {
+	return &unit{u.UnitValue.Base()}
+}
+
+// Equal checks whether two units are the same unit independent of their spelling. Use it
+// instead of comparing the strings of units.
+func (u *unit) Equal(other Unit) bool {
+	return u.UnitValue.Equal(ValueOf(other))
+}
+
+// Compatible checks whether values can be converted from the unit to the other unit
+func (u *unit) Compatible(other Unit) bool {
+	return u.UnitValue.Compatible(ValueOf(other))
+}
+
+// Clone returns a copy of the unit. Changes of the copy with SetPrefix() or
+// AddUnitDenominator() do not affect the original unit, which may be cached or shared.
+func (u *unit) Clone() Unit {
+	return &unit{u.UnitValue}
+}
+
+// GetPrefixPrefixFactor creates the default conversion function between two prefixes.
+// It returns a conversation function for the value. The factors between the built-in
+// prefixes are precomputed exactly. Integer values are multiplied or divided by integer
+// factors directly. For converting many values, NewPrefixConverter() avoids boxing each
+// value in interface{}.
+func GetPrefixPrefixFactor(in Prefix, out Prefix) func(value interface{}) interface{} {
+	key := [2]Prefix{in, out}
+	if conv, ok := prefixConversions.Load(key); ok {
+		return conv
+	}
+	conv := NewPrefixConverter(in, out).Apply
+	prefixConversions.Store(key, conv, nil)
+	return conv
+}
+
+// This is the conversion function between temperatures in Celsius to Fahrenheit
+func convertTempC2TempF(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return (v * 1.8) + 32
+	case float32:
+		return (v * 1.8) + 32
+	case int:
+		return int((float64(v) * 1.8) + 32)
+	case int32:
+		return int32((float64(v) * 1.8) + 32)
+	case int64:
+		return int64((float64(v) * 1.8) + 32)
+	case uint:
+		return uint((float64(v) * 1.8) + 32)
+	case uint32:
+		return uint32((float64(v) * 1.8) + 32)
+	case uint64:
+		return uint64((float64(v) * 1.8) + 32)
+	}
+	return value
+}
+
+// This is the conversion function between temperatures in Fahrenheit to Celsius
+func convertTempF2TempC(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		return (v - 32) / 1.8
+	case float32:
+		return (v - 32) / 1.8
+	case int:
+		return int(((float64(v) - 32) / 1.8))
+	case int32:
+		return int32(((float64(v) - 32) / 1.8))
+	case int64:
+		return int64(((float64(v) - 32) / 1.8))
+	case uint:
+		return uint(((float64(v) - 32) / 1.8))
+	case uint32:
+		return uint32(((float64(v) - 32) / 1.8))
+	case uint64:
+		return uint64(((float64(v) - 32) / 1.8))
+	}
+	return value
+}
+
+// GetPrefixStringPrefixStringFactor is a wrapper for GetPrefixPrefixFactor with string inputs instead
+// of prefixes. It also returns a conversation function for the value.
+func GetPrefixStringPrefixStringFactor(in string, out string) func(value interface{}) interface{} {
+	var i Prefix = NewPrefix(in)
+	var o Prefix = NewPrefix(out)
+	return GetPrefixPrefixFactor(i, o)
+}
+
+// GetUnitPrefixFactor gets the conversion function and resulting unit for a unit and a prefix. This is
+// the most common case where you have some input unit and want to convert it to the same unit but with
+// a different prefix. The returned unit represents the value after conversation.
+func GetUnitPrefixFactor(in Unit, out Prefix) (func(value interface{}) interface{}, Unit) {
+	outUnit := NewUnit(in.Short())
+	if outUnit.Valid() {
+		outUnit = outUnit.WithPrefix(out)
+		conv := GetPrefixPrefixFactor(in.GetPrefix(), out)
+		return conv, outUnit
+	}
+	return nil, INVALID_UNIT
+}
+
+// GetUnitPrefixStringFactor gets the conversion function and resulting unit for a unit and a prefix as string.
+// It is a wrapper for GetUnitPrefixFactor
+func GetUnitPrefixStringFactor(in Unit, out string) (func(value interface{}) interface{}, Unit) {
+	var o Prefix = NewPrefix(out)
+	return GetUnitPrefixFactor(in, o)
+}
+
+// GetUnitStringPrefixStringFactor gets the conversion function and resulting unit for a unit and a prefix when both are only string representations.
+// This is just a wrapper for GetUnitPrefixFactor with the given input unit and the desired output prefix.
+func GetUnitStringPrefixStringFactor(in string, out string) (func(value interface{}) interface{}, Unit) {
+	var i = NewUnit(in)
+	return GetUnitPrefixStringFactor(i, out)
+}
+
+// GetUnitUnitFactor gets the conversion function and (maybe) error for unit to unit conversion.
+// It is basically a wrapper for GetPrefixPrefixFactor with some special cases for temperature
+// conversion between Fahrenheit and Celsius, between units with different scales like ratios
+// and percentages and for the registered non-linear conversions like dBm to W. For converting many values, NewConverter()
+// avoids boxing each value in interface{}.
+func GetUnitUnitFactor(in Unit, out Unit) (func(value interface{}) interface{}, error) {
+	kind, err := converterKindOf(ValueOf(in), ValueOf(out))
+	switch {
+	case err != nil:
+		return func(value interface{}) interface{} { return 1.0 }, err
+	case kind == converterTempC2F && in.GetPrefix() == Base && out.GetPrefix() == Base:
+		return convertTempC2TempF, nil
+	case kind == converterTempF2C && in.GetPrefix() == Base && out.GetPrefix() == Base:
+		return convertTempF2TempC, nil
+	case kind != converterFactor || scaleRatio(ValueOf(in), ValueOf(out)) != 1:
+		conv, err := NewConverter(in, out)
+		return conv.Apply, err
+	}
+	return GetPrefixPrefixFactor(in.GetPrefix(), out.GetPrefix()), nil
+}
+
+// knownMeasure checks whether a measure is built-in or registered
+func knownMeasure(m Measure) bool {
+	if _, ok := builtinMeasure(m); ok {
+		return true
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	_, ok := MeasuresMap[m]
+	return ok
+}
+
+// knownPrefix checks whether a prefix is built-in or registered
+func knownPrefix(p Prefix) bool {
+	if _, ok := builtinPrefix(p); ok {
+		return true
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	_, ok := PrefixDataMap[p]
+	return ok
+}
+
+// NewUnitValueFromParts creates a unit value out of a prefix, a measure and a unit
+// denominator (InvalidMeasure for none) without formatting and parsing a unit string. If the
+// prefix or one of the measures is unknown, the invalid unit value is returned, like for an
+// invalid unit string.
+func NewUnitValueFromParts(prefix Prefix, measure Measure, div Measure) UnitValue {
+	if !knownPrefix(prefix) || !knownMeasure(measure) || (div != InvalidMeasure && !knownMeasure(div)) {
+		return invalidUnitValue
+	}
+	return UnitValue{
+		prefix:     prefix,
+		measure:    measure,
+		divMeasure: div,
+	}
+}
+
+// NewUnitFromParts creates a unit out of a prefix, a measure and a unit denominator
+// (InvalidMeasure for none) like NewUnitFromParts(Mega, Bytes, Time) for 'MB/s'. See
+// NewUnitValueFromParts().
+func NewUnitFromParts(prefix Prefix, measure Measure, div Measure) Unit {
+	return &unit{NewUnitValueFromParts(prefix, measure, div)}
+}
+
+// newUnit creates a unit out of its parts
+func newUnit(prefix Prefix, measure Measure, div Measure) Unit {
+	return &unit{UnitValue{
+		prefix:     prefix,
+		measure:    measure,
+		divMeasure: div,
+	}}
+}
+
+// newBaseUnit creates a unit without unit denominator
+func newBaseUnit(prefix Prefix, measure Measure) Unit {
+	return newUnit(prefix, measure, InvalidMeasure)
+}
+
+// cutInverse returns the unit of an inverse unit string like 's' for '1/s' or 's^-1' or of
+// a unit string with only a denominator like '/s' or 'per second'
+func cutInverse(unitStr string) (string, bool) {
+	for _, prefix := range []string{"1/", "/"} {
+		if s, ok := strings.CutPrefix(unitStr, prefix); ok {
+			return s, true
+		}
+	}
+	if len(unitStr) > 4 && strings.EqualFold(unitStr[:4], "per ") {
+		return strings.TrimSpace(unitStr[4:]), true
+	}
+	return strings.CutSuffix(unitStr, "^-1")
+}
+
+// inverseTime returns the frequency of an inverse time like 'kHz' for '1/ms'. Other units
+// and prefixes without inverse prefix like 'Ki' result in the invalid unit value, as well as
+// the time scales minutes, hours and days: there is no prefix of Hertz for '1/h'.
+func inverseTime(t UnitValue) UnitValue {
+	if t.measure != Time || t.divMeasure != InvalidMeasure {
+		return invalidUnitValue
+	}
+	for _, e := range prefixTableEntries {
+		if e.prefix != t.prefix {
+			continue
+		}
+		for _, inv := range prefixTableEntries {
+			if inv.base == e.base && inv.exponent == -e.exponent {
+				return UnitValue{prefix: inv.prefix, measure: Frequency, divMeasure: InvalidMeasure}
+			}
+		}
+	}
+	return invalidUnitValue
+}
+
+// parseUnitValue parses a string representing a unit. It detects the prefix, unit and
+// (maybe) unit denominator. Inverse times like '1/s' or 's^-1' are parsed as frequencies.
+func parseUnitValue(unitStr string) UnitValue {
+	return parseUnitValueTrace(unitStr, nil)
+}
+
+// parseUnitValueTrace is parseUnitValue() recording the matches and the applied special
+// cases in t (optional) for ExplainParse()
+func parseUnitValueTrace(unitStr string, t *ParseExplanation) UnitValue {
+	if timeStr, ok := cutInverse(unitStr); ok {
+		u := inverseTime(parseUnitValueTrace(timeStr, t))
+		if t != nil {
+			t.note("inverse '%s' parsed as frequency '%s'", unitStr, u.Short())
+		}
+		return u
+	}
+	if canonical, ok := legacySpelling(unitStr); ok {
+		if t != nil {
+			t.note("legacy spelling '%s' replaced by '%s'", unitStr, canonical)
+		}
+		return parseUnitValueTrace(canonical, t)
+	}
+	u := invalidUnitValue
+	prefixStr, measureStr := splitPrefix(unitStr)
+	pre := NewPrefix(prefixStr)
+	measureStr, divStr, hasDiv := strings.Cut(measureStr, "/")
+	m := NewMeasure(measureStr)
+	t.match(prefixStr, pre, measureStr, m)
+	// Special case for prefix 'p' or 'P' (Peta) and measures starting with 'p' or 'P'
+	// like 'packets' or 'percent'. Same for 'e' or 'E' (Exa) for measures starting with
+	// 'e' or 'E' like 'events' and for registered measures starting with a prefix
+	if m == InvalidMeasure && len(prefixStr) > 0 {
+		if pm := NewMeasure(prefixStr + measureStr); pm != InvalidMeasure {
+			m = pm
+			pre = Base
+			if t != nil {
+				t.note("'%s' is no prefix but part of the measure '%s'", prefixStr, prefixStr+measureStr)
+				t.match("", pre, prefixStr+measureStr, m)
+			}
+		}
+	}
+	div := InvalidMeasure
+	if hasDiv {
+		// Only the first unit denominator is used
+		divStr, rest, more := strings.Cut(divStr, "/")
+		div = NewMeasure(divStr)
+		t.matchDenominator(divStr, div)
+		if t != nil && more {
+			t.note("further unit denominator '/%s' ignored", rest)
+		}
+	}
+
+	switch {
+	// Special case for 'm' as prefix for Bytes and some others as thers is no unit like MilliBytes
+	case isNonDividable(m):
+		if pre == Milli {
+			pre = Mega
+			if t != nil {
+				t.note("prefix 'm' read as Mega because '%s' cannot be divided into fractions", m.String())
+			}
+		}
+		if pre < Base && pre != InvalidPrefix {
+			if t != nil {
+				t.note("prefix '%s' rejected because '%s' cannot be divided into fractions", prefixStr, m.String())
+			}
+			pre = InvalidPrefix
+		}
+	// Special case for percentage, ratio and logarithmic measures. Reject or ignore prefix
+	case isPrefixless(m) && pre != Base:
+		if rejectsPrefix(m) {
+			if t != nil && pre != InvalidPrefix {
+				t.note("prefix '%s' rejected because '%s' has no prefixes", prefixStr, m.String())
+			}
+			pre = InvalidPrefix
+			break
+		}
+		if t != nil && pre != InvalidPrefix {
+			t.note("prefix '%s' ignored because '%s' has no prefixes", prefixStr, m.String())
+		}
+		pre = Base
+	}
+	// The letters of Micro and Nano start many words, so they are only read as prefix in
+	// front of a whole measure like in 'us', 'nsec' or 'uJ' but not in 'uwords'
+	if (pre == Micro || pre == Nano) && !matchesWholeMeasure(measureStr, m) {
+		if t != nil {
+			t.note("prefix '%s' rejected because '%s' is no spelling of '%s'", prefixStr, measureStr, m.String())
+		}
+		pre = InvalidPrefix
+	}
+	if pre != InvalidPrefix && m != InvalidMeasure {
+		u.prefix = pre
+		u.measure = m
+		if div != InvalidMeasure {
+			u.divMeasure = div
+		}
+	}
+	return u
+}
+
+// NewUnitValue creates a new unit value out of a string representing a unit like 'Mbyte/s'
+// or 'GHz'. The unit strings are interned, so repeated parses of the same string return
+// the cached value.
+func NewUnitValue(unitStr string) UnitValue {
+	return InternUnit(unitStr)
+}
+
+// NewUnit creates a new unit out of a string representing a unit like 'Mbyte/s' or 'GHz'.
+// It detects the prefix, unit and (maybe) unit denominator. The parsed value is interned,
+// only the returned wrapper is allocated.
+func NewUnit(unitStr string) Unit {
+	return &unit{InternUnit(unitStr)}
+}
+
+// MustParseUnit is like NewUnit but panics if the unit string is invalid. It is meant for
+// package-level variables and tests where an invalid unit literal is a programming error.
+func MustParseUnit(unitStr string) Unit {
+	u := NewUnit(unitStr)
+	if !u.Valid() {
+		panic(fmt.Sprintf("ccunits: invalid unit '%s'", unitStr))
+	}
+	return u
+}
@@ -0,0 +1,437 @@
+package ccunits
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestUnitValue(t *testing.T) {
+	a := NewUnitValue("MB/s")
+	b := ValueOf(NewUnit("MByte/s"))
+	if a != b {
+		t.Errorf("Expected equal unit values for 'MB/s' and 'MByte/s'")
+	}
+	if a == NewUnitValue("GB/s") {
+		t.Errorf("Expected different unit values for 'MB/s' and 'GB/s'")
+	}
+	counts := map[UnitValue]int{a: 1}
+	counts[b]++
+	if counts[a] != 2 {
+		t.Errorf("Expected unit values as equal map keys")
+	}
+
+	// Changing the wrapper must not change the value
+	u := a.Unit()
+	u.SetPrefix(Giga)
+	if a.Short() != "MB/s" || u.Short() != "GB/s" {
+		t.Errorf("Unexpected units '%s' and '%s' after SetPrefix", a.Short(), u.Short())
+	}
+	if NewUnitValue("xyz").Valid() || ValueOf(nil).Valid() {
+		t.Errorf("Expected invalid unit values")
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	u := NewUnit("kB")
+	v := u.WithPrefix(Mega).WithDenominator(Time)
+	if u.Short() != "KB" || v.Short() != "MB/s" {
+		t.Errorf("Unexpected units '%s' and '%s'", u.Short(), v.Short())
+	}
+	if w := NewUnitValue("GB/s").WithDenominator(InvalidMeasure); w != NewUnitValue("GB") {
+		t.Errorf("Expected 'GB' but got '%s'", w.Short())
+	}
+}
+
+func TestEqualCompatible(t *testing.T) {
+	u := NewUnit("MB/s")
+	for _, c := range []struct {
+		other      string
+		equal      bool
+		compatible bool
+	}{
+		{"MByte/s", true, true},
+		{"GB/s", false, true},
+		{"MB", false, false},
+		{"GHz", false, false},
+		{"xyz", false, false},
+	} {
+		o := NewUnit(c.other)
+		if u.Equal(o) != c.equal || u.Compatible(o) != c.compatible {
+			t.Errorf("Unexpected Equal() %v and Compatible() %v for '%s'", u.Equal(o), u.Compatible(o), c.other)
+		}
+	}
+	if !NewUnit("degC").Compatible(NewUnit("degF")) || NewUnit("xyz").Compatible(NewUnit("abc")) {
+		t.Errorf("Unexpected compatibility of temperatures or invalid units")
+	}
+}
+
+func TestCategories(t *testing.T) {
+	for _, c := range []struct {
+		unit                                       string
+		rate, volume, temperature, percent, energy bool
+	}{
+		{"MB/s", true, false, false, false, false},
+		{"GB", false, true, false, false, false},
+		{"degF", false, false, true, false, false},
+		{"%", false, false, false, true, false},
+		{"kJ", false, false, false, false, true},
+		{"W", false, false, false, false, false},
+		{"xyz", false, false, false, false, false},
+	} {
+		u := NewUnit(c.unit)
+		if u.IsRate() != c.rate || u.IsDataVolume() != c.volume || u.IsTemperature() != c.temperature ||
+			u.IsPercentage() != c.percent || u.IsEnergy() != c.energy {
+			t.Errorf("Unexpected categories for '%s'", c.unit)
+		}
+	}
+}
+
+func TestInverseUnits(t *testing.T) {
+	for input, expected := range map[string]string{
+		"1/s":        "Hz",
+		"s^-1":       "Hz",
+		"1/ms":       "KHz",
+		"Ks^-1":      "mHz",
+		"/s":         "Hz",
+		"per second": "Hz",
+		"Per ms":     "KHz",
+		"/":          "",
+		"1/Kis":      "",
+		"1/B":        "",
+		"1/h":        "",
+		"1/min":      "",
+		"/d":         "",
+		"per hour":   "",
+	} {
+		u := NewUnit(input)
+		if (len(expected) == 0 && u.Valid()) || (len(expected) > 0 && u.Short() != expected) {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input, u.Short())
+		}
+	}
+	conv, err := GetUnitUnitFactor(NewUnit("1/ms"), NewUnit("Hz"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v := conv(2.5); v != 2500.0 {
+		t.Errorf("Expected 2.5 1/ms = 2500 Hz but got %v", v)
+	}
+
+	// Inverse time scales have no frequency with prefix, rates like 'count/h' are used instead
+	for _, input := range []string{"1/h", "1/min", "/d", "h^-1"} {
+		if _, err := ParseUnit(input); !errors.Is(err, ErrIncompatibleMeasure) || !strings.Contains(err.Error(), "use a rate like") {
+			t.Errorf("Expected error for inverse time scale '%s' but got %v", input, err)
+		}
+		if _, err := ParseUnit(input, WithStrictSI()); !errors.Is(err, ErrIncompatibleMeasure) {
+			t.Errorf("Expected error for inverse time scale '%s' in strict mode but got %v", input, err)
+		}
+	}
+	if _, err := ParseUnit("1/B"); !errors.Is(err, ErrIncompatibleMeasure) {
+		t.Errorf("Expected error for inverse bytes but got %v", err)
+	}
+	if _, err := GetUnitUnitFactor(NewUnit("count/h"), NewUnit("count/s")); err != nil {
+		t.Errorf("Expected conversion from 'count/h' to 'count/s': %v", err)
+	}
+}
+
+func TestMustParseUnit(t *testing.T) {
+	if u := MustParseUnit("GHz"); u.Short() != "GHz" {
+		t.Errorf("Expected 'GHz' but got '%s'", u.Short())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for invalid unit")
+		}
+	}()
+	MustParseUnit("xyz")
+}
+
+func TestClone(t *testing.T) {
+	u := NewUnit("MB/s")
+	c := u.Clone()
+	c.SetPrefix(Giga)
+	c.AddUnitDenominator(InvalidMeasure)
+	if u.Short() != "MB/s" || c.Short() != "GB" {
+		t.Errorf("Unexpected units '%s' and '%s' after changing the clone", u.Short(), c.Short())
+	}
+}
+
+func TestNewUnitFromParts(t *testing.T) {
+	if u := NewUnitFromParts(Mega, Bytes, Time); u.Short() != "MB/s" || ValueOf(u) != NewUnitValue("MB/s") {
+		t.Errorf("Expected 'MB/s' but got '%s'", u.Short())
+	}
+	if v := NewUnitValueFromParts(Base, Watt, InvalidMeasure); v != NewUnitValue("W") {
+		t.Errorf("Expected 'W' but got '%s'", v.Short())
+	}
+	for _, v := range []UnitValue{
+		NewUnitValueFromParts(Prefix(42), Bytes, InvalidMeasure),
+		NewUnitValueFromParts(Kilo, Measure(1000), InvalidMeasure),
+		NewUnitValueFromParts(Kilo, Bytes, Measure(1000)),
+	} {
+		if v.Valid() || v != NewUnitValue("xyz") {
+			t.Errorf("Expected invalid unit value but got '%s'", v.Short())
+		}
+	}
+}
+
+func TestUnitValueHash(t *testing.T) {
+	if NewUnitValue("MB/s").Hash() != NewUnitValue("MByte/s").Hash() {
+		t.Errorf("Expected equal hashes for 'MB/s' and 'MByte/s'")
+	}
+	if NewUnitValue("xyz") != ValueOf(nil) || NewUnitValue("xyz").Hash() != NewUnitValue("?").Hash() {
+		t.Errorf("Expected equal values and hashes for invalid units")
+	}
+	seen := make(map[uint64]string)
+	for _, s := range []string{"B", "kB", "MB", "MB/s", "GB/s", "KiB", "W", "mW", "J", "Hz", "GHz", "s", "ms", "degC", "degF", "%"} {
+		h := NewUnitValue(s).Hash()
+		if other, ok := seen[h]; ok {
+			t.Errorf("Units '%s' and '%s' have the same hash", s, other)
+		}
+		seen[h] = s
+	}
+}
+
+// copyMap returns a copy of a registry table
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	out := make(map[K]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// restoreRegistry restores the measures, prefixes, aliases, metadata, conversions and legacy
+// spellings of the registry after the test, so tests registering units are hermetic
+func restoreRegistry(t *testing.T) {
+	registryLock.RLock()
+	measures, prefixes := copyMap(MeasuresMap), copyMap(PrefixDataMap)
+	mAliases, pAliases := copyMap(measureAliases), copyMap(prefixAliases)
+	metadata, convs, legacy := copyMap(measureMetadata), copyMap(conversions), copyMap(legacySpellings)
+	registryLock.RUnlock()
+	t.Cleanup(func() {
+		registryLock.Lock()
+		defer registryLock.Unlock()
+		MeasuresMap, PrefixDataMap = measures, prefixes
+		measureAliases, prefixAliases = mAliases, pAliases
+		measureMetadata, conversions, legacySpellings = metadata, convs, legacy
+		updatePrefixSymbols()
+		registryChanged()
+	})
+}
+
+func TestInternUnit(t *testing.T) {
+	restoreRegistry(t)
+	if InternUnit("qqq").Valid() {
+		t.Fatalf("Expected invalid unit 'qqq'")
+	}
+	if err := RegisterMeasureAlias("qqq", Bytes); err != nil {
+		t.Fatalf("Failed to register alias: %v", err)
+	}
+	// Registration must drop the cached parse result
+	if v := InternUnit("qqq"); v != NewUnitValue("B") {
+		t.Errorf("Expected 'B' for alias 'qqq' but got '%s'", v.Short())
+	}
+	if allocs := testing.AllocsPerRun(100, func() { InternUnit("MB/s") }); allocs != 0 {
+		t.Errorf("Expected no allocations for interned units but got %v", allocs)
+	}
+}
+
+func TestInvalidUnitHook(t *testing.T) {
+	invalid := make([]string, 0)
+	SetInvalidUnitHook(func(unitStr string) { invalid = append(invalid, unitStr) })
+	defer SetInvalidUnitHook(nil)
+	NewUnit("MB/s")
+	NewUnit("qwertz")
+	NewUnitValue("qwertz") // Cached result
+	ParseUnit("Mbyt/s", WithStrictSI())
+	if len(invalid) != 3 || invalid[0] != "qwertz" || invalid[2] != "Mbyt/s" {
+		t.Errorf("Unexpected invalid unit strings %v", invalid)
+	}
+}
+
+func TestUnitStringsAllocations(t *testing.T) {
+	u := NewUnit("MB/s")
+	if u.Short() != "MB/s" || u.String() != "Megabyte/Seconds" {
+		t.Errorf("Unexpected strings '%s' and '%s'", u.Short(), u.String())
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = u.Short() }); allocs != 0 {
+		t.Errorf("Expected no allocations for Short() but got %v", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = u.String() }); allocs != 0 {
+		t.Errorf("Expected no allocations for String() but got %v", allocs)
+	}
+}
+
+func TestInvalidUnit(t *testing.T) {
+	if ValueOf(INVALID_UNIT) != NewUnitValue("foobar") {
+		t.Errorf("INVALID_UNIT differs from the parse result of 'foobar'")
+	}
+}
+
+func TestCanonicalUnit(t *testing.T) {
+	a := CanonicalUnit("MByte/s")
+	b := NewUnitValue("MB/s").Canonical()
+	if a != "MB/s" || b != "MB/s" {
+		t.Fatalf("Unexpected canonical units '%s' and '%s'", a, b)
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Errorf("Canonical strings of equal units are not shared")
+	}
+	// Strings stay shared after the formatted strings are dropped
+	registryLock.Lock()
+	registryChanged()
+	registryLock.Unlock()
+	if c := CanonicalUnit("Mbyte/s"); unsafe.StringData(c) != unsafe.StringData(a) {
+		t.Errorf("Canonical string changed after registration")
+	}
+}
+
+func TestBaseUnit(t *testing.T) {
+	for m, expected := range map[Measure]string{Bytes: "B", Frequency: "Hz", Watt: "W", Percentage: "%", InvalidMeasure: "invalinval"} {
+		if u := m.BaseUnit(); u.Short() != expected || u.Valid() != (m != InvalidMeasure) {
+			t.Errorf("Expected base unit '%s' for measure %d but got '%s'", expected, m, u.Short())
+		}
+	}
+	for in, expected := range map[string]string{"MB/s": "B/s", "GiB": "B", "kW": "W", "s": "s", "xyz": "invalinval"} {
+		if u := NewUnit(in).Base(); u.Short() != expected {
+			t.Errorf("Expected '%s' without prefix for '%s' but got '%s'", expected, in, u.Short())
+		}
+	}
+}
+
+// Unit strings and the units they parsed to before the package was forked from cc-units.
+// Later measures must not change how these strings are parsed.
+func TestBaselineUnitStrings(t *testing.T) {
+	for input, expected := range map[string]string{
+		"%":            "%",
+		"B":            "B",
+		"Bytes":        "B",
+		"Bytes/s":      "B/s",
+		"EB":           "EB",
+		"Events":       "events",
+		"Flops":        "Flops",
+		"GB":           "GB",
+		"GB/s":         "GB/s",
+		"GByte/s":      "GB/s",
+		"GBytes":       "GB",
+		"GFlops/s":     "GFlops/s",
+		"GHz":          "GHz",
+		"GiB":          "GiB",
+		"Hz":           "Hz",
+		"J":            "J",
+		"Joules":       "J",
+		"KB":           "KB",
+		"MB":           "MB",
+		"MByte":        "MB",
+		"MByte/s":      "MB/s",
+		"MFlops/s":     "MFlops/s",
+		"MHz":          "MHz",
+		"MiB":          "MiB",
+		"PB":           "PB",
+		"Packets/s":    "packets/s",
+		"Percent":      "%",
+		"TB/s":         "TB/s",
+		"W":            "W",
+		"Watt":         "W",
+		"Watts":        "W",
+		"bytes":        "B",
+		"bytes/sec":    "B/s",
+		"cycles":       "cyc",
+		"degC":         "degC",
+		"degF":         "degF",
+		"events":       "events",
+		"events/s":     "events/s",
+		"flops/s":      "Flops/s",
+		"foo":          "Flops",
+		"kB":           "KB",
+		"kW":           "KW",
+		"ks":           "Ks",
+		"mB":           "MB",
+		"mJ":           "mJ",
+		"mW":           "mW",
+		"ms":           "ms",
+		"pW":           "PW",
+		"packets":      "packets",
+		"packets/s":    "packets/s",
+		"packets/sec":  "packets/s",
+		"percent":      "%",
+		"reqs/s":       "requests/s",
+		"requests":     "requests",
+		"requests/s":   "requests/s",
+		"requests/sec": "requests/s",
+		"rpm":          "RPM",
+		"s":            "s",
+		"sec":          "s",
+		"seconds":      "s",
+		"watts":        "W",
+		"widget":       "W",
+		"°C":           "degC",
+	} {
+		if u := NewUnit(input); !u.Valid() || u.Short() != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input, u.Short())
+		}
+	}
+}
+
+func TestPrefixlessMeasures(t *testing.T) {
+	for input, expected := range map[string]string{
+		"ratio":    "ratio",
+		"dB":       "dB",
+		"dBm":      "dBm",
+		"min":      "min",
+		"minutes":  "min",
+		"h":        "h",
+		"d":        "d",
+		"kpercent": "%", // Prefixes of percentages are ignored
+	} {
+		if u := NewUnit(input); !u.Valid() || u.Short() != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input, u.Short())
+		}
+	}
+	// A prefix would silently scale the value
+	for _, input := range []string{"kratio", "Gratio", "mratio", "kdB", "MdBm", "mdBm", "kh", "mh", "Mh", "kmin", "Gd", "kh/s"} {
+		if u := NewUnit(input); u.Valid() {
+			t.Errorf("Expected invalid unit for '%s' but got '%s'", input, u.Short())
+		}
+		if _, err := ParseUnit(input); !errors.Is(err, ErrInvalidPrefix) {
+			t.Errorf("Expected invalid prefix error for '%s' but got %v", input, err)
+		}
+	}
+}
+
+func TestNonDividableMeasures(t *testing.T) {
+	for input, expected := range map[string]string{
+		"mb":     "MB", // Milli is read as Mega
+		"mflops": "MFlops",
+		"us":     "us",
+		"µs":     "us",
+		"nsec":   "ns",
+		"uW":     "uW",
+		"nJ/s":   "nJ/s",
+	} {
+		if u := NewUnit(input); !u.Valid() || u.Short() != expected {
+			t.Errorf("Expected '%s' for '%s' but got '%s'", expected, input, u.Short())
+		}
+	}
+	// Micro and Nano are not used for measures without fractions and only in front of whole
+	// measures, 'uwords' is no Micro Watt
+	for input, expected := range map[string]error{
+		"ubytes":     ErrInvalidPrefix,
+		"nflops/sec": ErrInvalidPrefix,
+		"upackets":   ErrInvalidPrefix,
+		"µB":         ErrInvalidPrefix,
+		"ncycles":    ErrInvalidPrefix,
+		"uwords":     ErrInvalidMeasure,
+	} {
+		if u := NewUnit(input); u.Valid() {
+			t.Errorf("Expected invalid unit for '%s' but got '%s'", input, u.Short())
+		}
+		if _, err := ParseUnit(input); !errors.Is(err, expected) {
+			t.Errorf("Expected error '%v' for '%s' but got %v", expected, input, err)
+		}
+		if _, err := ParseUnit(input, WithoutMilliByteHeuristic()); !errors.Is(err, expected) {
+			t.Errorf("Expected error '%v' for '%s' with options but got %v", expected, input, err)
+		}
+	}
+}
@@ -0,0 +1,545 @@
+// Code generated by ccunits-gen. DO NOT EDIT.
+
+export interface PrefixDefinition {
+  long: string;
+  short: string;
+  factor: number;
+  regex?: string;
+}
+
+export interface MeasureDefinition {
+  id: number;
+  long: string;
+  short: string;
+  regex?: string;
+  nonDividable: boolean;
+  description?: string;
+  singular?: string;
+  plural?: string;
+  category?: string;
+  color?: string;
+  typicalMin: number;
+  typicalMax: number;
+  validMin?: number;
+  validMax?: number;
+  nonNegative: boolean;
+  counter: boolean;
+}
+
+export const prefixes: PrefixDefinition[] = [
+  {
+    "long": "Nano",
+    "short": "n",
+    "factor": 1e-9,
+    "regex": "^[n]$"
+  },
+  {
+    "long": "Micro",
+    "short": "u",
+    "factor": 0.000001,
+    "regex": "^[uµμ]$"
+  },
+  {
+    "long": "Milli",
+    "short": "m",
+    "factor": 0.001,
+    "regex": "^[m]$"
+  },
+  {
+    "long": "",
+    "short": "",
+    "factor": 1,
+    "regex": "^$"
+  },
+  {
+    "long": "Kilo",
+    "short": "K",
+    "factor": 1000,
+    "regex": "^[kK]$"
+  },
+  {
+    "long": "Kibi",
+    "short": "Ki",
+    "factor": 1024,
+    "regex": "^[kK][i]$"
+  },
+  {
+    "long": "Mega",
+    "short": "M",
+    "factor": 1000000,
+    "regex": "^[M]$"
+  },
+  {
+    "long": "Mebi",
+    "short": "Mi",
+    "factor": 1048576,
+    "regex": "^[M][i]$"
+  },
+  {
+    "long": "Giga",
+    "short": "G",
+    "factor": 1000000000,
+    "regex": "^[gG]$"
+  },
+  {
+    "long": "Gibi",
+    "short": "Gi",
+    "factor": 1073741824,
+    "regex": "^[gG][i]$"
+  },
+  {
+    "long": "Tera",
+    "short": "T",
+    "factor": 1000000000000,
+    "regex": "^[tT]$"
+  },
+  {
+    "long": "Tebi",
+    "short": "Ti",
+    "factor": 1099511627776,
+    "regex": "^[tT][i]$"
+  },
+  {
+    "long": "Peta",
+    "short": "P",
+    "factor": 1000000000000000,
+    "regex": "^[pP]$"
+  },
+  {
+    "long": "Pebi",
+    "short": "Pi",
+    "factor": 1125899906842624,
+    "regex": "^[pP][i]$"
+  },
+  {
+    "long": "Exa",
+    "short": "E",
+    "factor": 1000000000000000000,
+    "regex": "^[eE]$"
+  },
+  {
+    "long": "Exbi",
+    "short": "Ei",
+    "factor": 1152921504606847000,
+    "regex": "^[eE][i]$"
+  },
+  {
+    "long": "Zetta",
+    "short": "Z",
+    "factor": 1e+21,
+    "regex": "^[zZ]$"
+  },
+  {
+    "long": "Zebi",
+    "short": "Zi",
+    "factor": 1.1805916207174113e+21,
+    "regex": "^[zZ][i]$"
+  },
+  {
+    "long": "Yotta",
+    "short": "Y",
+    "factor": 1e+24,
+    "regex": "^[yY]$"
+  },
+  {
+    "long": "Yobi",
+    "short": "Yi",
+    "factor": 1.2089258196146292e+24,
+    "regex": "^[yY][i]$"
+  }
+];
+
+export const measures: MeasureDefinition[] = [
+  {
+    "id": 1,
+    "long": "byte",
+    "short": "B",
+    "regex": "^([bB][yY]?[tT]?[eE]?[sS]?)",
+    "nonDividable": true,
+    "description": "Amount of data like memory usage or transferred data",
+    "singular": "Byte",
+    "plural": "Bytes",
+    "category": "data",
+    "color": "#1f77b4",
+    "typicalMin": 0,
+    "typicalMax": 1000000000000,
+    "nonNegative": true,
+    "counter": false
+  },
+  {
+    "id": 2,
+    "long": "Flops",
+    "short": "Flops",
+    "regex": "^([fF][lL]?[oO]?[pP]?[sS]?)",
+    "nonDividable": true,
+    "description": "Floating point operations",
+    "singular": "Flop",
+    "plural": "Flops",
+    "category": "compute",
+    "color": "#ff7f0e",
+    "typicalMin": 0,
+    "typicalMax": 1000000000000,
+    "nonNegative": true,
+    "counter": true
+  },
+  {
+    "id": 3,
+    "long": "Percent",
+    "short": "%",
+    "regex": "^(%|[pP]ercent)",
+    "nonDividable": false,
+    "description": "Share of a total like the CPU utilization",
+    "singular": "Percent",
+    "plural": "Percent",
+    "category": "ratio",
+    "color": "#2ca02c",
+    "typicalMin": 0,
+    "typicalMax": 100,
+    "validMin": 0,
+    "validMax": 100,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 4,
+    "long": "DegreeC",
+    "short": "degC",
+    "regex": "^(deg[Cc]|°[cC])",
+    "nonDividable": false,
+    "description": "Temperature in degree Celsius",
+    "singular": "Degree Celsius",
+    "plural": "Degrees Celsius",
+    "category": "temperature",
+    "color": "#d62728",
+    "typicalMin": 20,
+    "typicalMax": 100,
+    "validMin": -273.15,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 5,
+    "long": "DegreeF",
+    "short": "degF",
+    "regex": "^(deg[fF]|°[fF])",
+    "nonDividable": false,
+    "description": "Temperature in degree Fahrenheit",
+    "singular": "Degree Fahrenheit",
+    "plural": "Degrees Fahrenheit",
+    "category": "temperature",
+    "color": "#d62728",
+    "typicalMin": 68,
+    "typicalMax": 212,
+    "validMin": -459.67,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 6,
+    "long": "RPM",
+    "short": "RPM",
+    "regex": "^([rR][pP][mM])",
+    "nonDividable": false,
+    "description": "Rotational speed of fans in revolutions per minute",
+    "singular": "Revolution per Minute",
+    "plural": "Revolutions per Minute",
+    "category": "frequency",
+    "color": "#9467bd",
+    "typicalMin": 0,
+    "typicalMax": 20000,
+    "validMin": 0,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 7,
+    "long": "Hertz",
+    "short": "Hz",
+    "regex": "^([hH][eE]?[rR]?[tT]?[zZ])",
+    "nonDividable": false,
+    "description": "Clock frequency of processors and memory",
+    "singular": "Hertz",
+    "plural": "Hertz",
+    "category": "frequency",
+    "color": "#8c564b",
+    "typicalMin": 0,
+    "typicalMax": 5000000000,
+    "validMin": 0,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 8,
+    "long": "Seconds",
+    "short": "s",
+    "regex": "^([sS][eE]?[cC]?[oO]?[nN]?[dD]?[sS]?)",
+    "nonDividable": false,
+    "description": "Duration like runtimes and latencies",
+    "singular": "Second",
+    "plural": "Seconds",
+    "category": "time",
+    "color": "#e377c2",
+    "typicalMin": 0,
+    "typicalMax": 86400,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 9,
+    "long": "Watts",
+    "short": "W",
+    "regex": "^([wW][aA]?[tT]?[tT]?[sS]?)",
+    "nonDividable": false,
+    "description": "Power consumption",
+    "singular": "Watt",
+    "plural": "Watts",
+    "category": "power",
+    "color": "#bcbd22",
+    "typicalMin": 0,
+    "typicalMax": 1000,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 10,
+    "long": "Joules",
+    "short": "J",
+    "regex": "^([jJ][oO]?[uU]?[lL]?[eE]?[sS]?)",
+    "nonDividable": false,
+    "description": "Energy consumption",
+    "singular": "Joule",
+    "plural": "Joules",
+    "category": "energy",
+    "color": "#17becf",
+    "typicalMin": 0,
+    "typicalMax": 1000000000,
+    "nonNegative": false,
+    "counter": true
+  },
+  {
+    "id": 11,
+    "long": "Cycles",
+    "short": "cyc",
+    "regex": "^([cC][yY][cC]?[lL]?[eE]?[sS]?)",
+    "nonDividable": true,
+    "description": "Clock cycles of processors",
+    "singular": "Cycle",
+    "plural": "Cycles",
+    "category": "compute",
+    "color": "#ff9896",
+    "typicalMin": 0,
+    "typicalMax": 1000000000000,
+    "nonNegative": true,
+    "counter": true
+  },
+  {
+    "id": 12,
+    "long": "Requests",
+    "short": "requests",
+    "regex": "^([rR][eE][qQ][uU]?[eE]?[sS]?[tT]?[sS]?)",
+    "nonDividable": true,
+    "description": "Requests to services like file systems",
+    "singular": "Request",
+    "plural": "Requests",
+    "category": "count",
+    "color": "#aec7e8",
+    "typicalMin": 0,
+    "typicalMax": 1000000,
+    "nonNegative": true,
+    "counter": true
+  },
+  {
+    "id": 13,
+    "long": "Packets",
+    "short": "packets",
+    "regex": "^([pP][aA]?[cC]?[kK][eE]?[tT][sS]?)",
+    "nonDividable": true,
+    "description": "Network packets",
+    "singular": "Packet",
+    "plural": "Packets",
+    "category": "network",
+    "color": "#98df8a",
+    "typicalMin": 0,
+    "typicalMax": 1000000000,
+    "nonNegative": true,
+    "counter": true
+  },
+  {
+    "id": 14,
+    "long": "Events",
+    "short": "events",
+    "regex": "^([eE][vV]?[eE]?[nN][tT][sS]?)",
+    "nonDividable": true,
+    "description": "Occurrences of hardware or software events",
+    "singular": "Event",
+    "plural": "Events",
+    "category": "count",
+    "color": "#c5b0d5",
+    "typicalMin": 0,
+    "typicalMax": 1000000000,
+    "nonNegative": true,
+    "counter": true
+  },
+  {
+    "id": 15,
+    "long": "Volts",
+    "short": "V",
+    "regex": "^([vV][oO]?[lL]?[tT]?[sS]?)$",
+    "nonDividable": false,
+    "description": "Electrical voltage",
+    "singular": "Volt",
+    "plural": "Volts",
+    "category": "electrical",
+    "color": "#c49c94",
+    "typicalMin": 0,
+    "typicalMax": 250,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 16,
+    "long": "Ampere",
+    "short": "A",
+    "regex": "^([aA][mM]?[pP]?[eE]?[rR]?[eE]?[sS]?)$",
+    "nonDividable": false,
+    "description": "Electrical current",
+    "singular": "Ampere",
+    "plural": "Amperes",
+    "category": "electrical",
+    "color": "#f7b6d2",
+    "typicalMin": 0,
+    "typicalMax": 100,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 17,
+    "long": "Count",
+    "short": "count",
+    "regex": "^([cC][oO][uU][nN][tT][sS]?)",
+    "nonDividable": true,
+    "description": "Number of items like processes or threads",
+    "singular": "Count",
+    "plural": "Counts",
+    "category": "count",
+    "color": "#7f7f7f",
+    "typicalMin": 0,
+    "typicalMax": 10000,
+    "nonNegative": true,
+    "counter": false
+  },
+  {
+    "id": 18,
+    "long": "Ratio",
+    "short": "ratio",
+    "regex": "^([rR][aA][tT][iI][oO])",
+    "nonDividable": false,
+    "description": "Share of a total as fraction between 0 and 1 like a cache hit ratio",
+    "singular": "Ratio",
+    "plural": "Ratio",
+    "category": "ratio",
+    "color": "#98df8a",
+    "typicalMin": 0,
+    "typicalMax": 1,
+    "validMin": 0,
+    "validMax": 1,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 19,
+    "long": "Decibel",
+    "short": "dB",
+    "regex": "^(d[bB]$|[dD]ecibels?$)",
+    "nonDividable": false,
+    "description": "Logarithmic power ratio like a signal-to-noise ratio",
+    "singular": "Decibel",
+    "plural": "Decibels",
+    "category": "ratio",
+    "color": "#ff9896",
+    "typicalMin": -30,
+    "typicalMax": 30,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 20,
+    "long": "DecibelMilliwatt",
+    "short": "dBm",
+    "regex": "^(d[bB][mM]|[dD]ecibel-?[mM]illiwatts?)",
+    "nonDividable": false,
+    "description": "Logarithmic power level relative to 1 mW like the optical power of transceivers",
+    "singular": "Decibel-Milliwatt",
+    "plural": "Decibel-Milliwatts",
+    "category": "power",
+    "color": "#dbdb8d",
+    "typicalMin": -40,
+    "typicalMax": 10,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 21,
+    "long": "Minutes",
+    "short": "min",
+    "regex": "^(min|mins|[mM]inutes?)$",
+    "nonDividable": false,
+    "description": "Duration in minutes like queue wait times",
+    "singular": "Minute",
+    "plural": "Minutes",
+    "category": "time",
+    "color": "#e377c2",
+    "typicalMin": 0,
+    "typicalMax": 1440,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 22,
+    "long": "Hours",
+    "short": "h",
+    "regex": "^(h|hrs?|[hH]ours?)$",
+    "nonDividable": false,
+    "description": "Duration in hours like wallclock limits of jobs",
+    "singular": "Hour",
+    "plural": "Hours",
+    "category": "time",
+    "color": "#e377c2",
+    "typicalMin": 0,
+    "typicalMax": 168,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 23,
+    "long": "Days",
+    "short": "d",
+    "regex": "^(d|[dD]ays?)$",
+    "nonDividable": false,
+    "description": "Duration in days like the runtime of long jobs",
+    "singular": "Day",
+    "plural": "Days",
+    "category": "time",
+    "color": "#e377c2",
+    "typicalMin": 0,
+    "typicalMax": 30,
+    "nonNegative": false,
+    "counter": false
+  },
+  {
+    "id": 24,
+    "long": "InfinibandWords",
+    "short": "IBW",
+    "regex": "^(IBW|IB[wW]ords?|[iI]nfini[bB]and-?[wW]ords?)$",
+    "nonDividable": true,
+    "description": "Data counted by InfiniBand port counters (PortXmitData, PortRcvData) in 4-byte words",
+    "singular": "InfiniBand word",
+    "plural": "InfiniBand words",
+    "category": "network",
+    "color": "#1f77b4",
+    "typicalMin": 0,
+    "typicalMax": 250000000000,
+    "nonNegative": true,
+    "counter": true
+  }
+];
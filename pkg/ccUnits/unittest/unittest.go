@@ -0,0 +1,71 @@
+// Package unittest provides test assertions for code using the ccUnits package, so
+// downstream projects can test their unit handling concisely:
+//
+//	func TestBandwidth(t *testing.T) {
+//		unittest.AssertConvertible(t, "MB/s", "GiB/s")
+//		unittest.AssertQuantityEqual(t, got, units.NewQuantity(1.5, "GB/s"), 1e-9)
+//	}
+package unittest
+
+import (
+	"math"
+	"testing"
+
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+)
+
+// AssertUnit fails the test if a unit string is invalid or not parsed to the expected unit
+// in short notation
+func AssertUnit(t testing.TB, unitStr string, expected string) {
+	t.Helper()
+	u := units.NewUnit(unitStr)
+	if !u.Valid() {
+		t.Errorf("Unit '%s' is invalid", unitStr)
+		return
+	}
+	if u.Short() != expected {
+		t.Errorf("Unit '%s': expected '%s' but got '%s'", unitStr, expected, u.Short())
+	}
+}
+
+// AssertConvertible fails the test if values cannot be converted from one unit to the other
+func AssertConvertible(t testing.TB, in string, out string) {
+	t.Helper()
+	inUnit, outUnit := units.NewUnit(in), units.NewUnit(out)
+	if !inUnit.Valid() || !outUnit.Valid() {
+		t.Errorf("Invalid units '%s' or '%s'", in, out)
+		return
+	}
+	if _, err := units.NewConverter(inUnit, outUnit); err != nil {
+		t.Errorf("Unit '%s' is not convertible to '%s': %v", in, out, err)
+	}
+}
+
+// AssertNotConvertible fails the test if values can be converted from one unit to the other
+func AssertNotConvertible(t testing.TB, in string, out string) {
+	t.Helper()
+	if _, err := units.NewConverter(units.NewUnit(in), units.NewUnit(out)); err == nil {
+		t.Errorf("Unit '%s' is convertible to '%s'", in, out)
+	}
+}
+
+// AssertQuantityEqual fails the test if a quantity differs from the expected quantity by more
+// than the relative tolerance (used as absolute tolerance for values close to 0). The
+// quantity is converted to the unit of the expected quantity before comparing, so '1500 MB'
+// equals '1.5 GB'.
+func AssertQuantityEqual(t testing.TB, got units.Quantity, want units.Quantity, tolerance float64) {
+	t.Helper()
+	if !got.Valid() || !want.Valid() {
+		t.Errorf("Invalid quantities '%s' or '%s'", got.String(), want.String())
+		return
+	}
+	converted, err := got.ConvertTo(want.Unit)
+	if err != nil {
+		t.Errorf("Quantity '%s' is not convertible to '%s': %v", got.String(), want.Unit.Short(), err)
+		return
+	}
+	diff := math.Abs(converted.Value - want.Value)
+	if diff > tolerance*math.Abs(want.Value) && diff > tolerance {
+		t.Errorf("Expected '%s' but got '%s' ('%s')", want.String(), converted.String(), got.String())
+	}
+}
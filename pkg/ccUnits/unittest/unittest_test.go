@@ -0,0 +1,40 @@
+package unittest
+
+import (
+	"fmt"
+	"testing"
+
+	units "github.com/ClusterCockpit/cc-metric-collector/pkg/ccUnits"
+)
+
+// recorder records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	AssertUnit(t, "MByte/s", "MB/s")
+	AssertConvertible(t, "MB/s", "GiB/s")
+	AssertConvertible(t, "degC", "degF")
+	AssertNotConvertible(t, "MB/s", "MB")
+	AssertQuantityEqual(t, units.NewQuantity(1500, "MB"), units.NewQuantity(1.5, "GB"), 1e-9)
+	AssertQuantityEqual(t, units.NewQuantity(0, "W"), units.NewQuantity(1e-12, "W"), 1e-9)
+
+	r := &recorder{TB: t}
+	AssertUnit(r, "xyz", "B")
+	AssertUnit(r, "MB", "GB")
+	AssertConvertible(r, "MB/s", "W")
+	AssertNotConvertible(r, "KB", "MiB")
+	AssertQuantityEqual(r, units.NewQuantity(1600, "MB"), units.NewQuantity(1.5, "GB"), 0.01)
+	AssertQuantityEqual(r, units.NewQuantity(1, "MB"), units.NewQuantity(1, "s"), 0.01)
+	if len(r.failures) != 6 {
+		t.Errorf("Expected 6 failures but got %d: %v", len(r.failures), r.failures)
+	}
+}
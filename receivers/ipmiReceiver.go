@@ -191,7 +191,7 @@ func (r *IPMIReceiver) doReadMetric() {
 					// Utilization
 					metric = "utilization"
 					name = strings.TrimSuffix(name, "_utilization")
-					unit = "percent"
+					unit = "%"
 				} else {
 					if false {
 						// Debug output for unprocessed metrics
@@ -286,7 +286,7 @@ func (r *RedfishReceiver) readPowerMetrics(
 			"source":              r.name,
 			"group":               "Energy",
 			"interval_in_minutes": intervalInMin,
-			"unit":                "watts",
+			"unit":                "W",
 		}
 
 		// Delete empty meta data tags
@@ -386,7 +386,7 @@ func (r *RedfishReceiver) readProcessorMetrics(
 	metaPower := map[string]string{
 		"source": r.name,
 		"group":  "Energy",
-		"unit":   "watts",
+		"unit":   "W",
 	}
 
 	namePower := "consumed_power"